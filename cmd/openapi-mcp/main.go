@@ -2,12 +2,15 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
+	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -32,12 +35,14 @@ func main() {
 		return nil
 	})
 
+	specConfigFlag := flag.String("spec-config", "", "JSON file mapping spec paths to {\"baseUrl\", \"bearerTokenEnv\"} overrides")
 	baseURLFlag := flag.String("base-url", "", "Upstream API base URL (defaults to env or http://127.0.0.1:8000)")
 	timeoutFlag := flag.Duration("timeout", 15*time.Second, "HTTP client timeout")
 	serverName := flag.String("server-name", "openapi-mcp", "MCP server name")
 	serverVersion := flag.String("server-version", "0.1.0", "MCP server version")
 	logOutput := flag.String("log-output", "", "Write logs to this destination (stdout, stderr, or file path)")
 	teeConsole := flag.Bool("log-tee-console", false, "If true and log-output is a file, also write logs to stderr")
+	watch := flag.Bool("watch", false, "Reload a spec file's tools and resources when it changes on disk")
 	flag.Parse()
 
 	cleanup, err := configureLogging(*logOutput, *teeConsole)
@@ -46,6 +51,17 @@ func main() {
 	}
 	defer cleanup()
 
+	specConfig, err := loadSpecConfig(*specConfigFlag)
+	if err != nil {
+		log.Fatalf("failed to load spec config: %v", err)
+	}
+	if len(specConfig) > 0 {
+		if !specFlagSet {
+			specPaths = specPaths[:0]
+		}
+		specPaths = appendConfiguredSpecs(specPaths, specConfig)
+	}
+
 	if len(specPaths) == 0 {
 		log.Fatalf("no OpenAPI specifications provided")
 	}
@@ -60,9 +76,12 @@ func main() {
 		if err != nil {
 			absPath = path
 		}
+		entry := specConfig[filepath.ToSlash(absPath)]
 		specs = append(specs, server.Spec{
-			Path: filepath.ToSlash(absPath),
-			Data: data,
+			Path:           filepath.ToSlash(absPath),
+			Data:           data,
+			BaseURL:        entry.BaseURL,
+			BearerTokenEnv: entry.BearerTokenEnv,
 		})
 	}
 
@@ -82,14 +101,118 @@ func main() {
 		log.Fatalf("failed to construct OpenAPI MCP server: %v", err)
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if *watch {
+		for _, spec := range specs {
+			go watchSpec(ctx, srv, spec.Path, specWatchInterval)
+		}
+	}
+
 	stdio := mcpsrv.NewStdioServer(srv.MCPServer())
 	log.Printf("OpenAPI MCP server ready. Target base URL: %s", baseURL)
 
-	if err := stdio.Listen(context.Background(), os.Stdin, os.Stdout); err != nil && !errors.Is(err, io.EOF) {
+	if err := stdio.Listen(ctx, os.Stdin, os.Stdout); err != nil && !errors.Is(err, io.EOF) {
 		log.Fatalf("stdio server stopped: %v", err)
 	}
 }
 
+// specWatchInterval is how often -watch checks spec files for changes.
+const specWatchInterval = time.Second
+
+// watchSpec polls path until ctx is done and reloads the spec into srv
+// whenever its size or modification time changes. A spec that fails to read
+// or convert is logged and the previous components stay registered.
+func watchSpec(ctx context.Context, srv *server.Server, path string, interval time.Duration) {
+	last, err := os.Stat(path)
+	if err != nil {
+		log.Printf("watch %s: %v", path, err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if last != nil && info.Size() == last.Size() && info.ModTime().Equal(last.ModTime()) {
+			continue
+		}
+		last = info
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("watch %s: %v", path, err)
+			continue
+		}
+		if err := srv.ReloadSpec(path, data); err != nil {
+			log.Printf("failed to reload spec %s: %v", path, err)
+			continue
+		}
+		log.Printf("reloaded spec %s", path)
+	}
+}
+
+// specConfigEntry holds per-spec upstream overrides read from -spec-config.
+type specConfigEntry struct {
+	BaseURL        string `json:"baseUrl"`
+	BearerTokenEnv string `json:"bearerTokenEnv"`
+}
+
+// loadSpecConfig reads a JSON object keyed by spec path, e.g.
+// {"specs/users.yaml": {"baseUrl": "https://users.internal", "bearerTokenEnv": "USERS_TOKEN"}}.
+// Keys are normalised to absolute slash paths.
+func loadSpecConfig(path string) (map[string]specConfigEntry, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]specConfigEntry
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid spec config %s: %w", path, err)
+	}
+
+	entries := make(map[string]specConfigEntry, len(raw))
+	for specPath, entry := range raw {
+		absPath, err := filepath.Abs(specPath)
+		if err != nil {
+			absPath = specPath
+		}
+		entries[filepath.ToSlash(absPath)] = entry
+	}
+	return entries, nil
+}
+
+// appendConfiguredSpecs adds configured specs that were not passed via -spec, in path order.
+func appendConfiguredSpecs(specPaths []string, config map[string]specConfigEntry) []string {
+	seen := make(map[string]bool, len(specPaths))
+	for _, path := range specPaths {
+		if absPath, err := filepath.Abs(path); err == nil {
+			path = absPath
+		}
+		seen[filepath.ToSlash(path)] = true
+	}
+
+	var extra []string
+	for path := range config {
+		if !seen[path] {
+			extra = append(extra, path)
+		}
+	}
+	sort.Strings(extra)
+	return append(specPaths, extra...)
+}
+
 func firstNonEmpty(values ...string) string {
 	for _, v := range values {
 		if strings.TrimSpace(v) != "" {
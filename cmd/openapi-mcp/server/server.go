@@ -3,12 +3,16 @@ package server
 import (
 	"fmt"
 	"net/http"
+	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	mcpsrv "github.com/mark3labs/mcp-go/server"
 	"github.com/specx2/mcp-forgebird/core"
 	"github.com/specx2/mcp-forgebird/core/interfaces"
+	openapimcp "github.com/specx2/openapi-mcp/core"
 	executorpkg "github.com/specx2/openapi-mcp/core/executor"
 	openapiparser "github.com/specx2/openapi-mcp/core/parser"
 	forgebird "github.com/specx2/openapi-mcp/forgebird"
@@ -22,6 +26,11 @@ type Spec struct {
 	Data []byte
 	// Version optionally overrides the detected OpenAPI version.
 	Version string
+	// BaseURL optionally overrides Options.BaseURL for this spec's operations.
+	BaseURL string
+	// BearerTokenEnv optionally names an environment variable holding a bearer
+	// token sent as the Authorization header to this spec's upstream.
+	BearerTokenEnv string
 }
 
 // Options controls server construction.
@@ -41,6 +50,18 @@ type Server struct {
 	options    Options
 	mcpServer  *mcpsrv.MCPServer
 	httpClient *executorpkg.DefaultHTTPClient
+	forgebird  *core.DefaultForgebird
+
+	mu         sync.Mutex
+	registered []registeredSpec
+}
+
+// registeredSpec remembers what a spec put on the MCP server so a reload can
+// replace it.
+type registeredSpec struct {
+	spec       Spec
+	client     *executorpkg.DefaultHTTPClient
+	components []interfaces.MCPComponent
 }
 
 // New constructs a new Server instance using the supplied options.
@@ -80,13 +101,24 @@ func New(opts Options) (*Server, error) {
 		options:    opts,
 		mcpServer:  mcpServer,
 		httpClient: httpClient,
+		forgebird:  fb,
 	}
 
-	// Convert and register each spec
+	// Convert and register each spec; each gets its own upstream client
 	for _, spec := range opts.Specs {
-		if err := server.registerSpec(fb, spec); err != nil {
+		client, err := server.clientFor(spec)
+		if err != nil {
+			return nil, err
+		}
+		components, err := server.convertSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		entry := registeredSpec{spec: spec, client: client, components: components}
+		if err := server.registerSpec(entry); err != nil {
 			return nil, err
 		}
+		server.registered = append(server.registered, entry)
 	}
 
 	return server, nil
@@ -97,10 +129,147 @@ func (s *Server) MCPServer() *mcpsrv.MCPServer {
 	return s.mcpServer
 }
 
-func (s *Server) registerSpec(fb *core.DefaultForgebird, spec Spec) error {
+// clientFor returns the shared client, or a dedicated one when the spec carries its own credentials.
+func (s *Server) clientFor(spec Spec) (*executorpkg.DefaultHTTPClient, error) {
+	if spec.BearerTokenEnv == "" {
+		return s.httpClient, nil
+	}
+
+	token := os.Getenv(spec.BearerTokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("spec %s: environment variable %s is not set", spec.Path, spec.BearerTokenEnv)
+	}
+
+	headers := s.httpClient.Headers()
+	headers.Set("Authorization", "Bearer "+token)
+
+	client := executorpkg.NewDefaultHTTPClient().WithHeaders(headers)
+	if s.options.Timeout > 0 {
+		client.WithTimeout(s.options.Timeout)
+	}
+	return client, nil
+}
+
+// ReloadSpec converts data again for the spec registered under path and swaps
+// its components on the MCP server: the new ones replace the old, the ones
+// data no longer produces are removed, and clients are told the lists
+// changed. When data fails to convert the registered components stay as they
+// were.
+func (s *Server) ReloadSpec(path string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := -1
+	for i, entry := range s.registered {
+		if entry.spec.Path == path {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("spec %s is not registered", path)
+	}
+
+	previous := s.registered[idx]
+	next := previous
+	next.spec.Data = data
+	components, err := s.convertSpec(next.spec)
+	if err != nil {
+		return err
+	}
+	next.components = components
+	s.registered[idx] = next
+
+	stale := staleComponents(previous.components, next.components)
+	if hasComponentType(stale, interfaces.MCPTypeResourceTemplate) {
+		// mcp-go cannot delete a single resource template, so clear them all
+		// and register every spec again.
+		s.mcpServer.SetResourceTemplates()
+		for _, entry := range s.registered {
+			if err := s.registerSpec(entry); err != nil {
+				return err
+			}
+		}
+	} else if err := s.registerSpec(next); err != nil {
+		return err
+	}
+	forgebird.UnregisterComponents(s.mcpServer, stale)
+
+	// Tool list changes are announced by mcp-go; resource ones only when the
+	// capability was declared up front, which New does not do.
+	if hasResources(previous.components) || hasResources(next.components) {
+		s.mcpServer.SendNotificationToAllClients(mcp.MethodNotificationResourcesListChanged, nil)
+	}
+	return nil
+}
+
+// staleComponents lists the components of previous that next no longer has.
+func staleComponents(previous, next []interfaces.MCPComponent) []interfaces.MCPComponent {
+	previousKeys := componentKeys(previous)
+	stale := openapimcp.StaleComponentKeys(previousKeys, componentKeys(next))
+	var components []interfaces.MCPComponent
+	for i, component := range previous {
+		if stale[previousKeys[i]] {
+			components = append(components, component)
+		}
+	}
+	return components
+}
+
+// componentKeys identifies each component the way the MCP server indexes it.
+func componentKeys(components []interfaces.MCPComponent) []string {
+	keys := make([]string, len(components))
+	for i, component := range components {
+		switch component.GetType() {
+		case interfaces.MCPTypeTool:
+			keys[i] = openapimcp.ComponentKey(component.GetMCPTool(), nil, nil)
+		case interfaces.MCPTypeResource:
+			keys[i] = openapimcp.ComponentKey(nil, component.GetMCPResource(), nil)
+		case interfaces.MCPTypeResourceTemplate:
+			keys[i] = openapimcp.ComponentKey(nil, nil, component.GetMCPResourceTemplate())
+		}
+		if keys[i] == "" {
+			keys[i] = string(component.GetType()) + ":" + component.GetName()
+		}
+	}
+	return keys
+}
+
+func hasComponentType(components []interfaces.MCPComponent, componentType interfaces.MCPType) bool {
+	for _, component := range components {
+		if component.GetType() == componentType {
+			return true
+		}
+	}
+	return false
+}
+
+func hasResources(components []interfaces.MCPComponent) bool {
+	return hasComponentType(components, interfaces.MCPTypeResource) ||
+		hasComponentType(components, interfaces.MCPTypeResourceTemplate)
+}
+
+func (s *Server) registerSpec(entry registeredSpec) error {
+	// 使用 forgebird 的默认注册能力，零自定义 handler
+	return forgebird.RegisterComponents(
+		s.mcpServer,
+		entry.components,
+		forgebird.WithBaseURL(s.baseURLFor(entry.spec)),
+		forgebird.WithHTTPClient(entry.client),
+	)
+}
+
+func (s *Server) baseURLFor(spec Spec) string {
+	if spec.BaseURL != "" {
+		return spec.BaseURL
+	}
+	return s.options.BaseURL
+}
+
+func (s *Server) convertSpec(spec Spec) ([]interfaces.MCPComponent, error) {
 	data := spec.Data
 	if len(data) == 0 {
-		return fmt.Errorf("spec %s contains no data", spec.Path)
+		return nil, fmt.Errorf("spec %s contains no data", spec.Path)
 	}
 
 	absPath := spec.Path
@@ -121,8 +290,10 @@ func (s *Server) registerSpec(fb *core.DefaultForgebird, spec Spec) error {
 		}
 	}
 
+	baseURL := s.baseURLFor(spec)
+
 	conversionConfig := interfaces.ConversionConfig{
-		BaseURL: s.options.BaseURL,
+		BaseURL: baseURL,
 		Timeout: int(s.options.Timeout.Seconds()),
 		Spec: interfaces.SpecConfig{
 			Version: version,
@@ -138,16 +309,9 @@ func (s *Server) registerSpec(fb *core.DefaultForgebird, spec Spec) error {
 		},
 	}
 
-	components, err := fb.ConvertSpec(data, conversionConfig)
+	components, err := s.forgebird.ConvertSpec(data, conversionConfig)
 	if err != nil {
-		return fmt.Errorf("failed to convert spec %s: %w", spec.Path, err)
+		return nil, fmt.Errorf("failed to convert spec %s: %w", spec.Path, err)
 	}
-
-	// 使用 forgebird 的默认注册能力，零自定义 handler
-	return forgebird.RegisterComponents(
-		s.mcpServer,
-		components,
-		forgebird.WithBaseURL(s.options.BaseURL),
-		forgebird.WithHTTPClient(s.httpClient),
-	)
+	return components, nil
 }
@@ -0,0 +1,90 @@
+package openapimcp
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+	"github.com/specx2/openapi-mcp/core/parser"
+)
+
+// APIKeyFromEnv names an apiKey security scheme of the spec and the environment
+// variable holding its key.
+type APIKeyFromEnv struct {
+	Scheme string
+	EnvVar string
+}
+
+// apiKeyAuth injects the resolved API keys into every upstream request. The
+// interceptor is installed before the spec is parsed; the credentials are filled
+// in once the security schemes are known.
+type apiKeyAuth struct {
+	credentials []apiKeyCredential
+}
+
+type apiKeyCredential struct {
+	in    string
+	name  string
+	value string
+}
+
+func (a *apiKeyAuth) intercept(req *http.Request) error {
+	for _, cred := range a.credentials {
+		switch cred.in {
+		case "header":
+			req.Header.Set(cred.name, cred.value)
+		case "query":
+			query := req.URL.Query()
+			query.Set(cred.name, cred.value)
+			req.URL.RawQuery = query.Encode()
+		case "cookie":
+			req.AddCookie(&http.Cookie{Name: cred.name, Value: cred.value})
+		}
+	}
+	return nil
+}
+
+// resolve looks up every configured scheme in the parsed spec and reads its key
+// from the environment.
+func (a *apiKeyAuth) resolve(p parser.OpenAPIParser, keys []APIKeyFromEnv) error {
+	var schemes map[string]ir.SecurityScheme
+	if sp, ok := p.(parser.SecuritySchemeParser); ok {
+		schemes = sp.SecuritySchemes()
+	}
+
+	for _, key := range keys {
+		scheme, ok := schemes[key.Scheme]
+		if !ok {
+			return fmt.Errorf("security scheme %q is not defined in the spec (available: %s)", key.Scheme, availableSchemes(schemes))
+		}
+		if scheme.Type != "apiKey" {
+			return fmt.Errorf("security scheme %q has type %q, expected apiKey", key.Scheme, scheme.Type)
+		}
+		switch scheme.In {
+		case "header", "query", "cookie":
+		default:
+			return fmt.Errorf("security scheme %q has unsupported location %q", key.Scheme, scheme.In)
+		}
+		value := os.Getenv(key.EnvVar)
+		if value == "" {
+			return fmt.Errorf("environment variable %s for security scheme %q is not set", key.EnvVar, key.Scheme)
+		}
+		a.credentials = append(a.credentials, apiKeyCredential{in: scheme.In, name: scheme.ParamName, value: value})
+	}
+	return nil
+}
+
+func availableSchemes(schemes map[string]ir.SecurityScheme) string {
+	if len(schemes) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,132 @@
+package openapimcp
+
+import (
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/specx2/openapi-mcp/core/executor"
+	"github.com/specx2/openapi-mcp/core/ir"
+	"github.com/specx2/openapi-mcp/core/mapper"
+)
+
+// ComponentInfo summarises a registered MCP component and the operation behind it.
+type ComponentInfo struct {
+	Name        string
+	Type        mapper.MCPType
+	OperationID string
+	Method      string
+	Path        string
+	Tags        []string
+}
+
+// Component is an MCP tool, resource or resource template generated from a
+// spec, together with the handler that serves it. Exactly one of Tool, Resource
+// and ResourceTemplate is set.
+type Component struct {
+	ComponentInfo
+	Tool             *server.ServerTool
+	Resource         *server.ServerResource
+	ResourceTemplate *server.ServerResourceTemplate
+}
+
+// AddTo registers the component on mcpServer.
+func (c Component) AddTo(mcpServer *server.MCPServer) {
+	switch {
+	case c.Tool != nil:
+		mcpServer.AddTools(*c.Tool)
+	case c.Resource != nil:
+		mcpServer.AddResources(*c.Resource)
+	case c.ResourceTemplate != nil:
+		mcpServer.AddResourceTemplates(*c.ResourceTemplate)
+	}
+}
+
+// BuildComponents generates the MCP components of spec without handing out a
+// server, for callers that own their MCP server and register other tools on it
+// too. It accepts the same options as NewServer; the name, version and
+// transport-related options have no effect.
+func BuildComponents(spec []byte, opts ...ServerOption) ([]Component, error) {
+	s, err := NewServer(spec, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return s.RegisteredComponents(), nil
+}
+
+// RegisterInto adds every component of the server, webhook resources included,
+// to an externally owned MCP server. Components keep being served by s's
+// options, e.g. dry-run mode, and mcpServer follows later RegisterSpec and
+// ReloadSpec calls.
+func (s *Server) RegisterInto(mcpServer *server.MCPServer) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	for _, component := range s.registered {
+		component.AddTo(mcpServer)
+	}
+	s.targets = append(s.targets, mcpServer)
+}
+
+// RegisteredComponents returns the components RegisterInto would add, in
+// registration order.
+func (s *Server) RegisteredComponents() []Component {
+	s.reloadMu.RLock()
+	defer s.reloadMu.RUnlock()
+	return append([]Component(nil), s.registered...)
+}
+
+func (s *Server) addComponent(component Component) {
+	s.registered = append(s.registered, component)
+	component.AddTo(s.mcpServer)
+	for _, target := range s.targets {
+		component.AddTo(target)
+	}
+}
+
+// Components lists every registered tool, resource and resource template in
+// registration order, with custom names and route-map decisions applied.
+func (s *Server) Components() []ComponentInfo {
+	s.reloadMu.RLock()
+	defer s.reloadMu.RUnlock()
+	result := make([]ComponentInfo, len(s.components))
+	for i, info := range s.components {
+		info.Tags = append([]string(nil), info.Tags...)
+		result[i] = info
+	}
+	return result
+}
+
+// ExplainRouteMappings reports, for every operation that reached the route
+// mapper, which route map matched, whether the map func overrode it and the
+// resulting MCP type. Operations dropped by the allow/deny lists or as deprecated
+// are not listed; routes excluded by the mapper are, with type "exclude".
+func (s *Server) ExplainRouteMappings() []mapper.MappingExplanation {
+	s.reloadMu.RLock()
+	defer s.reloadMu.RUnlock()
+	return s.mapper.Explain(s.routes)
+}
+
+func describeComponent(component interface{}) (ComponentInfo, bool) {
+	var (
+		info  ComponentInfo
+		route ir.HTTPRoute
+	)
+	switch c := component.(type) {
+	case *executor.OpenAPITool:
+		info = ComponentInfo{Name: c.Tool().Name, Type: mapper.MCPTypeTool, Tags: c.Tags()}
+		route = c.GetRoute()
+	case *executor.OpenAPIResource:
+		info = ComponentInfo{Name: c.Resource().Name, Type: mapper.MCPTypeResource}
+		route = c.GetRoute()
+	case *executor.OpenAPIResourceTemplate:
+		info = ComponentInfo{Name: c.Template().Name, Type: mapper.MCPTypeResourceTemplate}
+		route = c.GetRoute()
+	default:
+		return ComponentInfo{}, false
+	}
+
+	info.OperationID = route.OperationID
+	info.Method = route.Method
+	info.Path = route.Path
+	if info.Tags == nil {
+		info.Tags = append([]string(nil), route.Tags...)
+	}
+	return info, true
+}
@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// AcceptArgument overrides the Accept header of a single call.
+const AcceptArgument = "_accept"
+
+// acceptHeader picks the Accept header value: the `_accept` argument, then an
+// Accept header parameter, then the configured default when the operation can
+// serve it, then the operation's preferred response media type.
+func (rb *RequestBuilder) acceptHeader(requested string, req *http.Request) (string, error) {
+	declared := declaredResponseContentTypes(rb.route.Responses)
+	if requested != "" {
+		if len(declared) > 0 && !acceptMatchesAny(requested, declared) {
+			return "", fmt.Errorf("accept type %q is not declared by the operation; declared: %s", requested, strings.Join(declared, ", "))
+		}
+		return requested, nil
+	}
+	if accept := req.Header.Get("Accept"); accept != "" {
+		return accept, nil
+	}
+	if rb.defaultAccept != "" && (len(declared) == 0 || acceptMatchesAny(rb.defaultAccept, declared)) {
+		return rb.defaultAccept, nil
+	}
+	return preferredResponseContentType(rb.route), nil
+}
+
+// declaredResponseContentTypes lists the media types of the 2XX and default responses.
+func declaredResponseContentTypes(responses map[string]ir.ResponseInfo) []string {
+	seen := make(map[string]bool)
+	var types []string
+	for status, response := range responses {
+		if status != "default" && !strings.HasPrefix(status, "2") {
+			continue
+		}
+		for contentType := range response.ContentSchemas {
+			if !seen[contentType] {
+				seen[contentType] = true
+				types = append(types, contentType)
+			}
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// acceptMatchesAny reports whether any media range of an Accept value (e.g.
+// "text/csv, application/*;q=0.5") matches one of the declared media types.
+func acceptMatchesAny(accept string, declared []string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		accepted := baseMediaType(part)
+		if accepted == "" {
+			continue
+		}
+		for _, contentType := range declared {
+			if mediaRangeMatches(accepted, baseMediaType(contentType)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func mediaRangeMatches(accepted, declared string) bool {
+	if accepted == "*/*" || declared == "*/*" || accepted == declared {
+		return true
+	}
+	acceptedType, acceptedSub, _ := strings.Cut(accepted, "/")
+	declaredType, declaredSub, _ := strings.Cut(declared, "/")
+	if acceptedType != declaredType {
+		return false
+	}
+	return acceptedSub == "*" || declaredSub == "*"
+}
+
+func baseMediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, _, _ = strings.Cut(contentType, ";")
+	}
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// isRawTextContentType reports text responses such as plain text or HTML that are
+// returned verbatim instead of being parsed as JSON.
+func isRawTextContentType(contentType string) bool {
+	mediaType := baseMediaType(contentType)
+	if mediaType == "" || strings.Contains(mediaType, "json") {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/")
+}
+
+// processRawText wraps a non-JSON text body as {"result": text}; the output
+// schema describes the JSON representation, so it is not validated.
+func (rp *ResponseProcessor) processRawText(body []byte, headers map[string]interface{}, meta *mcp.Meta) *mcp.CallToolResult {
+	structured := withDeclaredHeaders(rp.prepareStructuredResult(string(body)), headers)
+	return &mcp.CallToolResult{
+		StructuredContent: structured,
+		Content:           []mcp.Content{mcp.NewTextContent(string(body))},
+		Result:            mcp.Result{Meta: cloneMeta(meta)},
+	}
+}
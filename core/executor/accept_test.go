@@ -0,0 +1,34 @@
+package executor
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResponseProcessorReturnsRawTextForNonJSONTypes(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}},
+		Body:       io.NopCloser(strings.NewReader("42\n")),
+	}
+	result, err := NewResponseProcessor(nil, false, nil).Process(resp)
+	if err != nil {
+		t.Fatalf("Process returned error: %v", err)
+	}
+	structured, ok := result.StructuredContent.(map[string]interface{})
+	if !ok || structured["result"] != "42" {
+		t.Fatalf("expected the plain text in a result wrapper, got %#v", result.StructuredContent)
+	}
+
+	resp = jsonResponse(`42`)
+	result, err = NewResponseProcessor(nil, false, nil).Process(resp)
+	if err != nil {
+		t.Fatalf("Process returned error: %v", err)
+	}
+	if structured := result.StructuredContent.(map[string]interface{}); structured["result"] != float64(42) {
+		t.Fatalf("expected JSON bodies to be parsed, got %#v", structured)
+	}
+}
@@ -0,0 +1,223 @@
+package executor
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// AdditionalPropertiesMode controls how request body properties that the schema
+// does not declare are treated during input validation.
+type AdditionalPropertiesMode int
+
+const (
+	// AdditionalPropertiesStrict honours the spec: `additionalProperties: false` rejects extras.
+	AdditionalPropertiesStrict AdditionalPropertiesMode = iota
+	// AdditionalPropertiesAllow drops `additionalProperties: false` so extras pass through.
+	AdditionalPropertiesAllow
+	// AdditionalPropertiesStrip removes extras the spec forbids before validation.
+	AdditionalPropertiesStrip
+	// AdditionalPropertiesForbid rejects extras on every body object that declares properties.
+	AdditionalPropertiesForbid
+)
+
+// rewriteAdditionalProperties applies Allow/Forbid to the body arguments and
+// $defs (or draft-07 definitions) of a JSON input schema, returning the schema unchanged for other modes.
+func rewriteAdditionalProperties(raw json.RawMessage, paramMap map[string]ir.ParamMapping, mode AdditionalPropertiesMode) json.RawMessage {
+	if mode != AdditionalPropertiesAllow && mode != AdditionalPropertiesForbid {
+		return raw
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return raw
+	}
+
+	rewrite := func(node interface{}) {
+		if mode == AdditionalPropertiesAllow {
+			relaxAdditionalProperties(node)
+		} else {
+			forbidAdditionalProperties(node)
+		}
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, prop := range properties {
+			if mapping, ok := paramMap[name]; ok && mapping.Location == "body" {
+				rewrite(prop)
+			}
+		}
+	}
+	for _, key := range []string{"$defs", "definitions"} {
+		if defs, ok := schema[key].(map[string]interface{}); ok {
+			for _, def := range defs {
+				rewrite(def)
+			}
+		}
+	}
+
+	rewritten, err := json.Marshal(schema)
+	if err != nil {
+		return raw
+	}
+	return rewritten
+}
+
+func relaxAdditionalProperties(node interface{}) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if allowed, ok := v["additionalProperties"].(bool); ok && !allowed {
+			delete(v, "additionalProperties")
+		}
+		for key, child := range v {
+			if key == "const" || key == "enum" || key == "default" || key == "example" || key == "examples" {
+				continue
+			}
+			relaxAdditionalProperties(child)
+		}
+	case []interface{}:
+		for _, item := range v {
+			relaxAdditionalProperties(item)
+		}
+	}
+}
+
+// forbidAdditionalProperties closes object schemas that declare properties. It
+// does not descend into allOf/anyOf/oneOf, where closing a branch would reject
+// the properties contributed by its siblings.
+func forbidAdditionalProperties(node interface{}) {
+	schema, ok := node.(map[string]interface{})
+	if !ok {
+		return
+	}
+	properties, hasProperties := schema["properties"].(map[string]interface{})
+	_, composed := schema["allOf"]
+	if hasProperties && !composed {
+		if _, declared := schema["additionalProperties"]; !declared {
+			schema["additionalProperties"] = false
+		}
+	}
+	for _, prop := range properties {
+		forbidAdditionalProperties(prop)
+	}
+	patterns, _ := schema["patternProperties"].(map[string]interface{})
+	for _, prop := range patterns {
+		forbidAdditionalProperties(prop)
+	}
+	forbidAdditionalProperties(schema["items"])
+	forbidAdditionalProperties(schema["additionalProperties"])
+}
+
+// stripAdditionalProperties drops body properties the spec forbids via
+// `additionalProperties: false`, including unmapped top-level arguments that
+// would otherwise be merged into a flattened body.
+func (t *OpenAPITool) stripAdditionalProperties(args map[string]interface{}) {
+	if t.route.RequestBody == nil {
+		return
+	}
+	contentType := t.requestContentType()
+	if contentType == "" {
+		return
+	}
+
+	resolver := schemaResolver{defs: t.route.SchemaDefs}
+	body, _ := resolver.resolve(t.route.RequestBody.ContentSchemas[contentType])
+	if body == nil {
+		return
+	}
+
+	properties, _ := variantShape(body, resolver, 0)
+	if len(properties) > 0 && t.paramMap != nil && forbidsAdditionalProperties(body) {
+		for name := range args {
+			if _, ok := t.paramMap[name]; !ok && !isReservedArgument(name) {
+				delete(args, name)
+			}
+		}
+	}
+
+	for name, mapping := range t.paramMap {
+		if mapping.Location != "body" {
+			continue
+		}
+		value, ok := args[name]
+		if !ok {
+			continue
+		}
+		if schema, ok := properties[name]; ok {
+			stripUnknownProperties(value, schema, resolver, 0)
+		} else if len(properties) == 0 {
+			stripUnknownProperties(value, body, resolver, 0)
+		}
+	}
+}
+
+func stripUnknownProperties(value interface{}, schema ir.Schema, resolver schemaResolver, depth int) {
+	schema, _ = resolver.resolve(schema)
+	if schema == nil || depth > 16 {
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if _, upload := v["_file"]; upload {
+			return
+		}
+		properties, _ := variantShape(schema, resolver, 0)
+		for key, item := range v {
+			prop, known := properties[key]
+			if !known {
+				prop, known = patternPropertySchema(schema, key)
+			}
+			if !known {
+				if forbidsAdditionalProperties(schema) {
+					delete(v, key)
+				}
+				continue
+			}
+			stripUnknownProperties(item, prop, resolver, depth+1)
+		}
+	case []interface{}:
+		items := schemaFromValue(schema["items"])
+		if items == nil {
+			return
+		}
+		for _, item := range v {
+			stripUnknownProperties(item, items, resolver, depth+1)
+		}
+	}
+}
+
+func forbidsAdditionalProperties(schema ir.Schema) bool {
+	allowed, ok := schema["additionalProperties"].(bool)
+	return ok && !allowed
+}
+
+func schemaFromValue(value interface{}) ir.Schema {
+	switch v := value.(type) {
+	case ir.Schema:
+		return v
+	case map[string]interface{}:
+		return v
+	}
+	return nil
+}
+
+// patternPropertySchema returns the schema of the first patternProperties
+// entry whose pattern matches key. Invalid patterns match nothing.
+func patternPropertySchema(schema ir.Schema, key string) (ir.Schema, bool) {
+	patterns, _ := schema["patternProperties"].(map[string]interface{})
+	for _, pattern := range sortedKeys(patterns) {
+		if matched, err := regexp.MatchString(pattern, key); err == nil && matched {
+			return schemaFromValue(patterns[pattern]), true
+		}
+	}
+	return nil, false
+}
+
+// undeclaredPropertySchema is the schema a key missing from properties must
+// match: a matching patternProperties entry, else additionalProperties.
+func undeclaredPropertySchema(schema ir.Schema, key string) ir.Schema {
+	if prop, ok := patternPropertySchema(schema, key); ok {
+		return prop
+	}
+	return schemaFromValue(schema["additionalProperties"])
+}
@@ -0,0 +1,145 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func additionalPropertiesTool(closed bool) *OpenAPITool {
+	meta := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"color": map[string]interface{}{"type": "string"}},
+	}
+	body := ir.Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string"},
+			"meta": meta,
+		},
+	}
+	if closed {
+		meta["additionalProperties"] = false
+		body["additionalProperties"] = false
+	}
+
+	route := ir.HTTPRoute{
+		Path:   "/items",
+		Method: "POST",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{"application/json": body},
+		},
+	}
+	inputSchema := ir.Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string"},
+			"meta": meta,
+		},
+	}
+	paramMap := map[string]ir.ParamMapping{
+		"name": {OpenAPIName: "name", Location: "body", OriginalName: "name"},
+		"meta": {OpenAPIName: "meta", Location: "body", OriginalName: "meta"},
+	}
+	return NewOpenAPITool("create", "", inputSchema, nil, false, route, nil, "https://api.example.com", paramMap, nil, nil)
+}
+
+func extraArgs() map[string]interface{} {
+	return map[string]interface{}{
+		"name":    "widget",
+		"meta":    map[string]interface{}{"color": "red", "reason": "llm noise"},
+		"bogus":   true,
+		"_dryRun": true,
+	}
+}
+
+func TestAdditionalPropertiesModes(t *testing.T) {
+	if err := additionalPropertiesTool(true).validateArgs(extraArgs()); err == nil {
+		t.Fatalf("expected strict mode to reject undeclared meta.reason")
+	}
+
+	allow := additionalPropertiesTool(true).WithAdditionalPropertiesMode(AdditionalPropertiesAllow)
+	if strings.Contains(string(allow.Tool().RawInputSchema), "additionalProperties") {
+		t.Fatalf("expected allow mode to drop additionalProperties, got %s", allow.Tool().RawInputSchema)
+	}
+	if err := allow.validateArgs(extraArgs()); err != nil {
+		t.Fatalf("expected allow mode to accept extras, got %v", err)
+	}
+
+	strip := additionalPropertiesTool(true).WithAdditionalPropertiesMode(AdditionalPropertiesStrip)
+	args := extraArgs()
+	args["_rawBody"] = `{"name":"raw"}`
+	args["_contentType"] = "application/json"
+	args[AcceptArgument] = "application/json"
+	strip.normalizeArguments(args)
+	if _, ok := args["bogus"]; ok {
+		t.Fatalf("expected unmapped top-level argument to be stripped, got %v", args)
+	}
+	for _, reserved := range []string{"_dryRun", "_rawBody", "_contentType", AcceptArgument} {
+		if _, ok := args[reserved]; !ok {
+			t.Fatalf("expected %s to survive stripping, got %v", reserved, args)
+		}
+	}
+	meta := args["meta"].(map[string]interface{})
+	if _, ok := meta["reason"]; ok || meta["color"] != "red" {
+		t.Fatalf("expected only declared meta properties to remain, got %v", meta)
+	}
+	if err := strip.validateArgs(args); err != nil {
+		t.Fatalf("expected stripped arguments to validate, got %v", err)
+	}
+
+	openArgs := extraArgs()
+	additionalPropertiesTool(false).WithAdditionalPropertiesMode(AdditionalPropertiesStrip).normalizeArguments(openArgs)
+	if _, ok := openArgs["meta"].(map[string]interface{})["reason"]; !ok {
+		t.Fatalf("expected strip mode to keep extras the spec allows")
+	}
+
+	forbid := additionalPropertiesTool(false).WithAdditionalPropertiesMode(AdditionalPropertiesForbid)
+	if err := forbid.validateArgs(extraArgs()); err == nil {
+		t.Fatalf("expected forbid mode to reject undeclared meta.reason")
+	}
+	if err := forbid.validateArgs(map[string]interface{}{"name": "widget", "meta": map[string]interface{}{"color": "red"}}); err != nil {
+		t.Fatalf("expected declared properties to pass in forbid mode, got %v", err)
+	}
+}
+
+func TestStripModeKeepsPatternProperties(t *testing.T) {
+	labels := map[string]interface{}{
+		"type":                 "object",
+		"patternProperties":    map[string]interface{}{"^x-": map[string]interface{}{"type": "integer"}},
+		"additionalProperties": false,
+	}
+	route := ir.HTTPRoute{
+		Path:   "/items",
+		Method: "POST",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{"application/json": {
+				"type":       "object",
+				"properties": map[string]interface{}{"labels": labels},
+			}},
+		},
+	}
+	inputSchema := ir.Schema{
+		"type":       "object",
+		"properties": map[string]interface{}{"labels": labels},
+	}
+	paramMap := map[string]ir.ParamMapping{
+		"labels": {OpenAPIName: "labels", Location: "body", OriginalName: "labels"},
+	}
+	tool := NewOpenAPITool("create", "", inputSchema, nil, false, route, nil, "https://api.example.com", paramMap, nil, nil).
+		WithAdditionalPropertiesMode(AdditionalPropertiesStrip)
+
+	args := map[string]interface{}{"labels": map[string]interface{}{"x-count": "3", "other": "dropped"}}
+	tool.normalizeArguments(args)
+	got := args["labels"].(map[string]interface{})
+	if _, ok := got["other"]; ok {
+		t.Fatalf("expected the undeclared key to be stripped, got %v", got)
+	}
+	if got["x-count"] != float64(3) {
+		t.Fatalf("expected the pattern-matched value to be kept and coerced, got %#v", got["x-count"])
+	}
+	if err := tool.validateArgs(args); err != nil {
+		t.Fatalf("expected stripped arguments to validate, got %v", err)
+	}
+}
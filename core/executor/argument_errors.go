@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// ArgumentViolation is one problem with a tool call's arguments. Pointer is the
+// JSON pointer of the offending value, Field the same as a dotted path such as
+// "user.email"; both are empty for problems with the arguments as a whole.
+type ArgumentViolation struct {
+	Field   string `json:"field"`
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+// ArgumentValidationError lists why arguments do not match the input schema.
+type ArgumentValidationError struct {
+	Violations []ArgumentViolation
+}
+
+func (e *ArgumentValidationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		if violation.Field == "" {
+			parts = append(parts, violation.Message)
+		} else {
+			parts = append(parts, fmt.Sprintf("argument %q %s", violation.Field, violation.Message))
+		}
+	}
+	return "argument validation failed: " + strings.Join(parts, "; ")
+}
+
+var (
+	quotedNamePattern   = regexp.MustCompile(`'((?:[^'\\]|\\.)*)'`)
+	invalidFormatSuffix = regexp.MustCompile(`is not valid '([^']*)'$`)
+)
+
+// argumentViolations flattens a schema validation error into one entry per
+// failing value. Missing and disallowed properties are reported on the property
+// itself. Failed anyOf/oneOf alternatives are summed up by the composition, as
+// the reasons each alternative failed rarely help.
+func argumentViolations(err error) []ArgumentViolation {
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return []ArgumentViolation{{Message: err.Error()}}
+	}
+
+	var violations []ArgumentViolation
+	seen := make(map[ArgumentViolation]bool)
+	add := func(pointer, message string) {
+		violation := ArgumentViolation{
+			Field:   strings.ReplaceAll(strings.TrimPrefix(pointer, "/"), "/", "."),
+			Pointer: pointer,
+			Message: message,
+		}
+		if !seen[violation] {
+			seen[violation] = true
+			violations = append(violations, violation)
+		}
+	}
+
+	var walk func(current *jsonschema.ValidationError)
+	walk = func(current *jsonschema.ValidationError) {
+		keyword := current.KeywordLocation[strings.LastIndex(current.KeywordLocation, "/")+1:]
+		switch {
+		case keyword == "anyOf":
+			add(current.InstanceLocation, "must match at least one of the allowed shapes")
+		case keyword == "oneOf":
+			add(current.InstanceLocation, "must match exactly one of the allowed shapes")
+		case len(current.Causes) > 0:
+			for _, cause := range current.Causes {
+				walk(cause)
+			}
+		case keyword == "required":
+			for _, name := range quotedNames(current.Message) {
+				add(current.InstanceLocation+"/"+escapePointerToken(name), "is required")
+			}
+		case keyword == "additionalProperties":
+			for _, name := range quotedNames(current.Message) {
+				add(current.InstanceLocation+"/"+escapePointerToken(name), "is not allowed")
+			}
+		case keyword == "format":
+			if match := invalidFormatSuffix.FindStringSubmatch(current.Message); match != nil {
+				add(current.InstanceLocation, "must match format "+match[1])
+			} else {
+				add(current.InstanceLocation, current.Message)
+			}
+		default:
+			add(current.InstanceLocation, current.Message)
+		}
+	}
+	walk(validationErr)
+	return violations
+}
+
+func quotedNames(message string) []string {
+	var names []string
+	for _, match := range quotedNamePattern.FindAllStringSubmatch(message, -1) {
+		names = append(names, strings.ReplaceAll(match[1], `\'`, "'"))
+	}
+	return names
+}
+
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// HandleArgumentError reports invalid arguments one line per violation, with
+// the violations also listed in structured content, so the caller can fix each
+// named field.
+func (eh *ErrorHandler) HandleArgumentError(err *ArgumentValidationError) *mcp.CallToolResult {
+	lines := make([]string, 0, len(err.Violations)+1)
+	lines = append(lines, "Invalid arguments:")
+	violations := make([]interface{}, 0, len(err.Violations))
+	for _, violation := range err.Violations {
+		if violation.Field == "" {
+			lines = append(lines, "- "+violation.Message)
+		} else {
+			lines = append(lines, fmt.Sprintf("- field `%s` %s", violation.Field, violation.Message))
+		}
+		violations = append(violations, map[string]interface{}{
+			"field":   violation.Field,
+			"pointer": violation.Pointer,
+			"message": violation.Message,
+		})
+	}
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{mcp.NewTextContent(strings.Join(lines, "\n"))},
+		StructuredContent: map[string]interface{}{
+			"error":      "invalid arguments",
+			"violations": violations,
+			"retryable":  false,
+		},
+	}
+}
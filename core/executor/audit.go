@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// AuditKey holds the audit record of the upstream call in structured content.
+const AuditKey = "_audit"
+
+// requestIDHeaders are checked on the response, then on the request, for the
+// ID reported as requestId.
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "Request-Id"}
+
+// WithAudit adds an AuditKey record to every result, success or error, for the
+// call that started at started.
+func (rp *ResponseProcessor) WithAudit(started time.Time) *ResponseProcessor {
+	rp.audit = true
+	rp.auditStarted = started
+	return rp
+}
+
+func (rp *ResponseProcessor) attachAudit(result *mcp.CallToolResult, resp *http.Response) {
+	attachAuditRecord(result, rp.auditStarted, resp.Request, resp)
+}
+
+// attachAuditRecord records url, method, status, timestamp, durationMs and
+// requestId. Every key is always present so downstream tooling can rely on the
+// shape. resp is nil when the call failed before a response arrived; status is
+// then 0. The URL is reported without credentials, query or fragment.
+func attachAuditRecord(result *mcp.CallToolResult, started time.Time, req *http.Request, resp *http.Response) {
+	record := map[string]interface{}{
+		"url":        "",
+		"method":     "",
+		"status":     0,
+		"timestamp":  started.UTC().Format(time.RFC3339Nano),
+		"durationMs": time.Since(started).Milliseconds(),
+		"requestId":  "",
+	}
+	if req != nil {
+		record["method"] = req.Method
+		if req.URL != nil {
+			record["url"] = sanitizedURL(req.URL)
+		}
+	}
+	if resp != nil {
+		record["status"] = resp.StatusCode
+		record["requestId"] = auditRequestID(resp)
+	} else if req != nil {
+		record["requestId"] = headerRequestID(req.Header)
+	}
+
+	structured, ok := result.StructuredContent.(map[string]interface{})
+	if !ok || structured == nil {
+		structured = make(map[string]interface{})
+	}
+	structured[AuditKey] = record
+	result.StructuredContent = structured
+}
+
+func auditRequestID(resp *http.Response) string {
+	if id := headerRequestID(resp.Header); id != "" {
+		return id
+	}
+	if resp.Request != nil {
+		return headerRequestID(resp.Request.Header)
+	}
+	return ""
+}
+
+func headerRequestID(header http.Header) string {
+	for _, name := range requestIDHeaders {
+		if id := header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// AuditSchema describes the AuditKey object, for output schemas of tools that report it.
+func AuditSchema() ir.Schema {
+	return ir.Schema{
+		"type":        "object",
+		"description": "Audit record of the upstream HTTP call",
+		"properties": map[string]interface{}{
+			"url":        map[string]interface{}{"type": "string"},
+			"method":     map[string]interface{}{"type": "string"},
+			"status":     map[string]interface{}{"type": "integer"},
+			"timestamp":  map[string]interface{}{"type": "string", "format": "date-time"},
+			"durationMs": map[string]interface{}{"type": "integer"},
+			"requestId":  map[string]interface{}{"type": "string"},
+		},
+		"required": []interface{}{"url", "method", "status", "timestamp", "durationMs", "requestId"},
+	}
+}
@@ -0,0 +1,78 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func TestOpenAPIToolAddsAuditMetadata(t *testing.T) {
+	route := ir.HTTPRoute{Path: "/items", Method: "GET"}
+	call := func(resp *http.Response) map[string]interface{} {
+		t.Helper()
+		tool := NewOpenAPITool("listItems", "", ir.Schema{"type": "object"}, nil, false, route, staticClient{resp: resp}, "https://api.example.com", nil, nil, nil).
+			WithAuditMetadata(true)
+		result, err := tool.Run(context.Background(), mcp.CallToolRequest{})
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+		structured, ok := result.StructuredContent.(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected structured content, got %#v", result.StructuredContent)
+		}
+		audit, ok := structured[AuditKey].(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected %s in structured content, got %#v", AuditKey, structured)
+		}
+		return audit
+	}
+
+	success := jsonResponse(`{"items": []}`)
+	success.Header.Set("X-Request-Id", "req-42")
+	audit := call(success)
+	if audit["url"] != "https://api.example.com/items" || audit["method"] != "GET" || audit["status"] != http.StatusOK || audit["requestId"] != "req-42" {
+		t.Fatalf("unexpected audit record: %#v", audit)
+	}
+	if _, ok := audit["durationMs"].(int64); !ok || audit["timestamp"] == "" {
+		t.Fatalf("expected timing fields, got %#v", audit)
+	}
+
+	failure := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Status:     "404 Not Found",
+		Header:     http.Header{"Content-Type": []string{"text/plain"}},
+		Body:       io.NopCloser(strings.NewReader("missing")),
+	}
+	audit = call(failure)
+	if audit["status"] != http.StatusNotFound || audit["requestId"] != "" {
+		t.Fatalf("expected an audit record for the error result, got %#v", audit)
+	}
+}
+
+func TestOpenAPIToolAuditsTransportFailures(t *testing.T) {
+	route := ir.HTTPRoute{Path: "/items", Method: "GET", Parameters: []ir.ParameterInfo{
+		{Name: "token", In: ir.ParameterInQuery, Schema: ir.Schema{"type": "string"}},
+	}}
+	paramMap := map[string]ir.ParamMapping{"token": {OpenAPIName: "token", Location: ir.ParameterInQuery}}
+	tool := NewOpenAPITool("listItems", "", ir.Schema{"type": "object"}, nil, false, route, refusedClient{}, "https://api.example.com", paramMap, nil, nil).
+		WithAuditMetadata(true)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"token": "secret"}
+	result, err := tool.Run(context.Background(), request)
+	if err != nil || !result.IsError {
+		t.Fatalf("expected an error result, got %v %#v", err, result)
+	}
+	audit, ok := result.StructuredContent.(map[string]interface{})[AuditKey].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected an audit record for the transport failure, got %#v", result.StructuredContent)
+	}
+	if audit["url"] != "https://api.example.com/items" || audit["method"] != "GET" || audit["status"] != 0 {
+		t.Fatalf("unexpected audit record: %#v", audit)
+	}
+}
@@ -0,0 +1,125 @@
+package executor
+
+import (
+	"encoding/base64"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// IsBinaryContentType reports whether a media type is known to carry binary data
+// (images, audio, video, fonts, PDFs, archives, octet streams).
+func IsBinaryContentType(contentType string) bool {
+	mediaType := responseMediaType(contentType)
+	switch {
+	case mediaType == "":
+		return false
+	case strings.HasPrefix(mediaType, "image/"), strings.HasPrefix(mediaType, "audio/"),
+		strings.HasPrefix(mediaType, "video/"), strings.HasPrefix(mediaType, "font/"):
+		return true
+	}
+	switch mediaType {
+	case "application/octet-stream", "application/pdf", "application/zip", "application/gzip",
+		"application/x-gzip", "application/x-tar", "application/x-7z-compressed", "application/msword",
+		"application/vnd.ms-excel", "application/vnd.ms-powerpoint":
+		return true
+	}
+	return strings.HasPrefix(mediaType, "application/vnd.openxmlformats-officedocument.")
+}
+
+// isBinaryResponse also treats unrecognised application/* payloads that are not
+// valid UTF-8 as binary. JSON, XML, YAML and text types are always decoded as text.
+func isBinaryResponse(contentType string, body []byte) bool {
+	if IsBinaryContentType(contentType) {
+		return true
+	}
+	mediaType := responseMediaType(contentType)
+	if !strings.HasPrefix(mediaType, "application/") ||
+		strings.HasSuffix(mediaType, "json") || isXMLContentType(mediaType) || isYAMLContentType(mediaType) {
+		return false
+	}
+	return !utf8.Valid(body)
+}
+
+func responseMediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.TrimSpace(strings.Split(contentType, ";")[0])
+	}
+	return strings.ToLower(mediaType)
+}
+
+// contentDispositionFilename returns the filename advertised by Content-Disposition.
+func contentDispositionFilename(header http.Header) string {
+	disposition := header.Get("Content-Disposition")
+	if disposition == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(disposition)
+	if err != nil {
+		return ""
+	}
+	return params["filename"]
+}
+
+// processBinary returns the payload base64-encoded in structured content; images
+// are also attached as image content, anything else as an embedded blob resource.
+func (rp *ResponseProcessor) processBinary(resp *http.Response, body []byte, meta *mcp.Meta) *mcp.CallToolResult {
+	mediaType := responseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+	encoded := base64.StdEncoding.EncodeToString(body)
+
+	structured := map[string]interface{}{
+		"contentType": mediaType,
+		"data":        encoded,
+		"size":        len(body),
+	}
+	extra := make(map[string]any)
+	if filename := contentDispositionFilename(resp.Header); filename != "" {
+		structured["filename"] = filename
+		extra["filename"] = filename
+	}
+
+	var content mcp.Content
+	if strings.HasPrefix(mediaType, "image/") {
+		content = mcp.NewImageContent(encoded, mediaType)
+	} else {
+		uri := ""
+		if resp.Request != nil && resp.Request.URL != nil {
+			uri = resp.Request.URL.String()
+		}
+		content = mcp.NewEmbeddedResource(mcp.BlobResourceContents{URI: uri, MIMEType: mediaType, Blob: encoded})
+	}
+
+	resultMeta := cloneMeta(meta)
+	if len(extra) > 0 {
+		resultMeta = mergeMeta(resultMeta, mcp.NewMetaFromMap(extra))
+	}
+	return &mcp.CallToolResult{
+		StructuredContent: structured,
+		Content:           []mcp.Content{content},
+		Result:            mcp.Result{Meta: resultMeta},
+	}
+}
+
+// binaryResourceContents wraps a binary resource body as blob contents.
+func binaryResourceContents(uri string, resp *http.Response, body []byte) mcp.BlobResourceContents {
+	mediaType := responseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+	contents := mcp.BlobResourceContents{
+		URI:      uri,
+		MIMEType: mediaType,
+		Blob:     base64.StdEncoding.EncodeToString(body),
+	}
+	if filename := contentDispositionFilename(resp.Header); filename != "" {
+		contents.Meta = mcp.NewMetaFromMap(map[string]any{"filename": filename})
+	}
+	return contents
+}
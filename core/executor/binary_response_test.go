@@ -0,0 +1,126 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+var pdfBytes = []byte("%PDF-1.7\n\x00\xff\xfe binary")
+
+type staticClient struct{ resp *http.Response }
+
+func (c staticClient) Do(req *http.Request) (*http.Response, error) {
+	c.resp.Request = req
+	return c.resp, nil
+}
+
+func binaryResponse(contentType string, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header: http.Header{
+			"Content-Type":        []string{contentType},
+			"Content-Disposition": []string{`attachment; filename="report.pdf"`},
+		},
+		Body: io.NopCloser(bytes.NewReader(body)),
+	}
+}
+
+func TestResponseProcessorReturnsBinaryAsBase64(t *testing.T) {
+	resp := binaryResponse("application/pdf", pdfBytes)
+	resp.Request, _ = http.NewRequest(http.MethodGet, "https://api.example.com/reports/1", nil)
+
+	result, err := NewResponseProcessor(nil, false, nil).Process(resp)
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+
+	structured := result.StructuredContent.(map[string]interface{})
+	encoded := base64.StdEncoding.EncodeToString(pdfBytes)
+	if structured["data"] != encoded || structured["contentType"] != "application/pdf" || structured["filename"] != "report.pdf" {
+		t.Fatalf("unexpected structured content %#v", structured)
+	}
+	if result.Result.Meta == nil || result.Result.Meta.AdditionalFields["filename"] != "report.pdf" {
+		t.Fatalf("expected filename in meta, got %#v", result.Result.Meta)
+	}
+
+	embedded, ok := result.Content[0].(mcp.EmbeddedResource)
+	if !ok {
+		t.Fatalf("expected embedded resource content, got %T", result.Content[0])
+	}
+	blob, ok := embedded.Resource.(mcp.BlobResourceContents)
+	if !ok || blob.Blob != encoded || blob.URI != "https://api.example.com/reports/1" {
+		t.Fatalf("unexpected blob contents %#v", embedded.Resource)
+	}
+}
+
+func TestResponseProcessorReturnsImagesAsImageContent(t *testing.T) {
+	result, err := NewResponseProcessor(nil, false, nil).Process(binaryResponse("image/png", []byte{0x89, 'P', 'N', 'G'}))
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+	image, ok := result.Content[0].(mcp.ImageContent)
+	if !ok || image.MIMEType != "image/png" {
+		t.Fatalf("expected image content, got %#v", result.Content[0])
+	}
+}
+
+func TestOpenAPIResourceReadContentsReturnsBlob(t *testing.T) {
+	route := ir.HTTPRoute{Path: "/reports/1", Method: "GET"}
+	resource := NewOpenAPIResource("report", "", route, staticClient{binaryResponse("application/octet-stream", pdfBytes)}, "https://api.example.com")
+
+	contents, err := resource.ReadContents(context.Background(), "resource://report")
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	blob, ok := contents[0].(mcp.BlobResourceContents)
+	if !ok {
+		t.Fatalf("expected blob contents, got %T", contents[0])
+	}
+	if blob.Blob != base64.StdEncoding.EncodeToString(pdfBytes) || blob.MIMEType != "application/octet-stream" {
+		t.Fatalf("unexpected blob %#v", blob)
+	}
+	if blob.Meta == nil || blob.Meta.AdditionalFields["filename"] != "report.pdf" {
+		t.Fatalf("expected filename meta, got %#v", blob.Meta)
+	}
+}
+
+func TestOpenAPIResourceUsesDeclaredResponseMIMEType(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/reports/1",
+		Method: "GET",
+		Responses: map[string]ir.ResponseInfo{
+			"200": {ContentSchemas: map[string]ir.Schema{"text/csv": {"type": "string"}}},
+		},
+	}
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewReader([]byte("{\"a\":1}"))),
+	}
+	resource := NewOpenAPIResource("report", "", route, staticClient{resp}, "https://api.example.com")
+	if got := resource.Resource().MIMEType; got != "text/csv" {
+		t.Fatalf("expected the declared response type, got %q", got)
+	}
+
+	contents, err := resource.ReadContents(context.Background(), "resource://report")
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	text, ok := contents[0].(mcp.TextResourceContents)
+	if !ok || text.MIMEType != "text/csv" || text.Text != "{\"a\":1}" {
+		t.Fatalf("expected the raw body as text/csv, got %#v", contents[0])
+	}
+
+	template := NewOpenAPIResourceTemplate("report", "", route, nil, "")
+	if got := template.Template().MIMEType; got != "text/csv" {
+		t.Fatalf("expected the template to carry the declared type, got %q", got)
+	}
+}
@@ -0,0 +1,60 @@
+package executor
+
+import (
+	"encoding/json"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// coerceBodyArguments converts string-encoded scalars in body arguments, such as
+// "true" or "123", to the types the request body schema declares. Nested objects
+// and array items are walked; values whose schema also accepts a string are kept.
+func (t *OpenAPITool) coerceBodyArguments(args map[string]interface{}) {
+	if t.route.RequestBody == nil {
+		return
+	}
+	contentType := t.requestContentType()
+	if contentType == "" {
+		return
+	}
+
+	resolver := schemaResolver{defs: t.route.SchemaDefs}
+	body, _ := resolver.resolve(t.route.RequestBody.ContentSchemas[contentType])
+	if body == nil {
+		return
+	}
+
+	properties, _ := variantShape(body, resolver, 0)
+	for name, mapping := range t.paramMap {
+		if mapping.Location != "body" {
+			continue
+		}
+		value, ok := args[name]
+		if !ok || value == nil {
+			continue
+		}
+		schema, known := properties[mapping.OpenAPIName]
+		if !known {
+			if len(properties) > 0 {
+				continue
+			}
+			schema = body
+		}
+		if coerced, changed := resolver.walk(value, schema, coerceBodyScalar); changed {
+			args[name] = coerced
+		}
+	}
+}
+
+// coerceBodyScalar converts a string-encoded scalar to the schema's type,
+// keeping values such as "007" whose schema also accepts a string.
+func coerceBodyScalar(value interface{}, schema ir.Schema) (interface{}, bool) {
+	switch value.(type) {
+	case string, json.Number:
+		if schemaAllowsType(schema, "string") {
+			return value, false
+		}
+		return coerceValueForSchema(value, schema)
+	}
+	return value, false
+}
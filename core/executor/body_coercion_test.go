@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func TestNormalizeArgumentsCoercesBodyScalars(t *testing.T) {
+	body := ir.Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"quantity": map[string]interface{}{"type": "integer"},
+			"code":     map[string]interface{}{"type": []interface{}{"string", "integer"}},
+			"options":  map[string]interface{}{"$ref": "#/$defs/Options"},
+			"scores":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "number"}},
+			"labels":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		},
+	}
+	route := ir.HTTPRoute{
+		Path:   "/orders",
+		Method: "POST",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{"application/json": body},
+		},
+		SchemaDefs: ir.Schema{"$defs": map[string]interface{}{
+			"Options": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"gift":     map[string]interface{}{"type": "boolean"},
+					"priority": map[string]interface{}{"type": "integer"},
+				},
+			},
+		}},
+	}
+	paramMap := map[string]ir.ParamMapping{}
+	for _, name := range []string{"quantity", "code", "options", "scores", "labels"} {
+		paramMap[name] = ir.ParamMapping{OpenAPIName: name, Location: "body", OriginalName: name}
+	}
+	tool := NewOpenAPITool("createOrder", "", ir.Schema{"type": "object"}, nil, false, route, nil, "https://api.example.com", paramMap, nil, nil)
+
+	args := map[string]interface{}{
+		"quantity": "3",
+		"code":     "007",
+		"options":  map[string]interface{}{"gift": "true", "priority": " 2 "},
+		"scores":   []interface{}{"1.5", 2.0, "oops"},
+		"labels":   []interface{}{"10"},
+	}
+	tool.normalizeArguments(args)
+
+	want := map[string]interface{}{
+		"quantity": float64(3),
+		"code":     "007",
+		"options":  map[string]interface{}{"gift": true, "priority": float64(2)},
+		"scores":   []interface{}{1.5, 2.0, "oops"},
+		"labels":   []interface{}{"10"},
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("unexpected coerced arguments:\n got %#v\nwant %#v", args, want)
+	}
+}
+
+func TestNormalizeArgumentsRewritesDates(t *testing.T) {
+	body := ir.Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"due":    map[string]interface{}{"type": "string", "format": "date"},
+			"window": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string", "format": "date-time"}},
+			"note":   map[string]interface{}{"type": "string"},
+		},
+	}
+	route := ir.HTTPRoute{
+		Path:   "/tasks",
+		Method: "POST",
+		Parameters: []ir.ParameterInfo{
+			{Name: "since", In: ir.ParameterInQuery, Schema: ir.Schema{"type": "string", "format": "date-time"}},
+		},
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{"application/json": body},
+		},
+	}
+	paramMap := map[string]ir.ParamMapping{
+		"since": {OpenAPIName: "since", Location: ir.ParameterInQuery},
+	}
+	for _, name := range []string{"due", "window", "note"} {
+		paramMap[name] = ir.ParamMapping{OpenAPIName: name, Location: "body", OriginalName: name}
+	}
+	tool := NewOpenAPITool("createTask", "", ir.Schema{"type": "object"}, nil, false, route, nil, "https://api.example.com", paramMap, nil, nil)
+
+	args := map[string]interface{}{
+		"since":  "2024-03-05 10:30",
+		"due":    "March 9, 2024",
+		"window": []interface{}{"2024/03/05", "next tuesday"},
+		"note":   "Jan 2, 2006",
+	}
+	tool.normalizeArguments(args)
+	if args["due"] != "March 9, 2024" {
+		t.Fatalf("expected dates untouched unless enabled, got %v", args["due"])
+	}
+
+	tool.WithNormalizeDates(true).normalizeArguments(args)
+	want := map[string]interface{}{
+		"since":  "2024-03-05T10:30:00Z",
+		"due":    "2024-03-09",
+		"window": []interface{}{"2024-03-05T00:00:00Z", "next tuesday"},
+		"note":   "Jan 2, 2006",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("unexpected normalized arguments:\n got %#v\nwant %#v", args, want)
+	}
+}
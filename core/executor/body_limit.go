@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// BodyTooLargeError reports a request or response body above the configured limit.
+type BodyTooLargeError struct {
+	Kind  string // "request" or "response"
+	Size  int64  // bytes seen; for responses this is the limit plus one
+	Limit int64
+}
+
+func (e *BodyTooLargeError) Error() string {
+	if e.Kind == "request" {
+		return fmt.Sprintf("request body of %d bytes exceeds the %d byte limit", e.Size, e.Limit)
+	}
+	return fmt.Sprintf("response body exceeds the %d byte limit", e.Limit)
+}
+
+// limitedBody reads at most limit bytes and fails with *BodyTooLargeError on the
+// first byte past it, so a cut-off document is never mistaken for a complete one.
+type limitedBody struct {
+	reader io.Reader
+	closer io.Closer
+	limit  int64
+	read   int64
+}
+
+func limitResponseBody(body io.ReadCloser, limit int64) io.ReadCloser {
+	if body == nil || limit <= 0 {
+		return body
+	}
+	return &limitedBody{reader: io.LimitReader(body, limit+1), closer: body, limit: limit}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.reader.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		return n - int(b.read-b.limit), &BodyTooLargeError{Kind: "response", Size: b.read, Limit: b.limit}
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.closer.Close()
+}
+
+// failingReader returns err once the bytes before it are consumed; it keeps a
+// read error attached to a body that had to be buffered.
+type failingReader struct {
+	err error
+}
+
+func (r failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// checkRequestBodySize rejects in-memory request bodies above limit. Such bodies
+// are kept for redirect replays (http.Request.GetBody), so the cap bounds that copy
+// too. Streamed file uploads are not buffered and are not checked.
+func checkRequestBodySize(body io.Reader, limit int64) error {
+	if limit <= 0 || body == nil {
+		return nil
+	}
+	var size int64
+	switch b := body.(type) {
+	case *bytes.Reader:
+		size = int64(b.Len())
+	case *bytes.Buffer:
+		size = int64(b.Len())
+	case *strings.Reader:
+		size = int64(b.Len())
+	default:
+		return nil
+	}
+	if size > limit {
+		return &BodyTooLargeError{Kind: "request", Size: size, Limit: limit}
+	}
+	return nil
+}
+
+func isBodyTooLarge(err error) (*BodyTooLargeError, bool) {
+	var tooLarge *BodyTooLargeError
+	if errors.As(err, &tooLarge) {
+		return tooLarge, true
+	}
+	return nil, false
+}
+
+// processTooLarge explains that the response was cut off instead of returning partial data.
+func (rp *ResponseProcessor) processTooLarge(err *BodyTooLargeError, meta *mcp.Meta) *mcp.CallToolResult {
+	resultMeta := cloneMeta(meta)
+	if resultMeta == nil {
+		resultMeta = mcp.NewMetaFromMap(map[string]any{})
+	}
+	if resultMeta.AdditionalFields == nil {
+		resultMeta.AdditionalFields = make(map[string]any)
+	}
+	resultMeta.AdditionalFields["truncated"] = true
+	resultMeta.AdditionalFields["maxResponseBytes"] = err.Limit
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{
+			mcp.NewTextContent(fmt.Sprintf("Response body exceeded the %d byte limit and was truncated, so no partial data is returned. Narrow the request (filters, pagination) to receive a smaller response.", err.Limit)),
+		},
+		Result: mcp.Result{Meta: resultMeta},
+	}
+}
@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func TestResponseProcessorRejectsOversizedBodies(t *testing.T) {
+	body := `{"items":["` + strings.Repeat("x", 200) + `"]}`
+
+	result, err := NewResponseProcessor(nil, false, nil).WithMaxResponseBytes(64).Process(jsonResponse(body))
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+	if !result.IsError || result.StructuredContent != nil {
+		t.Fatalf("expected error result without partial data, got %#v", result)
+	}
+	text, _ := result.Content[0].(mcp.TextContent)
+	if !strings.Contains(text.Text, "64 byte limit") || !strings.Contains(text.Text, "truncated") {
+		t.Fatalf("expected truncation explanation, got %q", text.Text)
+	}
+	if result.Result.Meta.AdditionalFields["truncated"] != true {
+		t.Fatalf("expected truncated meta, got %#v", result.Result.Meta.AdditionalFields)
+	}
+
+	result, err = NewResponseProcessor(nil, false, nil).WithMaxResponseBytes(int64(len(body))).Process(jsonResponse(body))
+	if err != nil || result.IsError {
+		t.Fatalf("expected body at the limit to pass, got %v %#v", err, result)
+	}
+}
+
+func TestPaginatorKeepsOversizedPageError(t *testing.T) {
+	client := &pageClient{pages: map[string]*http.Response{
+		"https://api.example.com/items": jsonPage(`[`+strings.Repeat(`"item",`, 50)+`"last"]`, nil),
+	}}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/items", nil)
+
+	pager := newPaginator(client, PaginationConfig{NextCursorPointer: "/next", CursorParam: "cursor"})
+	pager.maxBytes = 32
+	resp, err := pager.fetch(req)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+
+	result, err := NewResponseProcessor(nil, false, nil).WithMaxResponseBytes(32).Process(resp)
+	if err != nil || !result.IsError {
+		t.Fatalf("expected oversized page to produce an error result, got %v %#v", err, result)
+	}
+}
+
+func TestRequestBuilderRejectsOversizedBodies(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/notes",
+		Method: "POST",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{
+				"application/json": {"type": "object", "properties": map[string]interface{}{
+					"text": map[string]interface{}{"type": "string"},
+					"tag":  map[string]interface{}{"type": "string"},
+				}},
+			},
+		},
+	}
+	args := map[string]interface{}{"text": strings.Repeat("a", 100), "tag": "x"}
+
+	_, err := NewRequestBuilder(route, nil, "https://api.example.com").WithMaxBodyBytes(50).Build(context.Background(), args)
+	if tooLarge, ok := isBodyTooLarge(err); !ok || tooLarge.Kind != "request" || tooLarge.Limit != 50 {
+		t.Fatalf("expected request body limit error, got %v", err)
+	}
+
+	if _, err := NewRequestBuilder(route, nil, "https://api.example.com").WithMaxBodyBytes(1024).Build(context.Background(), args); err != nil {
+		t.Fatalf("expected body under the limit to build, got %v", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/textproto"
 	"net/url"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -24,6 +25,21 @@ type RequestBuilder struct {
 	baseURL         string
 	bodyContentType string
 	bodyEncoding    map[string]ir.EncodingInfo
+
+	serverVariables        map[string]string
+	serverVariableResolver ServerVariableResolver
+	ignoreOperationServers bool
+	compressMinBytes       int
+	fileUploadRoot         string
+	maxBodyBytes           int64
+	fixedContentType       string
+	pathPrefix             string
+	defaultAccept          string
+	joinCookieArrays       bool
+	maxURLLength           int
+	unwrapSingleProperty   bool
+	queryArrayFormat       QueryArrayFormat
+	dryRun                 bool
 }
 
 func NewRequestBuilder(route ir.HTTPRoute, paramMap map[string]ir.ParamMapping, baseURL string) *RequestBuilder {
@@ -42,28 +58,142 @@ func NewRequestBuilder(route ir.HTTPRoute, paramMap map[string]ir.ParamMapping,
 	return rb
 }
 
+// WithServerVariables supplies values for operation server variables, taking precedence over spec defaults.
+func (rb *RequestBuilder) WithServerVariables(vars map[string]string) *RequestBuilder {
+	rb.serverVariables = vars
+	return rb
+}
+
+// WithServerVariableResolver resolves server variables per call, ahead of the
+// static values and the spec defaults.
+func (rb *RequestBuilder) WithServerVariableResolver(fn ServerVariableResolver) *RequestBuilder {
+	rb.serverVariableResolver = fn
+	return rb
+}
+
+// WithRequestCompression gzips request bodies of at least minBytes for compressible content types.
+func (rb *RequestBuilder) WithRequestCompression(minBytes int) *RequestBuilder {
+	rb.compressMinBytes = minBytes
+	return rb
+}
+
+// WithMaxBodyBytes rejects in-memory request bodies larger than limit bytes.
+func (rb *RequestBuilder) WithMaxBodyBytes(limit int64) *RequestBuilder {
+	rb.maxBodyBytes = limit
+	return rb
+}
+
+// WithContentType pins the request body media type; `_contentType` arguments
+// and content negotiation are ignored afterwards.
+func (rb *RequestBuilder) WithContentType(contentType string) *RequestBuilder {
+	rb.fixedContentType = contentType
+	rb.setContentType(contentType)
+	return rb
+}
+
+// WithPathPrefix mounts every route path under prefix (e.g. "/api/v2"), after
+// path parameters are substituted and the base or operation server is chosen.
+func (rb *RequestBuilder) WithPathPrefix(prefix string) *RequestBuilder {
+	rb.pathPrefix = prefix
+	return rb
+}
+
+// WithDefaultAccept sets the Accept header used when a call passes no `_accept`
+// argument and the operation declares the media type (or declares none).
+func (rb *RequestBuilder) WithDefaultAccept(contentType string) *RequestBuilder {
+	rb.defaultAccept = contentType
+	return rb
+}
+
+// WithMaxURLLength rejects requests whose URL exceeds limit bytes instead of
+// letting the server answer 414. Query parameters that the request body also
+// declares are moved into the body first. Zero or less disables the check.
+func (rb *RequestBuilder) WithMaxURLLength(limit int) *RequestBuilder {
+	rb.maxURLLength = limit
+	return rb
+}
+
+// WithUnwrapSingleProperty sends the value of the only property of an object
+// body as the whole body, e.g. [...] instead of {"items": [...]}. By default the
+// body keeps the shape its schema declares.
+func (rb *RequestBuilder) WithUnwrapSingleProperty(enabled bool) *RequestBuilder {
+	rb.unwrapSingleProperty = enabled
+	return rb
+}
+
+// WithJoinedCookieArrays sends array cookie parameters as one comma-separated
+// cookie even when they explode. By default `explode: true` (the default for
+// cookies) sends one cookie per value, all with the same name.
+func (rb *RequestBuilder) WithJoinedCookieArrays(enabled bool) *RequestBuilder {
+	rb.joinCookieArrays = enabled
+	return rb
+}
+
+// DryRun reports whether the last Build call received `_dryRun: true`.
+func (rb *RequestBuilder) DryRun() bool {
+	return rb.dryRun
+}
+
+// WithOperationServers toggles whether operation/path-item servers override the configured base URL.
+func (rb *RequestBuilder) WithOperationServers(enabled bool) *RequestBuilder {
+	rb.ignoreOperationServers = !enabled
+	return rb
+}
+
+// Control arguments steer a call instead of filling a parameter.
+const (
+	contentTypeArgument = "_contentType"
+	rawBodyArgument     = "_rawBody"
+	dryRunArgument      = "_dryRun"
+)
+
+// isReservedArgument reports whether name is a control argument, which passes
+// any filtering of arguments that the operation does not declare.
+func isReservedArgument(name string) bool {
+	switch name {
+	case contentTypeArgument, rawBodyArgument, dryRunArgument, AcceptArgument:
+		return true
+	}
+	return false
+}
+
 func (rb *RequestBuilder) Build(ctx context.Context, args map[string]interface{}) (*http.Request, error) {
 	pathParams := make(map[string]string)
-	queryParams := make([]EncodedParameter, 0)
+	var queryArgs []queryArgument
 	var headerParams []EncodedParameter
-	var cookieParams []EncodedParameter
+	var cookies []*http.Cookie
+	var secureCookies []string
 	bodyParams := make(map[string]interface{})
 	var rawBody interface{}
 	var overrideContentType string
+	var overrideAccept string
 
+	args = rb.withConstParameters(args)
 	for argName, argValue := range args {
-		if argName == "_contentType" {
+		if argName == contentTypeArgument {
 			if s, ok := argValue.(string); ok {
 				overrideContentType = s
 			}
 			continue
 		}
 
-		if argName == "_rawBody" {
+		if argName == AcceptArgument {
+			if s, ok := argValue.(string); ok {
+				overrideAccept = strings.TrimSpace(s)
+			}
+			continue
+		}
+
+		if argName == rawBodyArgument {
 			rawBody = argValue
 			continue
 		}
 
+		if argName == dryRunArgument {
+			rb.dryRun = isTruthy(argValue)
+			continue
+		}
+
 		mapping, ok := rb.paramMap[argName]
 		if !ok {
 			// 未显式映射的参数默认归入请求体，保持向后兼容
@@ -89,11 +219,11 @@ func (rb *RequestBuilder) Build(ctx context.Context, args map[string]interface{}
 				if paramInfo != nil {
 					info = *paramInfo
 				}
-				encoded, err := encodeParameterValues(info, argValue)
+				encoded, err := rb.encodeQueryParameter(info, argValue)
 				if err != nil {
 					return nil, err
 				}
-				queryParams = append(queryParams, encoded...)
+				queryArgs = append(queryArgs, queryArgument{name: mapping.OpenAPIName, value: argValue, encoded: encoded})
 			}
 		case ir.ParameterInHeader:
 			if argValue != nil {
@@ -119,7 +249,10 @@ func (rb *RequestBuilder) Build(ctx context.Context, args map[string]interface{}
 				if err != nil {
 					return nil, err
 				}
-				cookieParams = append(cookieParams, encoded...)
+				cookies = append(cookies, rb.cookiesFor(info, encoded)...)
+				if info.Cookie != nil && info.Cookie.Secure {
+					secureCookies = append(secureCookies, info.Name)
+				}
 			}
 		case "body":
 			if argValue != nil {
@@ -129,10 +262,15 @@ func (rb *RequestBuilder) Build(ctx context.Context, args map[string]interface{}
 	}
 
 	if rawBody == nil {
-		rb.applyBodyDefaults(bodyParams)
+		rawBody = rb.applyBodyDefaults(bodyParams)
 	}
 
-	if overrideContentType != "" {
+	if err := rb.resolveFileUploads(bodyParams); err != nil {
+		return nil, err
+	}
+	if rb.fixedContentType != "" {
+		rb.setContentType(rb.fixedContentType)
+	} else if overrideContentType != "" {
 		rb.setContentType(overrideContentType)
 	} else {
 		selected := rb.chooseContentType(bodyParams, rawBody)
@@ -142,8 +280,11 @@ func (rb *RequestBuilder) Build(ctx context.Context, args map[string]interface{}
 	if missing := rb.missingPathParameters(pathParams); len(missing) > 0 {
 		return nil, fmt.Errorf("missing required path parameter(s): %s", strings.Join(missing, ", "))
 	}
+	if unfilled := rb.unfilledPathPlaceholders(pathParams); len(unfilled) > 0 {
+		return nil, fmt.Errorf("path %s has no value for placeholder(s) %s; the spec declares no matching path parameter", rb.route.Path, strings.Join(unfilled, ", "))
+	}
 
-	reqURL, err := rb.buildURL(pathParams, queryParams)
+	reqURL, err := rb.buildURLWithinLimit(ctx, pathParams, queryArgs, bodyParams, rawBody)
 	if err != nil {
 		return nil, err
 	}
@@ -153,27 +294,48 @@ func (rb *RequestBuilder) Build(ctx context.Context, args map[string]interface{}
 		return nil, err
 	}
 
+	if err := checkRequestBodySize(bodyReader, rb.maxBodyBytes); err != nil {
+		return nil, err
+	}
+
+	bodyReader, compressed, err := compressRequestBody(bodyReader, contentType, rb.compressMinBytes)
+	if err != nil {
+		return nil, err
+	}
+
 	req, err := http.NewRequestWithContext(ctx, rb.route.Method, reqURL, bodyReader)
 	if err != nil {
 		return nil, err
 	}
+	if stream, ok := bodyReader.(*multipartStream); ok {
+		req.ContentLength = stream.size
+		req.GetBody = stream.replay
+	}
 
 	if contentType != "" {
 		req.Header.Set("Content-Type", contentType)
 	}
+	if compressed {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
 
 	for _, pair := range headerParams {
 		req.Header.Add(pair.Name, pair.Value)
 	}
 
-	for _, cookie := range cookieParams {
-		req.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	if err := checkSecureCookies(secureCookies, req.URL); err != nil {
+		return nil, err
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
 	}
 
-	if req.Header.Get("Accept") == "" {
-		if accept := preferredResponseContentType(rb.route); accept != "" {
-			req.Header.Set("Accept", accept)
-		}
+	accept, err := rb.acceptHeader(overrideAccept, req)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
 	}
 
 	return req, nil
@@ -216,27 +378,50 @@ func resolveEncodingHeaderValue(header ir.HeaderInfo) (string, bool) {
 	return "", false
 }
 
-func (rb *RequestBuilder) applyBodyDefaults(bodyParams map[string]interface{}) {
-	if rb.route.RequestBody == nil || len(rb.route.RequestBody.ContentSchemas) == 0 {
-		return
+// applyBodyDefaults fills omitted body properties from schema defaults and sets
+// `const` properties, which the input schema does not offer, to their value. A
+// primitive or free-form body without a default falls back to the media type
+// example; when the media type has no schema at all, that example is returned to
+// be sent as the raw body.
+func (rb *RequestBuilder) applyBodyDefaults(bodyParams map[string]interface{}) interface{} {
+	body := rb.route.RequestBody
+	if body == nil {
+		return nil
 	}
 
 	contentType := rb.bodyContentType
 	if contentType == "" {
-		for ct := range rb.route.RequestBody.ContentSchemas {
-			contentType = ct
-			break
+		if len(body.ContentOrder) > 0 {
+			contentType = body.ContentOrder[0]
+		} else {
+			for ct := range body.ContentSchemas {
+				contentType = ct
+				break
+			}
 		}
 	}
+	example, hasExample := body.MediaExamples[contentType]
+	// an optional body the caller left out is not made up from the example; only
+	// schema defaults can still produce one
+	if !body.Required && len(bodyParams) == 0 {
+		hasExample = false
+	}
 
 	schema := rb.lookupBodySchema(contentType)
 	if schema == nil {
-		return
+		if hasExample && example != nil && len(bodyParams) == 0 {
+			return cloneAnyValue(example)
+		}
+		return nil
 	}
 
 	properties := schema.Properties()
 	if len(properties) > 0 {
 		for name, propSchema := range properties {
+			if value, ok := ConstValue(propSchema, rb.route.SchemaDefs); ok {
+				bodyParams[name] = value
+				continue
+			}
 			if _, exists := bodyParams[name]; exists {
 				continue
 			}
@@ -244,18 +429,24 @@ func (rb *RequestBuilder) applyBodyDefaults(bodyParams map[string]interface{}) {
 				bodyParams[name] = cloneAnyValue(def)
 			}
 		}
-		return
+		return nil
 	}
 
-	if def, ok := schema["default"]; ok {
-		propName := rb.primaryBodyPropertyName()
-		if propName == "" {
-			return
-		}
-		if _, exists := bodyParams[propName]; !exists {
-			bodyParams[propName] = cloneAnyValue(def)
-		}
+	fallback, ok := schema["default"]
+	if !ok {
+		fallback, ok = example, hasExample && example != nil
+	}
+	if !ok {
+		return nil
+	}
+	propName := rb.primaryBodyPropertyName()
+	if propName == "" {
+		return nil
+	}
+	if _, exists := bodyParams[propName]; !exists {
+		bodyParams[propName] = cloneAnyValue(fallback)
 	}
+	return nil
 }
 
 func (rb *RequestBuilder) primaryBodyPropertyName() string {
@@ -270,7 +461,11 @@ func (rb *RequestBuilder) primaryBodyPropertyName() string {
 func (rb *RequestBuilder) missingPathParameters(pathParams map[string]string) []string {
 	var missing []string
 	for _, param := range rb.route.Parameters {
-		if param.In != ir.ParameterInPath || !param.Required {
+		if param.In != ir.ParameterInPath {
+			continue
+		}
+		// a placeholder in the path needs a value even if the spec forgot required: true
+		if !param.Required && !strings.Contains(rb.route.Path, "{"+param.Name+"}") {
 			continue
 		}
 		if _, ok := pathParams[param.Name]; ok {
@@ -286,17 +481,39 @@ func (rb *RequestBuilder) missingPathParameters(pathParams map[string]string) []
 	return missing
 }
 
-func (rb *RequestBuilder) buildURL(pathParams map[string]string, queryParams []EncodedParameter) (string, error) {
+// pathPlaceholder matches a {name} template placeholder of a route path.
+var pathPlaceholder = regexp.MustCompile(`\{[^{}]+\}`)
+
+// unfilledPathPlaceholders returns the {placeholders} of the route path that
+// would stay in the URL: usually a spec naming the placeholder differently from
+// its path parameter.
+func (rb *RequestBuilder) unfilledPathPlaceholders(pathParams map[string]string) []string {
+	var unfilled []string
+	for _, match := range pathPlaceholder.FindAllString(rb.route.Path, -1) {
+		if _, ok := pathParams[match[1:len(match)-1]]; !ok {
+			unfilled = append(unfilled, match)
+		}
+	}
+	return unfilled
+}
+
+func (rb *RequestBuilder) buildURL(ctx context.Context, pathParams map[string]string, queryParams []EncodedParameter) (string, error) {
 	urlPath := rb.route.Path
 
 	for paramName, paramValue := range pathParams {
 		placeholder := fmt.Sprintf("{%s}", paramName)
 		urlPath = strings.ReplaceAll(urlPath, placeholder, paramValue)
 	}
+	urlPath = joinPathPrefix(rb.pathPrefix, urlPath)
+
+	baseURL, err := rb.effectiveBaseURL(ctx)
+	if err != nil {
+		return "", err
+	}
 
 	var fullURL string
-	if rb.baseURL != "" {
-		baseURL := strings.TrimSuffix(rb.baseURL, "/")
+	if baseURL != "" {
+		baseURL = strings.TrimSuffix(baseURL, "/")
 		urlPath = strings.TrimPrefix(urlPath, "/")
 		fullURL = fmt.Sprintf("%s/%s", baseURL, urlPath)
 	} else {
@@ -315,6 +532,29 @@ func (rb *RequestBuilder) buildURL(pathParams map[string]string, queryParams []E
 	return parsedURL.String(), nil
 }
 
+// joinPathPrefix puts exactly one slash between prefix and path.
+func joinPathPrefix(prefix, path string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return path
+	}
+	return "/" + prefix + "/" + strings.TrimPrefix(path, "/")
+}
+
+// effectiveBaseURL prefers an operation- or path-item-level server over the configured
+// base URL. Document-level servers are not consulted; the base URL stands in for them.
+func (rb *RequestBuilder) effectiveBaseURL(ctx context.Context) (string, error) {
+	if len(rb.route.Servers) == 0 || rb.ignoreOperationServers {
+		return rb.baseURL, nil
+	}
+
+	serverURL, err := expandServerURL(ctx, rb.route.Servers[0], rb.serverVariables, rb.serverVariableResolver)
+	if err != nil {
+		return "", err
+	}
+	return joinServerURL(rb.baseURL, serverURL)
+}
+
 var reservedReplacer = strings.NewReplacer(
 	"%3A", ":",
 	"%2F", "/",
@@ -413,6 +653,9 @@ func containsBinaryValues(bodyParams map[string]interface{}) bool {
 		case []byte:
 			return true
 		}
+		if _, ok := fileUploadFromValue(value); ok {
+			return true
+		}
 	}
 	return false
 }
@@ -659,8 +902,19 @@ func (rb *RequestBuilder) buildBody(bodyParams map[string]interface{}, rawBody i
 		contentType = "application/json"
 	}
 
+	if encode := lookupContentEncoder(contentType); encode != nil {
+		reader, encodedType, err := encode(bodyParams, schema)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to encode %s body: %w", baseMediaType(contentType), err)
+		}
+		if encodedType == "" {
+			encodedType = contentType
+		}
+		return reader, encodedType, nil
+	}
+
 	if len(bodyParams) == 1 &&
-		!strings.Contains(contentType, "multipart/form-data") &&
+		!isMultipartContentType(contentType) &&
 		!strings.Contains(contentType, "application/x-www-form-urlencoded") {
 		for name, value := range bodyParams {
 			propSchema := rb.lookupPropertySchema(schema, name)
@@ -675,8 +929,12 @@ func (rb *RequestBuilder) buildBody(bodyParams map[string]interface{}, rawBody i
 		return rb.encodeJSONBody(bodyParams, contentType)
 	case strings.Contains(contentType, "application/x-www-form-urlencoded"):
 		return rb.encodeFormBody(bodyParams)
-	case strings.Contains(contentType, "multipart/form-data"):
-		return rb.encodeMultipartBody(bodyParams)
+	case isMultipartContentType(contentType):
+		return rb.encodeMultipartBody(bodyParams, contentType)
+	case isXMLContentType(contentType):
+		return rb.encodeXMLBody(bodyParams, schema, contentType)
+	case isYAMLContentType(contentType):
+		return rb.encodeYAMLBody(bodyParams, contentType)
 	case strings.HasPrefix(contentType, "text/"):
 		return rb.encodeTextBody(bodyParams, contentType)
 	default:
@@ -693,6 +951,7 @@ func (rb *RequestBuilder) serializePathParam(name string, value interface{}) (st
 			style = paramInfo.Style
 		}
 		explodePtr = paramInfo.Explode
+		value = formatTupleValue(value, paramInfo.Schema)
 	}
 
 	explode := false
@@ -806,20 +1065,14 @@ func serializeMatrixPath(name string, value interface{}, explode bool) string {
 	return fmt.Sprintf(";%s=%s", name, formatScalar(value))
 }
 
+// shouldUseRawBody reports whether the only body argument is the whole body:
+// either it stands for a body without declared properties, or it is the single
+// property of an object body and unwrapping is enabled.
 func (rb *RequestBuilder) shouldUseRawBody(parent ir.Schema, property ir.Schema) bool {
 	if property == nil {
 		return true
 	}
-
-	if t := property.Type(); t != "" && t != "object" {
-		return true
-	}
-
-	if len(property.Properties()) == 0 {
-		return true
-	}
-
-	return false
+	return rb.unwrapSingleProperty && len(parent.Properties()) == 1
 }
 
 func (rb *RequestBuilder) encodeRawBodyFromParams(bodyParams map[string]interface{}, schema ir.Schema) (io.Reader, string, error) {
@@ -856,6 +1109,12 @@ func (rb *RequestBuilder) encodeRawBody(value interface{}, schema ir.Schema) (io
 	case json.RawMessage:
 		return bytes.NewReader(v), contentType, nil
 	default:
+		if isXMLContentType(contentType) {
+			return rb.encodeXMLBody(v, schema, contentType)
+		}
+		if isYAMLContentType(contentType) {
+			return rb.encodeYAMLBody(v, contentType)
+		}
 		if strings.HasPrefix(contentType, "text/") {
 			return strings.NewReader(fmt.Sprintf("%v", v)), contentType, nil
 		}
@@ -883,6 +1142,10 @@ func (rb *RequestBuilder) encodeFormBody(body map[string]interface{}) (io.Reader
 		if style == "" {
 			style = "form"
 		}
+		if obj, ok := valueAsMap(val); ok && style == "deepObject" {
+			rb.addSerializedFormValue(values, name, obj, style, encoding)
+			continue
+		}
 		param := ParamInfo{
 			Name:    name,
 			In:      "form",
@@ -898,61 +1161,157 @@ func (rb *RequestBuilder) encodeFormBody(body map[string]interface{}) (io.Reader
 	return strings.NewReader(values.Encode()), "application/x-www-form-urlencoded", nil
 }
 
-func (rb *RequestBuilder) encodeMultipartBody(body map[string]interface{}) (io.Reader, string, error) {
+// encodeMultipartBody writes one part per body property in the schema's
+// declared order. multipart/form-data parts carry their property name;
+// multipart/mixed parts are positional and only describe their content. An
+// array of binary items is written as one part per element, all under the
+// property's name.
+func (rb *RequestBuilder) encodeMultipartBody(body map[string]interface{}, contentType string) (io.Reader, string, error) {
 	buf := &bytes.Buffer{}
 	writer := multipart.NewWriter(buf)
+	stream := &multipartStreamBuilder{}
+	mixed := strings.Contains(strings.ToLower(contentType), "multipart/mixed")
+	mediaTypes := rb.multipartMediaTypes()
+	fileArrays := rb.multipartFileArrays()
 
-	for name, val := range body {
+	for _, name := range rb.multipartPartOrder(body) {
+		val := body[name]
 		encoding := rb.lookupEncoding(name)
-		headers := make(textproto.MIMEHeader)
+		declared := encoding.ContentType
+		if declared == "" {
+			declared = mediaTypes[name]
+		}
+		values := []interface{}{val}
+		if fileArrays[name] {
+			if elements, ok := multipartArrayElements(val); ok {
+				values = elements
+			}
+		}
+		for _, value := range values {
+			if err := writeMultipartPart(writer, buf, stream, name, value, declared, encoding, mixed); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	resultType := writer.FormDataContentType()
+	if mixed {
+		resultType = "multipart/mixed; boundary=" + writer.Boundary()
+	}
+
+	if stream.files > 0 {
+		stream.flush(buf)
+		return stream.stream(), resultType, nil
+	}
+
+	return buf, resultType, nil
+}
+
+// writeMultipartPart writes val as one part named name. File uploads only
+// reserve their place in stream and are read when the body is sent.
+func writeMultipartPart(writer *multipart.Writer, buf *bytes.Buffer, stream *multipartStreamBuilder, name string, val interface{}, declared string, encoding ir.EncodingInfo, mixed bool) error {
+	headers := make(textproto.MIMEHeader)
+	if !mixed {
 		headers.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, name))
-		if encoding.ContentType != "" {
-			headers.Set("Content-Type", encoding.ContentType)
+	}
+	if declared != "" {
+		headers.Set("Content-Type", resolvePartContentType(declared, detectPartContentType(val)))
+	} else if mixed {
+		headers.Set("Content-Type", mixedPartContentType(val))
+	}
+	upload, isFile := fileUploadFromValue(val)
+	if isFile {
+		if mixed {
+			headers.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`,
+				quoteEscaper.Replace(upload.filename())))
+		} else {
+			headers.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+				quoteEscaper.Replace(name), quoteEscaper.Replace(upload.filename())))
 		}
-		if len(encoding.Headers) > 0 {
-			for headerName, headerInfo := range encoding.Headers {
-				if headerValue, ok := resolveEncodingHeaderValue(headerInfo); ok {
-					headers.Set(headerName, headerValue)
-				}
+		headers.Set("Content-Type", upload.contentType(declared))
+	}
+	if len(encoding.Headers) > 0 {
+		for headerName, headerInfo := range encoding.Headers {
+			if headerValue, ok := resolveEncodingHeaderValue(headerInfo); ok {
+				headers.Set(headerName, headerValue)
 			}
 		}
+	}
 
-		part, err := writer.CreatePart(headers)
-		if err != nil {
-			return nil, "", err
+	part, err := writer.CreatePart(headers)
+	if err != nil {
+		return err
+	}
+
+	if isFile {
+		// 文件内容在发送时才读取，避免将大文件整体缓冲到内存
+		stream.flush(buf)
+		return stream.addFile(upload.Path)
+	}
+
+	switch v := val.(type) {
+	case []byte:
+		_, err = part.Write(v)
+	case string:
+		_, err = io.WriteString(part, v)
+	case fmt.Stringer:
+		_, err = io.WriteString(part, v.String())
+	default:
+		var data []byte
+		data, err = json.Marshal(v)
+		if err == nil {
+			_, err = part.Write(data)
 		}
+	}
+	return err
+}
 
-		switch v := val.(type) {
-		case []byte:
-			if _, err := part.Write(v); err != nil {
-				return nil, "", err
-			}
-		case string:
-			if _, err := io.WriteString(part, v); err != nil {
-				return nil, "", err
-			}
-		case fmt.Stringer:
-			if _, err := io.WriteString(part, v.String()); err != nil {
-				return nil, "", err
-			}
-		default:
-			data, err := json.Marshal(v)
-			if err != nil {
-				return nil, "", err
-			}
-			if _, err := part.Write(data); err != nil {
-				return nil, "", err
-			}
+// multipartPartOrder lists body keys in the order the request schema declares
+// them, followed by any undeclared keys sorted by name.
+func (rb *RequestBuilder) multipartPartOrder(body map[string]interface{}) []string {
+	var declared []string
+	if rb.route.RequestBody != nil {
+		declared = rb.route.RequestBody.PropertyOrder[rb.bodyContentType]
+	}
+
+	order := make([]string, 0, len(body))
+	seen := make(map[string]bool, len(body))
+	for _, name := range declared {
+		if _, ok := body[name]; ok && !seen[name] {
+			seen[name] = true
+			order = append(order, name)
 		}
 	}
+	for _, name := range sortedKeys(body) {
+		if !seen[name] {
+			order = append(order, name)
+		}
+	}
+	return order
+}
 
-	if err := writer.Close(); err != nil {
-		return nil, "", err
+func mixedPartContentType(value interface{}) string {
+	switch value.(type) {
+	case []byte:
+		return "application/octet-stream"
+	case string, fmt.Stringer:
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/json"
 	}
+}
 
-	return buf, writer.FormDataContentType(), nil
+func isMultipartContentType(contentType string) bool {
+	lower := strings.ToLower(contentType)
+	return strings.Contains(lower, "multipart/form-data") || strings.Contains(lower, "multipart/mixed")
 }
 
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
 func (rb *RequestBuilder) encodeTextBody(body map[string]interface{}, contentType string) (io.Reader, string, error) {
 	if len(body) == 1 {
 		for _, value := range body {
@@ -966,6 +1325,22 @@ func (rb *RequestBuilder) encodeTextBody(body map[string]interface{}, contentTyp
 	return bytes.NewReader(data), contentType, nil
 }
 
+func (rb *RequestBuilder) encodeXMLBody(body interface{}, schema ir.Schema, contentType string) (io.Reader, string, error) {
+	data, err := encodeXMLBody(body, schema, rb.route.SchemaDefs)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal XML request body: %w", err)
+	}
+	return bytes.NewReader(data), contentType, nil
+}
+
+func (rb *RequestBuilder) encodeYAMLBody(body interface{}, contentType string) (io.Reader, string, error) {
+	data, err := encodeYAML(body)
+	if err != nil {
+		return nil, "", err
+	}
+	return bytes.NewReader(data), contentType, nil
+}
+
 func (rb *RequestBuilder) encodeGenericBody(body map[string]interface{}, contentType string) (io.Reader, string, error) {
 	data, err := json.Marshal(body)
 	if err != nil {
@@ -982,11 +1357,12 @@ func (rb *RequestBuilder) addSerializedFormValue(values url.Values, name string,
 		}
 	case map[string]interface{}:
 		if style == "deepObject" {
-			for key, val := range v {
+			explode := encoding.Explode == nil || *encoding.Explode
+			for _, key := range sortedKeys(v) {
 				if strings.HasPrefix(key, "[") {
-					values.Add(fmt.Sprintf("%s%s", name, key), fmt.Sprintf("%v", val))
+					addDeepObjectFormValue(values, name+key, v[key], explode)
 				} else {
-					values.Add(fmt.Sprintf("%s[%s]", name, key), fmt.Sprintf("%v", val))
+					addDeepObjectFormValue(values, fmt.Sprintf("%s[%s]", name, key), v[key], explode)
 				}
 			}
 			return
@@ -1011,6 +1387,31 @@ func (rb *RequestBuilder) addSerializedFormValue(values url.Values, name string,
 	}
 }
 
+// addDeepObjectFormValue flattens nested objects into bracketed keys such as
+// user[address][city]. Scalar arrays repeat the key when explode is set and are
+// indexed (tags[0], tags[1]) otherwise; objects inside arrays are always indexed.
+func addDeepObjectFormValue(values url.Values, key string, value interface{}, explode bool) {
+	if obj, ok := valueAsMap(value); ok {
+		for _, name := range sortedKeys(obj) {
+			addDeepObjectFormValue(values, fmt.Sprintf("%s[%s]", key, name), obj[name], explode)
+		}
+		return
+	}
+	if items, ok := valueAsSlice(value); ok {
+		for i, item := range items {
+			_, isObject := valueAsMap(item)
+			_, isArray := valueAsSlice(item)
+			if explode && !isObject && !isArray {
+				values.Add(key, formatScalar(item))
+				continue
+			}
+			addDeepObjectFormValue(values, fmt.Sprintf("%s[%d]", key, i), item, explode)
+		}
+		return
+	}
+	values.Add(key, formatScalar(value))
+}
+
 func (rb *RequestBuilder) lookupEncoding(name string) ir.EncodingInfo {
 	if rb.bodyEncoding != nil {
 		if enc, ok := rb.bodyEncoding[name]; ok {
@@ -2,6 +2,10 @@ package executor_test
 
 import (
 	"context"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/specx2/openapi-mcp/core/executor"
@@ -102,6 +106,150 @@ func TestRequestBuilderRawBodyPrefersTextForPlainString(t *testing.T) {
 	}
 }
 
+func TestRequestBuilderFallsBackToMediaExample(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/notes",
+		Method: "POST",
+		RequestBody: &ir.RequestBodyInfo{
+			Required:       true,
+			ContentSchemas: map[string]ir.Schema{"text/plain": {"type": "string"}},
+			ContentOrder:   []string{"text/plain"},
+			MediaExamples:  map[string]interface{}{"text/plain": "Remember the milk"},
+		},
+	}
+	paramMap := map[string]ir.ParamMapping{"body": {OpenAPIName: "body", Location: "body", OriginalName: "body"}}
+
+	req, err := executor.NewRequestBuilder(route, paramMap, "").Build(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	data, _ := io.ReadAll(req.Body)
+	if string(data) != "Remember the milk" {
+		t.Fatalf("expected the media example as body, got %q", data)
+	}
+
+	schemaless := ir.HTTPRoute{
+		Path:   "/events",
+		Method: "POST",
+		RequestBody: &ir.RequestBodyInfo{
+			Required:       true,
+			ContentSchemas: map[string]ir.Schema{},
+			ContentOrder:   []string{"application/x-ndjson"},
+			MediaExamples:  map[string]interface{}{"application/x-ndjson": "{\"event\":\"ping\"}\n"},
+		},
+	}
+	req, err = executor.NewRequestBuilder(schemaless, nil, "").Build(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	data, _ = io.ReadAll(req.Body)
+	if string(data) != "{\"event\":\"ping\"}\n" {
+		t.Fatalf("expected the media example as raw body, got %q", data)
+	}
+
+	req, err = executor.NewRequestBuilder(schemaless, nil, "").Build(context.Background(), map[string]interface{}{"_rawBody": "{}"})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	data, _ = io.ReadAll(req.Body)
+	if string(data) != "{}" {
+		t.Fatalf("expected an explicit _rawBody to win over the example, got %q", data)
+	}
+}
+
+func TestRequestBuilderOmitsOptionalBody(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:       "/jobs/{id}/restart",
+		Method:     "POST",
+		Parameters: []ir.ParameterInfo{{Name: "id", In: "path", Required: true, Schema: ir.Schema{"type": "string"}}},
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{"application/json": {
+				"type": "object",
+				"properties": map[string]interface{}{
+					"force":  map[string]interface{}{"type": "boolean"},
+					"reason": map[string]interface{}{"type": "string"},
+				},
+			}},
+			ContentOrder:  []string{"application/json"},
+			MediaExamples: map[string]interface{}{"application/json": map[string]interface{}{"force": true}},
+		},
+	}
+	paramMap := map[string]ir.ParamMapping{
+		"id":     {OpenAPIName: "id", Location: "path", OriginalName: "id"},
+		"force":  {OpenAPIName: "force", Location: "body", OriginalName: "force"},
+		"reason": {OpenAPIName: "reason", Location: "body", OriginalName: "reason"},
+	}
+
+	req, err := executor.NewRequestBuilder(route, paramMap, "https://api.example.com").
+		Build(context.Background(), map[string]interface{}{"id": "7"})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if req.Body != nil || req.ContentLength != 0 || req.Header.Get("Content-Type") != "" {
+		t.Fatalf("expected no body and no Content-Type, got length %d and %q", req.ContentLength, req.Header.Get("Content-Type"))
+	}
+
+	req, err = executor.NewRequestBuilder(route, paramMap, "https://api.example.com").
+		Build(context.Background(), map[string]interface{}{"id": "7", "_rawBody": map[string]interface{}{}})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	data, _ := io.ReadAll(req.Body)
+	if string(data) != "{}" || req.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("expected an explicit empty object to be sent, got %q (%q)", data, req.Header.Get("Content-Type"))
+	}
+
+	properties := route.RequestBody.ContentSchemas["application/json"].Properties()
+	properties["force"]["default"] = false
+	properties["reason"]["default"] = "manual"
+	req, err = executor.NewRequestBuilder(route, paramMap, "https://api.example.com").
+		Build(context.Background(), map[string]interface{}{"id": "7"})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if req.Body == nil {
+		t.Fatalf("expected schema defaults to still produce a body")
+	}
+	data, _ = io.ReadAll(req.Body)
+	if string(data) != `{"force":false,"reason":"manual"}` {
+		t.Fatalf("expected only the defaults in the body, got %q", data)
+	}
+}
+
+func TestRequestBuilderMountsPathPrefix(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:       "/users/{id}",
+		Method:     "GET",
+		Parameters: []ir.ParameterInfo{{Name: "id", In: "path", Required: true, Schema: ir.Schema{"type": "string"}}},
+	}
+	paramMap := map[string]ir.ParamMapping{"id": {OpenAPIName: "id", Location: "path", OriginalName: "id"}}
+
+	req, err := executor.NewRequestBuilder(route, paramMap, "https://gateway.example.com/").
+		WithPathPrefix("api/v2/").
+		Build(context.Background(), map[string]interface{}{"id": "7"})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if got := req.URL.String(); got != "https://gateway.example.com/api/v2/users/7" {
+		t.Fatalf("unexpected URL %s", got)
+	}
+
+	route.Servers = []ir.ServerInfo{{
+		URL:       "https://{region}.example.com/base",
+		Variables: map[string]ir.ServerVariable{"region": {Default: "us"}},
+	}}
+	req, err = executor.NewRequestBuilder(route, paramMap, "").
+		WithServerVariables(map[string]string{"region": "eu"}).
+		WithPathPrefix("/api/v2").
+		Build(context.Background(), map[string]interface{}{"id": "7"})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if got := req.URL.String(); got != "https://eu.example.com/base/api/v2/users/7" {
+		t.Fatalf("expected the prefix after the operation server, got %s", got)
+	}
+}
+
 func TestRequestBuilderSetsAcceptHeaderFromResponses(t *testing.T) {
 	route := ir.HTTPRoute{
 		Path:   "/widgets",
@@ -162,3 +310,602 @@ func TestRequestBuilderDoesNotOverrideAcceptHeader(t *testing.T) {
 		t.Fatalf("expected Accept header application/xml, got %q", got)
 	}
 }
+
+func TestRequestBuilderAcceptOverride(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/reports",
+		Method: "GET",
+		Responses: map[string]ir.ResponseInfo{
+			"200": {
+				ContentSchemas: map[string]ir.Schema{
+					"application/json": {"type": "array"},
+					"text/csv":         {"type": "string"},
+				},
+			},
+		},
+	}
+
+	req, err := executor.NewRequestBuilder(route, nil, "").
+		Build(context.Background(), map[string]interface{}{"_accept": "text/csv"})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if got := req.Header.Get("Accept"); got != "text/csv" {
+		t.Fatalf("expected Accept text/csv, got %q", got)
+	}
+	if req.ContentLength > 0 {
+		t.Fatalf("_accept must not be sent as a body property")
+	}
+
+	req, err = executor.NewRequestBuilder(route, nil, "").
+		WithDefaultAccept("text/*").
+		Build(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if got := req.Header.Get("Accept"); got != "text/*" {
+		t.Fatalf("expected the default Accept, got %q", got)
+	}
+
+	req, err = executor.NewRequestBuilder(route, nil, "").
+		WithDefaultAccept("application/xml").
+		Build(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if got := req.Header.Get("Accept"); got != "application/json" {
+		t.Fatalf("expected an undeclared default to fall back to application/json, got %q", got)
+	}
+
+	_, err = executor.NewRequestBuilder(route, nil, "").
+		Build(context.Background(), map[string]interface{}{"_accept": "application/pdf"})
+	if err == nil || !strings.Contains(err.Error(), "text/csv") {
+		t.Fatalf("expected an undeclared _accept to be rejected, got %v", err)
+	}
+}
+
+func TestRequestBuilderPrefersOperationServer(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/uploads/{id}",
+		Method: "PUT",
+		Parameters: []ir.ParameterInfo{
+			{Name: "id", In: ir.ParameterInPath, Required: true},
+		},
+		Servers: []ir.ServerInfo{
+			{
+				URL: "https://{region}.cdn.example.com/",
+				Variables: map[string]ir.ServerVariable{
+					"region": {Default: "eu", Enum: []string{"eu", "us"}},
+				},
+			},
+		},
+	}
+
+	paramMap := map[string]ir.ParamMapping{
+		"id": {OpenAPIName: "id", Location: ir.ParameterInPath},
+	}
+
+	builder := executor.NewRequestBuilder(route, paramMap, "https://api.example.com/v1/")
+	req, err := builder.Build(context.Background(), map[string]interface{}{"id": "42"})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	if got := req.URL.String(); got != "https://eu.cdn.example.com/uploads/42" {
+		t.Fatalf("expected operation server URL, got %q", got)
+	}
+}
+
+func TestRequestBuilderResolvesRelativeOperationServer(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:    "/files",
+		Method:  "GET",
+		Servers: []ir.ServerInfo{{URL: "/storage"}},
+	}
+
+	builder := executor.NewRequestBuilder(route, nil, "https://api.example.com/v1")
+	req, err := builder.Build(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	if got := req.URL.String(); got != "https://api.example.com/storage/files" {
+		t.Fatalf("expected relative server to resolve against base origin, got %q", got)
+	}
+}
+
+func TestRequestBuilderAppliesServerVariableOverrides(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/uploads",
+		Method: "POST",
+		Servers: []ir.ServerInfo{
+			{
+				URL: "https://{region}.cdn.example.com",
+				Variables: map[string]ir.ServerVariable{
+					"region": {Default: "eu", Enum: []string{"eu", "us"}},
+				},
+			},
+		},
+	}
+
+	builder := executor.NewRequestBuilder(route, nil, "https://api.example.com").
+		WithServerVariables(map[string]string{"region": "us"})
+	req, err := builder.Build(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if got := req.URL.String(); got != "https://us.cdn.example.com/uploads" {
+		t.Fatalf("expected override to select us region, got %q", got)
+	}
+
+	builder = executor.NewRequestBuilder(route, nil, "https://api.example.com").
+		WithServerVariables(map[string]string{"region": "ap"})
+	if _, err := builder.Build(context.Background(), nil); err == nil {
+		t.Fatalf("expected override outside enum to fail")
+	}
+}
+
+type tenantKey struct{}
+
+func TestRequestBuilderResolvesServerVariablesFromContext(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/orders",
+		Method: "GET",
+		Servers: []ir.ServerInfo{
+			{
+				URL: "https://{tenant}.example.com/{version}",
+				Variables: map[string]ir.ServerVariable{
+					"tenant":  {Default: "public"},
+					"version": {Default: "v1", Enum: []string{"v1", "v2"}},
+				},
+			},
+		},
+	}
+	resolver := func(ctx context.Context, name string) (string, bool) {
+		switch name {
+		case "tenant":
+			tenant, ok := ctx.Value(tenantKey{}).(string)
+			return tenant, ok
+		case "version":
+			return "v3", ctx.Value(tenantKey{}) == "legacy"
+		}
+		return "", false
+	}
+	builder := executor.NewRequestBuilder(route, nil, "https://api.example.com").
+		WithServerVariables(map[string]string{"tenant": "static"}).
+		WithServerVariableResolver(resolver)
+
+	req, err := builder.Build(context.WithValue(context.Background(), tenantKey{}, "acme"), nil)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if got := req.URL.String(); got != "https://acme.example.com/v1/orders" {
+		t.Fatalf("expected tenant from context, got %q", got)
+	}
+
+	req, err = builder.Build(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if got := req.URL.String(); got != "https://static.example.com/v1/orders" {
+		t.Fatalf("expected static value without a resolved tenant, got %q", got)
+	}
+
+	if _, err := builder.Build(context.WithValue(context.Background(), tenantKey{}, "legacy"), nil); err == nil {
+		t.Fatalf("expected resolved value outside enum to fail")
+	}
+
+	for _, tenant := range []string{"evil.com/", "evil.com?", "evil.com#", "user@evil.com", "evil.com:8080", "acme corp"} {
+		if _, err := builder.Build(context.WithValue(context.Background(), tenantKey{}, tenant), nil); err == nil {
+			t.Fatalf("expected resolved value %q with URL delimiters to fail", tenant)
+		}
+	}
+}
+
+func TestRequestBuilderOperationServerErrors(t *testing.T) {
+	cases := map[string]ir.ServerInfo{
+		"missing variable": {URL: "https://{region}.example.com"},
+		"empty default": {
+			URL:       "https://{region}.example.com",
+			Variables: map[string]ir.ServerVariable{"region": {}},
+		},
+		"default outside enum": {
+			URL: "https://{region}.example.com",
+			Variables: map[string]ir.ServerVariable{
+				"region": {Default: "ap", Enum: []string{"eu", "us"}},
+			},
+		},
+	}
+
+	for name, server := range cases {
+		t.Run(name, func(t *testing.T) {
+			route := ir.HTTPRoute{Path: "/items", Method: "GET", Servers: []ir.ServerInfo{server}}
+			builder := executor.NewRequestBuilder(route, nil, "https://api.example.com")
+			if _, err := builder.Build(context.Background(), nil); err == nil {
+				t.Fatalf("expected build error")
+			}
+		})
+	}
+}
+
+func TestRequestBuilderAbsoluteOperationServerWithoutBaseURL(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:    "/items",
+		Method:  "GET",
+		Servers: []ir.ServerInfo{{URL: "https://cdn.example.com/"}},
+	}
+
+	req, err := executor.NewRequestBuilder(route, nil, "").Build(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if got := req.URL.String(); got != "https://cdn.example.com/items" {
+		t.Fatalf("expected operation server origin, got %q", got)
+	}
+}
+
+func TestRequestBuilderCanIgnoreOperationServers(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:    "/items",
+		Method:  "GET",
+		Servers: []ir.ServerInfo{{URL: "https://cdn.example.com"}},
+	}
+
+	builder := executor.NewRequestBuilder(route, nil, "http://localhost:8080").WithOperationServers(false)
+	req, err := builder.Build(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if got := req.URL.String(); got != "http://localhost:8080/items" {
+		t.Fatalf("expected base URL to win, got %q", got)
+	}
+}
+
+func TestRequestBuilderEncodesJSONContentParameters(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/items",
+		Method: "GET",
+		Parameters: []ir.ParameterInfo{
+			{
+				Name:    "filter",
+				In:      ir.ParameterInQuery,
+				Content: map[string]ir.Schema{"application/json": {"type": "object"}},
+			},
+			{
+				Name:          "where",
+				In:            ir.ParameterInQuery,
+				AllowReserved: true,
+				Content:       map[string]ir.Schema{"application/json": {"type": "object"}},
+			},
+		},
+	}
+	paramMap := map[string]ir.ParamMapping{
+		"filter": {OpenAPIName: "filter", Location: ir.ParameterInQuery},
+		"where":  {OpenAPIName: "where", Location: ir.ParameterInQuery},
+	}
+
+	req, err := executor.NewRequestBuilder(route, paramMap, "https://api.example.com").Build(context.Background(), map[string]interface{}{
+		"filter": map[string]interface{}{"status": "open & ready"},
+		"where":  map[string]interface{}{"id": "a/b"},
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	got := req.URL.RawQuery
+	if !strings.Contains(got, "filter=%7B%22status%22%3A%22open+%26+ready%22%7D") {
+		t.Fatalf("expected URL-escaped JSON filter, got %s", got)
+	}
+	if !strings.Contains(got, "where=%7B%22id%22:%22a/b%22%7D") {
+		t.Fatalf("expected allowReserved to keep reserved characters, got %s", got)
+	}
+}
+
+func TestRequestBuilderExplodesDelimitedArrays(t *testing.T) {
+	explode := true
+	route := ir.HTTPRoute{
+		Path:   "/items",
+		Method: "GET",
+		Parameters: []ir.ParameterInfo{
+			{Name: "tags", In: ir.ParameterInQuery, Style: "spaceDelimited", Explode: &explode},
+			{Name: "ids", In: ir.ParameterInQuery, Style: "pipeDelimited", Explode: &explode},
+			{Name: "sort", In: ir.ParameterInQuery, Style: "pipeDelimited"},
+		},
+	}
+	paramMap := map[string]ir.ParamMapping{
+		"tags": {OpenAPIName: "tags", Location: ir.ParameterInQuery},
+		"ids":  {OpenAPIName: "ids", Location: ir.ParameterInQuery},
+		"sort": {OpenAPIName: "sort", Location: ir.ParameterInQuery},
+	}
+
+	req, err := executor.NewRequestBuilder(route, paramMap, "https://api.example.com").Build(context.Background(), map[string]interface{}{
+		"tags": []interface{}{"red", "blue"},
+		"ids":  []interface{}{1, 2},
+		"sort": []interface{}{"name", "age"},
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	query := req.URL.Query()
+	if got := query["tags"]; !reflect.DeepEqual(got, []string{"red", "blue"}) {
+		t.Fatalf("expected repeated tags, got %v", got)
+	}
+	if got := query["ids"]; !reflect.DeepEqual(got, []string{"1", "2"}) {
+		t.Fatalf("expected repeated ids, got %v", got)
+	}
+	if got := query["sort"]; !reflect.DeepEqual(got, []string{"name|age"}) {
+		t.Fatalf("expected non-exploded sort to stay pipe-delimited, got %v", got)
+	}
+}
+
+func TestRequestBuilderFlattensNestedDeepObjectFormFields(t *testing.T) {
+	noExplode := false
+	route := ir.HTTPRoute{
+		Path:   "/users",
+		Method: "POST",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{
+				"application/x-www-form-urlencoded": {
+					"type": "object",
+					"properties": map[string]interface{}{
+						"user":   map[string]interface{}{"type": "object"},
+						"filter": map[string]interface{}{"type": "object"},
+					},
+				},
+			},
+			Encodings: map[string]map[string]ir.EncodingInfo{
+				"application/x-www-form-urlencoded": {
+					"user":   {Style: "deepObject"},
+					"filter": {Style: "deepObject", Explode: &noExplode},
+				},
+			},
+		},
+	}
+
+	req, err := executor.NewRequestBuilder(route, nil, "https://api.example.com").Build(context.Background(), map[string]interface{}{
+		"user": map[string]interface{}{
+			"name":    "Ada",
+			"address": map[string]interface{}{"city": "London", "zip": "N1"},
+			"tags":    []interface{}{"admin", "ops"},
+		},
+		"filter": map[string]interface{}{
+			"ids": []interface{}{float64(1), float64(2)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	data, _ := io.ReadAll(req.Body)
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		t.Fatalf("invalid form body %q: %v", data, err)
+	}
+	expected := url.Values{
+		"user[name]":          {"Ada"},
+		"user[address][city]": {"London"},
+		"user[address][zip]":  {"N1"},
+		"user[tags]":          {"admin", "ops"},
+		"filter[ids][0]":      {"1"},
+		"filter[ids][1]":      {"2"},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Fatalf("unexpected form fields %v", values)
+	}
+}
+
+func TestRequestBuilderCookieParameters(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/session",
+		Method: "GET",
+		Parameters: []ir.ParameterInfo{
+			{Name: "features", In: ir.ParameterInCookie, Schema: ir.Schema{"type": "array", "items": map[string]interface{}{"type": "string"}}},
+			{Name: "sid", In: ir.ParameterInCookie, Schema: ir.Schema{"type": "string"}, Cookie: &ir.CookieAttributes{Secure: true, SameSite: "Strict"}},
+		},
+	}
+	paramMap := map[string]ir.ParamMapping{
+		"features": {OpenAPIName: "features", Location: ir.ParameterInCookie},
+		"sid":      {OpenAPIName: "sid", Location: ir.ParameterInCookie},
+	}
+	args := map[string]interface{}{"features": []interface{}{"beta", "dark"}, "sid": "abc"}
+
+	req, err := executor.NewRequestBuilder(route, paramMap, "https://api.example.com").
+		WithJoinedCookieArrays(true).
+		Build(context.Background(), args)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	cookies := make(map[string]string)
+	for _, cookie := range req.Cookies() {
+		cookies[cookie.Name] = cookie.Value
+	}
+	if len(req.Cookies()) != 2 || cookies["features"] != "beta,dark" || cookies["sid"] != "abc" {
+		t.Fatalf("expected one joined features cookie, got %q", req.Header.Get("Cookie"))
+	}
+
+	_, err = executor.NewRequestBuilder(route, paramMap, "http://api.example.com").Build(context.Background(), args)
+	if err == nil || !strings.Contains(err.Error(), `"sid" is declared secure`) {
+		t.Fatalf("expected the secure cookie to be refused over http, got %v", err)
+	}
+}
+
+func TestRequestBuilderMaxURLLength(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/search",
+		Method: "POST",
+		Parameters: []ir.ParameterInfo{
+			{Name: "ids", In: ir.ParameterInQuery, Schema: ir.Schema{"type": "array", "items": map[string]interface{}{"type": "string"}}},
+			{Name: "q", In: ir.ParameterInQuery, Schema: ir.Schema{"type": "string"}},
+		},
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{
+				"application/json": {
+					"type": "object",
+					"properties": map[string]interface{}{
+						"ids":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+						"limit": map[string]interface{}{"type": "integer"},
+					},
+				},
+			},
+		},
+	}
+	paramMap := map[string]ir.ParamMapping{
+		"ids":   {OpenAPIName: "ids", Location: ir.ParameterInQuery},
+		"q":     {OpenAPIName: "q", Location: ir.ParameterInQuery},
+		"limit": {OpenAPIName: "limit", Location: "body"},
+	}
+	ids := make([]interface{}, 50)
+	for i := range ids {
+		ids[i] = strings.Repeat("x", 20)
+	}
+
+	req, err := executor.NewRequestBuilder(route, paramMap, "https://api.example.com").
+		WithMaxURLLength(200).
+		Build(context.Background(), map[string]interface{}{"ids": ids, "q": "cats", "limit": 5})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if got := req.URL.String(); got != "https://api.example.com/search?q=cats" {
+		t.Fatalf("expected ids to leave the query, got %s", got)
+	}
+	data, _ := io.ReadAll(req.Body)
+	if !strings.Contains(string(data), `"ids":["xxxxxxxxxxxxxxxxxxxx"`) {
+		t.Fatalf("expected ids in the body, got %s", data)
+	}
+
+	route.RequestBody = nil
+	_, err = executor.NewRequestBuilder(route, paramMap, "https://api.example.com").
+		WithMaxURLLength(200).
+		Build(context.Background(), map[string]interface{}{"ids": ids, "q": "cats"})
+	if err == nil || !strings.Contains(err.Error(), `over the 200 byte limit; query parameter "ids"`) {
+		t.Fatalf("expected an error naming ids, got %v", err)
+	}
+}
+
+func TestRequestBuilderUnwrapSingleProperty(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/import",
+		Method: "POST",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{
+				"application/json": {
+					"type": "object",
+					"properties": map[string]interface{}{
+						"items": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "integer"}},
+					},
+				},
+			},
+		},
+	}
+	paramMap := map[string]ir.ParamMapping{"items": {OpenAPIName: "items", Location: "body"}}
+	args := map[string]interface{}{"items": []interface{}{1, 2}}
+
+	req, err := executor.NewRequestBuilder(route, paramMap, "https://api.example.com").Build(context.Background(), args)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if data, _ := io.ReadAll(req.Body); string(data) != `{"items":[1,2]}` {
+		t.Fatalf("expected the declared object body, got %s", data)
+	}
+
+	req, err = executor.NewRequestBuilder(route, paramMap, "https://api.example.com").
+		WithUnwrapSingleProperty(true).
+		Build(context.Background(), args)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if data, _ := io.ReadAll(req.Body); string(data) != `[1,2]` {
+		t.Fatalf("expected the bare array body, got %s", data)
+	}
+}
+
+func TestRequestBuilderFillsConstValues(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/users",
+		Method: "POST",
+		Parameters: []ir.ParameterInfo{
+			{Name: "api-version", In: ir.ParameterInHeader, Schema: ir.Schema{"type": "string", "const": "2024-01-01"}},
+		},
+		RequestBody: &ir.RequestBodyInfo{
+			Required: true,
+			ContentSchemas: map[string]ir.Schema{
+				"application/json": {
+					"type": "object",
+					"properties": map[string]interface{}{
+						"kind": map[string]interface{}{"type": "string", "const": "user"},
+						"name": map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+	paramMap := map[string]ir.ParamMapping{
+		"api-version": {OpenAPIName: "api-version", Location: ir.ParameterInHeader},
+		"kind":        {OpenAPIName: "kind", Location: "body"},
+		"name":        {OpenAPIName: "name", Location: "body"},
+	}
+
+	req, err := executor.NewRequestBuilder(route, paramMap, "https://api.example.com").
+		Build(context.Background(), map[string]interface{}{"name": "Ada", "kind": "admin"})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if got := req.Header.Get("api-version"); got != "2024-01-01" {
+		t.Fatalf("expected the const header, got %q", got)
+	}
+	if data, _ := io.ReadAll(req.Body); string(data) != `{"kind":"user","name":"Ada"}` {
+		t.Fatalf("expected the const body property, got %s", data)
+	}
+}
+
+func TestRequestBuilderRejectsPlaceholderWithoutParameter(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/users/{userId}",
+		Method: "GET",
+		Parameters: []ir.ParameterInfo{
+			{Name: "id", In: ir.ParameterInPath, Required: true, Schema: ir.Schema{"type": "string"}},
+		},
+	}
+	paramMap := map[string]ir.ParamMapping{
+		"id": {OpenAPIName: "id", Location: ir.ParameterInPath},
+	}
+
+	_, err := executor.NewRequestBuilder(route, paramMap, "https://api.example.com").Build(context.Background(), map[string]interface{}{"id": "42"})
+	if err == nil || !strings.Contains(err.Error(), "{userId}") {
+		t.Fatalf("expected an error naming the unfilled placeholder, got %v", err)
+	}
+}
+
+func TestRequestBuilderSendsEmptyQueryValues(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/logs",
+		Method: "GET",
+		Parameters: []ir.ParameterInfo{
+			{Name: "verbose", In: ir.ParameterInQuery, AllowEmptyValue: true, Schema: ir.Schema{"type": "string"}},
+			{Name: "filter", In: ir.ParameterInQuery, Schema: ir.Schema{"type": "string"}},
+		},
+	}
+	paramMap := map[string]ir.ParamMapping{
+		"verbose": {OpenAPIName: "verbose", Location: ir.ParameterInQuery},
+		"filter":  {OpenAPIName: "filter", Location: ir.ParameterInQuery},
+	}
+
+	req, err := executor.NewRequestBuilder(route, paramMap, "https://api.example.com").
+		Build(context.Background(), map[string]interface{}{"verbose": "", "filter": ""})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if got := req.URL.Query(); !got.Has("verbose") || !got.Has("filter") || got.Get("verbose") != "" || got.Get("filter") != "" {
+		t.Fatalf("expected explicit empty values to be sent as name=, got %q", req.URL.RawQuery)
+	}
+
+	req, err = executor.NewRequestBuilder(route, paramMap, "https://api.example.com").
+		Build(context.Background(), map[string]interface{}{"filter": "error"})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if got := req.URL.RawQuery; got != "filter=error" {
+		t.Fatalf("expected the omitted flag to stay absent, got %q", got)
+	}
+}
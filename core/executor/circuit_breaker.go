@@ -0,0 +1,100 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitOpenError is returned without contacting the upstream while a host's
+// circuit is open. It is always retryable once RetryAfter has elapsed.
+type CircuitOpenError struct {
+	Host       string
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit open for %s: upstream failing, retry after %s", e.Host, e.RetryAfter.Round(time.Second))
+}
+
+// circuitBreaker tracks consecutive failures per host. After threshold failures
+// the circuit opens for cooldown, then a single trial request is let through:
+// success closes the circuit, failure reopens it.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	hosts     map[string]*hostCircuit
+	now       func() time.Time
+}
+
+type hostCircuit struct {
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		hosts:     make(map[string]*hostCircuit),
+		now:       time.Now,
+	}
+}
+
+func (cb *circuitBreaker) allow(host string) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	state := cb.hosts[host]
+	if state == nil || state.failures < cb.threshold {
+		return nil
+	}
+	if remaining := state.openedAt.Add(cb.cooldown).Sub(cb.now()); remaining > 0 {
+		return &CircuitOpenError{Host: host, RetryAfter: remaining}
+	}
+	if state.trial {
+		return &CircuitOpenError{Host: host, RetryAfter: cb.cooldown}
+	}
+	state.trial = true
+	return nil
+}
+
+func (cb *circuitBreaker) record(host string, resp *http.Response, err error) {
+	if err != nil && errors.Is(err, context.Canceled) {
+		cb.release(host)
+		return
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if err == nil && resp != nil && resp.StatusCode < 500 {
+		delete(cb.hosts, host)
+		return
+	}
+
+	state := cb.hosts[host]
+	if state == nil {
+		state = &hostCircuit{}
+		cb.hosts[host] = state
+	}
+	state.failures++
+	state.trial = false
+	if state.failures >= cb.threshold {
+		state.openedAt = cb.now()
+	}
+}
+
+// release frees a trial slot when the caller gave up before the upstream answered.
+func (cb *circuitBreaker) release(host string) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if state := cb.hosts[host]; state != nil {
+		state.trial = false
+	}
+}
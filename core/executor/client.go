@@ -1,23 +1,73 @@
 package executor
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"runtime/debug"
+	"strings"
 	"time"
 )
 
+// modulePath is the module whose version the default User-Agent reports.
+const modulePath = "github.com/specx2/openapi-mcp"
+
+// Version is the openapi-mcp release reported in the default User-Agent, read
+// from the build info of the binary. Builds that do not record a module
+// version, such as those from a source checkout, report "devel".
+var Version = moduleVersion()
+
+// DefaultUserAgent identifies requests sent by DefaultHTTPClient.
+var DefaultUserAgent = "openapi-mcp/" + Version
+
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	module := &info.Main
+	if module.Path != modulePath {
+		module = nil
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				module = dep
+				break
+			}
+		}
+	}
+	if module == nil || module.Version == "" || module.Version == "(devel)" {
+		return "devel"
+	}
+	return strings.TrimPrefix(module.Version, "v")
+}
+
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// RequestInterceptor inspects or mutates a fully built request right before it
+// is sent, e.g. to add correlation IDs or signatures. Returning an error aborts the call.
+type RequestInterceptor func(req *http.Request) error
+
 type DefaultHTTPClient struct {
-	client  *http.Client
-	headers http.Header
+	client       *http.Client
+	headers      http.Header
+	interceptors []RequestInterceptor
+	breaker      *circuitBreaker
+	limiter      *rateLimiter
+	userAgent    string
+	signer       RequestSigner
+	compressed   bool
 }
 
 func NewDefaultHTTPClient() *DefaultHTTPClient {
 	return &DefaultHTTPClient{
-		client:  &http.Client{},
-		headers: make(http.Header),
+		client:    &http.Client{},
+		headers:   make(http.Header),
+		userAgent: DefaultUserAgent,
 	}
 }
 
@@ -29,7 +79,60 @@ func (c *DefaultHTTPClient) Do(req *http.Request) (*http.Response, error) {
 			}
 		}
 	}
-	return c.client.Do(req)
+	// 显式声明压缩支持后 Transport 不再自动解压，由 ResponseProcessor 负责解码
+	if c.compressed && req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+	}
+	for _, intercept := range c.interceptors {
+		if err := intercept(req); err != nil {
+			return nil, err
+		}
+	}
+	c.applyUserAgent(req)
+	if c.signer != nil {
+		if err := c.signer.Sign(req); err != nil {
+			return nil, fmt.Errorf("failed to sign request: %w", err)
+		}
+	}
+
+	// The cache is consulted only now, so its key covers the headers that
+	// would be sent, and a hit neither waits for the limiter nor counts
+	// towards the breaker.
+	lookup := responseCacheLookupFrom(req.Context())
+	if lookup != nil {
+		if resp, ok := lookup.get(req); ok {
+			return resp, nil
+		}
+	}
+	if c.limiter != nil {
+		if err := c.limiter.wait(req.Context(), req.URL.Host); err != nil {
+			return nil, err
+		}
+	}
+	if c.breaker != nil {
+		if err := c.breaker.allow(req.URL.Host); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := c.client.Do(req)
+	if c.breaker != nil {
+		c.breaker.record(req.URL.Host, resp, err)
+	}
+	if c.limiter != nil {
+		c.limiter.observe(req.URL.Host, resp)
+	}
+	if lookup != nil && err == nil {
+		resp = lookup.store(req, resp)
+	}
+	return resp, err
+}
+
+// WithCompressedResponses asks upstreams for gzip or deflate bodies, which the
+// response processor decodes. Without it net/http asks for gzip on its own and
+// decodes it transparently.
+func (c *DefaultHTTPClient) WithCompressedResponses() *DefaultHTTPClient {
+	c.compressed = true
+	return c
 }
 
 func (c *DefaultHTTPClient) WithTimeout(timeout time.Duration) *DefaultHTTPClient {
@@ -46,6 +149,163 @@ func (c *DefaultHTTPClient) WithHeaders(headers http.Header) *DefaultHTTPClient
 	return c
 }
 
+// WithUserAgent replaces the product token sent as User-Agent; an empty value
+// leaves the header to the request, or to Go's default.
+func (c *DefaultHTTPClient) WithUserAgent(userAgent string) *DefaultHTTPClient {
+	c.userAgent = strings.TrimSpace(userAgent)
+	return c
+}
+
+// applyUserAgent runs after the interceptors. A User-Agent they, the spec or the
+// default headers already set is kept, and the product token is appended to it.
+func (c *DefaultHTTPClient) applyUserAgent(req *http.Request) {
+	if c.userAgent == "" {
+		return
+	}
+	current := req.Header.Get("User-Agent")
+	switch {
+	case current == "":
+		req.Header.Set("User-Agent", c.userAgent)
+	case !strings.Contains(current, c.userAgent):
+		req.Header.Set("User-Agent", current+" "+c.userAgent)
+	}
+}
+
+// WithInterceptor appends interceptors, which run in order after default headers are applied.
+func (c *DefaultHTTPClient) WithInterceptor(interceptors ...RequestInterceptor) *DefaultHTTPClient {
+	for _, intercept := range interceptors {
+		if intercept != nil {
+			c.interceptors = append(c.interceptors, intercept)
+		}
+	}
+	return c
+}
+
+// WithRequestSigner signs every request last, after interceptors and the
+// User-Agent, so the signature covers the headers that are actually sent.
+func (c *DefaultHTTPClient) WithRequestSigner(signer RequestSigner) *DefaultHTTPClient {
+	c.signer = signer
+	return c
+}
+
+// WithCircuitBreaker fails fast with a retryable *CircuitOpenError for cooldown
+// after threshold consecutive transport errors or 5xx responses from a host,
+// then lets one trial request through. A threshold below 1 disables it.
+func (c *DefaultHTTPClient) WithCircuitBreaker(threshold int, cooldown time.Duration) *DefaultHTTPClient {
+	if threshold < 1 {
+		c.breaker = nil
+		return c
+	}
+	c.breaker = newCircuitBreaker(threshold, cooldown)
+	return c
+}
+
+// WithRateLimit throttles requests per host to perSecond with the given burst and
+// pauses a host while the upstream reports an exhausted quota via
+// X-RateLimit-Remaining/X-RateLimit-Reset or a 429/503 Retry-After. Waiting honours
+// request cancellation. perSecond <= 0 keeps only the header-driven pauses.
+func (c *DefaultHTTPClient) WithRateLimit(perSecond float64, burst int) *DefaultHTTPClient {
+	c.limiter = newRateLimiter(perSecond, burst)
+	return c
+}
+
+// WithRedirectPolicy limits how many redirects are followed; with maxRedirects 0
+// the 3xx response itself is returned. When stripAuthOnHostChange is set,
+// Authorization, Proxy-Authorization and Cookie headers are dropped once a redirect
+// leaves the original host (including moves to a subdomain or another port).
+func (c *DefaultHTTPClient) WithRedirectPolicy(maxRedirects int, stripAuthOnHostChange bool) *DefaultHTTPClient {
+	c.client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if maxRedirects <= 0 {
+			return http.ErrUseLastResponse
+		}
+		if len(via) > maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		if stripAuthOnHostChange && len(via) > 0 && !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+			req.Header.Del("Authorization")
+			req.Header.Del("Proxy-Authorization")
+			req.Header.Del("Cookie")
+		}
+		return nil
+	}
+	return c
+}
+
+// WithCookieJar keeps cookies set by upstream responses and sends them on later
+// requests to the same host, so login-then-call flows share a session. The client
+// stays stateless unless this is enabled.
+func (c *DefaultHTTPClient) WithCookieJar() *DefaultHTTPClient {
+	jar, _ := cookiejar.New(nil)
+	c.client.Jar = jar
+	return c
+}
+
+// WithTLSConfig uses a copy of cfg for upstream connections. Only the transport's
+// TLS settings change, so timeouts, headers and other client options are kept.
+func (c *DefaultHTTPClient) WithTLSConfig(cfg *tls.Config) *DefaultHTTPClient {
+	if cfg == nil {
+		return c
+	}
+	c.transport().TLSClientConfig = cfg.Clone()
+	return c
+}
+
+// WithClientCertificate presents the PEM certificate/key pair for mutual TLS.
+func (c *DefaultHTTPClient) WithClientCertificate(certFile, keyFile string) (*DefaultHTTPClient, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return c, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	cfg := c.tlsConfig()
+	cfg.Certificates = append(cfg.Certificates, cert)
+	return c, nil
+}
+
+// WithRootCAs trusts the CA certificates in the given PEM files in addition to
+// the system pool, for upstreams signed by a private CA.
+func (c *DefaultHTTPClient) WithRootCAs(pemPaths ...string) (*DefaultHTTPClient, error) {
+	cfg := c.tlsConfig()
+	// The pool may be shared with a caller's tls.Config, so add to a copy.
+	var pool *x509.CertPool
+	if cfg.RootCAs != nil {
+		pool = cfg.RootCAs.Clone()
+	} else if system, err := x509.SystemCertPool(); err == nil {
+		pool = system
+	} else {
+		pool = x509.NewCertPool()
+	}
+	for _, path := range pemPaths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return c, fmt.Errorf("failed to read CA file %s: %w", path, err)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return c, fmt.Errorf("no certificates found in CA file %s", path)
+		}
+	}
+	cfg.RootCAs = pool
+	return c, nil
+}
+
+// transport returns the client's *http.Transport, cloning the default transport
+// on first use so proxy, keep-alive and HTTP/2 settings stay intact.
+func (c *DefaultHTTPClient) transport() *http.Transport {
+	if t, ok := c.client.Transport.(*http.Transport); ok {
+		return t
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	c.client.Transport = t
+	return t
+}
+
+func (c *DefaultHTTPClient) tlsConfig() *tls.Config {
+	t := c.transport()
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	return t.TLSClientConfig
+}
+
 func (c *DefaultHTTPClient) Headers() http.Header {
 	return c.headers.Clone()
 }
@@ -0,0 +1,505 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func TestDefaultHTTPClientRunsInterceptors(t *testing.T) {
+	var received http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	var order []string
+	client := NewDefaultHTTPClient().
+		WithHeaders(http.Header{"X-Default": []string{"yes"}}).
+		WithInterceptor(
+			func(req *http.Request) error {
+				order = append(order, "first:"+req.Header.Get("X-Default"))
+				body, err := req.GetBody()
+				if err != nil {
+					return err
+				}
+				data, _ := io.ReadAll(body)
+				req.Header.Set("X-Signature", "sig-"+string(data))
+				return nil
+			},
+			func(req *http.Request) error {
+				order = append(order, "second:"+req.URL.Path)
+				return nil
+			},
+		)
+
+	req, _ := http.NewRequest(http.MethodPost, upstream.URL+"/items", strings.NewReader("payload"))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	if strings.Join(order, ",") != "first:yes,second:/items" {
+		t.Fatalf("unexpected interceptor order: %v", order)
+	}
+	if received.Get("X-Signature") != "sig-payload" {
+		t.Fatalf("expected signature header upstream, got %v", received)
+	}
+}
+
+func TestDefaultHTTPClientUserAgent(t *testing.T) {
+	var agents []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.Header.Get("User-Agent"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer upstream.Close()
+
+	send := func(client *DefaultHTTPClient, userAgent string) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, upstream.URL, nil)
+		if userAgent != "" {
+			req.Header.Set("User-Agent", userAgent)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	send(NewDefaultHTTPClient(), "")
+	send(NewDefaultHTTPClient(), "billing-sync/2.1")
+	send(NewDefaultHTTPClient().WithUserAgent("acme-gateway/1.0"), "")
+	send(NewDefaultHTTPClient().WithInterceptor(func(req *http.Request) error {
+		req.Header.Set("User-Agent", "signer/3")
+		return nil
+	}), "")
+
+	want := []string{
+		DefaultUserAgent,
+		"billing-sync/2.1 " + DefaultUserAgent,
+		"acme-gateway/1.0",
+		"signer/3 " + DefaultUserAgent,
+	}
+	for i := range want {
+		if agents[i] != want[i] {
+			t.Fatalf("request %d: expected User-Agent %q, got %q", i, want[i], agents[i])
+		}
+	}
+}
+
+func TestOpenAPIToolSurfacesInterceptorErrors(t *testing.T) {
+	client := NewDefaultHTTPClient().WithInterceptor(func(*http.Request) error {
+		return errors.New("signing key unavailable")
+	})
+	route := ir.HTTPRoute{Path: "/items", Method: "POST"}
+	tool := NewOpenAPITool("create", "", ir.Schema{"type": "object"}, nil, false, route, client, "http://127.0.0.1:1", nil, nil, nil)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{}
+
+	result, err := tool.Run(context.Background(), request)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("expected error result")
+	}
+	text, _ := result.Content[0].(mcp.TextContent)
+	if !strings.Contains(text.Text, "signing key unavailable") {
+		t.Fatalf("expected interceptor error in result, got %#v", result.Content)
+	}
+}
+
+func TestDefaultHTTPClientCircuitBreaker(t *testing.T) {
+	var calls int
+	status := http.StatusBadGateway
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+	}))
+	defer upstream.Close()
+
+	client := NewDefaultHTTPClient().WithCircuitBreaker(2, time.Minute)
+	now := time.Unix(1000, 0)
+	client.breaker.now = func() time.Time { return now }
+
+	do := func() error {
+		req, _ := http.NewRequest(http.MethodGet, upstream.URL, nil)
+		resp, err := client.Do(req)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := do(); err != nil {
+			t.Fatalf("expected upstream response while closed, got %v", err)
+		}
+	}
+
+	err := do()
+	var circuitErr *CircuitOpenError
+	if !errors.As(err, &circuitErr) || calls != 2 {
+		t.Fatalf("expected fast failure without contacting upstream, got %v after %d calls", err, calls)
+	}
+	result := NewErrorHandler("info").HandleHTTPError(err)
+	structured := result.StructuredContent.(map[string]interface{})
+	if structured["retryable"] != true || structured["circuitOpen"] != true || structured["retryAfterSeconds"] != float64(60) {
+		t.Fatalf("expected retryable circuit-open result, got %#v", structured)
+	}
+
+	now = now.Add(time.Minute)
+	status = http.StatusOK
+	if err := do(); err != nil || calls != 3 {
+		t.Fatalf("expected trial request after cooldown, got %v after %d calls", err, calls)
+	}
+	if err := do(); err != nil || calls != 4 {
+		t.Fatalf("expected circuit to close after successful trial, got %v", err)
+	}
+}
+
+func TestDefaultHTTPClientCookieJar(t *testing.T) {
+	var sessions []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cr3t", Path: "/"})
+			return
+		}
+		cookie, err := r.Cookie("session")
+		if err != nil {
+			sessions = append(sessions, "")
+			return
+		}
+		sessions = append(sessions, cookie.Value+";"+r.Header.Get("Cookie"))
+	}))
+	defer upstream.Close()
+
+	call := func(client *DefaultHTTPClient, path string, cookies ...*http.Cookie) {
+		req, _ := http.NewRequest(http.MethodGet, upstream.URL+path, nil)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Do returned error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	stateless := NewDefaultHTTPClient()
+	call(stateless, "/login")
+	call(stateless, "/me")
+
+	withJar := NewDefaultHTTPClient().WithCookieJar()
+	call(withJar, "/login")
+	call(withJar, "/me", &http.Cookie{Name: "tenant", Value: "acme"})
+
+	if len(sessions) != 2 || sessions[0] != "" {
+		t.Fatalf("expected no session without a jar, got %q", sessions)
+	}
+	if !strings.HasPrefix(sessions[1], "s3cr3t;") || !strings.Contains(sessions[1], "tenant=acme") {
+		t.Fatalf("expected jar cookie alongside request cookie, got %q", sessions[1])
+	}
+}
+
+func TestDefaultHTTPClientRedirectPolicy(t *testing.T) {
+	var storageAuth []string
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		storageAuth = append(storageAuth, r.Header.Get("Authorization"))
+	}))
+	defer storage.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, storage.URL+"/signed/file.bin", http.StatusFound)
+	}))
+	defer api.Close()
+
+	call := func(client *DefaultHTTPClient) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, api.URL+"/files/1", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Do returned error: %v", err)
+		}
+		return resp
+	}
+
+	resp := call(NewDefaultHTTPClient().WithRedirectPolicy(5, true))
+	resp.Body.Close()
+	if len(storageAuth) != 1 || storageAuth[0] != "" {
+		t.Fatalf("expected Authorization stripped on host change, got %q", storageAuth)
+	}
+
+	resp = call(NewDefaultHTTPClient().WithRedirectPolicy(0, false))
+	if resp.StatusCode != http.StatusFound || len(storageAuth) != 1 {
+		t.Fatalf("expected unfollowed 302, got %d", resp.StatusCode)
+	}
+	result, err := NewResponseProcessor(nil, false, nil).Process(resp)
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+	structured := result.StructuredContent.(map[string]interface{})
+	if structured["status"] != http.StatusFound || structured["location"] != storage.URL+"/signed/file.bin" {
+		t.Fatalf("expected redirect location in result, got %#v", structured)
+	}
+}
+
+func TestRateLimiterHonoursUpstreamHeaders(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := newRateLimiter(2, 2)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if delay := limiter.reserve("api"); delay != 0 {
+			t.Fatalf("expected burst request %d to pass, got delay %v", i, delay)
+		}
+	}
+	if delay := limiter.reserve("api"); delay != 500*time.Millisecond {
+		t.Fatalf("expected static rate delay of 500ms, got %v", delay)
+	}
+	if delay := limiter.reserve("other"); delay != 0 {
+		t.Fatalf("expected hosts to be limited independently, got %v", delay)
+	}
+
+	limiter.observe("api", &http.Response{StatusCode: http.StatusOK, Header: http.Header{
+		"X-Ratelimit-Remaining": []string{"0"},
+		"X-Ratelimit-Reset":     []string{"1700000030"},
+	}})
+	if delay := limiter.reserve("api"); delay != 30*time.Second {
+		t.Fatalf("expected pause until reset, got %v", delay)
+	}
+
+	limiter.observe("api", &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{
+		"Retry-After": []string{"60"},
+	}})
+	if delay := limiter.reserve("api"); delay != 60*time.Second {
+		t.Fatalf("expected Retry-After to extend the pause, got %v", delay)
+	}
+
+	now = now.Add(60 * time.Second)
+	if delay := limiter.reserve("api"); delay != 0 {
+		t.Fatalf("expected no extra wait once Retry-After elapsed, got %v", delay)
+	}
+}
+
+func TestDefaultHTTPClientRateLimitRespectsCancellation(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer upstream.Close()
+
+	client := NewDefaultHTTPClient().WithRateLimit(0, 0)
+	req, _ := http.NewRequest(http.MethodGet, upstream.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL, nil)
+	start := time.Now()
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected cancellation while paused, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected wait to stop on cancellation, took %v", elapsed)
+	}
+}
+
+func TestDefaultHTTPClientTrustsCustomRootCAs(t *testing.T) {
+	upstream := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("X-Default")))
+	}))
+	defer upstream.Close()
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: upstream.Certificate().Raw})
+	if err := os.WriteFile(caPath, caPEM, 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	untrusted := NewDefaultHTTPClient()
+	req, _ := http.NewRequest(http.MethodGet, upstream.URL, nil)
+	if _, err := untrusted.Do(req); err == nil {
+		t.Fatalf("expected certificate verification to fail without the CA")
+	}
+
+	client, err := NewDefaultHTTPClient().
+		WithTimeout(5 * time.Second).
+		WithHeaders(http.Header{"X-Default": []string{"kept"}}).
+		WithRootCAs(caPath)
+	if err != nil {
+		t.Fatalf("load CA failed: %v", err)
+	}
+	req, _ = http.NewRequest(http.MethodGet, upstream.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "kept" || client.Client().Timeout != 5*time.Second {
+		t.Fatalf("expected headers and timeout to survive TLS setup, got %q %v", body, client.Client().Timeout)
+	}
+
+	callerPool := x509.NewCertPool()
+	if _, err := NewDefaultHTTPClient().WithTLSConfig(&tls.Config{RootCAs: callerPool}).WithRootCAs(caPath); err != nil {
+		t.Fatalf("load CA failed: %v", err)
+	}
+	if !callerPool.Equal(x509.NewCertPool()) {
+		t.Fatalf("expected the caller's cert pool to be left untouched")
+	}
+
+	if _, err := NewDefaultHTTPClient().WithClientCertificate(filepath.Join(t.TempDir(), "missing.pem"), caPath); err == nil {
+		t.Fatalf("expected missing client certificate to fail")
+	}
+}
+
+func TestDefaultHTTPClientSendsRequestsThroughProxy(t *testing.T) {
+	var seenURL, seenAuth string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenURL = r.URL.String()
+		seenAuth = r.Header.Get("Proxy-Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+
+	client, err := NewDefaultHTTPClient().
+		WithTLSConfig(&tls.Config{ServerName: "api.example.com"}).
+		WithProxy(strings.Replace(proxy.URL, "http://", "http://user:secret@", 1), "internal.example.com")
+	if err != nil {
+		t.Fatalf("WithProxy: %v", err)
+	}
+	if client.transport().TLSClientConfig.ServerName != "api.example.com" {
+		t.Fatal("expected the TLS config to survive the proxy setting")
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://api.example.com/items", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if seenURL != "http://api.example.com/items" {
+		t.Fatalf("expected the proxy to receive the absolute URL, got %q", seenURL)
+	}
+	if seenAuth != "Basic dXNlcjpzZWNyZXQ=" {
+		t.Fatalf("expected proxy credentials, got %q", seenAuth)
+	}
+
+	bypassed, _ := http.NewRequest(http.MethodGet, "http://svc.internal.example.com/items", nil)
+	if target, _ := client.transport().Proxy(bypassed); target != nil {
+		t.Fatalf("expected NO_PROXY hosts to be reached directly, got %v", target)
+	}
+}
+
+func TestDefaultHTTPClientProxyFromEnvironment(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "plain-proxy:3128")
+	t.Setenv("HTTPS_PROXY", "https://secure-proxy:3129")
+	t.Setenv("NO_PROXY", ".corp.example, 10.0.0.0/8, api.example.com:8443")
+
+	client, err := NewDefaultHTTPClient().WithProxyFromEnvironment()
+	if err != nil {
+		t.Fatalf("WithProxyFromEnvironment: %v", err)
+	}
+	cases := map[string]string{
+		"http://api.example.com/":       "http://plain-proxy:3128",
+		"https://api.example.com/":      "https://secure-proxy:3129",
+		"https://api.example.com:8443/": "",
+		"http://git.corp.example/":      "",
+		"http://corp.example/":          "http://plain-proxy:3128",
+		"http://10.1.2.3/":              "",
+		"http://localhost:8080/":        "",
+	}
+	for target, want := range cases {
+		req, _ := http.NewRequest(http.MethodGet, target, nil)
+		proxy, err := client.transport().Proxy(req)
+		if err != nil {
+			t.Fatalf("%s: %v", target, err)
+		}
+		got := ""
+		if proxy != nil {
+			got = proxy.String()
+		}
+		if got != want {
+			t.Fatalf("%s: expected proxy %q, got %q", target, want, got)
+		}
+	}
+
+	t.Setenv("HTTP_PROXY", "ftp://proxy")
+	if _, err := NewDefaultHTTPClient().WithProxyFromEnvironment(); err == nil {
+		t.Fatal("expected an unsupported proxy scheme to be rejected")
+	}
+}
+
+func TestDefaultHTTPClientSelectsHTTPProtocol(t *testing.T) {
+	proto := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	})
+	get := func(client *DefaultHTTPClient, url string) string {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	secure := httptest.NewUnstartedServer(proto)
+	secure.EnableHTTP2 = true
+	secure.StartTLS()
+	defer secure.Close()
+	trusted := secure.Client().Transport.(*http.Transport).TLSClientConfig
+
+	if got := get(NewDefaultHTTPClient().WithTLSConfig(trusted).WithHTTP2(true), secure.URL); got != "HTTP/2.0" {
+		t.Fatalf("expected HTTP/2 with a custom TLS config, got %s", got)
+	}
+	if got := get(NewDefaultHTTPClient().WithTLSConfig(trusted).WithHTTP2(false), secure.URL); got != "HTTP/1.1" {
+		t.Fatalf("expected HTTP/1.1 with HTTP/2 disabled, got %s", got)
+	}
+
+	cleartext := httptest.NewUnstartedServer(proto)
+	cleartext.Config.Protocols = new(http.Protocols)
+	cleartext.Config.Protocols.SetHTTP1(true)
+	cleartext.Config.Protocols.SetUnencryptedHTTP2(true)
+	cleartext.Start()
+	defer cleartext.Close()
+
+	if got := get(NewDefaultHTTPClient(), cleartext.URL); got != "HTTP/1.1" {
+		t.Fatalf("expected HTTP/1.1 by default, got %s", got)
+	}
+	client, err := NewDefaultHTTPClient().WithH2C(true).WithProxy("http://proxy.invalid:3128")
+	if err != nil {
+		t.Fatalf("WithProxy: %v", err)
+	}
+	if got := get(client, cleartext.URL); got != "HTTP/2.0" {
+		t.Fatalf("expected h2c to a loopback upstream past the proxy, got %s", got)
+	}
+	if got := get(client.WithH2C(false), cleartext.URL); got != "HTTP/1.1" {
+		t.Fatalf("expected HTTP/1.1 after disabling h2c, got %s", got)
+	}
+}
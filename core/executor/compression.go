@@ -0,0 +1,124 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// decodeContentEncoding swaps a gzip/deflate encoded response body for a decoding
+// reader. Once decoded the Content-Encoding header is dropped so repeated calls are no-ops.
+func decodeContentEncoding(resp *http.Response) error {
+	if resp == nil || resp.Body == nil {
+		return nil
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	var reader io.ReadCloser
+	switch encoding {
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			if err == io.EOF {
+				resp.Header.Del("Content-Encoding")
+				return nil
+			}
+			return fmt.Errorf("failed to decode gzip response: %w", err)
+		}
+		reader = gz
+	case "deflate":
+		deflate, err := newDeflateReader(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to decode deflate response: %w", err)
+		}
+		reader = deflate
+	default:
+		return nil
+	}
+
+	resp.Body = &decodedBody{Reader: reader, decoder: reader, source: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// newDeflateReader reads HTTP deflate, which RFC 9110 defines as zlib-wrapped
+// data. Some servers send raw deflate instead, so a stream without a zlib
+// header is read as raw deflate.
+func newDeflateReader(body io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(body)
+	header, err := buffered.Peek(2)
+	if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(buffered)
+	}
+	return flate.NewReader(buffered), nil
+}
+
+type decodedBody struct {
+	io.Reader
+	decoder io.Closer
+	source  io.Closer
+}
+
+func (b *decodedBody) Close() error {
+	decodeErr := b.decoder.Close()
+	if err := b.source.Close(); err != nil {
+		return err
+	}
+	return decodeErr
+}
+
+// compressRequestBody gzips bodies at or above minBytes for content types that
+// upstreams commonly accept compressed. Multipart and form bodies are left alone.
+func compressRequestBody(body io.Reader, contentType string, minBytes int) (io.Reader, bool, error) {
+	if body == nil || minBytes <= 0 || !compressibleContentType(contentType) {
+		return body, false, nil
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if len(data) < minBytes {
+		return bytes.NewReader(data), false, nil
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return nil, false, fmt.Errorf("failed to gzip request body: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, false, fmt.Errorf("failed to gzip request body: %w", err)
+	}
+	return bytes.NewReader(buf.Bytes()), true, nil
+}
+
+func compressibleContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	switch {
+	case mediaType == "":
+		return false
+	case strings.HasPrefix(mediaType, "multipart/"), mediaType == "application/x-www-form-urlencoded":
+		return false
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	case strings.HasSuffix(mediaType, "/json"), strings.HasSuffix(mediaType, "+json"):
+		return true
+	case strings.HasSuffix(mediaType, "/xml"), strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package executor
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func TestResponseProcessorDecodesGzipBody(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, _ = gz.Write([]byte(`{"id":7}`))
+	_ = gz.Close()
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header: http.Header{
+			"Content-Type":     []string{"application/json"},
+			"Content-Encoding": []string{"gzip"},
+		},
+		Body: io.NopCloser(&buf),
+	}
+
+	result, err := NewResponseProcessor(nil, false, nil).Process(resp)
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+	structured, ok := result.StructuredContent.(map[string]interface{})
+	if !ok || structured["id"] != float64(7) {
+		t.Fatalf("expected decoded JSON body, got %#v", result.StructuredContent)
+	}
+}
+
+func TestResponseProcessorDecodesZlibAndRawDeflateBodies(t *testing.T) {
+	var wrapped, raw bytes.Buffer
+	zw := zlib.NewWriter(&wrapped)
+	_, _ = zw.Write([]byte(`{"id":7}`))
+	_ = zw.Close()
+	fw, _ := flate.NewWriter(&raw, flate.DefaultCompression)
+	_, _ = fw.Write([]byte(`{"id":7}`))
+	_ = fw.Close()
+
+	for name, body := range map[string]*bytes.Buffer{"zlib": &wrapped, "raw": &raw} {
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "200 OK",
+			Header: http.Header{
+				"Content-Type":     []string{"application/json"},
+				"Content-Encoding": []string{"deflate"},
+			},
+			Body: io.NopCloser(body),
+		}
+		result, err := NewResponseProcessor(nil, false, nil).Process(resp)
+		if err != nil {
+			t.Fatalf("%s: process failed: %v", name, err)
+		}
+		structured, ok := result.StructuredContent.(map[string]interface{})
+		if !ok || structured["id"] != float64(7) {
+			t.Fatalf("%s: expected decoded JSON body, got %#v", name, result.StructuredContent)
+		}
+	}
+}
+
+func TestDefaultHTTPClientAsksForCompressionOnlyWhenEnabled(t *testing.T) {
+	var seen []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Accept-Encoding"))
+	}))
+	defer upstream.Close()
+
+	for _, client := range []*DefaultHTTPClient{NewDefaultHTTPClient(), NewDefaultHTTPClient().WithCompressedResponses()} {
+		req, _ := http.NewRequest(http.MethodGet, upstream.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+	// net/http adds its own gzip request when the header is left unset
+	if len(seen) != 2 || seen[0] != "gzip" || seen[1] != "gzip, deflate" {
+		t.Fatalf("unexpected Accept-Encoding headers %q", seen)
+	}
+}
+
+func TestRequestBuilderCompressesLargeJSONBodies(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/notes",
+		Method: "POST",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{
+				"application/json": {"type": "object", "properties": map[string]interface{}{
+					"text": map[string]interface{}{"type": "string"},
+					"tag":  map[string]interface{}{"type": "string"},
+				}},
+			},
+		},
+	}
+	args := map[string]interface{}{"text": strings.Repeat("a", 256), "tag": "x"}
+
+	req, err := NewRequestBuilder(route, nil, "https://api.example.com").
+		WithRequestCompression(64).
+		Build(context.Background(), args)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if req.Header.Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip content encoding, got %q", req.Header.Get("Content-Encoding"))
+	}
+
+	compressed, _ := io.ReadAll(req.Body)
+	if req.ContentLength != int64(len(compressed)) {
+		t.Fatalf("expected content length %d to match compressed body %d", req.ContentLength, len(compressed))
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("body is not gzip: %v", err)
+	}
+	plain, _ := io.ReadAll(reader)
+	if !strings.Contains(string(plain), `"tag":"x"`) {
+		t.Fatalf("unexpected decompressed body %q", plain)
+	}
+
+	req, err = NewRequestBuilder(route, nil, "https://api.example.com").
+		WithRequestCompression(1024).
+		Build(context.Background(), args)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if req.Header.Get("Content-Encoding") != "" {
+		t.Fatalf("expected small body to stay uncompressed")
+	}
+}
+
+func TestCompressibleContentTypeSkipsFormBodies(t *testing.T) {
+	for _, ct := range []string{"multipart/form-data; boundary=x", "application/x-www-form-urlencoded", "application/octet-stream"} {
+		if compressibleContentType(ct) {
+			t.Fatalf("expected %s to be left uncompressed", ct)
+		}
+	}
+	for _, ct := range []string{"application/json", "application/vnd.api+json", "text/plain; charset=utf-8", "application/xml"} {
+		if !compressibleContentType(ct) {
+			t.Fatalf("expected %s to be compressible", ct)
+		}
+	}
+}
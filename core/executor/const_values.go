@@ -0,0 +1,46 @@
+package executor
+
+import "github.com/specx2/openapi-mcp/core/ir"
+
+// withConstParameters returns args with every parameter whose schema has a
+// `const` set to that value. Such parameters are left out of the input schema,
+// so a value passed anyway is replaced.
+func (rb *RequestBuilder) withConstParameters(args map[string]interface{}) map[string]interface{} {
+	var filled map[string]interface{}
+	for argName, mapping := range rb.paramMap {
+		if mapping.Location == "body" {
+			continue
+		}
+		param := rb.findParameterInfo(mapping.OpenAPIName, mapping.Location)
+		if param == nil {
+			continue
+		}
+		value, ok := ConstValue(param.Schema, rb.route.SchemaDefs)
+		if !ok {
+			continue
+		}
+		if filled == nil {
+			filled = make(map[string]interface{}, len(args)+1)
+			for name, arg := range args {
+				filled[name] = arg
+			}
+		}
+		filled[argName] = value
+	}
+	if filled == nil {
+		return args
+	}
+	return filled
+}
+
+// ConstValue returns a copy of the schema's `const` value, following a $ref
+// into defs. Schema generation uses it to hide the parameters and body
+// properties that the request builder fills in.
+func ConstValue(schema ir.Schema, defs ir.Schema) (interface{}, bool) {
+	schema, _ = schemaResolver{defs: defs}.resolve(schema)
+	value, ok := schema["const"]
+	if !ok {
+		return nil, false
+	}
+	return cloneAnyValue(value), true
+}
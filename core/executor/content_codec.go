@@ -0,0 +1,66 @@
+package executor
+
+import (
+	"io"
+	"sync"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// ContentEncoder writes the body arguments of a request as mediaType, for
+// media types the builder has no encoding for such as protobuf or msgpack.
+// schema is the request body schema of the operation, if any. The returned
+// content type is sent as Content-Type; empty keeps the declared one.
+type ContentEncoder func(body map[string]interface{}, schema ir.Schema) (io.Reader, string, error)
+
+// ContentDecoder turns a response body of a registered media type into a
+// JSON-like value, which is then validated and returned like a JSON body.
+// schema is the output body schema of the operation, if any.
+type ContentDecoder func(body []byte, schema ir.Schema) (interface{}, error)
+
+var contentCodecs = struct {
+	sync.RWMutex
+	encoders map[string]ContentEncoder
+	decoders map[string]ContentDecoder
+}{
+	encoders: make(map[string]ContentEncoder),
+	decoders: make(map[string]ContentDecoder),
+}
+
+// RegisterContentEncoder makes request bodies of mediaType, matched without
+// parameters, go through enc instead of the built-in encodings. A nil enc
+// removes the registration.
+func RegisterContentEncoder(mediaType string, enc ContentEncoder) {
+	contentCodecs.Lock()
+	defer contentCodecs.Unlock()
+	if enc == nil {
+		delete(contentCodecs.encoders, baseMediaType(mediaType))
+		return
+	}
+	contentCodecs.encoders[baseMediaType(mediaType)] = enc
+}
+
+// RegisterContentDecoder makes responses of mediaType, matched without
+// parameters, go through dec instead of the built-in decoding. A nil dec
+// removes the registration.
+func RegisterContentDecoder(mediaType string, dec ContentDecoder) {
+	contentCodecs.Lock()
+	defer contentCodecs.Unlock()
+	if dec == nil {
+		delete(contentCodecs.decoders, baseMediaType(mediaType))
+		return
+	}
+	contentCodecs.decoders[baseMediaType(mediaType)] = dec
+}
+
+func lookupContentEncoder(contentType string) ContentEncoder {
+	contentCodecs.RLock()
+	defer contentCodecs.RUnlock()
+	return contentCodecs.encoders[baseMediaType(contentType)]
+}
+
+func lookupContentDecoder(contentType string) ContentDecoder {
+	contentCodecs.RLock()
+	defer contentCodecs.RUnlock()
+	return contentCodecs.decoders[baseMediaType(contentType)]
+}
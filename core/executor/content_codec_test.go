@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// encodeKV and decodeKV stand in for a proprietary format: one key=value per line.
+func encodeKV(body map[string]interface{}, schema ir.Schema) (io.Reader, string, error) {
+	var lines []string
+	for _, key := range sortedKeys(body) {
+		lines = append(lines, fmt.Sprintf("%s=%v", key, body[key]))
+	}
+	return strings.NewReader(strings.Join(lines, "\n")), "application/x-kv; version=1", nil
+}
+
+func decodeKV(body []byte, schema ir.Schema) (interface{}, error) {
+	decoded := make(map[string]interface{})
+	for _, line := range strings.Split(string(body), "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed line %q", line)
+		}
+		decoded[key] = value
+	}
+	return decoded, nil
+}
+
+func TestRegisteredContentEncoderBuildsBody(t *testing.T) {
+	RegisterContentEncoder("application/x-kv", encodeKV)
+	defer RegisterContentEncoder("application/x-kv", nil)
+
+	route := ir.HTTPRoute{
+		Path:   "/settings",
+		Method: "PUT",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{"application/x-kv": {
+				"type": "object",
+				"properties": map[string]interface{}{
+					"mode":  map[string]interface{}{"type": "string"},
+					"level": map[string]interface{}{"type": "integer"},
+				},
+			}},
+		},
+	}
+	req, err := NewRequestBuilder(route, nil, "https://api.example.com").Build(context.Background(), map[string]interface{}{
+		"mode":  "fast",
+		"level": float64(2),
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/x-kv; version=1" {
+		t.Fatalf("expected the encoder's content type, got %q", ct)
+	}
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != "level=2\nmode=fast" {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
+
+func TestRegisteredContentDecoderDecodesResponse(t *testing.T) {
+	RegisterContentDecoder("application/x-kv", decodeKV)
+	defer RegisterContentDecoder("application/x-kv", nil)
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/x-kv; version=1"}},
+		Body:       io.NopCloser(strings.NewReader("level=2\nmode=fast")),
+	}
+	result, err := NewResponseProcessor(nil, false, nil).Process(resp)
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+	expected := map[string]interface{}{"level": "2", "mode": "fast"}
+	if !reflect.DeepEqual(result.StructuredContent, expected) {
+		t.Fatalf("unexpected structured content: %#v", result.StructuredContent)
+	}
+}
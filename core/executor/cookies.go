@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// cookiesFor turns the encoded values of one cookie parameter into cookies. An
+// exploded array yields one cookie per value, all of the same name, unless joined
+// cookie arrays are enabled; then it is a single comma-separated cookie, as with
+// `explode: false`. A request carries only name=value pairs, so attributes
+// declared via x-cookie are not sent; Secure is enforced by checkSecureCookies.
+func (rb *RequestBuilder) cookiesFor(param ir.ParameterInfo, encoded []EncodedParameter) []*http.Cookie {
+	if rb.joinCookieArrays {
+		encoded = joinCookieValues(param.Name, encoded)
+	}
+
+	cookies := make([]*http.Cookie, 0, len(encoded))
+	for _, pair := range encoded {
+		cookies = append(cookies, &http.Cookie{Name: pair.Name, Value: pair.Value})
+	}
+	return cookies
+}
+
+// joinCookieValues merges repeated values of name into one comma-separated value
+// at the position of the first one.
+func joinCookieValues(name string, encoded []EncodedParameter) []EncodedParameter {
+	var values []string
+	for _, pair := range encoded {
+		if pair.Name == name {
+			values = append(values, pair.Value)
+		}
+	}
+	if len(values) < 2 {
+		return encoded
+	}
+
+	joined := make([]EncodedParameter, 0, len(encoded)-len(values)+1)
+	added := false
+	for _, pair := range encoded {
+		if pair.Name != name {
+			joined = append(joined, pair)
+			continue
+		}
+		if !added {
+			pair.Value = strings.Join(values, ",")
+			joined = append(joined, pair)
+			added = true
+		}
+	}
+	return joined
+}
+
+// checkSecureCookies refuses to send a cookie parameter declared Secure over
+// plain HTTP.
+func checkSecureCookies(secure []string, reqURL *url.URL) error {
+	if len(secure) == 0 || strings.EqualFold(reqURL.Scheme, "https") {
+		return nil
+	}
+	return fmt.Errorf("cookie parameter %q is declared secure and cannot be sent over %s", secure[0], reqURL.Scheme)
+}
@@ -0,0 +1,107 @@
+package executor
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// CSVOptions describes how text/csv responses are parsed.
+type CSVOptions struct {
+	// Delimiter separates fields; zero means a comma.
+	Delimiter rune
+	// HasHeader uses the first record as the keys of every row. Without a
+	// header each row is an array of its fields.
+	HasHeader bool
+}
+
+// DefaultCSVOptions parses comma-separated files with a header row.
+func DefaultCSVOptions() CSVOptions {
+	return CSVOptions{Delimiter: ',', HasHeader: true}
+}
+
+// processCSV returns the rows as {"result": rows}, validated against the
+// array schema when the spec declares one.
+func (rp *ResponseProcessor) processCSV(rows []interface{}, headers map[string]interface{}, meta *mcp.Meta) (*mcp.CallToolResult, error) {
+	toolResult, err := rp.processJSON(rows, headers)
+	if err != nil {
+		return nil, err
+	}
+	toolResult.Result.Meta = mergeMeta(toolResult.Result.Meta, meta)
+	return toolResult, nil
+}
+
+func isCSVContentType(contentType string) bool {
+	switch baseMediaType(contentType) {
+	case "text/csv", "application/csv", "text/tab-separated-values":
+		return true
+	}
+	return false
+}
+
+// decodeCSVBody reads body record by record, so the response size limit applies
+// while parsing. Values are typed by the item schema of an array body schema
+// when it declares the column, and stay strings otherwise.
+func decodeCSVBody(body io.Reader, options CSVOptions, contentType string, schema ir.Schema) ([]interface{}, error) {
+	reader := csv.NewReader(body)
+	reader.Comma = options.Delimiter
+	if reader.Comma == 0 {
+		reader.Comma = ','
+		if baseMediaType(contentType) == "text/tab-separated-values" {
+			reader.Comma = '\t'
+		}
+	}
+	reader.FieldsPerRecord = -1
+
+	itemSchema := schemaFromValue(schema["items"])
+	columns := itemSchema.Properties()
+
+	var header []string
+	rows := make([]interface{}, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("failed to parse CSV response: %w", err)
+			}
+			return nil, err
+		}
+
+		if options.HasHeader && header == nil {
+			header = record
+			continue
+		}
+
+		if !options.HasHeader {
+			row := make([]interface{}, len(record))
+			for i, field := range record {
+				row[i] = field
+			}
+			rows = append(rows, row)
+			continue
+		}
+
+		row := make(map[string]interface{}, len(record))
+		for i, field := range record {
+			key := "column" + strconv.Itoa(i+1)
+			if i < len(header) && header[i] != "" {
+				key = header[i]
+			}
+			var value interface{} = field
+			if columnSchema, ok := columns[key]; ok {
+				value, _ = coerceValueForSchema(field, columnSchema)
+			}
+			row[key] = value
+		}
+		rows = append(rows, row)
+	}
+}
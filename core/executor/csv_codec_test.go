@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func csvResponse(contentType, body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{contentType}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestResponseProcessorParsesCSVRows(t *testing.T) {
+	outputSchema := ir.Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"result": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"count": map[string]interface{}{"type": "integer"},
+					},
+				},
+			},
+		},
+	}
+	result, err := NewResponseProcessor(outputSchema, true, nil).
+		Process(csvResponse("text/csv; charset=utf-8", "name,count\nwidgets,3\n\"gears, large\",12\n"))
+	if err != nil {
+		t.Fatalf("Process returned error: %v", err)
+	}
+	want := []interface{}{
+		map[string]interface{}{"name": "widgets", "count": float64(3)},
+		map[string]interface{}{"name": "gears, large", "count": float64(12)},
+	}
+	structured := result.StructuredContent.(map[string]interface{})
+	if result.IsError || !reflect.DeepEqual(structured["result"], want) {
+		t.Fatalf("unexpected rows: %#v", result)
+	}
+
+	result, err = NewResponseProcessor(nil, false, nil).
+		WithCSVOptions(';', false).
+		Process(csvResponse("text/csv", "a;1\nb;2\n"))
+	if err != nil {
+		t.Fatalf("Process returned error: %v", err)
+	}
+	want = []interface{}{[]interface{}{"a", "1"}, []interface{}{"b", "2"}}
+	if got := result.StructuredContent.(map[string]interface{})["result"]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected headerless rows: %#v", got)
+	}
+}
+
+func TestResponseProcessorLimitsCSVBodies(t *testing.T) {
+	body := "id\n" + strings.Repeat("1\n", 100)
+	result, err := NewResponseProcessor(nil, false, nil).
+		WithMaxResponseBytes(32).
+		Process(csvResponse("text/csv", body))
+	if err != nil {
+		t.Fatalf("Process returned error: %v", err)
+	}
+	if !result.IsError || result.Meta.AdditionalFields["truncated"] != true {
+		t.Fatalf("expected a truncated error result, got %#v", result)
+	}
+}
+
+func TestResponseProcessorKeepsCSVTextForStringSchemas(t *testing.T) {
+	outputSchema := ir.Schema{
+		"type":       "object",
+		"properties": map[string]interface{}{"result": map[string]interface{}{"type": "string"}},
+	}
+	const body = "name,count\nwidgets,3"
+	for _, contentType := range []string{"text/csv", "application/csv"} {
+		result, err := NewResponseProcessor(outputSchema, true, nil).Process(csvResponse(contentType, body+"\n"))
+		if err != nil {
+			t.Fatalf("Process returned error: %v", err)
+		}
+		if result.IsError || result.StructuredContent.(map[string]interface{})["result"] != body {
+			t.Fatalf("expected the raw CSV text for %s, got %#v", contentType, result)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package executor
+
+import (
+	"strings"
+	"time"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// dateLayouts are the spellings accepted for date and date-time values. Numeric
+// day/month orders such as 01/02/2006 are left out because they are ambiguous.
+// Layouts without a zone are read as UTC.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	"20060102",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.ANSIC,
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+}
+
+// normalizeDateArguments rewrites string arguments whose parameter or body schema
+// has `format: date` or `date-time` into 2006-01-02 or RFC 3339 form. Values that
+// match no known layout are left for validation to report.
+func (t *OpenAPITool) normalizeDateArguments(args map[string]interface{}) {
+	resolver := schemaResolver{defs: t.route.SchemaDefs}
+
+	var bodyProperties map[string]ir.Schema
+	var body ir.Schema
+	if t.route.RequestBody != nil {
+		if contentType := t.requestContentType(); contentType != "" {
+			body, _ = resolver.resolve(t.route.RequestBody.ContentSchemas[contentType])
+			bodyProperties, _ = variantShape(body, resolver, 0)
+		}
+	}
+
+	for name, value := range args {
+		if value == nil {
+			continue
+		}
+		mapping, ok := t.paramMap[name]
+		if !ok {
+			continue
+		}
+
+		var schema ir.Schema
+		if mapping.Location == "body" {
+			schema, ok = bodyProperties[mapping.OpenAPIName]
+			if !ok && len(bodyProperties) == 0 {
+				schema = body
+			}
+		} else if param := t.findRouteParameter(mapping); param != nil {
+			schema = param.Schema
+		}
+		if schema == nil {
+			continue
+		}
+		if normalized, changed := resolver.walk(value, schema, normalizeDateLeaf); changed {
+			args[name] = normalized
+		}
+	}
+}
+
+// normalizeDateLeaf rewrites a string whose schema has a date format.
+func normalizeDateLeaf(value interface{}, schema ir.Schema) (interface{}, bool) {
+	if text, ok := value.(string); ok {
+		return normalizeDateString(text, dateFormat(schema))
+	}
+	return value, false
+}
+
+// dateFormat returns "date" or "date-time" for the schema, looking into the
+// variants of a nullable anyOf/oneOf wrapper.
+func dateFormat(schema ir.Schema) string {
+	if format, ok := schema["format"].(string); ok {
+		if format == "date" || format == "date-time" {
+			return format
+		}
+		return ""
+	}
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		variants, _ := schema[keyword].([]interface{})
+		for _, variant := range variants {
+			if format := dateFormat(schemaFromValue(variant)); format != "" {
+				return format
+			}
+		}
+	}
+	return ""
+}
+
+func normalizeDateString(value, format string) (interface{}, bool) {
+	if format == "" {
+		return value, false
+	}
+	trimmed := strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		parsed, err := time.Parse(layout, trimmed)
+		if err != nil {
+			continue
+		}
+		var normalized string
+		if format == "date" {
+			normalized = parsed.Format("2006-01-02")
+		} else {
+			normalized = parsed.Format(time.RFC3339Nano)
+		}
+		return normalized, normalized != value
+	}
+	return value, false
+}
@@ -0,0 +1,166 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// applyDiscriminators checks discriminated oneOf/anyOf body values against the
+// variant their discriminator selects, coercing scalar fields on the way.
+func (t *OpenAPITool) applyDiscriminators(args map[string]interface{}) error {
+	if t.route.RequestBody == nil {
+		return nil
+	}
+	contentType := t.requestContentType()
+	if contentType == "" {
+		return nil
+	}
+
+	resolver := schemaResolver{defs: t.route.SchemaDefs}
+	body, _ := resolver.resolve(t.route.RequestBody.ContentSchemas[contentType])
+	if body == nil {
+		return nil
+	}
+
+	properties := body.Properties()
+	for name, mapping := range t.paramMap {
+		if mapping.Location != "body" {
+			continue
+		}
+		obj, ok := args[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		schema := body
+		if len(properties) > 0 {
+			propSchema, ok := properties[mapping.OpenAPIName]
+			if !ok {
+				continue
+			}
+			schema, _ = resolver.resolve(propSchema)
+		}
+		if err := applyDiscriminator(name, obj, schema, resolver); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyDiscriminator(name string, obj map[string]interface{}, schema ir.Schema, resolver schemaResolver) error {
+	discriminator, ok := schema["discriminator"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	propertyName, _ := discriminator["propertyName"].(string)
+	if propertyName == "" {
+		return nil
+	}
+
+	variants, ok := schema["oneOf"].([]interface{})
+	if !ok {
+		if variants, ok = schema["anyOf"].([]interface{}); !ok {
+			return nil
+		}
+	}
+
+	selected, ok := obj[propertyName].(string)
+	if !ok || selected == "" {
+		return fmt.Errorf("%s: missing discriminator property %q", name, propertyName)
+	}
+
+	candidates := discriminatorTargets(discriminator, variants)
+	target, ok := candidates[selected]
+	if !ok {
+		accepted := make([]string, 0, len(candidates))
+		for value := range candidates {
+			accepted = append(accepted, value)
+		}
+		sort.Strings(accepted)
+		return fmt.Errorf("%s: unknown %s %q (expected one of %s)", name, propertyName, selected, strings.Join(accepted, ", "))
+	}
+
+	props, required := variantShape(ir.Schema{"$ref": target}, resolver, 0)
+	for key, value := range obj {
+		propSchema, ok := props[key]
+		if !ok || value == nil {
+			continue
+		}
+		propSchema, _ = resolver.resolve(propSchema)
+		if coerced, changed := coerceValueForSchema(value, propSchema); changed {
+			obj[key] = coerced
+		}
+	}
+	for _, key := range required {
+		if _, ok := obj[key]; !ok {
+			return fmt.Errorf("%s: %s %q requires property %q", name, propertyName, selected, key)
+		}
+	}
+	return nil
+}
+
+// discriminatorTargets maps each accepted discriminator value to its `#/$defs/`
+// reference. Without an explicit mapping the component names are the values.
+func discriminatorTargets(discriminator map[string]interface{}, variants []interface{}) map[string]string {
+	targets := make(map[string]string)
+	if mapping, ok := discriminator["mapping"].(map[string]interface{}); ok && len(mapping) > 0 {
+		for value, raw := range mapping {
+			target, _ := raw.(string)
+			if !strings.HasPrefix(target, "#/") {
+				target = "#/$defs/" + target
+			}
+			targets[value] = target
+		}
+		return targets
+	}
+
+	for _, variant := range variants {
+		var ref string
+		switch v := variant.(type) {
+		case map[string]interface{}:
+			ref, _ = v["$ref"].(string)
+		case ir.Schema:
+			ref, _ = v["$ref"].(string)
+		}
+		if strings.HasPrefix(ref, "#/$defs/") {
+			targets[strings.TrimPrefix(ref, "#/$defs/")] = ref
+		}
+	}
+	return targets
+}
+
+// variantShape gathers properties and required names across $ref and allOf.
+func variantShape(schema ir.Schema, resolver schemaResolver, depth int) (map[string]ir.Schema, []string) {
+	props := make(map[string]ir.Schema)
+	schema, _ = resolver.resolve(schema)
+	if schema == nil || depth > 16 {
+		return props, nil
+	}
+
+	required := schema.Required()
+	for key, prop := range schema.Properties() {
+		props[key] = prop
+	}
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, item := range allOf {
+			var sub ir.Schema
+			switch v := item.(type) {
+			case map[string]interface{}:
+				sub = v
+			case ir.Schema:
+				sub = v
+			default:
+				continue
+			}
+			nestedProps, nestedRequired := variantShape(sub, resolver, depth+1)
+			for key, prop := range nestedProps {
+				props[key] = prop
+			}
+			required = append(required, nestedRequired...)
+		}
+	}
+	return props, required
+}
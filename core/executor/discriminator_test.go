@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func TestOpenAPIToolValidatesDiscriminatedBody(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/pets",
+		Method: "POST",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{
+				"application/json": {
+					"oneOf": []interface{}{
+						map[string]interface{}{"$ref": "#/$defs/Dog"},
+						map[string]interface{}{"$ref": "#/$defs/Cat"},
+					},
+					"discriminator": map[string]interface{}{
+						"propertyName": "petType",
+						"mapping":      map[string]interface{}{"dog": "#/$defs/Dog", "cat": "#/$defs/Cat"},
+					},
+				},
+			},
+		},
+		SchemaDefs: ir.Schema{"$defs": map[string]interface{}{
+			"Pet": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"petType": map[string]interface{}{"type": "string"}},
+				"required":   []interface{}{"petType"},
+			},
+			"Dog": map[string]interface{}{
+				"allOf": []interface{}{
+					map[string]interface{}{"$ref": "#/$defs/Pet"},
+					map[string]interface{}{
+						"properties": map[string]interface{}{"age": map[string]interface{}{"type": "integer"}},
+						"required":   []interface{}{"age"},
+					},
+				},
+			},
+			"Cat": map[string]interface{}{
+				"allOf": []interface{}{
+					map[string]interface{}{"$ref": "#/$defs/Pet"},
+					map[string]interface{}{"properties": map[string]interface{}{"hunts": map[string]interface{}{"type": "boolean"}}},
+				},
+			},
+		}},
+	}
+	paramMap := map[string]ir.ParamMapping{"body": {OpenAPIName: "body", Location: "body"}}
+	tool := NewOpenAPITool("createPet", "", ir.Schema{"type": "object"}, nil, false, route, failingClient{t}, "https://api.example.com", paramMap, nil, nil)
+
+	run := func(body map[string]interface{}) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{"body": body, "_dryRun": true}
+		result, err := tool.Run(context.Background(), request)
+		if err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+		return result
+	}
+
+	result := run(map[string]interface{}{"petType": "dog", "age": "3"})
+	if result.IsError {
+		t.Fatalf("expected dog to validate, got %#v", result.Content)
+	}
+	preview := result.StructuredContent.(map[string]interface{})["request"].(map[string]interface{})
+	if preview["body"] != `{"age":3,"petType":"dog"}` {
+		t.Fatalf("expected age to be coerced for the dog variant, got %#v", preview["body"])
+	}
+
+	for message, body := range map[string]map[string]interface{}{
+		`unknown petType "fish" (expected one of cat, dog)`: {"petType": "fish"},
+		`petType "dog" requires property "age"`:             {"petType": "dog"},
+		`missing discriminator property "petType"`:          {"hunts": true},
+	} {
+		result := run(body)
+		if !result.IsError {
+			t.Fatalf("expected error for %v", body)
+		}
+		text := result.Content[0].(mcp.TextContent).Text
+		if !strings.Contains(text, message) {
+			t.Fatalf("expected %q in error, got %s", message, text)
+		}
+	}
+}
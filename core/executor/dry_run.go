@@ -0,0 +1,93 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/internal"
+)
+
+// WithDryRun marks ctx so tools return the request they would send instead of executing it.
+func WithDryRun(ctx context.Context, enabled bool) context.Context {
+	return internal.SetDryRun(ctx, enabled)
+}
+
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(v))
+		return err == nil && parsed
+	case float64:
+		return v != 0
+	}
+	return false
+}
+
+// buildDryRunResult renders the prepared request (method, URL, headers, body) as structured content.
+func buildDryRunResult(req *http.Request) (*mcp.CallToolResult, error) {
+	request := map[string]interface{}{
+		"method": req.Method,
+		"url":    req.URL.String(),
+	}
+
+	if len(req.Header) > 0 {
+		headers := make(map[string]interface{}, len(req.Header))
+		for name, values := range req.Header {
+			copied := make([]interface{}, len(values))
+			for i, v := range values {
+				copied[i] = v
+			}
+			headers[name] = copied
+		}
+		request["headers"] = headers
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if len(body) > 0 {
+			if isPrintable(body) {
+				request["body"] = string(body)
+			} else {
+				request["body"] = base64.StdEncoding.EncodeToString(body)
+				request["bodyEncoding"] = "base64"
+			}
+		}
+	}
+
+	structured := map[string]interface{}{
+		"dryRun":  true,
+		"request": request,
+	}
+	return &mcp.CallToolResult{
+		StructuredContent: structured,
+		Content:           buildStructuredTextContent(structured),
+	}, nil
+}
+
+func isPrintable(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+type failingClient struct{ t *testing.T }
+
+func (c failingClient) Do(req *http.Request) (*http.Response, error) {
+	c.t.Fatalf("dry run must not execute %s %s", req.Method, req.URL)
+	return nil, nil
+}
+
+func TestOpenAPIToolDryRunReturnsRequest(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/items",
+		Method: "POST",
+		Parameters: []ir.ParameterInfo{
+			{Name: "q", In: ir.ParameterInQuery, Schema: ir.Schema{"type": "string"}},
+		},
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{
+				"application/json": {"type": "object", "properties": map[string]interface{}{
+					"name":  map[string]interface{}{"type": "string"},
+					"count": map[string]interface{}{"type": "integer"},
+				}},
+			},
+		},
+	}
+	paramMap := map[string]ir.ParamMapping{
+		"q":     {OpenAPIName: "q", Location: ir.ParameterInQuery},
+		"name":  {OpenAPIName: "name", Location: "body"},
+		"count": {OpenAPIName: "count", Location: "body"},
+	}
+
+	tool := NewOpenAPITool("create", "", ir.Schema{"type": "object"}, nil, false, route, failingClient{t}, "https://api.example.com", paramMap, nil, nil)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"q": "a b", "name": "widget", "count": float64(2), "_dryRun": true}
+
+	result, err := tool.Run(context.Background(), request)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	structured, _ := result.StructuredContent.(map[string]interface{})
+	preview, _ := structured["request"].(map[string]interface{})
+	if preview["method"] != "POST" || preview["url"] != "https://api.example.com/items?q=a+b" {
+		t.Fatalf("unexpected request preview: %#v", preview)
+	}
+	if preview["body"] != `{"count":2,"name":"widget"}` {
+		t.Fatalf("expected JSON body text, got %#v", preview["body"])
+	}
+
+	request.Params.Arguments = map[string]interface{}{"q": "x"}
+	result, err = tool.Run(WithDryRun(context.Background(), true), request)
+	if err != nil || result.IsError {
+		t.Fatalf("expected context dry run to succeed, got %v %#v", err, result)
+	}
+}
+
+type tenantKey struct{}
+
+func TestOpenAPIToolResolvesBaseURLPerCall(t *testing.T) {
+	route := ir.HTTPRoute{Path: "/items/{id}", Method: "GET", Parameters: []ir.ParameterInfo{
+		{Name: "id", In: ir.ParameterInPath, Required: true, Schema: ir.Schema{"type": "string"}},
+	}}
+	paramMap := map[string]ir.ParamMapping{"id": {OpenAPIName: "id", Location: ir.ParameterInPath}}
+	tool := NewOpenAPITool("getItem", "", ir.Schema{"type": "object"}, nil, false, route, failingClient{t}, "https://api.example.com/v1", paramMap, nil, nil).
+		WithBaseURLResolver(func(ctx context.Context, route ir.HTTPRoute) (string, error) {
+			tenant, _ := ctx.Value(tenantKey{}).(string)
+			if tenant == "unknown" {
+				return "", fmt.Errorf("no upstream for tenant %q", tenant)
+			}
+			if tenant == "" {
+				return "", nil
+			}
+			return "https://" + tenant + ".example.com/v1", nil
+		})
+
+	run := func(tenant string) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{"id": "42", "_dryRun": true}
+		result, err := tool.Run(context.WithValue(context.Background(), tenantKey{}, tenant), request)
+		if err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+		return result
+	}
+	urlOf := func(result *mcp.CallToolResult) interface{} {
+		return result.StructuredContent.(map[string]interface{})["request"].(map[string]interface{})["url"]
+	}
+
+	if got := urlOf(run("acme")); got != "https://acme.example.com/v1/items/42" {
+		t.Fatalf("expected tenant base URL, got %v", got)
+	}
+	if got := urlOf(run("")); got != "https://api.example.com/v1/items/42" {
+		t.Fatalf("expected static base URL fallback, got %v", got)
+	}
+
+	result := run("unknown")
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, `no upstream for tenant "unknown"`) {
+		t.Fatalf("expected resolver error to fail the call, got %#v", result)
+	}
+}
@@ -3,9 +3,12 @@ package executor
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -45,6 +48,11 @@ func (eh *ErrorHandler) HandleHTTPError(err error) *mcp.CallToolResult {
 		"error":     err.Error(),
 		"retryable": retryable,
 	}
+	var circuitErr *CircuitOpenError
+	if errors.As(err, &circuitErr) {
+		structured["circuitOpen"] = true
+		structured["retryAfterSeconds"] = math.Ceil(circuitErr.RetryAfter.Seconds())
+	}
 
 	return &mcp.CallToolResult{
 		IsError:           true,
@@ -92,6 +100,23 @@ func (eh *ErrorHandler) HandleBuildError(err error) *mcp.CallToolResult {
 	}
 }
 
+// HandleTimeout 处理由工具自身超时设置触发的取消，此类错误不建议自动重试
+func (eh *ErrorHandler) HandleTimeout(timeout time.Duration, err error) *mcp.CallToolResult {
+	message := fmt.Sprintf("Request timed out after %s", timeout)
+	structured := map[string]interface{}{
+		"error":          err.Error(),
+		"timeout":        true,
+		"timeoutSeconds": timeout.Seconds(),
+		"retryable":      false,
+	}
+
+	return &mcp.CallToolResult{
+		IsError:           true,
+		Content:           []mcp.Content{mcp.NewTextContent(message)},
+		StructuredContent: structured,
+	}
+}
+
 // HandleResponseError 处理响应处理错误
 func (eh *ErrorHandler) HandleResponseError(err error) *mcp.CallToolResult {
 	return &mcp.CallToolResult{
@@ -188,6 +213,11 @@ func formatJSON(data []byte) (string, error) {
 
 // IsRetryableError 判断错误是否可重试
 func IsRetryableError(err error) bool {
+	var circuitErr *CircuitOpenError
+	if errors.As(err, &circuitErr) {
+		return true
+	}
+
 	if httpErr, ok := err.(*HTTPError); ok {
 		// 5xx 错误和部分 4xx 错误可重试
 		return httpErr.StatusCode >= 500 ||
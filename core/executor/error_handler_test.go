@@ -136,7 +136,7 @@ func TestResponseProcessorValidationFailure(t *testing.T) {
 	}
 
 	processor := NewResponseProcessor(schema, false, NewErrorHandler("info"))
-	result, err := processor.processJSON(map[string]interface{}{"other": "x"})
+	result, err := processor.processJSON(map[string]interface{}{"other": "x"}, nil)
 	if err != nil {
 		t.Fatalf("processJSON returned error: %v", err)
 	}
@@ -160,7 +160,7 @@ func TestResponseProcessorValidationSuccess(t *testing.T) {
 		},
 	}
 	processor := NewResponseProcessor(schema, false, NewErrorHandler("info"))
-	result, err := processor.processJSON(map[string]interface{}{"value": "hello"})
+	result, err := processor.processJSON(map[string]interface{}{"value": "hello"}, nil)
 	if err != nil {
 		t.Fatalf("processJSON returned error: %v", err)
 	}
@@ -242,3 +242,93 @@ func TestResponseProcessorProcessNoContent(t *testing.T) {
 		t.Fatalf("expected status 204 in meta, got %v", result.Result.Meta.AdditionalFields["status"])
 	}
 }
+
+func TestResponseProcessorReadsDeclaredErrorSchema(t *testing.T) {
+	route := ir.HTTPRoute{
+		Responses: map[string]ir.ResponseInfo{
+			"400": {ContentSchemas: map[string]ir.Schema{
+				"application/json": {"$ref": "#/$defs/ValidationError"},
+			}},
+			"4XX": {ContentSchemas: map[string]ir.Schema{
+				"application/problem+json": {
+					"type": "object",
+					"properties": map[string]interface{}{
+						"error": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"code":    map[string]interface{}{"type": "integer"},
+								"message": map[string]interface{}{"type": "string"},
+							},
+						},
+					},
+				},
+			}},
+		},
+		SchemaDefs: ir.Schema{"$defs": map[string]interface{}{
+			"ValidationError": map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"code", "message"},
+				"properties": map[string]interface{}{
+					"code":    map[string]interface{}{"type": "string"},
+					"message": map[string]interface{}{"type": "string"},
+					"fieldErrors": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"field":   map[string]interface{}{"type": "string"},
+								"message": map[string]interface{}{"type": "string"},
+							},
+						},
+					},
+				},
+			},
+		}},
+	}
+	process := func(status int, contentType, body string) *mcp.CallToolResult {
+		t.Helper()
+		resp := &http.Response{
+			StatusCode: status,
+			Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     http.Header{"Content-Type": []string{contentType}},
+		}
+		result, err := NewResponseProcessor(nil, false, NewErrorHandler("info")).
+			WithTransformer(route, nil).
+			Process(resp)
+		if err != nil || !result.IsError {
+			t.Fatalf("expected an error result, got %+v (%v)", result, err)
+		}
+		return result
+	}
+
+	result := process(http.StatusBadRequest, "application/json",
+		`{"code":"VALIDATION_FAILED","message":"Invalid pet","fieldErrors":[{"field":"name","message":"must not be empty"}]}`)
+	text := result.Content[0].(mcp.TextContent).Text
+	if text != "HTTP 400: 400 Bad Request - VALIDATION_FAILED: Invalid pet\n- field `name` must not be empty" {
+		t.Fatalf("unexpected text %q", text)
+	}
+	structured := result.StructuredContent.(map[string]interface{})
+	want := map[string]interface{}{
+		"code":        "VALIDATION_FAILED",
+		"message":     "Invalid pet",
+		"fieldErrors": []interface{}{map[string]interface{}{"field": "name", "message": "must not be empty"}},
+	}
+	if fmt.Sprint(structured["error"]) != fmt.Sprint(want) || structured["status"] != http.StatusBadRequest || structured["body"] == nil {
+		t.Fatalf("unexpected structured content %#v", structured)
+	}
+
+	result = process(http.StatusConflict, "application/problem+json", `{"error":{"code":409,"message":"Pet exists"}}`)
+	if text := result.Content[0].(mcp.TextContent).Text; text != "HTTP 409: 409 Conflict - 409: Pet exists" {
+		t.Fatalf("unexpected nested error text %q", text)
+	}
+
+	result = process(http.StatusBadRequest, "application/json", `{"detail":"not the declared shape"}`)
+	structured = result.StructuredContent.(map[string]interface{})
+	if _, typed := structured["error"]; typed {
+		t.Fatalf("expected the generic error for a body outside the schema, got %#v", structured)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "not the declared shape") {
+		t.Fatalf("expected the raw body in the fallback text, got %q", text)
+	}
+}
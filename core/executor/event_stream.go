@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxProgressMessage caps the part of a streamed record quoted in a progress
+// notification.
+const maxProgressMessage = 200
+
+func isEventStreamContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return mediaType == "text/event-stream"
+}
+
+// decodeEventStream reads a text/event-stream body event by event until the
+// upstream closes it. The data of each event becomes one record, decoded as
+// JSON when it is valid JSON and kept as text otherwise; event names, ids and
+// comments are dropped. onRecord, when set, sees each record as it arrives.
+func decodeEventStream(r io.Reader, onRecord func(record interface{})) ([]interface{}, error) {
+	reader := bufio.NewReader(r)
+	records := make([]interface{}, 0)
+	var data []string
+	dispatch := func() {
+		if len(data) == 0 {
+			return
+		}
+		payload := strings.Join(data, "\n")
+		data = data[:0]
+
+		var record interface{} = payload
+		if json.Valid([]byte(payload)) {
+			_ = json.Unmarshal([]byte(payload), &record)
+		}
+		records = append(records, record)
+		if onRecord != nil {
+			onRecord(record)
+		}
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" || err == nil {
+			line = strings.TrimRight(line, "\r\n")
+			switch {
+			case line == "":
+				dispatch()
+			case strings.HasPrefix(line, ":"):
+			default:
+				field, value, _ := strings.Cut(line, ":")
+				if field == "data" {
+					data = append(data, strings.TrimPrefix(value, " "))
+				}
+			}
+		}
+		if err == io.EOF {
+			dispatch()
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// progressReporter sends an MCP progress notification per streamed record to
+// the client that called the tool. It is nil when the request carries no
+// progress token or the call did not come through an MCP server.
+func progressReporter(ctx context.Context, request mcp.CallToolRequest, logger Logger) func(record interface{}) {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return nil
+	}
+	mcpServer := server.ServerFromContext(ctx)
+	if mcpServer == nil {
+		return nil
+	}
+	token := request.Params.Meta.ProgressToken
+	progress := 0
+	return func(record interface{}) {
+		progress++
+		err := mcpServer.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+			"progressToken": token,
+			"progress":      progress,
+			"message":       progressMessage(record),
+		})
+		if err != nil {
+			logger.Debug("failed to send progress notification", "error", err)
+		}
+	}
+}
+
+func progressMessage(record interface{}) string {
+	message, ok := record.(string)
+	if !ok {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return ""
+		}
+		message = string(data)
+	}
+	if len(message) <= maxProgressMessage {
+		return message
+	}
+	cut := maxProgressMessage
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	return message[:cut] + "…"
+}
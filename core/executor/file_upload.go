@@ -0,0 +1,277 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileUpload streams a local file into a multipart part instead of buffering it
+// in memory. Filename defaults to the base name of Path and ContentType to the
+// part encoding, then to a type guessed from the file extension.
+//
+// Only Go callers can pass a FileUpload. Tool arguments may use the map form
+// {"_file": "reports/q3.pdf", "filename": "...", "contentType": "..."} once
+// WithFileUploadRoot allows it, and only for files under that root.
+type FileUpload struct {
+	Path        string
+	Filename    string
+	ContentType string
+}
+
+func fileUploadFromValue(value interface{}) (FileUpload, bool) {
+	switch v := value.(type) {
+	case FileUpload:
+		return v, v.Path != ""
+	case *FileUpload:
+		if v == nil || v.Path == "" {
+			return FileUpload{}, false
+		}
+		return *v, true
+	}
+	return FileUpload{}, false
+}
+
+// WithFileUploadRoot lets tool arguments upload files by path with the
+// {"_file": path} map form. Relative paths are taken from root, and a path
+// that resolves outside root, symbolic links followed, is refused. Without a
+// root the map form is refused, so a model cannot read files off the host.
+func (rb *RequestBuilder) WithFileUploadRoot(root string) *RequestBuilder {
+	rb.fileUploadRoot = root
+	return rb
+}
+
+// resolveFileUploads replaces {"_file": path} maps among the body values, and
+// the elements of array values, with FileUploads confined to the upload root.
+func (rb *RequestBuilder) resolveFileUploads(bodyParams map[string]interface{}) error {
+	for name, value := range bodyParams {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			upload, ok, err := rb.fileUploadFromArgument(v)
+			if err != nil {
+				return fmt.Errorf("argument %q: %w", name, err)
+			}
+			if ok {
+				bodyParams[name] = upload
+			}
+		case []interface{}:
+			var items []interface{}
+			for i, item := range v {
+				object, isObject := item.(map[string]interface{})
+				if !isObject {
+					continue
+				}
+				upload, ok, err := rb.fileUploadFromArgument(object)
+				if err != nil {
+					return fmt.Errorf("argument %q: %w", name, err)
+				}
+				if ok {
+					if items == nil {
+						items = append([]interface{}(nil), v...)
+					}
+					items[i] = upload
+				}
+			}
+			if items != nil {
+				bodyParams[name] = items
+			}
+		}
+	}
+	return nil
+}
+
+func (rb *RequestBuilder) fileUploadFromArgument(value map[string]interface{}) (FileUpload, bool, error) {
+	path, ok := value["_file"].(string)
+	if !ok || path == "" {
+		return FileUpload{}, false, nil
+	}
+	resolved, err := confineUploadPath(rb.fileUploadRoot, path)
+	if err != nil {
+		return FileUpload{}, false, err
+	}
+	upload := FileUpload{Path: resolved}
+	upload.Filename, _ = value["filename"].(string)
+	if upload.Filename == "" {
+		upload.Filename = filepath.Base(path)
+	}
+	upload.ContentType, _ = value["contentType"].(string)
+	return upload, true, nil
+}
+
+// confineUploadPath resolves path against root and returns it only when it
+// stays inside root once symbolic links are followed.
+func confineUploadPath(root, path string) (string, error) {
+	if root == "" {
+		return "", fmt.Errorf("uploading files by path is not enabled")
+	}
+	realRoot, err := filepath.Abs(root)
+	if err == nil {
+		realRoot, err = filepath.EvalSymlinks(realRoot)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid file upload root: %w", err)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(realRoot, path)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read upload %s: %w", path, err)
+	}
+	rel, err := filepath.Rel(realRoot, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return "", fmt.Errorf("upload %s is outside the file upload root", path)
+	}
+	return resolved, nil
+}
+
+func (f FileUpload) filename() string {
+	if f.Filename != "" {
+		return f.Filename
+	}
+	return filepath.Base(f.Path)
+}
+
+// contentType prefers the explicit ContentType, then declared. A declared
+// wildcard or list is narrowed to the type guessed from the extension, or
+// sniffed from the file when the extension does not match it.
+func (f FileUpload) contentType(declared string) string {
+	if f.ContentType != "" {
+		return f.ContentType
+	}
+	guessed := mime.TypeByExtension(filepath.Ext(f.Path))
+	switch {
+	case declared == "":
+	case !isMediaRange(declared):
+		return declared
+	case guessed != "" && resolvePartContentType(declared, guessed) != "application/octet-stream":
+		return resolvePartContentType(declared, guessed)
+	default:
+		return resolvePartContentType(declared, sniffFileContentType(f.Path))
+	}
+	if guessed != "" {
+		return guessed
+	}
+	return "application/octet-stream"
+}
+
+// multipartStream concatenates buffered part headers with lazily opened files
+// so large uploads are copied to the connection without being held in memory.
+// replay starts the same body over, reopening the files, for Request.GetBody.
+type multipartStream struct {
+	io.Reader
+	files    []*lazyFile
+	size     int64
+	segments []streamSegment
+}
+
+func (s *multipartStream) replay() (io.ReadCloser, error) {
+	return newMultipartStream(s.segments, s.size), nil
+}
+
+func (s *multipartStream) Close() error {
+	var firstErr error
+	for _, file := range s.files {
+		if err := file.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// streamSegment is either buffered bytes or the path of a file read on demand.
+type streamSegment struct {
+	data []byte
+	path string
+}
+
+func newMultipartStream(segments []streamSegment, size int64) *multipartStream {
+	stream := &multipartStream{size: size, segments: segments}
+	readers := make([]io.Reader, len(segments))
+	for i, segment := range segments {
+		if segment.path == "" {
+			readers[i] = bytes.NewReader(segment.data)
+			continue
+		}
+		file := &lazyFile{path: segment.path}
+		stream.files = append(stream.files, file)
+		readers[i] = file
+	}
+	stream.Reader = io.MultiReader(readers...)
+	return stream
+}
+
+type multipartStreamBuilder struct {
+	segments []streamSegment
+	files    int
+	size     int64
+}
+
+// flush moves the bytes written so far into a segment of their own.
+func (b *multipartStreamBuilder) flush(buf *bytes.Buffer) {
+	if buf.Len() == 0 {
+		return
+	}
+	chunk := append([]byte(nil), buf.Bytes()...)
+	b.segments = append(b.segments, streamSegment{data: chunk})
+	b.size += int64(len(chunk))
+	buf.Reset()
+}
+
+func (b *multipartStreamBuilder) addFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read upload %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("failed to read upload %s: is a directory", path)
+	}
+	b.segments = append(b.segments, streamSegment{path: path})
+	b.files++
+	b.size += info.Size()
+	return nil
+}
+
+func (b *multipartStreamBuilder) stream() *multipartStream {
+	return newMultipartStream(b.segments, b.size)
+}
+
+// lazyFile opens its file on first read and closes it once drained.
+type lazyFile struct {
+	path string
+	file *os.File
+	done bool
+}
+
+func (f *lazyFile) Read(p []byte) (int, error) {
+	if f.done {
+		return 0, io.EOF
+	}
+	if f.file == nil {
+		file, err := os.Open(f.path)
+		if err != nil {
+			f.done = true
+			return 0, err
+		}
+		f.file = file
+	}
+	n, err := f.file.Read(p)
+	if err == io.EOF {
+		f.done = true
+		f.Close()
+	}
+	return n, err
+}
+
+func (f *lazyFile) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
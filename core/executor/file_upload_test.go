@@ -0,0 +1,169 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func TestRequestBuilderStreamsFileUploads(t *testing.T) {
+	dir := t.TempDir()
+	imagePath := filepath.Join(dir, "avatar.png")
+	if err := os.WriteFile(imagePath, []byte("png-bytes"), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	reportPath := filepath.Join(dir, "report.bin")
+	if err := os.WriteFile(reportPath, []byte(strings.Repeat("r", 4096)), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	route := ir.HTTPRoute{
+		Path:   "/uploads",
+		Method: "POST",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{
+				"multipart/form-data": {"type": "object", "properties": map[string]interface{}{
+					"avatar": map[string]interface{}{"type": "string", "format": "binary"},
+					"report": map[string]interface{}{"type": "string", "format": "binary"},
+					"note":   map[string]interface{}{"type": "string"},
+				}},
+			},
+			Encodings: map[string]map[string]ir.EncodingInfo{
+				"multipart/form-data": {"report": {ContentType: "application/pdf"}},
+			},
+		},
+	}
+
+	req, err := NewRequestBuilder(route, nil, "https://api.example.com").WithFileUploadRoot(dir).Build(context.Background(), map[string]interface{}{
+		"avatar": FileUpload{Path: imagePath},
+		"report": map[string]interface{}{"_file": reportPath, "filename": "q3.pdf"},
+		"note":   "hello",
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if _, ok := req.Body.(*multipartStream); !ok {
+		t.Fatalf("expected streamed multipart body, got %T", req.Body)
+	}
+
+	data, _ := io.ReadAll(req.Body)
+	if req.ContentLength != int64(len(data)) {
+		t.Fatalf("expected content length %d, got %d", len(data), req.ContentLength)
+	}
+	replayed, err := req.GetBody()
+	if err != nil {
+		t.Fatalf("GetBody failed: %v", err)
+	}
+	again, _ := io.ReadAll(replayed)
+	replayed.Close()
+	if string(again) != string(data) {
+		t.Fatalf("expected GetBody to replay the same body")
+	}
+
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("bad content type: %v", err)
+	}
+	reader := multipart.NewReader(strings.NewReader(string(data)), params["boundary"])
+	parts := make(map[string]*multipart.Part)
+	contents := make(map[string]string)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read part failed: %v", err)
+		}
+		content, _ := io.ReadAll(part)
+		parts[part.FormName()] = part
+		contents[part.FormName()] = string(content)
+	}
+
+	if parts["avatar"].FileName() != "avatar.png" || parts["avatar"].Header.Get("Content-Type") != "image/png" || contents["avatar"] != "png-bytes" {
+		t.Fatalf("unexpected avatar part %v %q", parts["avatar"].Header, contents["avatar"])
+	}
+	if parts["report"].FileName() != "q3.pdf" || parts["report"].Header.Get("Content-Type") != "application/pdf" || len(contents["report"]) != 4096 {
+		t.Fatalf("unexpected report part %v (%d bytes)", parts["report"].Header, len(contents["report"]))
+	}
+	if contents["note"] != "hello" {
+		t.Fatalf("unexpected note part %q", contents["note"])
+	}
+}
+
+func TestRequestBuilderRejectsMissingUploadFile(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/uploads",
+		Method: "POST",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{
+				"multipart/form-data": {"type": "object", "properties": map[string]interface{}{
+					"file": map[string]interface{}{"type": "string", "format": "binary"},
+					"note": map[string]interface{}{"type": "string"},
+				}},
+			},
+		},
+	}
+
+	_, err := NewRequestBuilder(route, nil, "https://api.example.com").Build(context.Background(), map[string]interface{}{
+		"file": FileUpload{Path: filepath.Join(t.TempDir(), "missing.bin")},
+		"note": "x",
+	})
+	if err == nil || !strings.Contains(err.Error(), "missing.bin") {
+		t.Fatalf("expected missing file error, got %v", err)
+	}
+}
+
+func TestRequestBuilderConfinesUploadPathsToTheRoot(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/uploads",
+		Method: "POST",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{
+				"multipart/form-data": {"type": "object", "properties": map[string]interface{}{
+					"file": map[string]interface{}{"type": "string", "format": "binary"},
+				}},
+			},
+		},
+	}
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "secret.txt")
+	if err := os.WriteFile(secret, []byte("secret"), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "ok.txt"), []byte("ok"), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := os.Symlink(secret, filepath.Join(root, "link.txt")); err != nil {
+		t.Fatalf("symlink failed: %v", err)
+	}
+	build := func(builder *RequestBuilder, path string) error {
+		_, err := builder.Build(context.Background(), map[string]interface{}{
+			"file": map[string]interface{}{"_file": path},
+		})
+		return err
+	}
+
+	if err := build(NewRequestBuilder(route, nil, "https://api.example.com"), secret); err == nil || !strings.Contains(err.Error(), "not enabled") {
+		t.Fatalf("expected path uploads to be refused by default, got %v", err)
+	}
+	confined := func() *RequestBuilder {
+		return NewRequestBuilder(route, nil, "https://api.example.com").WithFileUploadRoot(root)
+	}
+	for _, path := range []string{secret, "../" + filepath.Base(outside) + "/secret.txt", "link.txt"} {
+		if err := build(confined(), path); err == nil || !strings.Contains(err.Error(), "outside the file upload root") {
+			t.Fatalf("expected %s to be refused, got %v", path, err)
+		}
+	}
+	if err := build(confined(), "ok.txt"); err != nil {
+		t.Fatalf("expected a file under the root to upload, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/specx2/openapi-mcp/core/internal"
+)
+
+// HeaderPropagation decides which inbound MCP headers are forwarded upstream.
+// Patterns match case-insensitively and may end in `*` to match a prefix,
+// e.g. "X-Trace-*". Deny wins over Allow; an empty Allow forwards everything
+// not denied. Credential headers are only forwarded when allowed by name.
+type HeaderPropagation struct {
+	Allow []string
+	Deny  []string
+}
+
+// credentialHeaders carry credentials or sessions. They are forwarded only
+// when allowed by name and never copied into results.
+var credentialHeaders = map[string]struct{}{
+	"authorization":       {},
+	"proxy-authorization": {},
+	"cookie":              {},
+	"set-cookie":          {},
+}
+
+func isCredentialHeader(name string) bool {
+	_, credential := credentialHeaders[strings.ToLower(name)]
+	return credential
+}
+
+// Allows reports whether the named header may be forwarded.
+func (p *HeaderPropagation) Allows(name string) bool {
+	if p == nil {
+		return true
+	}
+	for _, pattern := range p.Deny {
+		if headerPatternMatches(pattern, name) {
+			return false
+		}
+	}
+
+	if isCredentialHeader(name) {
+		for _, pattern := range p.Allow {
+			if strings.EqualFold(strings.TrimSpace(pattern), name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range p.Allow {
+		if headerPatternMatches(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func headerPatternMatches(pattern, name string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return len(name) >= len(prefix) && strings.EqualFold(name[:len(prefix)], prefix)
+	}
+	return strings.EqualFold(pattern, name)
+}
+
+// applyMCPHeaders copies the inbound MCP headers carried by ctx onto header,
+// skipping those the policy rejects. A nil policy forwards every header.
+func applyMCPHeaders(ctx context.Context, header http.Header, policy *HeaderPropagation) {
+	for k, v := range internal.GetMCPHeaders(ctx) {
+		if !policy.Allows(k) {
+			continue
+		}
+		header.Set(k, v)
+	}
+}
@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/internal"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func TestHeaderPropagationAllows(t *testing.T) {
+	policy := &HeaderPropagation{
+		Allow: []string{"x-request-id", "X-Trace-*"},
+		Deny:  []string{"X-Trace-Debug"},
+	}
+	cases := map[string]bool{
+		"X-Request-Id":  true,
+		"X-TRACE-SPAN":  true,
+		"X-Trace-Debug": false,
+		"X-Tenant":      false,
+		"Authorization": false,
+	}
+	for name, want := range cases {
+		if got := policy.Allows(name); got != want {
+			t.Fatalf("Allows(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	open := &HeaderPropagation{Deny: []string{"X-Internal-*"}}
+	if !open.Allows("X-Tenant") || open.Allows("x-internal-key") || open.Allows("authorization") {
+		t.Fatalf("expected empty allow list to forward all but denied and credential headers")
+	}
+	if !(&HeaderPropagation{Allow: []string{"Authorization"}}).Allows("authorization") {
+		t.Fatalf("expected Authorization to be forwarded when allowed by name")
+	}
+	if (&HeaderPropagation{Allow: []string{"*"}}).Allows("Authorization") {
+		t.Fatalf("expected wildcard allow not to forward Authorization")
+	}
+
+	var nilPolicy *HeaderPropagation
+	if !nilPolicy.Allows("Authorization") {
+		t.Fatalf("expected nil policy to forward every header")
+	}
+}
+
+func TestOpenAPIToolFiltersMCPHeaders(t *testing.T) {
+	route := ir.HTTPRoute{Path: "/items", Method: "GET"}
+	tool := NewOpenAPITool("list", "", ir.Schema{"type": "object"}, nil, false, route, failingClient{t}, "https://api.example.com", nil, nil, nil).
+		WithHeaderPropagation(&HeaderPropagation{Allow: []string{"X-Request-Id", "X-Tenant"}})
+
+	ctx := internal.SetMCPHeaders(WithDryRun(context.Background(), true), map[string]string{
+		"X-Request-Id":  "req-1",
+		"X-Tenant":      "acme",
+		"Authorization": "Bearer mcp-token",
+		"X-Other":       "nope",
+	})
+	result, err := tool.Run(ctx, mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	preview := result.StructuredContent.(map[string]interface{})["request"].(map[string]interface{})
+	headers := http.Header{}
+	for k, v := range preview["headers"].(map[string]interface{}) {
+		headers.Set(k, v.([]interface{})[0].(string))
+	}
+	if headers.Get("X-Request-Id") != "req-1" || headers.Get("X-Tenant") != "acme" {
+		t.Fatalf("expected allowed headers to be forwarded, got %v", headers)
+	}
+	if headers.Get("Authorization") != "" || headers.Get("X-Other") != "" {
+		t.Fatalf("expected other headers to be dropped, got %v", headers)
+	}
+}
@@ -0,0 +1,83 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// responseMethod is the method of the request that produced resp, falling back
+// to the route when the response carries no request.
+func (rp *ResponseProcessor) responseMethod(resp *http.Response) string {
+	if resp.Request != nil && resp.Request.Method != "" {
+		return strings.ToUpper(resp.Request.Method)
+	}
+	return strings.ToUpper(rp.route.Method)
+}
+
+// processHeadersOnly builds the result of a HEAD, or a bodiless OPTIONS, call
+// from the status and headers, which are the whole payload of these methods.
+func (rp *ResponseProcessor) processHeadersOnly(resp *http.Response, method string, declared map[string]interface{}, meta *mcp.Meta) *mcp.CallToolResult {
+	headers := make(map[string]interface{}, len(resp.Header))
+	for name, values := range resp.Header {
+		headers[name] = strings.Join(values, ", ")
+	}
+
+	structured := map[string]interface{}{
+		"status":  resp.StatusCode,
+		"headers": headers,
+	}
+	switch method {
+	case http.MethodHead:
+		if resp.ContentLength >= 0 {
+			structured["contentLength"] = resp.ContentLength
+		}
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			structured["etag"] = etag
+		}
+		if modified := resp.Header.Get("Last-Modified"); modified != "" {
+			structured["lastModified"] = modified
+		}
+	case http.MethodOptions:
+		if allow := allowedMethods(resp.Header); len(allow) > 0 {
+			structured["allow"] = allow
+		}
+	}
+	structured = withDeclaredHeaders(structured, declared)
+
+	text := fmt.Sprintf("HTTP %d", resp.StatusCode)
+	if data, err := json.MarshalIndent(structured, "", "  "); err == nil {
+		text = string(data)
+	}
+	return &mcp.CallToolResult{
+		StructuredContent: structured,
+		Content:           []mcp.Content{mcp.NewTextContent(text)},
+		Result:            mcp.Result{Meta: cloneMeta(meta)},
+	}
+}
+
+// allowedMethods reads Allow, or the CORS Access-Control-Allow-Methods header,
+// as a sorted, de-duplicated list.
+func allowedMethods(header http.Header) []string {
+	values := header.Values("Allow")
+	if len(values) == 0 {
+		values = header.Values("Access-Control-Allow-Methods")
+	}
+	seen := make(map[string]bool)
+	var methods []string
+	for _, value := range values {
+		for _, method := range strings.Split(value, ",") {
+			method = strings.ToUpper(strings.TrimSpace(method))
+			if method != "" && !seen[method] {
+				seen[method] = true
+				methods = append(methods, method)
+			}
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
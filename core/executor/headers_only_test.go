@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestResponseProcessorBuildsHeadAndOptionsResultsFromHeaders(t *testing.T) {
+	head := &http.Response{
+		StatusCode:    http.StatusOK,
+		Status:        "200 OK",
+		Header:        http.Header{"Etag": []string{`"v42"`}, "Content-Type": []string{"application/json"}},
+		ContentLength: 1234,
+		Body:          http.NoBody,
+		Request:       &http.Request{Method: http.MethodHead},
+	}
+	result, err := NewResponseProcessor(nil, false, nil).Process(head)
+	if err != nil {
+		t.Fatalf("Process returned error: %v", err)
+	}
+	structured := result.StructuredContent.(map[string]interface{})
+	if structured["status"] != http.StatusOK || structured["contentLength"] != int64(1234) || structured["etag"] != `"v42"` {
+		t.Fatalf("unexpected HEAD result: %#v", structured)
+	}
+	if headers := structured["headers"].(map[string]interface{}); headers["Content-Type"] != "application/json" {
+		t.Fatalf("expected response headers in the result, got %#v", headers)
+	}
+
+	options := &http.Response{
+		StatusCode: http.StatusNoContent,
+		Status:     "204 No Content",
+		Header:     http.Header{"Allow": []string{"post, GET", "OPTIONS"}},
+		Body:       http.NoBody,
+		Request:    &http.Request{Method: http.MethodOptions},
+	}
+	result, err = NewResponseProcessor(nil, false, nil).Process(options)
+	if err != nil {
+		t.Fatalf("Process returned error: %v", err)
+	}
+	structured = result.StructuredContent.(map[string]interface{})
+	if want := []string{"GET", "OPTIONS", "POST"}; !reflect.DeepEqual(structured["allow"], want) {
+		t.Fatalf("expected allowed methods %v, got %#v", want, structured["allow"])
+	}
+}
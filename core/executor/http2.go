@@ -0,0 +1,54 @@
+package executor
+
+import "net/http"
+
+// WithHTTP2 turns HTTP/2 to TLS upstreams on or off. When on, HTTP/2 is
+// negotiated even with a TLS config from WithTLSConfig, WithClientCertificate
+// or WithRootCAs, which would otherwise leave the client on HTTP/1.1. When
+// off, every request uses HTTP/1.1 and WithH2C is undone. Only the
+// transport's protocols change, so TLS and proxy settings are kept; idle
+// connections are closed so later requests use the new protocols.
+func (c *DefaultHTTPClient) WithHTTP2(enabled bool) *DefaultHTTPClient {
+	t := c.transport()
+	protocols := c.protocols()
+	protocols.SetHTTP2(enabled)
+	if !enabled {
+		protocols.SetUnencryptedHTTP2(false)
+		protocols.SetHTTP1(true)
+	}
+	t.ForceAttemptHTTP2 = enabled
+	t.Protocols = &protocols
+	t.CloseIdleConnections()
+	return c
+}
+
+// WithH2C sends requests to http:// upstreams as cleartext HTTP/2 with prior
+// knowledge, for internal services such as gRPC gateways that speak nothing
+// else. https:// upstreams then have to accept HTTP/2 over TLS too, since
+// HTTP/1.1 is no longer offered. WithH2C(false) goes back to HTTP/1.1 for
+// http:// upstreams. TLS and proxy settings are kept.
+func (c *DefaultHTTPClient) WithH2C(enabled bool) *DefaultHTTPClient {
+	t := c.transport()
+	protocols := c.protocols()
+	protocols.SetUnencryptedHTTP2(enabled)
+	protocols.SetHTTP1(!enabled)
+	if enabled {
+		protocols.SetHTTP2(true)
+		t.ForceAttemptHTTP2 = true
+	}
+	t.Protocols = &protocols
+	t.CloseIdleConnections()
+	return c
+}
+
+// protocols returns the transport's protocol set, defaulting to HTTP/1.1 plus
+// HTTP/2 over TLS as the default transport negotiates them.
+func (c *DefaultHTTPClient) protocols() http.Protocols {
+	if p := c.transport().Protocols; p != nil {
+		return *p
+	}
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(c.transport().ForceAttemptHTTP2)
+	return protocols
+}
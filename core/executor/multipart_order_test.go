@@ -0,0 +1,281 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/specx2/openapi-mcp/core/parser"
+)
+
+const orderedMultipartSpec = `{
+    "openapi": "3.0.3",
+    "info": {"title": "Uploads", "version": "1.0"},
+    "paths": {
+        "/uploads": {
+            "post": {
+                "operationId": "upload",
+                "requestBody": {
+                    "content": {
+                        "%s": {
+                            "schema": {
+                                "type": "object",
+                                "properties": {
+                                    "metadata": {"type": "object"},
+                                    "title": {"type": "string"},
+                                    "attachment": {"type": "string", "format": "binary"},
+                                    "checksum": {"type": "string"}
+                                }
+                            }
+                        }
+                    }
+                },
+                "responses": {"200": {"description": "ok"}}
+            }
+        }
+    }
+}`
+
+func readMultipartParts(t *testing.T, body io.Reader, contentType string) ([]*multipart.Part, []string) {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		t.Fatalf("bad content type %q: %v", contentType, err)
+	}
+	reader := multipart.NewReader(body, params["boundary"])
+	var parts []*multipart.Part
+	var contents []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts, contents
+		}
+		if err != nil {
+			t.Fatalf("read part failed: %v", err)
+		}
+		content, _ := io.ReadAll(part)
+		parts = append(parts, part)
+		contents = append(contents, string(content))
+	}
+}
+
+func TestRequestBuilderWritesMultipartPartsInDeclaredOrder(t *testing.T) {
+	routes, err := parser.NewOpenAPI30Parser().ParseSpec([]byte(strings.Replace(orderedMultipartSpec, "%s", "multipart/form-data", 1)))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	args := map[string]interface{}{
+		"checksum":   "abc123",
+		"attachment": []byte("payload"),
+		"title":      "Q3 report",
+		"metadata":   map[string]interface{}{"owner": "ops"},
+		"extra":      "tail",
+	}
+	for i := 0; i < 5; i++ {
+		req, err := NewRequestBuilder(routes[0], nil, "https://api.example.com").Build(context.Background(), args)
+		if err != nil {
+			t.Fatalf("build failed: %v", err)
+		}
+
+		var names []string
+		parts, _ := readMultipartParts(t, req.Body, req.Header.Get("Content-Type"))
+		for _, part := range parts {
+			names = append(names, part.FormName())
+		}
+		if got := strings.Join(names, ","); got != "metadata,title,attachment,checksum,extra" {
+			t.Fatalf("unexpected part order %s", got)
+		}
+	}
+}
+
+func TestRequestBuilderEncodesMultipartMixed(t *testing.T) {
+	routes, err := parser.NewOpenAPI30Parser().ParseSpec([]byte(strings.Replace(orderedMultipartSpec, "%s", "multipart/mixed", 1)))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	req, err := NewRequestBuilder(routes[0], nil, "https://api.example.com").Build(context.Background(), map[string]interface{}{
+		"title":    "Q3 report",
+		"metadata": map[string]interface{}{"owner": "ops"},
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if ct := req.Header.Get("Content-Type"); !strings.HasPrefix(ct, "multipart/mixed; boundary=") {
+		t.Fatalf("expected multipart/mixed content type, got %q", ct)
+	}
+
+	parts, contents := readMultipartParts(t, req.Body, req.Header.Get("Content-Type"))
+	if len(parts) != 2 {
+		t.Fatalf("expected two parts, got %d", len(parts))
+	}
+	for _, part := range parts {
+		if part.Header.Get("Content-Disposition") != "" {
+			t.Fatalf("expected unnamed mixed part, got %v", part.Header)
+		}
+	}
+	if parts[0].Header.Get("Content-Type") != "application/json" || contents[0] != `{"owner":"ops"}` {
+		t.Fatalf("unexpected first part %v %q", parts[0].Header, contents[0])
+	}
+	if !strings.HasPrefix(parts[1].Header.Get("Content-Type"), "text/plain") || contents[1] != "Q3 report" {
+		t.Fatalf("unexpected second part %v %q", parts[1].Header, contents[1])
+	}
+}
+
+func TestRequestBuilderResolvesMultipartPartContentTypes(t *testing.T) {
+	spec := `{
+        "openapi": "3.1.0",
+        "info": {"title": "Uploads", "version": "1.0"},
+        "paths": {
+            "/uploads": {
+                "post": {
+                    "operationId": "upload",
+                    "requestBody": {
+                        "content": {
+                            "multipart/form-data": {
+                                "schema": {
+                                    "type": "object",
+                                    "properties": {
+                                        "image": {"type": "string", "format": "binary"},
+                                        "document": {"type": "string", "format": "binary"},
+                                        "notes": {"type": "string", "contentMediaType": "text/markdown"},
+                                        "blob": {"type": "string", "format": "binary"},
+                                        "scan": {"type": "string", "format": "binary"}
+                                    }
+                                },
+                                "encoding": {
+                                    "image": {"contentType": "image/*"},
+                                    "document": {"contentType": "application/pdf, text/plain"},
+                                    "blob": {"contentType": "image/png, image/jpeg"},
+                                    "scan": {"contentType": "image/*"}
+                                }
+                            }
+                        }
+                    },
+                    "responses": {"200": {"description": "ok"}}
+                }
+            }
+        }
+    }`
+	routes, err := parser.NewOpenAPI31Parser().ParseSpec([]byte(spec))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	scan := filepath.Join(t.TempDir(), "scan.bin")
+	if err := os.WriteFile(scan, png, 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	req, err := NewRequestBuilder(routes[0], nil, "https://api.example.com").Build(context.Background(), map[string]interface{}{
+		"image":    png,
+		"document": []byte("plain words"),
+		"notes":    "# Title",
+		"blob":     []byte{0x00, 0x01, 0x02},
+		"scan":     FileUpload{Path: scan},
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	parts, _ := readMultipartParts(t, req.Body, req.Header.Get("Content-Type"))
+	got := make(map[string]string)
+	for _, part := range parts {
+		got[part.FormName()] = part.Header.Get("Content-Type")
+	}
+	want := map[string]string{
+		"image":    "image/png",
+		"document": "text/plain",
+		"notes":    "text/markdown",
+		"blob":     "application/octet-stream",
+		"scan":     "image/png",
+	}
+	for name, contentType := range want {
+		if got[name] != contentType {
+			t.Fatalf("part %s: expected Content-Type %q, got %q (all: %v)", name, contentType, got[name], got)
+		}
+	}
+}
+
+func TestRequestBuilderWritesOnePartPerFileInArray(t *testing.T) {
+	spec := `{
+        "openapi": "3.0.3",
+        "info": {"title": "Uploads", "version": "1.0"},
+        "paths": {
+            "/attachments": {
+                "post": {
+                    "operationId": "attach",
+                    "requestBody": {
+                        "content": {
+                            "multipart/form-data": {
+                                "schema": {
+                                    "type": "object",
+                                    "properties": {
+                                        "ticket": {"type": "string"},
+                                        "files": {"type": "array", "items": {"type": "string", "format": "binary"}},
+                                        "labels": {"type": "array", "items": {"type": "string"}}
+                                    }
+                                },
+                                "encoding": {
+                                    "files": {"contentType": "image/png, text/plain"}
+                                }
+                            }
+                        }
+                    },
+                    "responses": {"200": {"description": "ok"}}
+                }
+            }
+        }
+    }`
+	routes, err := parser.NewOpenAPI30Parser().ParseSpec([]byte(spec))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	screenshot := filepath.Join(dir, "screenshot.png")
+	if err := os.WriteFile(screenshot, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	req, err := NewRequestBuilder(routes[0], nil, "https://api.example.com").WithFileUploadRoot(dir).Build(context.Background(), map[string]interface{}{
+		"ticket": "T-42",
+		"files": []interface{}{
+			FileUpload{Path: screenshot},
+			map[string]interface{}{"_file": screenshot, "filename": "notes.txt", "contentType": "text/plain"},
+		},
+		"labels": []interface{}{"bug", "ui"},
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	parts, contents := readMultipartParts(t, req.Body, req.Header.Get("Content-Type"))
+	if len(parts) != 4 {
+		t.Fatalf("expected four parts, got %d", len(parts))
+	}
+	want := []struct{ name, filename, contentType string }{
+		{"ticket", "", ""},
+		{"files", "screenshot.png", "image/png"},
+		{"files", "notes.txt", "text/plain"},
+		{"labels", "", ""},
+	}
+	for i, w := range want {
+		part := parts[i]
+		if part.FormName() != w.name || part.FileName() != w.filename || part.Header.Get("Content-Type") != w.contentType {
+			t.Fatalf("part %d: got name=%q filename=%q type=%q, want %+v",
+				i, part.FormName(), part.FileName(), part.Header.Get("Content-Type"), w)
+		}
+	}
+	if !strings.HasPrefix(contents[1], "\x89PNG") || contents[1] != contents[2] {
+		t.Fatalf("unexpected file contents %q %q", contents[1], contents[2])
+	}
+	if contents[3] != `["bug","ui"]` {
+		t.Fatalf("expected non-binary array as one JSON part, got %q", contents[3])
+	}
+}
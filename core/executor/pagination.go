@@ -0,0 +1,310 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const defaultPaginationMaxPages = 10
+
+// PaginationConfig describes how list operations expose their next page.
+type PaginationConfig struct {
+	// NextCursorPointer is a JSON pointer (e.g. "/meta/next") to the next-page cursor in the response body.
+	NextCursorPointer string
+	// UseLinkHeader follows `Link: <...>; rel="next"` headers instead of a body cursor.
+	UseLinkHeader bool
+	// CursorParam is the query parameter that receives the cursor on subsequent requests.
+	CursorParam string
+	// ItemsPointer locates the array to concatenate; empty means the response itself is the array.
+	ItemsPointer string
+	// MaxPages caps the number of pages fetched per call (defaults to 10).
+	MaxPages int
+}
+
+type paginator struct {
+	client   HTTPClient
+	config   PaginationConfig
+	maxBytes int64
+	// truncated explains why fetch stopped before the last page, when a later
+	// page failed; the pages merged until then are still returned.
+	truncated *paginationTruncation
+}
+
+// paginationTruncation is reported in the result _meta when a page after the
+// first fails.
+type paginationTruncation struct {
+	page   int
+	reason string
+}
+
+func (t *paginationTruncation) meta() *mcp.Meta {
+	return mcp.NewMetaFromMap(map[string]any{
+		"paginationTruncated":  true,
+		"paginationFailedPage": t.page,
+		"paginationError":      t.reason,
+	})
+}
+
+func newPaginator(client HTTPClient, config PaginationConfig) *paginator {
+	if config.MaxPages <= 0 {
+		config.MaxPages = defaultPaginationMaxPages
+	}
+	return &paginator{client: client, config: config}
+}
+
+// fetch executes req and keeps following next pages, returning a single response
+// whose body holds the concatenated items. Responses that cannot be paginated are
+// returned untouched.
+func (p *paginator) fetch(req *http.Request) (*http.Response, error) {
+	resp, err := p.client.Do(req)
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, err
+	}
+
+	first, doc, ok := readPageDocument(resp, p.maxBytes)
+	if !ok {
+		return first, nil
+	}
+
+	items, ok := p.pageItems(doc)
+	if !ok {
+		return first, nil
+	}
+
+	merged := append([]interface{}{}, items...)
+	visited := map[string]struct{}{p.pageKey(req): {}}
+	current, currentDoc, currentReq := first, doc, req
+
+	for pages := 1; pages < p.config.MaxPages; pages++ {
+		nextReq, ok := p.nextRequest(currentReq, current, currentDoc, visited)
+		if !ok {
+			break
+		}
+
+		nextResp, err := p.client.Do(nextReq)
+		if err != nil {
+			p.truncated = &paginationTruncation{page: pages + 1, reason: err.Error()}
+			break
+		}
+		if nextResp.StatusCode < 200 || nextResp.StatusCode >= 300 {
+			nextResp.Body.Close()
+			p.truncated = &paginationTruncation{page: pages + 1, reason: fmt.Sprintf("HTTP %d", nextResp.StatusCode)}
+			break
+		}
+
+		page, pageDoc, ok := readPageDocument(nextResp, p.maxBytes)
+		if !ok {
+			break
+		}
+		pageItems, ok := p.pageItems(pageDoc)
+		if !ok {
+			break
+		}
+
+		merged = append(merged, pageItems...)
+		current, currentDoc, currentReq = page, pageDoc, nextReq
+	}
+
+	var result interface{} = merged
+	if p.config.ItemsPointer != "" {
+		if !setJSONPointer(doc, p.config.ItemsPointer, merged) {
+			return first, nil
+		}
+		result = doc
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode paginated result: %w", err)
+	}
+
+	first.Body = io.NopCloser(bytes.NewReader(encoded))
+	first.ContentLength = int64(len(encoded))
+	first.Header.Del("Content-Length")
+	return first, nil
+}
+
+// pageKey is how visited remembers the page req fetches: its URL when
+// following Link headers, otherwise the cursor it sends.
+func (p *paginator) pageKey(req *http.Request) string {
+	if p.config.UseLinkHeader {
+		return req.URL.String()
+	}
+	return req.URL.Query().Get(p.config.CursorParam)
+}
+
+func (p *paginator) pageItems(doc interface{}) ([]interface{}, bool) {
+	value := doc
+	if p.config.ItemsPointer != "" {
+		found, ok := lookupJSONPointer(doc, p.config.ItemsPointer)
+		if !ok {
+			return nil, false
+		}
+		value = found
+	}
+	items, ok := value.([]interface{})
+	return items, ok
+}
+
+func (p *paginator) nextRequest(prev *http.Request, resp *http.Response, doc interface{}, visited map[string]struct{}) (*http.Request, bool) {
+	if p.config.UseLinkHeader {
+		next := nextLinkURL(resp.Header.Values("Link"))
+		if next == "" {
+			return nil, false
+		}
+		target, err := prev.URL.Parse(next)
+		if err != nil {
+			return nil, false
+		}
+		key := target.String()
+		if _, seen := visited[key]; seen {
+			return nil, false
+		}
+		visited[key] = struct{}{}
+
+		req := prev.Clone(prev.Context())
+		req.URL = target
+		req.Host = ""
+		return req, true
+	}
+
+	if p.config.NextCursorPointer == "" || p.config.CursorParam == "" {
+		return nil, false
+	}
+	raw, ok := lookupJSONPointer(doc, p.config.NextCursorPointer)
+	if !ok || raw == nil {
+		return nil, false
+	}
+	cursor := formatScalar(raw)
+	if cursor == "" {
+		return nil, false
+	}
+	if _, seen := visited[cursor]; seen {
+		return nil, false
+	}
+	visited[cursor] = struct{}{}
+
+	req := prev.Clone(prev.Context())
+	query := req.URL.Query()
+	query.Set(p.config.CursorParam, cursor)
+	req.URL.RawQuery = query.Encode()
+	return req, true
+}
+
+// readPageDocument drains the response body (up to maxBytes) and decodes it as
+// JSON, restoring the body so the response can still be processed when decoding
+// fails. A read error stays attached to the restored body.
+func readPageDocument(resp *http.Response, maxBytes int64) (*http.Response, interface{}, bool) {
+	if err := decodeContentEncoding(resp); err != nil {
+		return resp, nil, false
+	}
+	body, err := io.ReadAll(limitResponseBody(resp.Body, maxBytes))
+	resp.Body.Close()
+	if err != nil {
+		resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), failingReader{err: err}))
+		return resp, nil, false
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var doc interface{}
+	if err := json.Unmarshal(bytes.TrimSpace(body), &doc); err != nil {
+		return resp, nil, false
+	}
+	return resp, doc, true
+}
+
+func nextLinkURL(values []string) string {
+	for _, header := range values {
+		for _, part := range strings.Split(header, ",") {
+			segments := strings.Split(part, ";")
+			target := strings.TrimSpace(segments[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for _, attr := range segments[1:] {
+				key, value, found := strings.Cut(strings.TrimSpace(attr), "=")
+				if !found || !strings.EqualFold(strings.TrimSpace(key), "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(value), `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func splitJSONPointer(pointer string) []string {
+	pointer = strings.TrimPrefix(pointer, "#")
+	if pointer == "" || pointer == "/" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		token = strings.ReplaceAll(token, "~1", "/")
+		tokens[i] = strings.ReplaceAll(token, "~0", "~")
+	}
+	return tokens
+}
+
+func lookupJSONPointer(doc interface{}, pointer string) (interface{}, bool) {
+	return lookupPointerTokens(doc, splitJSONPointer(pointer))
+}
+
+func lookupPointerTokens(doc interface{}, tokens []string) (interface{}, bool) {
+	current := doc
+	for _, token := range tokens {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			next, ok := node[token]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, false
+			}
+			current = node[idx]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func setJSONPointer(doc interface{}, pointer string, value interface{}) bool {
+	tokens := splitJSONPointer(pointer)
+	if len(tokens) == 0 {
+		return false
+	}
+	parent, ok := lookupPointerTokens(doc, tokens[:len(tokens)-1])
+	if !ok {
+		return false
+	}
+	last := tokens[len(tokens)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+		return true
+	case []interface{}:
+		idx, err := strconv.Atoi(last)
+		if err != nil || idx < 0 || idx >= len(node) {
+			return false
+		}
+		node[idx] = value
+		return true
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package executor
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type pageClient struct {
+	pages    map[string]*http.Response
+	requests []string
+}
+
+func (c *pageClient) Do(req *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req.URL.String())
+	if resp, ok := c.pages[req.URL.String()]; ok {
+		return resp, nil
+	}
+	return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Header: http.Header{}, Body: http.NoBody}, nil
+}
+
+func jsonPage(body string, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestPaginatorFollowsBodyCursor(t *testing.T) {
+	client := &pageClient{pages: map[string]*http.Response{
+		"https://api.example.com/items?limit=2":          jsonPage(`{"data":[1,2],"meta":{"next":"b"}}`, nil),
+		"https://api.example.com/items?cursor=b&limit=2": jsonPage(`{"data":[3],"meta":{"next":"b"}}`, nil),
+	}}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/items?limit=2", nil)
+	resp, err := newPaginator(client, PaginationConfig{
+		NextCursorPointer: "/meta/next",
+		CursorParam:       "cursor",
+		ItemsPointer:      "/data",
+	}).fetch(req)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	data, _ := result["data"].([]interface{})
+	if len(data) != 3 {
+		t.Fatalf("expected 3 merged items, got %v", result["data"])
+	}
+	if len(client.requests) != 2 {
+		t.Fatalf("expected repeated cursor to stop pagination, got requests %v", client.requests)
+	}
+}
+
+func TestPaginatorFollowsLinkHeaderWithPageCap(t *testing.T) {
+	link := func(next string) http.Header {
+		return http.Header{"Link": []string{`<` + next + `>; rel="next", </items?page=1>; rel="first"`}}
+	}
+	client := &pageClient{pages: map[string]*http.Response{
+		"https://api.example.com/items":        jsonPage(`["a"]`, link("/items?page=2")),
+		"https://api.example.com/items?page=2": jsonPage(`["b"]`, link("/items?page=3")),
+		"https://api.example.com/items?page=3": jsonPage(`["c"]`, link("/items?page=4")),
+	}}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/items", nil)
+	resp, err := newPaginator(client, PaginationConfig{UseLinkHeader: true, MaxPages: 2}).fetch(req)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+
+	var items []string
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if strings.Join(items, ",") != "a,b" {
+		t.Fatalf("expected page cap to stop after two pages, got %v", items)
+	}
+}
+
+func TestPaginatorKeepsMergedPagesWhenALaterPageFails(t *testing.T) {
+	link := func(next string) http.Header {
+		return http.Header{"Link": []string{`<` + next + `>; rel="next"`}}
+	}
+	client := &pageClient{pages: map[string]*http.Response{
+		"https://api.example.com/items":        jsonPage(`["a"]`, link("/items?page=2")),
+		"https://api.example.com/items?page=2": jsonPage(`["b"]`, link("/items")),
+	}}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/items", nil)
+	pager := newPaginator(client, PaginationConfig{UseLinkHeader: true})
+	if _, err := pager.fetch(req); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if len(client.requests) != 2 || pager.truncated != nil {
+		t.Fatalf("expected a link back to the first page to stop pagination, got requests %v", client.requests)
+	}
+
+	client = &pageClient{pages: map[string]*http.Response{
+		"https://api.example.com/items":        jsonPage(`["a"]`, link("/items?page=2")),
+		"https://api.example.com/items?page=2": jsonPage(`["b"]`, link("/items?page=3")),
+	}}
+	req, _ = http.NewRequest(http.MethodGet, "https://api.example.com/items", nil)
+	pager = newPaginator(client, PaginationConfig{UseLinkHeader: true})
+	resp, err := pager.fetch(req)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	var items []string
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if strings.Join(items, ",") != "a,b" || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the merged pages to be kept, got %v %d", items, resp.StatusCode)
+	}
+	if pager.truncated == nil || pager.truncated.page != 3 || pager.truncated.reason != "HTTP 404" {
+		t.Fatalf("expected the truncation to be reported, got %+v", pager.truncated)
+	}
+	fields := pager.truncated.meta().AdditionalFields
+	if fields["paginationTruncated"] != true || fields["paginationFailedPage"] != 3 {
+		t.Fatalf("unexpected truncation meta %v", fields)
+	}
+}
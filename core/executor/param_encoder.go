@@ -1,7 +1,10 @@
 package executor
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
@@ -20,6 +23,18 @@ func encodeParameterValues(param ir.ParameterInfo, value interface{}) ([]Encoded
 		return nil, nil
 	}
 
+	if isJSONParameterContent(param) {
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(value); err != nil {
+			return nil, fmt.Errorf("failed to encode parameter %s as JSON: %w", param.Name, err)
+		}
+		data := strings.TrimSuffix(buf.String(), "\n")
+		return []EncodedParameter{{Name: param.Name, Value: data, AllowReserved: param.AllowReserved}}, nil
+	}
+	value = formatTupleValue(value, param.Schema)
+
 	style := param.Style
 	if style == "" {
 		style = defaultStyleForLocation(param.In)
@@ -41,9 +56,9 @@ func encodeParameterValues(param ir.ParameterInfo, value interface{}) ([]Encoded
 	case "simple":
 		encoded = encodeSimpleStyle(param.Name, value, explode)
 	case "spaceDelimited":
-		encoded, err = encodeDelimitedStyle(param.Name, value, " ")
+		encoded, err = encodeDelimitedStyle(param.Name, value, " ", explode)
 	case "pipeDelimited":
-		encoded, err = encodeDelimitedStyle(param.Name, value, "|")
+		encoded, err = encodeDelimitedStyle(param.Name, value, "|", explode)
 	case "deepObject":
 		encoded = encodeDeepObjectStyle(param.Name, value)
 	default:
@@ -67,6 +82,17 @@ func encodeParameterValues(param ir.ParameterInfo, value interface{}) ([]Encoded
 	return encoded, nil
 }
 
+// isJSONParameterContent reports whether the parameter is declared via `content`
+// with a JSON media type, in which case style/explode do not apply.
+func isJSONParameterContent(param ir.ParameterInfo) bool {
+	for mediaType := range param.Content {
+		if strings.Contains(strings.ToLower(mediaType), "json") {
+			return true
+		}
+	}
+	return false
+}
+
 func defaultStyleForLocation(location string) string {
 	switch location {
 	case ir.ParameterInPath, ir.ParameterInHeader:
@@ -155,11 +181,16 @@ func encodeSimpleStyle(name string, value interface{}, explode bool) []EncodedPa
 	return []EncodedParameter{{Name: name, Value: formatScalar(value)}}
 }
 
-func encodeDelimitedStyle(name string, value interface{}, delimiter string) ([]EncodedParameter, error) {
+// encodeDelimitedStyle joins array items with delimiter; exploded arrays repeat
+// the key per item instead (name=a&name=b), as for form style.
+func encodeDelimitedStyle(name string, value interface{}, delimiter string, explode bool) ([]EncodedParameter, error) {
 	arr, ok := valueAsSlice(value)
 	if !ok {
 		return nil, fmt.Errorf("%s style requires array value", delimiter)
 	}
+	if explode {
+		return encodeSlice(name, arr), nil
+	}
 	return []EncodedParameter{
 		{
 			Name:  name,
@@ -296,3 +327,38 @@ func formatScalar(value interface{}) string {
 		return fmt.Sprintf("%v", value)
 	}
 }
+
+// tupleItemSchema returns the schema for position i of an array: its prefixItems
+// entry when present, otherwise the schema in `items`.
+func tupleItemSchema(schema ir.Schema, i int) ir.Schema {
+	if prefixItems, ok := schema["prefixItems"].([]interface{}); ok && i < len(prefixItems) {
+		return schemaFromValue(prefixItems[i])
+	}
+	return schemaFromValue(schema["items"])
+}
+
+// formatTupleValue stringifies each element of a tuple-typed array (prefixItems)
+// with its positional schema, so the style serializers only join the results.
+// Other values are returned unchanged.
+func formatTupleValue(value interface{}, schema ir.Schema) interface{} {
+	if _, ok := schema["prefixItems"].([]interface{}); !ok {
+		return value
+	}
+	arr, ok := valueAsSlice(value)
+	if !ok {
+		return value
+	}
+	formatted := make([]interface{}, len(arr))
+	for i, item := range arr {
+		formatted[i] = formatTupleItem(item, tupleItemSchema(schema, i))
+	}
+	return formatted
+}
+
+func formatTupleItem(value interface{}, schema ir.Schema) string {
+	value, _ = coerceValueForSchema(value, schema)
+	if f, ok := value.(float64); ok && schemaAllowsType(schema, "integer") && f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return formatScalar(value)
+}
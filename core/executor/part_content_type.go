@@ -0,0 +1,125 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// multipartMediaTypes returns the contentMediaType of each body property, or of
+// its items for an array, used as the part Content-Type when the encoding
+// object declares none.
+func (rb *RequestBuilder) multipartMediaTypes() map[string]string {
+	resolver := schemaResolver{defs: rb.route.SchemaDefs}
+	properties, _ := variantShape(rb.lookupBodySchema(rb.bodyContentType), resolver, 0)
+	mediaTypes := make(map[string]string)
+	for name, prop := range properties {
+		prop, _ = resolver.resolve(prop)
+		if items := schemaItems(prop); items != nil && prop.Type() == "array" {
+			prop, _ = resolver.resolve(items)
+		}
+		if mediaType, ok := prop["contentMediaType"].(string); ok && strings.TrimSpace(mediaType) != "" {
+			mediaTypes[name] = strings.TrimSpace(mediaType)
+		}
+	}
+	return mediaTypes
+}
+
+// multipartFileArrays reports the body properties that are arrays of binary
+// items, sent as one part per element under the property's name.
+func (rb *RequestBuilder) multipartFileArrays() map[string]bool {
+	resolver := schemaResolver{defs: rb.route.SchemaDefs}
+	properties, _ := variantShape(rb.lookupBodySchema(rb.bodyContentType), resolver, 0)
+	arrays := make(map[string]bool)
+	for name, prop := range properties {
+		prop, _ = resolver.resolve(prop)
+		if prop.Type() != "array" {
+			continue
+		}
+		item, _ := resolver.resolve(schemaItems(prop))
+		if item != nil && item.Type() != "array" && schemaIndicatesBinary(item) {
+			arrays[name] = true
+		}
+	}
+	return arrays
+}
+
+// multipartArrayElements splits an array value into its elements.
+func multipartArrayElements(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case [][]byte:
+		elements := make([]interface{}, len(v))
+		for i, item := range v {
+			elements[i] = item
+		}
+		return elements, true
+	case []FileUpload:
+		elements := make([]interface{}, len(v))
+		for i, item := range v {
+			elements[i] = item
+		}
+		return elements, true
+	}
+	return valueAsSlice(value)
+}
+
+// isMediaRange reports whether declared is a wildcard such as image/* or a
+// comma-separated list rather than one concrete media type.
+func isMediaRange(declared string) bool {
+	return strings.ContainsAny(declared, "*,")
+}
+
+// resolvePartContentType turns a declared part content type into a concrete
+// one. A single concrete type is used as is. For a wildcard or a list, the
+// first entry matching detected, the type of the actual bytes, wins: a
+// concrete entry is sent as declared, a wildcard as detected. Nothing matching
+// falls back to application/octet-stream.
+func resolvePartContentType(declared, detected string) string {
+	if !isMediaRange(declared) {
+		return strings.TrimSpace(declared)
+	}
+	base := baseMediaType(detected)
+	for _, candidate := range strings.Split(declared, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "" || base == "" || !mediaRangeMatches(baseMediaType(candidate), base) {
+			continue
+		}
+		if strings.Contains(candidate, "*") {
+			return detected
+		}
+		return candidate
+	}
+	return "application/octet-stream"
+}
+
+// detectPartContentType sniffs the type of an in-memory part value. Values
+// that are not bytes or text are written as JSON.
+func detectPartContentType(value interface{}) string {
+	switch v := value.(type) {
+	case []byte:
+		return http.DetectContentType(v)
+	case string:
+		return http.DetectContentType([]byte(v))
+	case fmt.Stringer:
+		return http.DetectContentType([]byte(v.String()))
+	default:
+		return "application/json"
+	}
+}
+
+// sniffFileContentType detects the type of a file from its first 512 bytes.
+func sniffFileContentType(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	if n == 0 {
+		return ""
+	}
+	return http.DetectContentType(head[:n])
+}
@@ -6,18 +6,38 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/santhosh-tekuri/jsonschema/v5"
 	"github.com/specx2/openapi-mcp/core/ir"
 )
 
+// ResponseTransformer reshapes a decoded response body before it is validated
+// against the output schema. The route lets one transformer branch per operation.
+type ResponseTransformer func(route ir.HTTPRoute, data interface{}) (interface{}, error)
+
 type ResponseProcessor struct {
 	outputSchema ir.Schema
 	wrapResult   bool
 	errorHandler *ErrorHandler
 	validator    *jsonschema.Schema
+
+	route        ir.HTTPRoute
+	transformer  ResponseTransformer
+	maxBytes     int64
+	audit        bool
+	auditStarted time.Time
+	csv          CSVOptions
+	schemaStatus string
+	streaming    bool
+	onRecord     func(record interface{})
+	envelope     bool
+	payload      interface{}
+	hasPayload   bool
+	extraMeta    *mcp.Meta
 }
 
 func NewResponseProcessor(outputSchema ir.Schema, wrapResult bool, errorHandler *ErrorHandler) *ResponseProcessor {
@@ -30,26 +50,174 @@ func NewResponseProcessor(outputSchema ir.Schema, wrapResult bool, errorHandler
 		wrapResult:   wrapResult,
 		errorHandler: errorHandler,
 		validator:    validator,
+		csv:          DefaultCSVOptions(),
 	}
 }
 
+// WithTransformer runs fn on every decoded success body for route.
+func (rp *ResponseProcessor) WithTransformer(route ir.HTTPRoute, fn ResponseTransformer) *ResponseProcessor {
+	rp.route = route
+	rp.transformer = fn
+	return rp
+}
+
+// WithMaxResponseBytes caps how much of a (decoded) response body is read; larger
+// bodies produce an error result instead of partial data. Zero means no limit.
+func (rp *ResponseProcessor) WithMaxResponseBytes(limit int64) *ResponseProcessor {
+	rp.maxBytes = limit
+	return rp
+}
+
+// WithCSVOptions sets how text/csv bodies are split into rows.
+func (rp *ResponseProcessor) WithCSVOptions(delimiter rune, hasHeader bool) *ResponseProcessor {
+	rp.csv = CSVOptions{Delimiter: delimiter, HasHeader: hasHeader}
+	return rp
+}
+
+// WithStreamingProgress reads text/event-stream bodies event by event into a
+// list of records, as for NDJSON, instead of returning the raw stream. onRecord,
+// which may be nil, sees every event or NDJSON record as it arrives.
+func (rp *ResponseProcessor) WithStreamingProgress(onRecord func(record interface{})) *ResponseProcessor {
+	rp.streaming = true
+	rp.onRecord = onRecord
+	return rp
+}
+
+// WithSchemaStatus restricts the output schema to responses with status, such
+// as "200" or "2XX". A 202 Accepted body is neither checked against the 200
+// schema nor returned as structured content, which clients would hold to the
+// advertised schema; its result carries the body as text only.
+func (rp *ResponseProcessor) WithSchemaStatus(status string) *ResponseProcessor {
+	rp.schemaStatus = status
+	return rp
+}
+
+// WithMeta adds meta to the _meta of every result, alongside the response
+// metadata.
+func (rp *ResponseProcessor) WithMeta(meta *mcp.Meta) *ResponseProcessor {
+	rp.extraMeta = meta
+	return rp
+}
+
 func (rp *ResponseProcessor) Process(resp *http.Response) (*mcp.CallToolResult, error) {
+	result, err := rp.process(resp)
+	if err == nil && rp.extraMeta != nil {
+		result.Result.Meta = mergeMeta(result.Result.Meta, rp.extraMeta)
+	}
+	if err == nil && rp.envelope && !result.IsError {
+		rp.wrapInEnvelope(result, resp)
+	}
+	if err == nil && rp.audit {
+		rp.attachAudit(result, resp)
+	}
+	if err == nil && !result.IsError && rp.outsideSchemaStatus(resp) {
+		result.StructuredContent = nil
+	}
+	return result, err
+}
+
+// outsideSchemaStatus reports whether the output schema does not describe the
+// response's status.
+func (rp *ResponseProcessor) outsideSchemaStatus(resp *http.Response) bool {
+	return rp.outputSchema != nil && rp.schemaStatus != "" && !statusMatches(rp.schemaStatus, resp.StatusCode)
+}
+
+func (rp *ResponseProcessor) process(resp *http.Response) (*mcp.CallToolResult, error) {
+	if err := decodeContentEncoding(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	resp.Body = limitResponseBody(resp.Body, rp.maxBytes)
 	defer resp.Body.Close()
 
 	meta := buildResponseMeta(resp)
+	headers := rp.declaredHeaders(resp)
 
 	if resp.StatusCode >= 400 {
 		return rp.processError(resp, meta)
 	}
 
+	if isRedirectResponse(resp) {
+		return rp.processRedirect(resp, meta), nil
+	}
+
+	if rp.outsideSchemaStatus(resp) {
+		rp.validator = nil
+	}
+
+	// HEAD never has a body; its headers are the result
+	method := rp.responseMethod(resp)
+	if method == http.MethodHead {
+		return rp.processHeadersOnly(resp, method, headers, meta), nil
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if isJSONStreamContentType(contentType) || (rp.streaming && isEventStreamContentType(contentType)) {
+		var records []interface{}
+		var err error
+		if isJSONStreamContentType(contentType) {
+			records, err = decodeJSONStream(resp.Body, rp.onRecord)
+		} else {
+			records, err = decodeEventStream(resp.Body, rp.onRecord)
+		}
+		if tooLarge, ok := isBodyTooLarge(err); ok {
+			return rp.processTooLarge(tooLarge, meta), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response stream: %w", err)
+		}
+		toolResult, err := rp.processJSON(records, headers)
+		if err != nil {
+			return nil, err
+		}
+		toolResult.Result.Meta = mergeMeta(toolResult.Result.Meta, meta)
+		return toolResult, nil
+	}
+
+	// CSV is parsed into rows only when the spec describes the body as an
+	// array or not at all; a string schema keeps the raw text below.
+	if schema := rp.bodySchema(); isCSVContentType(resp.Header.Get("Content-Type")) && (schema == nil || schemaAllowsType(schema, "array")) {
+		rows, err := decodeCSVBody(resp.Body, rp.csv, resp.Header.Get("Content-Type"), schema)
+		if tooLarge, ok := isBodyTooLarge(err); ok {
+			return rp.processTooLarge(tooLarge, meta), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return rp.processCSV(rows, headers, meta)
+	}
+
 	body, err := io.ReadAll(resp.Body)
+	if tooLarge, ok := isBodyTooLarge(err); ok {
+		return rp.processTooLarge(tooLarge, meta), nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	if decode := lookupContentDecoder(resp.Header.Get("Content-Type")); decode != nil && len(body) > 0 {
+		decoded, err := decode(body, rp.bodySchema())
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s response: %w", baseMediaType(resp.Header.Get("Content-Type")), err)
+		}
+		toolResult, err := rp.processJSON(decoded, headers)
+		if err != nil {
+			return nil, err
+		}
+		toolResult.Result.Meta = mergeMeta(toolResult.Result.Meta, meta)
+		return toolResult, nil
+	}
+
+	if len(body) > 0 && isBinaryResponse(resp.Header.Get("Content-Type"), body) {
+		return rp.processBinary(resp, body, meta), nil
+	}
+
 	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 && method == http.MethodOptions {
+		return rp.processHeadersOnly(resp, method, headers, meta), nil
+	}
 	if len(trimmed) == 0 {
-		structured := rp.prepareStructuredResult(nil)
+		structured := withDeclaredHeaders(rp.prepareStructuredResult(nil), headers)
 		return &mcp.CallToolResult{
 			StructuredContent: structured,
 			Content:           buildStructuredTextContent(structured),
@@ -57,9 +225,36 @@ func (rp *ResponseProcessor) Process(resp *http.Response) (*mcp.CallToolResult,
 		}, nil
 	}
 
+	if isXMLContentType(resp.Header.Get("Content-Type")) {
+		if decoded, err := decodeXMLBody(trimmed, rp.bodySchema(), rp.outputSchema); err == nil {
+			toolResult, err := rp.processJSON(decoded, headers)
+			if err != nil {
+				return nil, err
+			}
+			toolResult.Result.Meta = mergeMeta(toolResult.Result.Meta, meta)
+			return toolResult, nil
+		}
+	}
+
+	if isYAMLContentType(resp.Header.Get("Content-Type")) {
+		if decoded, err := decodeYAMLBody(trimmed); err == nil {
+			toolResult, err := rp.processJSON(decoded, headers)
+			if err != nil {
+				return nil, err
+			}
+			toolResult.Result.Meta = mergeMeta(toolResult.Result.Meta, meta)
+			return toolResult, nil
+		}
+	}
+
+	// HTML, plain text and other non-JSON text is returned as is, even when it happens to parse as JSON
+	if isRawTextContentType(resp.Header.Get("Content-Type")) || isCSVContentType(resp.Header.Get("Content-Type")) {
+		return rp.processRawText(trimmed, headers, meta), nil
+	}
+
 	var result interface{}
 	if err := json.Unmarshal(trimmed, &result); err == nil {
-		toolResult, err := rp.processJSON(result)
+		toolResult, err := rp.processJSON(result, headers)
 		if err != nil {
 			return nil, err
 		}
@@ -75,7 +270,25 @@ func (rp *ResponseProcessor) Process(resp *http.Response) (*mcp.CallToolResult,
 	}, nil
 }
 
-func (rp *ResponseProcessor) processJSON(result interface{}) (*mcp.CallToolResult, error) {
+// processJSON validates the body and then attaches the declared response headers,
+// which the spec's body schema does not describe.
+func (rp *ResponseProcessor) processJSON(result interface{}, headers map[string]interface{}) (*mcp.CallToolResult, error) {
+	if rp.transformer != nil {
+		transformed, err := rp.transformer(rp.route, result)
+		if err != nil {
+			if rp.errorHandler != nil {
+				return rp.errorHandler.HandleResponseError(err), nil
+			}
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					mcp.NewTextContent("Failed to process response: " + err.Error()),
+				},
+			}, nil
+		}
+		result = transformed
+	}
+
 	structured := rp.prepareStructuredResult(result)
 	if rp.validator != nil {
 		if err := rp.validator.Validate(structured); err != nil {
@@ -90,13 +303,27 @@ func (rp *ResponseProcessor) processJSON(result interface{}) (*mcp.CallToolResul
 			}, nil
 		}
 	}
+	structured = withDeclaredHeaders(structured, headers)
 	return &mcp.CallToolResult{
 		StructuredContent: structured,
 		Content:           buildStructuredTextContent(structured),
 	}, nil
 }
 
+// bodySchema returns the schema describing the raw response body, unwrapping the
+// synthetic "result" envelope used for non-object responses.
+func (rp *ResponseProcessor) bodySchema() ir.Schema {
+	if rp.outputSchema == nil {
+		return nil
+	}
+	if rp.wrapResult {
+		return rp.outputSchema.Properties()["result"]
+	}
+	return rp.outputSchema
+}
+
 func (rp *ResponseProcessor) prepareStructuredResult(result interface{}) map[string]interface{} {
+	rp.payload, rp.hasPayload = result, true
 	if rp.wrapResult {
 		return map[string]interface{}{"result": result}
 	}
@@ -119,6 +346,32 @@ func buildStructuredTextContent(structured map[string]interface{}) []mcp.Content
 	return []mcp.Content{mcp.NewTextContent(string(data))}
 }
 
+// isRedirectResponse reports an unfollowed 3xx carrying a Location header, as
+// returned when the client's redirect policy does not follow redirects.
+func isRedirectResponse(resp *http.Response) bool {
+	return resp.StatusCode >= 300 && resp.StatusCode < 400 && resp.Header.Get("Location") != ""
+}
+
+// processRedirect returns the redirect target instead of the (usually empty) body,
+// e.g. a signed storage URL the caller can fetch directly.
+func (rp *ResponseProcessor) processRedirect(resp *http.Response, meta *mcp.Meta) *mcp.CallToolResult {
+	location := resp.Header.Get("Location")
+	if resp.Request != nil && resp.Request.URL != nil {
+		if resolved, err := resp.Request.URL.Parse(location); err == nil {
+			location = resolved.String()
+		}
+	}
+	structured := map[string]interface{}{
+		"status":   resp.StatusCode,
+		"location": location,
+	}
+	return &mcp.CallToolResult{
+		StructuredContent: structured,
+		Content:           []mcp.Content{mcp.NewTextContent(fmt.Sprintf("HTTP %d redirect to %s", resp.StatusCode, location))},
+		Result:            mcp.Result{Meta: cloneMeta(meta)},
+	}
+}
+
 func buildResponseMeta(resp *http.Response) *mcp.Meta {
 	if resp == nil {
 		return nil
@@ -224,6 +477,16 @@ func (rp *ResponseProcessor) processError(resp *http.Response, meta *mcp.Meta) (
 		}, nil
 	}
 
+	if upstream, ok := rp.upstreamError(resp, body); ok {
+		handler := rp.errorHandler
+		if handler == nil {
+			handler = NewErrorHandler("info")
+		}
+		result := handler.HandleUpstreamError(resp, body, upstream)
+		result.Result.Meta = mergeMeta(result.Result.Meta, meta)
+		return result, nil
+	}
+
 	if rp.errorHandler != nil {
 		result := rp.errorHandler.HandleHTTPResponse(resp, body)
 		result.Result.Meta = mergeMeta(result.Result.Meta, meta)
@@ -250,3 +513,18 @@ func (rp *ResponseProcessor) processError(resp *http.Response, meta *mcp.Meta) (
 		Result: mcp.Result{Meta: cloneMeta(meta)},
 	}, nil
 }
+
+// statusMatches compares a response status with a declared one, where "2XX"
+// covers the whole class.
+func statusMatches(declared string, status int) bool {
+	code := strconv.Itoa(status)
+	if len(declared) != len(code) {
+		return false
+	}
+	for i := range declared {
+		if declared[i] != code[i] && declared[i] != 'X' && declared[i] != 'x' {
+			return false
+		}
+	}
+	return true
+}
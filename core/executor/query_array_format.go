@@ -0,0 +1,58 @@
+package executor
+
+import "github.com/specx2/openapi-mcp/core/ir"
+
+// QueryArrayFormat is how array query parameters without a declared style are
+// sent.
+type QueryArrayFormat string
+
+const (
+	// QueryArrayRepeat repeats the key per value: ids=1&ids=2 (form, exploded).
+	QueryArrayRepeat QueryArrayFormat = "repeat"
+	// QueryArrayComma joins the values: ids=1,2 (form, not exploded).
+	QueryArrayComma QueryArrayFormat = "comma"
+	// QueryArrayBrackets repeats a bracketed key per value: ids[]=1&ids[]=2.
+	QueryArrayBrackets QueryArrayFormat = "brackets"
+	// QueryArraySpaceDelimited joins the values with spaces: ids=1%202.
+	QueryArraySpaceDelimited QueryArrayFormat = "spaceDelimited"
+	// QueryArrayPipeDelimited joins the values with pipes: ids=1|2.
+	QueryArrayPipeDelimited QueryArrayFormat = "pipeDelimited"
+)
+
+// WithDefaultQueryArrayFormat sets how array query parameters are sent when the
+// spec declares neither style nor explode for them. The empty format keeps the
+// OpenAPI default, form with explode, as do unknown formats.
+func (rb *RequestBuilder) WithDefaultQueryArrayFormat(format QueryArrayFormat) *RequestBuilder {
+	rb.queryArrayFormat = format
+	return rb
+}
+
+// encodeQueryParameter encodes a query parameter, applying the default array
+// format to arrays whose parameter leaves the serialization unspecified.
+func (rb *RequestBuilder) encodeQueryParameter(param ir.ParameterInfo, value interface{}) ([]EncodedParameter, error) {
+	if rb.queryArrayFormat == "" || param.Style != "" || param.Explode != nil || isJSONParameterContent(param) {
+		return encodeParameterValues(param, value)
+	}
+	if _, ok := valueAsSlice(formatTupleValue(value, param.Schema)); !ok {
+		return encodeParameterValues(param, value)
+	}
+
+	explode := false
+	switch rb.queryArrayFormat {
+	case QueryArrayComma:
+		param.Style = "form"
+	case QueryArraySpaceDelimited:
+		param.Style = "spaceDelimited"
+	case QueryArrayPipeDelimited:
+		param.Style = "pipeDelimited"
+	case QueryArrayBrackets:
+		param.Name += "[]"
+		param.Style = "form"
+		explode = true
+	default:
+		param.Style = "form"
+		explode = true
+	}
+	param.Explode = &explode
+	return encodeParameterValues(param, value)
+}
@@ -0,0 +1,55 @@
+package executor
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func TestDefaultQueryArrayFormatAppliesToUnstyledArrays(t *testing.T) {
+	explode := false
+	route := ir.HTTPRoute{
+		Path:   "/items",
+		Method: "GET",
+		Parameters: []ir.ParameterInfo{
+			{Name: "ids", In: ir.ParameterInQuery, Schema: ir.Schema{"type": "array"}},
+			{Name: "tags", In: ir.ParameterInQuery, Style: "pipeDelimited", Explode: &explode, Schema: ir.Schema{"type": "array"}},
+			{Name: "q", In: ir.ParameterInQuery, Schema: ir.Schema{"type": "string"}},
+		},
+	}
+	paramMap := map[string]ir.ParamMapping{
+		"ids":  {OpenAPIName: "ids", Location: ir.ParameterInQuery},
+		"tags": {OpenAPIName: "tags", Location: ir.ParameterInQuery},
+		"q":    {OpenAPIName: "q", Location: ir.ParameterInQuery},
+	}
+	args := map[string]interface{}{
+		"ids":  []interface{}{float64(1), float64(2)},
+		"tags": []interface{}{"a", "b"},
+		"q":    "x",
+	}
+
+	cases := map[QueryArrayFormat]string{
+		"":                       "ids=1&ids=2&q=x&tags=a%7Cb",
+		QueryArrayRepeat:         "ids=1&ids=2&q=x&tags=a%7Cb",
+		QueryArrayComma:          "ids=1%2C2&q=x&tags=a%7Cb",
+		QueryArrayBrackets:       "ids%5B%5D=1&ids%5B%5D=2&q=x&tags=a%7Cb",
+		QueryArraySpaceDelimited: "ids=1+2&q=x&tags=a%7Cb",
+		QueryArrayPipeDelimited:  "ids=1%7C2&q=x&tags=a%7Cb",
+	}
+	for format, want := range cases {
+		req, err := NewRequestBuilder(route, paramMap, "https://api.example.com").
+			WithDefaultQueryArrayFormat(format).
+			Build(context.Background(), args)
+		if err != nil {
+			t.Fatalf("%q: build failed: %v", format, err)
+		}
+		pairs := strings.Split(req.URL.RawQuery, "&")
+		sort.Strings(pairs)
+		if got := strings.Join(pairs, "&"); got != want {
+			t.Fatalf("%q: expected query %q, got %q", format, want, got)
+		}
+	}
+}
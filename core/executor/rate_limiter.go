@@ -0,0 +1,146 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a per-host token bucket that also pauses a host when the
+// upstream reports an exhausted quota (X-RateLimit-Remaining: 0 until
+// X-RateLimit-Reset) or answers 429/503 with Retry-After. Pauses are absolute
+// deadlines, so a caller that already waited for Retry-After is not delayed again.
+type rateLimiter struct {
+	mu        sync.Mutex
+	perSecond float64
+	burst     float64
+	hosts     map[string]*hostLimit
+	now       func() time.Time
+}
+
+type hostLimit struct {
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+func newRateLimiter(perSecond float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		perSecond: perSecond,
+		burst:     float64(burst),
+		hosts:     make(map[string]*hostLimit),
+		now:       time.Now,
+	}
+}
+
+// wait blocks until a request to host may be sent or ctx is done.
+func (rl *rateLimiter) wait(ctx context.Context, host string) error {
+	for {
+		delay := rl.reserve(host)
+		if delay <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token and returns zero, or returns how long to wait before retrying.
+func (rl *rateLimiter) reserve(host string) time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	state := rl.hosts[host]
+	if state == nil {
+		state = &hostLimit{tokens: rl.burst, last: now}
+		rl.hosts[host] = state
+	}
+	if now.Before(state.blockedUntil) {
+		return state.blockedUntil.Sub(now)
+	}
+	if rl.perSecond <= 0 {
+		return 0
+	}
+
+	state.tokens += now.Sub(state.last).Seconds() * rl.perSecond
+	if state.tokens > rl.burst {
+		state.tokens = rl.burst
+	}
+	state.last = now
+	if state.tokens >= 1 {
+		state.tokens--
+		return 0
+	}
+	return time.Duration((1 - state.tokens) / rl.perSecond * float64(time.Second))
+}
+
+// observe records quota information from a response.
+func (rl *rateLimiter) observe(host string, resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	now := rl.now()
+
+	var until time.Time
+	if remaining, err := strconv.Atoi(strings.TrimSpace(resp.Header.Get("X-RateLimit-Remaining"))); err == nil && remaining <= 0 {
+		until = parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset"), now)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		if retryAt := parseRetryAfter(resp.Header.Get("Retry-After"), now); retryAt.After(until) {
+			until = retryAt
+		}
+	}
+	if until.IsZero() || !until.After(now) {
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	state := rl.hosts[host]
+	if state == nil {
+		state = &hostLimit{tokens: rl.burst, last: now}
+		rl.hosts[host] = state
+	}
+	if until.After(state.blockedUntil) {
+		state.blockedUntil = until
+	}
+}
+
+// parseRateLimitReset accepts either a Unix timestamp or a number of seconds from now.
+func parseRateLimitReset(value string, now time.Time) time.Time {
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || seconds <= 0 {
+		return time.Time{}
+	}
+	if seconds > 1e9 {
+		return time.Unix(0, int64(seconds*float64(time.Second)))
+	}
+	return now.Add(time.Duration(seconds * float64(time.Second)))
+}
+
+// parseRetryAfter accepts delta-seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return now.Add(time.Duration(seconds) * time.Second)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return at
+	}
+	return time.Time{}
+}
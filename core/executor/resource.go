@@ -11,7 +11,6 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/specx2/openapi-mcp/core/ir"
-	"github.com/specx2/openapi-mcp/core/internal"
 )
 
 type OpenAPIResource struct {
@@ -19,6 +18,8 @@ type OpenAPIResource struct {
 	route    ir.HTTPRoute
 	client   HTTPClient
 	baseURL  string
+
+	headerPropagation *HeaderPropagation
 }
 
 func NewOpenAPIResource(
@@ -34,7 +35,7 @@ func NewOpenAPIResource(
 		uri,
 		name,
 		mcp.WithResourceDescription(description),
-		mcp.WithMIMEType("application/json"),
+		mcp.WithMIMEType(resourceMIMEType(route)),
 	)
 
 	return &OpenAPIResource{
@@ -45,6 +46,12 @@ func NewOpenAPIResource(
 	}
 }
 
+// WithHeaderPropagation restricts which inbound MCP headers reach the upstream.
+func (r *OpenAPIResource) WithHeaderPropagation(policy *HeaderPropagation) *OpenAPIResource {
+	r.headerPropagation = policy
+	return r
+}
+
 func (r *OpenAPIResource) Resource() mcp.Resource {
 	return r.resource
 }
@@ -53,50 +60,108 @@ func (r *OpenAPIResource) SetResource(resource mcp.Resource) {
 	r.resource = resource
 }
 
+func (r *OpenAPIResource) GetRoute() ir.HTTPRoute {
+	return r.route
+}
+
 func (r *OpenAPIResource) Read(ctx context.Context) (string, error) {
-	reqURL, err := r.buildURL()
+	contents, err := r.ReadContents(ctx, r.resource.URI)
 	if err != nil {
 		return "", err
 	}
+	return resourceContentsText(contents), nil
+}
+
+// ReadContents reads the resource for a resources/read request. Binary payloads
+// are returned as blob contents, everything else as text.
+func (r *OpenAPIResource) ReadContents(ctx context.Context, uri string) ([]mcp.ResourceContents, error) {
+	reqURL, err := r.buildURL()
+	if err != nil {
+		return nil, err
+	}
+	return r.readContents(ctx, reqURL, uri, nil)
+}
 
+func (r *OpenAPIResource) readContents(ctx context.Context, reqURL, uri string, headerParams map[string]string) ([]mcp.ResourceContents, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if mcpHeaders := internal.GetMCPHeaders(ctx); mcpHeaders != nil {
-		for k, v := range mcpHeaders {
-			req.Header.Set(k, v)
-		}
+	applyMCPHeaders(ctx, req.Header, r.headerPropagation)
+
+	for headerName, headerValue := range headerParams {
+		req.Header.Set(headerName, headerValue)
 	}
 
 	resp, err := r.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	if err := decodeContentEncoding(resp); err != nil {
+		return nil, err
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	contentType := resp.Header.Get("Content-Type")
-	if strings.Contains(contentType, "json") {
+	if len(body) > 0 && isBinaryResponse(contentType, body) {
+		return []mcp.ResourceContents{binaryResourceContents(uri, resp, body)}, nil
+	}
+
+	mimeType := baseMediaType(contentType)
+	if mimeType == "" {
+		mimeType = r.resource.MIMEType
+	}
+
+	text := string(body)
+	if strings.Contains(mimeType, "json") {
 		var jsonResult interface{}
 		if json.Unmarshal(body, &jsonResult) == nil {
 			prettyJSON, err := json.MarshalIndent(jsonResult, "", "  ")
 			if err == nil {
-				return string(prettyJSON), nil
+				text = string(prettyJSON)
 			}
 		}
 	}
 
-	return string(body), nil
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: mimeType,
+		Text:     text,
+	}}, nil
+}
+
+// resourceMIMEType is the media type of the operation's preferred success
+// response, without parameters; application/json when none or only a range
+// such as */* is declared.
+func resourceMIMEType(route ir.HTTPRoute) string {
+	if mediaType := baseMediaType(preferredResponseContentType(route)); mediaType != "" && !strings.Contains(mediaType, "*") {
+		return mediaType
+	}
+	return "application/json"
+}
+
+// resourceContentsText flattens contents to a string; blobs stay base64-encoded.
+func resourceContentsText(contents []mcp.ResourceContents) string {
+	for _, content := range contents {
+		switch c := content.(type) {
+		case mcp.TextResourceContents:
+			return c.Text
+		case mcp.BlobResourceContents:
+			return c.Blob
+		}
+	}
+	return ""
 }
 
 func (r *OpenAPIResource) buildURL() (string, error) {
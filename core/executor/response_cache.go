@@ -0,0 +1,274 @@
+package executor
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// traceHeaders carry per-call trace context. They are the only request headers
+// left out of cache keys; any other header may select a representation or a
+// principal.
+var traceHeaders = map[string]bool{
+	"Traceparent":  true,
+	"Tracestate":   true,
+	"Baggage":      true,
+	"B3":           true,
+	"X-B3-Traceid": true,
+	"X-B3-Spanid":  true,
+	"X-B3-Sampled": true,
+}
+
+// ResponseCache keeps successful GET/HEAD responses of read-only tools in memory.
+// Entries expire after the configured TTL (or a shorter Cache-Control max-age) and
+// the least recently used entry is evicted when the cache is full. It is safe for
+// concurrent use and is meant to be shared by all tools of a server.
+type ResponseCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List // front is the most recently used entry
+	now        func() time.Time
+}
+
+type cachedResponse struct {
+	key     string
+	status  int
+	header  http.Header
+	body    []byte
+	vary    map[string]string
+	expires time.Time
+}
+
+// NewResponseCache creates a cache holding up to maxEntries responses for at
+// most ttl each. A non-positive maxEntries leaves the size unbounded.
+func NewResponseCache(ttl time.Duration, maxEntries int) *ResponseCache {
+	return &ResponseCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		now:        time.Now,
+	}
+}
+
+// Len returns the number of cached responses, expired ones included until they are looked up.
+func (c *ResponseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// get returns a copy of the cached response for req, if it is fresh and its Vary headers match.
+func (c *ResponseCache) get(req *http.Request) (*http.Response, bool) {
+	key := responseCacheKey(req)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cachedResponse)
+	if !c.now().Before(entry.expires) {
+		c.remove(elem)
+		return nil, false
+	}
+	for name, value := range entry.vary {
+		if req.Header.Get(name) != value {
+			return nil, false
+		}
+	}
+	c.lru.MoveToFront(elem)
+
+	return &http.Response{
+		Status:        strconv.Itoa(entry.status) + " " + http.StatusText(entry.status),
+		StatusCode:    entry.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        entry.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(entry.body)),
+		ContentLength: int64(len(entry.body)),
+		Request:       req,
+	}, true
+}
+
+// store caches resp when the request method, status and Cache-Control allow it.
+// The body is read up to maxBytes; the returned response replays it for the caller.
+func (c *ResponseCache) store(req *http.Request, resp *http.Response, maxBytes int64) *http.Response {
+	ttl, vary, ok := c.cacheable(req, resp)
+	if !ok {
+		return resp
+	}
+
+	body, err := io.ReadAll(limitResponseBody(resp.Body, maxBytes))
+	resp.Body.Close()
+	if err != nil {
+		resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), failingReader{err: err}))
+		return resp
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry := &cachedResponse{
+		key:     responseCacheKey(req),
+		status:  resp.StatusCode,
+		header:  resp.Header.Clone(),
+		body:    body,
+		vary:    vary,
+		expires: c.now().Add(ttl),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, exists := c.entries[entry.key]; exists {
+		c.remove(elem)
+	}
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+	return resp
+}
+
+// cacheable reports how long resp may be kept and the request header values it varies on.
+func (c *ResponseCache) cacheable(req *http.Request, resp *http.Response) (time.Duration, map[string]string, bool) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return 0, nil, false
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || resp.Body == nil {
+		return 0, nil, false
+	}
+	// event streams never end, so they cannot be buffered
+	if strings.HasPrefix(strings.ToLower(resp.Header.Get("Content-Type")), "text/event-stream") {
+		return 0, nil, false
+	}
+
+	ttl := c.ttl
+	for _, directive := range strings.Split(strings.ToLower(resp.Header.Get("Cache-Control")), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch name {
+		case "no-store", "no-cache":
+			return 0, nil, false
+		case "max-age":
+			seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+			if err != nil {
+				continue
+			}
+			if maxAge := time.Duration(seconds) * time.Second; maxAge < ttl {
+				ttl = maxAge
+			}
+		}
+	}
+	if ttl <= 0 {
+		return 0, nil, false
+	}
+
+	var vary map[string]string
+	for _, header := range resp.Header.Values("Vary") {
+		for _, name := range strings.Split(header, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if name == "*" {
+				return 0, nil, false
+			}
+			if vary == nil {
+				vary = make(map[string]string)
+			}
+			vary[name] = req.Header.Get(name)
+		}
+	}
+	return ttl, vary, true
+}
+
+func (c *ResponseCache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*cachedResponse)
+	delete(c.entries, entry.key)
+}
+
+// responseCacheKey hashes the method, URL and request headers, so requests
+// differing in any credential or negotiation header never share an entry.
+func responseCacheKey(req *http.Request) string {
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		if !traceHeaders[http.CanonicalHeaderKey(name)] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", req.Method, req.URL.String())
+	for _, name := range names {
+		fmt.Fprintf(hash, "%s:%q\n", http.CanonicalHeaderKey(name), req.Header[name])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type responseCacheContextKey struct{}
+
+// responseCacheLookup is a cache handed to the HTTP client with a request, so
+// the client can consult it once the request carries its final headers.
+type responseCacheLookup struct {
+	cache    *ResponseCache
+	maxBytes int64
+	hit      bool
+}
+
+// withResponseCache attaches cache to req; the returned lookup reports whether
+// the response was served from it.
+func withResponseCache(req *http.Request, cache *ResponseCache, maxBytes int64) (*http.Request, *responseCacheLookup) {
+	lookup := &responseCacheLookup{cache: cache, maxBytes: maxBytes}
+	return req.WithContext(context.WithValue(req.Context(), responseCacheContextKey{}, lookup)), lookup
+}
+
+func responseCacheLookupFrom(ctx context.Context) *responseCacheLookup {
+	lookup, _ := ctx.Value(responseCacheContextKey{}).(*responseCacheLookup)
+	return lookup
+}
+
+func (l *responseCacheLookup) get(req *http.Request) (*http.Response, bool) {
+	resp, ok := l.cache.get(req)
+	l.hit = l.hit || ok
+	return resp, ok
+}
+
+func (l *responseCacheLookup) store(req *http.Request, resp *http.Response) *http.Response {
+	return l.cache.store(req, resp, l.maxBytes)
+}
+
+// cachingClient consults the cache around an HTTPClient that is not a
+// DefaultHTTPClient. Such a client may still add headers of its own, which the
+// key cannot see.
+type cachingClient struct {
+	HTTPClient
+}
+
+func (c cachingClient) Do(req *http.Request) (*http.Response, error) {
+	lookup := responseCacheLookupFrom(req.Context())
+	if lookup == nil {
+		return c.HTTPClient.Do(req)
+	}
+	if resp, ok := lookup.get(req); ok {
+		return resp, nil
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err == nil {
+		resp = lookup.store(req, resp)
+	}
+	return resp, err
+}
@@ -0,0 +1,170 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+type countingClient struct {
+	calls        int
+	cacheControl string
+}
+
+func (c *countingClient) Do(req *http.Request) (*http.Response, error) {
+	c.calls++
+	header := http.Header{"Content-Type": []string{"application/json"}}
+	if c.cacheControl != "" {
+		header.Set("Cache-Control", c.cacheControl)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(`{"path":"` + req.URL.Path + `"}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestOpenAPIToolServesReadOnlyCallsFromCache(t *testing.T) {
+	newTool := func(method string, client HTTPClient, cache *ResponseCache) *OpenAPITool {
+		route := ir.HTTPRoute{Path: "/items/{id}", Method: method, Parameters: []ir.ParameterInfo{
+			{Name: "id", In: ir.ParameterInPath, Required: true, Schema: ir.Schema{"type": "string"}},
+		}}
+		paramMap := map[string]ir.ParamMapping{"id": {OpenAPIName: "id", Location: ir.ParameterInPath}}
+		return NewOpenAPITool("item", "", ir.Schema{"type": "object"}, nil, false, route, client, "https://api.example.com", paramMap, nil, nil).
+			WithResponseCache(cache)
+	}
+	call := func(tool *OpenAPITool, id string) {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{"id": id}
+		result, err := tool.Run(context.Background(), request)
+		if err != nil || result.IsError {
+			t.Fatalf("call failed: %v %#v", err, result)
+		}
+		if structured := result.StructuredContent.(map[string]interface{}); structured["path"] != "/items/"+id {
+			t.Fatalf("unexpected result %v", structured)
+		}
+	}
+
+	client := &countingClient{}
+	cache := NewResponseCache(time.Minute, 10)
+	get := newTool(http.MethodGet, client, cache)
+	call(get, "1")
+	call(get, "1")
+	call(get, "2")
+	if client.calls != 2 {
+		t.Fatalf("expected the repeated GET to be served from cache, got %d upstream calls", client.calls)
+	}
+
+	post := newTool(http.MethodPost, client, cache)
+	call(post, "1")
+	call(post, "1")
+	if client.calls != 4 {
+		t.Fatalf("expected non read-only calls to bypass the cache, got %d upstream calls", client.calls)
+	}
+
+	noStore := &countingClient{cacheControl: "no-store"}
+	uncached := newTool(http.MethodGet, noStore, NewResponseCache(time.Minute, 10))
+	call(uncached, "1")
+	call(uncached, "1")
+	if noStore.calls != 2 {
+		t.Fatalf("expected no-store responses to be refetched, got %d upstream calls", noStore.calls)
+	}
+
+	streamed := &countingClient{}
+	streaming := newTool(http.MethodGet, streamed, NewResponseCache(time.Minute, 10)).WithStreamingProgress(true)
+	for i := 0; i < 2; i++ {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{"id": "1"}
+		request.Params.Meta = &mcp.Meta{ProgressToken: "p1"}
+		if _, err := streaming.Run(context.Background(), request); err != nil {
+			t.Fatalf("call failed: %v", err)
+		}
+	}
+	if streamed.calls != 2 {
+		t.Fatalf("expected calls asking for progress to bypass the cache, got %d upstream calls", streamed.calls)
+	}
+}
+
+func TestResponseCacheExpiresAndEvicts(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cache := NewResponseCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	client := &countingClient{cacheControl: "max-age=10"}
+	fetch := func(path string) bool {
+		req, _ := http.NewRequest(http.MethodGet, "https://api.example.com"+path, nil)
+		if resp, ok := cache.get(req); ok {
+			resp.Body.Close()
+			return true
+		}
+		resp, _ := client.Do(req)
+		resp = cache.store(req, resp, 0)
+		io.Copy(io.Discard, resp.Body)
+		return false
+	}
+
+	fetch("/a")
+	if !fetch("/a") {
+		t.Fatalf("expected /a to be cached")
+	}
+	now = now.Add(11 * time.Second)
+	if fetch("/a") {
+		t.Fatalf("expected max-age to expire /a before the TTL")
+	}
+
+	fetch("/b")
+	fetch("/a") // most recently used
+	fetch("/c") // evicts /b
+	if cache.Len() != 2 {
+		t.Fatalf("expected two entries, got %d", cache.Len())
+	}
+	if !fetch("/a") || fetch("/b") {
+		t.Fatalf("expected the least recently used entry to be evicted")
+	}
+}
+
+func TestResponseCacheKeysOnHeadersTheClientAdds(t *testing.T) {
+	calls := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"key":"` + r.Header.Get("X-API-Key") + `"}`))
+	}))
+	defer upstream.Close()
+
+	apiKey := "alice"
+	client := NewDefaultHTTPClient().WithInterceptor(func(req *http.Request) error {
+		req.Header.Set("X-API-Key", apiKey)
+		return nil
+	})
+	readOnly := true
+	route := ir.HTTPRoute{Path: "/me", Method: http.MethodGet}
+	tool := NewOpenAPITool("me", "", ir.Schema{"type": "object"}, nil, false, route, client, upstream.URL, nil, nil, nil).
+		WithResponseCache(NewResponseCache(time.Minute, 10))
+	tool.tool.Annotations.ReadOnlyHint = &readOnly
+
+	call := func() string {
+		result, err := tool.Run(context.Background(), mcp.CallToolRequest{})
+		if err != nil || result.IsError {
+			t.Fatalf("call failed: %v %#v", err, result)
+		}
+		return result.StructuredContent.(map[string]interface{})["key"].(string)
+	}
+
+	call()
+	if got := call(); got != "alice" || calls != 1 {
+		t.Fatalf("expected the repeated call to be served from cache, got %q after %d upstream calls", got, calls)
+	}
+	apiKey = "bob"
+	if got := call(); got != "bob" || calls != 2 {
+		t.Fatalf("expected a different credential to miss the cache, got %q after %d upstream calls", got, calls)
+	}
+}
@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// ResponseHeadersKey holds the spec-declared response headers in structured content.
+const ResponseHeadersKey = "_headers"
+
+// declaredHeaders collects the headers the spec declares for the response's
+// status, typed according to their schemas. Undeclared headers stay in meta only.
+func (rp *ResponseProcessor) declaredHeaders(resp *http.Response) map[string]interface{} {
+	info := responseInfoForStatus(rp.route.Responses, resp.StatusCode)
+	if info == nil || len(info.Headers) == 0 {
+		return nil
+	}
+
+	headers := make(map[string]interface{})
+	for name, header := range info.Headers {
+		// OpenAPI ignores a declared Content-Type header; the media type describes it.
+		if strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		values := resp.Header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		headers[name] = typedHeaderValue(values, header.Schema)
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	return headers
+}
+
+// responseInfoForStatus looks up the exact status, then its range (e.g. 2XX), then default.
+func responseInfoForStatus(responses map[string]ir.ResponseInfo, status int) *ir.ResponseInfo {
+	code := strconv.Itoa(status)
+	for _, key := range []string{code, code[:1] + "XX", code[:1] + "xx", "default"} {
+		if info, ok := responses[key]; ok {
+			return &info
+		}
+	}
+	return nil
+}
+
+func typedHeaderValue(values []string, schema ir.Schema) interface{} {
+	if schemaAllowsType(schema, "array") {
+		itemSchema := schemaFromValue(schema["items"])
+		items := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			for _, part := range strings.Split(value, ",") {
+				item, _ := coerceValueForSchema(strings.TrimSpace(part), itemSchema)
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	value, _ := coerceValueForSchema(values[0], schema)
+	return value
+}
+
+// withDeclaredHeaders adds the declared headers to a structured result.
+func withDeclaredHeaders(structured map[string]interface{}, headers map[string]interface{}) map[string]interface{} {
+	if len(headers) == 0 {
+		return structured
+	}
+	if structured == nil {
+		structured = make(map[string]interface{})
+	}
+	structured[ResponseHeadersKey] = headers
+	return structured
+}
@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/parser"
+)
+
+const responseHeadersSpec = `{
+    "openapi": "3.0.3",
+    "info": {"title": "Headers", "version": "1.0"},
+    "paths": {
+        "/items": {
+            "post": {
+                "operationId": "createItem",
+                "responses": {
+                    "201": {
+                        "description": "created",
+                        "headers": {
+                            "Location": {"description": "URL of the new item", "schema": {"type": "string"}},
+                            "X-Rate-Limit": {"schema": {"type": "integer"}},
+                            "X-Tags": {"schema": {"type": "array", "items": {"type": "string"}}}
+                        },
+                        "content": {"application/json": {"schema": {"type": "object", "properties": {"id": {"type": "string"}}}}}
+                    }
+                }
+            }
+        }
+    }
+}`
+
+func TestOpenAPIToolSurfacesDeclaredResponseHeaders(t *testing.T) {
+	routes, err := parser.NewOpenAPI30Parser().ParseSpec([]byte(responseHeadersSpec))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	route := routes[0]
+	if header, ok := route.Responses["201"].Headers["Location"]; !ok || header.Description != "URL of the new item" {
+		t.Fatalf("expected parsed Location header, got %#v", route.Responses["201"].Headers)
+	}
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/items/42")
+		w.Header().Set("X-Rate-Limit", "99")
+		w.Header().Set("X-Tags", "a, b")
+		w.Header().Set("X-Undeclared", "hidden")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"42"}`))
+	}))
+	defer upstream.Close()
+
+	tool := NewOpenAPITool("createItem", "", nil, nil, false, route, NewDefaultHTTPClient(), upstream.URL, nil, nil, nil)
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{}
+
+	result, err := tool.Run(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("run failed: %v %#v", err, result)
+	}
+	structured := result.StructuredContent.(map[string]interface{})
+	want := map[string]interface{}{
+		"Location":     "/items/42",
+		"X-Rate-Limit": float64(99),
+		"X-Tags":       []interface{}{"a", "b"},
+	}
+	if !reflect.DeepEqual(structured[ResponseHeadersKey], want) || structured["id"] != "42" {
+		t.Fatalf("unexpected structured content %#v", structured)
+	}
+	text, _ := result.Content[0].(mcp.TextContent)
+	if !strings.Contains(text.Text, "/items/42") {
+		t.Fatalf("expected headers in text content, got %q", text.Text)
+	}
+}
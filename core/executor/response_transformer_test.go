@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func jsonResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestResponseProcessorAppliesTransformerBeforeValidation(t *testing.T) {
+	outputSchema := ir.Schema{
+		"type":                 "object",
+		"properties":           map[string]interface{}{"id": map[string]interface{}{"type": "integer"}},
+		"required":             []interface{}{"id"},
+		"additionalProperties": false,
+	}
+	unwrap := func(route ir.HTTPRoute, data interface{}) (interface{}, error) {
+		if route.OperationID != "getPet" {
+			return data, nil
+		}
+		return data.(map[string]interface{})["data"], nil
+	}
+
+	result, err := NewResponseProcessor(outputSchema, false, NewErrorHandler("info")).
+		WithTransformer(ir.HTTPRoute{OperationID: "getPet"}, unwrap).
+		Process(jsonResponse(`{"data":{"id":7},"links":{"self":"/pets/7"}}`))
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected transformed body to validate, got %#v", result.Content)
+	}
+	structured := result.StructuredContent.(map[string]interface{})
+	if structured["id"] != float64(7) || len(structured) != 1 {
+		t.Fatalf("unexpected structured content %#v", structured)
+	}
+}
+
+func TestResponseProcessorReportsTransformerErrors(t *testing.T) {
+	failing := func(ir.HTTPRoute, interface{}) (interface{}, error) {
+		return nil, errors.New("unexpected envelope")
+	}
+
+	result, err := NewResponseProcessor(nil, false, NewErrorHandler("info")).
+		WithTransformer(ir.HTTPRoute{}, failing).
+		Process(jsonResponse(`{"id":7}`))
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("expected error result")
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "unexpected envelope") {
+		t.Fatalf("expected transformer error in result, got %q", text)
+	}
+}
+
+func TestResponseProcessorValidatesOnlySchemaStatus(t *testing.T) {
+	outputSchema := ir.Schema{
+		"type":       "object",
+		"properties": map[string]interface{}{"rows": map[string]interface{}{"type": "array"}},
+		"required":   []interface{}{"rows"},
+	}
+	accepted := jsonResponse(`{"jobId":"42"}`)
+	accepted.StatusCode = http.StatusAccepted
+
+	result, err := NewResponseProcessor(outputSchema, false, NewErrorHandler("info")).
+		WithSchemaStatus("200").
+		Process(accepted)
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected a 202 body not to be checked against the 200 schema, got %#v", result.Content)
+	}
+	if result.StructuredContent != nil || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "jobId") {
+		t.Fatalf("expected the 202 body as text only, got %#v", result)
+	}
+
+	result, err = NewResponseProcessor(outputSchema, false, NewErrorHandler("info")).
+		WithSchemaStatus("2XX").
+		Process(jsonResponse(`{"jobId":"42"}`))
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("expected a 200 body to be validated against the 2XX schema")
+	}
+}
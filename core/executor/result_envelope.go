@@ -0,0 +1,130 @@
+package executor
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// WithResultEnvelope returns every successful result as {data, status, headers}:
+// the decoded body, the HTTP status code and the response headers. Error
+// results keep their shape.
+func (rp *ResponseProcessor) WithResultEnvelope(enabled bool) *ResponseProcessor {
+	rp.envelope = enabled
+	return rp
+}
+
+// wrapInEnvelope moves a result's structured content under "data". The body
+// goes there as decoded, without the "result" wrapper of non-object bodies;
+// results that have no body, such as binary or HEAD responses, go there as
+// built. Declared response headers keep their typed values under "headers".
+func (rp *ResponseProcessor) wrapInEnvelope(result *mcp.CallToolResult, resp *http.Response) {
+	structured, _ := result.StructuredContent.(map[string]interface{})
+	dump := buildStructuredTextContent(structured)
+
+	declared, _ := structured[ResponseHeadersKey].(map[string]interface{})
+	delete(structured, ResponseHeadersKey)
+	var data interface{} = structured
+	if rp.hasPayload {
+		data = rp.payload
+	}
+
+	headers := make(map[string]interface{}, len(resp.Header))
+	for name, values := range resp.Header {
+		if !isCredentialHeader(name) {
+			headers[name] = strings.Join(values, ", ")
+		}
+	}
+	for name, value := range declared {
+		if isCredentialHeader(name) {
+			continue
+		}
+		delete(headers, http.CanonicalHeaderKey(name))
+		headers[name] = value
+	}
+
+	envelope := map[string]interface{}{
+		"data":    data,
+		"status":  resp.StatusCode,
+		"headers": headers,
+	}
+	result.StructuredContent = envelope
+	// a text dump of the structured content follows it; other content stays
+	if len(result.Content) == 1 {
+		text, ok := result.Content[0].(mcp.TextContent)
+		if ok && text.Text == dump[0].(mcp.TextContent).Text {
+			result.Content = buildStructuredTextContent(envelope)
+		}
+	}
+}
+
+// WithResultEnvelope advertises the {data, status, headers} shape the tool's
+// results take when the processor wraps them; see
+// ResponseProcessor.WithResultEnvelope.
+func (t *OpenAPITool) WithResultEnvelope(enabled bool) *OpenAPITool {
+	t.resultEnvelope = enabled
+	if enabled {
+		t.tool.RawOutputSchema, _ = json.Marshal(ResultEnvelopeSchema(t.outputSchema, t.wrapResult))
+	} else if t.outputSchema != nil {
+		t.tool.RawOutputSchema, _ = json.Marshal(t.outputSchema)
+	} else {
+		t.tool.RawOutputSchema = nil
+	}
+	return t
+}
+
+// ResultEnvelopeSchema describes enveloped results given the tool's output
+// schema, which may be nil: its body schema becomes "data", typed declared
+// headers become the properties of "headers", and $defs and the audit record
+// stay at the top level.
+func ResultEnvelopeSchema(outputSchema ir.Schema, wrapResult bool) ir.Schema {
+	data := ir.Schema{}
+	headers := ir.Schema{"type": "object"}
+	properties := map[string]interface{}{}
+	envelope := ir.Schema{"type": "object"}
+
+	if outputSchema != nil {
+		data = make(ir.Schema, len(outputSchema))
+		for key, value := range outputSchema {
+			switch key {
+			case "$defs":
+				envelope[key] = value
+			case "x-fastmcp-wrap-result":
+			default:
+				data[key] = value
+			}
+		}
+		if props, ok := data["properties"].(map[string]interface{}); ok {
+			bodyProps := make(map[string]interface{}, len(props))
+			for name, prop := range props {
+				switch name {
+				case ResponseHeadersKey:
+					if declared := schemaFromValue(prop); declared != nil {
+						headers["properties"] = declared["properties"]
+					}
+				case AuditKey:
+					properties[name] = prop
+				default:
+					bodyProps[name] = prop
+				}
+			}
+			data["properties"] = bodyProps
+		}
+		if wrapResult {
+			data = schemaFromValue(data.Properties()["result"])
+			if data == nil {
+				data = ir.Schema{}
+			}
+		}
+	}
+
+	properties["data"] = map[string]interface{}(data)
+	properties["status"] = map[string]interface{}{"type": "integer"}
+	properties["headers"] = map[string]interface{}(headers)
+	envelope["properties"] = properties
+	envelope["required"] = []string{"data", "status", "headers"}
+	return envelope
+}
@@ -0,0 +1,101 @@
+package executor
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func envelopeResponse(status int, contentType, body string) *http.Response {
+	header := http.Header{
+		"X-Request-Id":  []string{"req-1"},
+		"Set-Cookie":    []string{"session=secret"},
+		"Authorization": []string{"Bearer secret"},
+	}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestResponseProcessorWrapsResultsInEnvelope(t *testing.T) {
+	arraySchema := ir.Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"result": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "integer"}},
+		},
+		"required":              []string{"result"},
+		"x-fastmcp-wrap-result": true,
+	}
+	envelopeValidator := compileIRSchema(ResultEnvelopeSchema(arraySchema, true))
+
+	cases := []struct {
+		name string
+		resp *http.Response
+		data interface{}
+	}{
+		{"array", envelopeResponse(http.StatusOK, "application/json", "[1,2]"), []interface{}{float64(1), float64(2)}},
+		{"empty", envelopeResponse(http.StatusNoContent, "", ""), nil},
+		{"text", envelopeResponse(http.StatusOK, "text/plain", "hello"), "hello"},
+	}
+	for _, tc := range cases {
+		result, err := NewResponseProcessor(arraySchema, true, nil).WithResultEnvelope(true).Process(tc.resp)
+		if err != nil {
+			t.Fatalf("%s: process failed: %v", tc.name, err)
+		}
+		structured, _ := result.StructuredContent.(map[string]interface{})
+		if !reflect.DeepEqual(structured["data"], tc.data) || structured["status"] != tc.resp.StatusCode {
+			t.Fatalf("%s: unexpected envelope %#v", tc.name, structured)
+		}
+		if headers, _ := structured["headers"].(map[string]interface{}); headers["X-Request-Id"] != "req-1" {
+			t.Fatalf("%s: expected response headers in the envelope, got %#v", tc.name, structured["headers"])
+		}
+		for _, name := range []string{"Set-Cookie", "Authorization"} {
+			if _, ok := structured["headers"].(map[string]interface{})[name]; ok {
+				t.Fatalf("%s: expected %s to be left out of the envelope, got %#v", tc.name, name, structured["headers"])
+			}
+		}
+		if tc.name == "array" {
+			if err := envelopeValidator.Validate(structured); err != nil {
+				t.Fatalf("envelope does not match its schema: %v", err)
+			}
+			if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, `"status": 200`) {
+				t.Fatalf("expected the text content to show the envelope, got %s", text)
+			}
+		}
+	}
+}
+
+func TestResultEnvelopeSchemaMovesDeclaredHeaders(t *testing.T) {
+	schema := ResultEnvelopeSchema(ir.Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"id": map[string]interface{}{"type": "string"},
+			ResponseHeadersKey: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"X-Rate-Limit": map[string]interface{}{"type": "integer"}},
+			},
+		},
+		"$defs": map[string]interface{}{"Thing": map[string]interface{}{"type": "object"}},
+	}, false)
+
+	props := schema.Properties()
+	if _, ok := props["data"].Properties()[ResponseHeadersKey]; ok {
+		t.Fatalf("expected declared headers to leave the data schema, got %#v", props["data"])
+	}
+	if _, ok := props["headers"].Properties()["X-Rate-Limit"]; !ok {
+		t.Fatalf("expected declared headers under headers, got %#v", props["headers"])
+	}
+	if _, ok := schema["$defs"]; !ok {
+		t.Fatalf("expected $defs to stay at the top level, got %#v", schema)
+	}
+}
@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"strings"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// schemaResolver follows local `#/$defs/` references against the defs of a
+// route, so schema-guided encoding and validation see referenced components.
+type schemaResolver struct {
+	defs ir.Schema
+}
+
+func (r schemaResolver) resolve(schema ir.Schema) (ir.Schema, string) {
+	component := ""
+	for depth := 0; schema != nil && depth < 16; depth++ {
+		ref, ok := schema["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/$defs/") {
+			break
+		}
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		target := r.lookup(name)
+		if target == nil {
+			break
+		}
+		schema, component = target, name
+	}
+	return schema, component
+}
+
+func (r schemaResolver) lookup(name string) ir.Schema {
+	if r.defs == nil {
+		return nil
+	}
+	switch defs := r.defs["$defs"].(type) {
+	case map[string]interface{}:
+		if m, ok := defs[name].(map[string]interface{}); ok {
+			return m
+		}
+		if s, ok := defs[name].(ir.Schema); ok {
+			return s
+		}
+	case map[string]ir.Schema:
+		return defs[name]
+	}
+	return nil
+}
+
+// schemaLeafFunc rewrites a scalar value according to its resolved schema and
+// reports whether it changed.
+type schemaLeafFunc func(value interface{}, schema ir.Schema) (interface{}, bool)
+
+// walk visits value guided by schema: object properties, undeclared properties
+// typed by patternProperties or additionalProperties and array items are
+// followed, and every other non-nil value is handed to leaf. Objects and
+// arrays are rewritten in place; only a top-level leaf is returned as a new
+// value.
+func (r schemaResolver) walk(value interface{}, schema ir.Schema, leaf schemaLeafFunc) (interface{}, bool) {
+	return r.walkDepth(value, schema, leaf, 0)
+}
+
+func (r schemaResolver) walkDepth(value interface{}, schema ir.Schema, leaf schemaLeafFunc, depth int) (interface{}, bool) {
+	schema, _ = r.resolve(schema)
+	if schema == nil || depth > 16 {
+		return value, false
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := variantShape(schema, r, 0)
+		for key, item := range v {
+			prop, known := properties[key]
+			if !known {
+				prop = undeclaredPropertySchema(schema, key)
+			}
+			if prop == nil || item == nil {
+				continue
+			}
+			if rewritten, changed := r.walkDepth(item, prop, leaf, depth+1); changed {
+				v[key] = rewritten
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			itemSchema := tupleItemSchema(schema, i)
+			if itemSchema == nil || item == nil {
+				continue
+			}
+			if rewritten, changed := r.walkDepth(item, itemSchema, leaf, depth+1); changed {
+				v[i] = rewritten
+			}
+		}
+	default:
+		return leaf(value, schema)
+	}
+	return value, false
+}
@@ -9,10 +9,24 @@ import (
 )
 
 func compileJSONSchema(raw json.RawMessage) *jsonschema.Schema {
+	return compileJSONSchemaWithFormats(raw, true)
+}
+
+// compileJSONSchemaWithFormats controls `format` assertions. Only formats known to
+// the validator (email, uuid, date, date-time, uri, ...) are checked; custom
+// formats are ignored. Schemas without `$schema` enable the format-assertion
+// vocabulary, so disabling assertions swaps the known checkers for no-ops.
+func compileJSONSchemaWithFormats(raw json.RawMessage, assertFormat bool) *jsonschema.Schema {
 	if raw == nil {
 		return nil
 	}
 	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat = assertFormat
+	if !assertFormat {
+		for name := range jsonschema.Formats {
+			compiler.Formats[name] = func(interface{}) bool { return true }
+		}
+	}
 	if err := compiler.AddResource("schema.json", bytes.NewReader(raw)); err != nil {
 		return nil
 	}
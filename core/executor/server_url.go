@@ -0,0 +1,93 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+var serverVariablePattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// ServerVariableResolver supplies a server variable's value for one call, e.g.
+// a tenant taken from a request header. ok=false leaves the variable to the
+// configured overrides and the declared default.
+type ServerVariableResolver func(ctx context.Context, name string) (string, bool)
+
+// expandServerURL substitutes server variables, preferring the resolver, then
+// caller-supplied overrides, then the declared defaults, and rejecting values
+// that fall outside a variable's enum. Resolved values come from the request,
+// so unless an enum bounds them they may not contain URL delimiters or
+// whitespace that would move the request to another host or path.
+func expandServerURL(ctx context.Context, server ir.ServerInfo, overrides map[string]string, resolver ServerVariableResolver) (string, error) {
+	var expandErr error
+	expanded := serverVariablePattern.ReplaceAllStringFunc(server.URL, func(match string) string {
+		if expandErr != nil {
+			return match
+		}
+		name := strings.TrimSpace(match[1 : len(match)-1])
+		variable, declared := server.Variables[name]
+		value := variable.Default
+		if override, ok := overrides[name]; ok && override != "" {
+			value = override
+		}
+		if resolver != nil {
+			if resolved, ok := resolver(ctx, name); ok && resolved != "" {
+				if !(declared && len(variable.Enum) > 0) && !safeServerVariable(resolved) {
+					expandErr = fmt.Errorf("server variable %q value %q contains URL delimiters", name, resolved)
+					return match
+				}
+				value = resolved
+			}
+		}
+		if value == "" {
+			expandErr = fmt.Errorf("server variable %q has no value", name)
+			return match
+		}
+		if declared && len(variable.Enum) > 0 && !containsString(variable.Enum, value) {
+			expandErr = fmt.Errorf("server variable %q value %q is not one of %s", name, value, strings.Join(variable.Enum, ", "))
+			return match
+		}
+		return value
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return expanded, nil
+}
+
+// safeServerVariable reports whether value can be substituted into a server
+// URL without changing its structure.
+func safeServerVariable(value string) bool {
+	return !strings.ContainsAny(value, "/?#@:") && strings.IndexFunc(value, unicode.IsSpace) < 0
+}
+
+// joinServerURL resolves a (possibly relative) server URL against the configured base URL.
+func joinServerURL(baseURL, serverURL string) (string, error) {
+	server, err := url.Parse(serverURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid server URL %q: %w", serverURL, err)
+	}
+	if server.IsAbs() || baseURL == "" {
+		return strings.TrimSuffix(serverURL, "/"), nil
+	}
+
+	base, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/")
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	return strings.TrimSuffix(base.ResolveReference(server).String(), "/"), nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
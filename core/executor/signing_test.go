@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSigV4Signer() *SigV4Signer {
+	signer := NewSigV4Signer("us-east-1", "service", AWSCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	})
+	signer.now = func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) }
+	return signer
+}
+
+func TestSigV4SignerMatchesReferenceSignature(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	if err := testSigV4Signer().Sign(req); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, " +
+		"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Fatalf("unexpected Authorization header:\n got %s\nwant %s", got, want)
+	}
+	if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+		t.Fatalf("unexpected X-Amz-Date %q", got)
+	}
+}
+
+func TestSigV4SignerBuffersBodyAndResigns(t *testing.T) {
+	signer := testSigV4Signer()
+	signer.credentials.SessionToken = "token"
+	req, _ := http.NewRequest(http.MethodPost, "https://example.amazonaws.com/items?b=2&a=1", io.NopCloser(strings.NewReader(`{"name":"x"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	if err := signer.Sign(req); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	first := req.Header.Get("Authorization")
+	if !strings.Contains(first, "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token,") {
+		t.Fatalf("unexpected signed headers in %s", first)
+	}
+	if req.Header.Get("X-Amz-Security-Token") != "token" {
+		t.Fatalf("expected session token header")
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != `{"name":"x"}` || req.ContentLength != int64(len(body)) {
+		t.Fatalf("body not restored after signing: %q (%d)", body, req.ContentLength)
+	}
+	replay, err := req.GetBody()
+	if err != nil {
+		t.Fatalf("GetBody: %v", err)
+	}
+	req.Body = replay
+
+	signer.now = func() time.Time { return time.Date(2015, 8, 30, 12, 37, 0, 0, time.UTC) }
+	if err := signer.Sign(req); err != nil {
+		t.Fatalf("re-sign: %v", err)
+	}
+	if got := req.Header.Get("X-Amz-Date"); got != "20150830T123700Z" {
+		t.Fatalf("retry kept stale timestamp %q", got)
+	}
+	if got := req.Header.Values("Authorization"); len(got) != 1 || got[0] == first {
+		t.Fatalf("expected a single fresh signature, got %v", got)
+	}
+}
+
+func TestSigV4SignerRequiresCredentials(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	if err := NewSigV4Signer("us-east-1", "execute-api", AWSCredentials{}).Sign(req); err == nil {
+		t.Fatal("expected an error without credentials")
+	}
+}
@@ -0,0 +1,62 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+)
+
+// recordSeparator prefixes each record in application/json-seq (RFC 7464).
+const recordSeparator = 0x1E
+
+func isJSONStreamContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	switch mediaType {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/json-seq":
+		return true
+	}
+	return false
+}
+
+// decodeJSONStream reads NDJSON / json-seq records incrementally into a slice,
+// calling onRecord, when set, for each one.
+func decodeJSONStream(r io.Reader, onRecord func(record interface{})) ([]interface{}, error) {
+	decoder := json.NewDecoder(&separatorReader{r: r})
+
+	records := make([]interface{}, 0)
+	for {
+		var record interface{}
+		err := decoder.Decode(&record)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode record %d: %w", len(records)+1, err)
+		}
+		records = append(records, record)
+		if onRecord != nil {
+			onRecord(record)
+		}
+	}
+}
+
+// separatorReader turns json-seq record separators into whitespace so a plain
+// json.Decoder can consume both NDJSON and json-seq streams.
+type separatorReader struct {
+	r io.Reader
+}
+
+func (s *separatorReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	for i := 0; i < n; i++ {
+		if p[i] == recordSeparator {
+			p[i] = '\n'
+		}
+	}
+	return n, err
+}
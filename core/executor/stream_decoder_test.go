@@ -0,0 +1,73 @@
+package executor
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResponseProcessorDecodesJSONStreams(t *testing.T) {
+	cases := map[string]string{
+		"application/x-ndjson": "{\"id\":1}\n{\"id\":2}\n\n",
+		"application/json-seq": "\x1e{\"id\":1}\n\x1e{\"id\":2}\n",
+	}
+
+	for contentType, body := range cases {
+		t.Run(contentType, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: http.StatusOK,
+				Status:     "200 OK",
+				Header:     http.Header{"Content-Type": []string{contentType}},
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}
+
+			result, err := NewResponseProcessor(nil, true, nil).Process(resp)
+			if err != nil {
+				t.Fatalf("process failed: %v", err)
+			}
+
+			expected := map[string]interface{}{"result": []interface{}{
+				map[string]interface{}{"id": float64(1)},
+				map[string]interface{}{"id": float64(2)},
+			}}
+			if !reflect.DeepEqual(result.StructuredContent, expected) {
+				t.Fatalf("unexpected structured content: %#v", result.StructuredContent)
+			}
+		})
+	}
+}
+
+func TestResponseProcessorStreamsEventStreamRecords(t *testing.T) {
+	body := ": keep-alive\n\nevent: status\ndata: {\"step\":1}\n\ndata: first line\r\ndata: second line\r\n\r\nid: 3\ndata: {\"step\":2}"
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"text/event-stream; charset=utf-8"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+
+	var seen []interface{}
+	result, err := NewResponseProcessor(nil, true, nil).
+		WithStreamingProgress(func(record interface{}) { seen = append(seen, record) }).
+		Process(resp)
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"step": float64(1)},
+		"first line\nsecond line",
+		map[string]interface{}{"step": float64(2)},
+	}
+	if !reflect.DeepEqual(result.StructuredContent, map[string]interface{}{"result": expected}) {
+		t.Fatalf("unexpected structured content: %#v", result.StructuredContent)
+	}
+	if !reflect.DeepEqual(seen, expected) {
+		t.Fatalf("expected each event to be reported as it arrived, got %#v", seen)
+	}
+	if message := progressMessage(strings.Repeat("é", maxProgressMessage)); len(message) > maxProgressMessage+len("…") || !strings.HasSuffix(message, "…") {
+		t.Fatalf("expected a truncated progress message, got %d bytes", len(message))
+	}
+}
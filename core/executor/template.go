@@ -2,14 +2,14 @@ package executor
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"io"
-	"net/http"
+	"net/url"
+	"sort"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/specx2/openapi-mcp/core/internal"
 	"github.com/specx2/openapi-mcp/core/ir"
 )
 
@@ -18,6 +18,8 @@ type OpenAPIResourceTemplate struct {
 	route    ir.HTTPRoute
 	client   HTTPClient
 	baseURL  string
+
+	headerPropagation *HeaderPropagation
 }
 
 func NewOpenAPIResourceTemplate(
@@ -35,7 +37,7 @@ func NewOpenAPIResourceTemplate(
 		uriTemplate,
 		name,
 		mcp.WithTemplateDescription(description),
-		mcp.WithTemplateMIMEType("application/json"),
+		mcp.WithTemplateMIMEType(resourceMIMEType(route)),
 	)
 
 	return &OpenAPIResourceTemplate{
@@ -66,8 +68,21 @@ func (rt *OpenAPIResourceTemplate) GetBaseURL() string {
 	return rt.baseURL
 }
 
+// WithHeaderPropagation restricts which inbound MCP headers reach the upstream.
+func (rt *OpenAPIResourceTemplate) WithHeaderPropagation(policy *HeaderPropagation) *OpenAPIResourceTemplate {
+	rt.headerPropagation = policy
+	return rt
+}
+
+func (rt *OpenAPIResourceTemplate) GetHeaderPropagation() *HeaderPropagation {
+	return rt.headerPropagation
+}
+
 func (rt *OpenAPIResourceTemplate) CreateResource(ctx context.Context, uri string, params map[string]string) (mcp.Resource, error) {
-	name := fmt.Sprintf("%s_%s", rt.template.Name, generateResourceSuffix(params))
+	name := rt.template.Name
+	if suffix := generateResourceSuffix(params); suffix != "" {
+		name += "_" + suffix
+	}
 
 	resource := NewOpenAPIParameterizedResource(
 		name,
@@ -77,6 +92,7 @@ func (rt *OpenAPIResourceTemplate) CreateResource(ctx context.Context, uri strin
 		rt.baseURL,
 		params,
 	)
+	resource.WithHeaderPropagation(rt.headerPropagation)
 
 	return resource.Resource(), nil
 }
@@ -109,7 +125,7 @@ func buildURITemplate(name string, route ir.HTTPRoute) string {
 			queryParamNames = append(queryParamNames, param.Name)
 		} else if param.In == ir.ParameterInHeader {
 			// Header parameters use special prefix
-			headerParamNames = append(headerParamNames, "__header__"+param.Name)
+			headerParamNames = append(headerParamNames, headerParamPrefix+param.Name)
 		}
 	}
 
@@ -154,17 +170,57 @@ func buildParametersSchema(route ir.HTTPRoute) ir.Schema {
 	return schema
 }
 
+// maxResourceSuffix bounds the readable part of a parameterized resource name.
+const maxResourceSuffix = 48
+
+// generateResourceSuffix names a parameter set deterministically: the sorted
+// key-value pairs, cut to maxResourceSuffix, followed by a short hash of the exact
+// set, so different sets never share a name even when their readable parts match.
 func generateResourceSuffix(params map[string]string) string {
-	var parts []string
+	if len(params) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(params))
+	canonical := make(url.Values, len(params))
 	for k, v := range params {
-		parts = append(parts, fmt.Sprintf("%s_%s", k, v))
+		keys = append(keys, k)
+		canonical.Set(k, v)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = resourceNamePart(k) + "-" + resourceNamePart(params[k])
+	}
+	readable := strings.Join(parts, "_")
+	if len(readable) > maxResourceSuffix {
+		readable = strings.TrimRight(readable[:maxResourceSuffix], "_-")
 	}
-	return strings.Join(parts, "_")
+
+	sum := sha256.Sum256([]byte(canonical.Encode()))
+	return readable + "_" + hex.EncodeToString(sum[:4])
+}
+
+// resourceNamePart keeps ASCII letters, digits and dots and replaces the rest with '-'.
+func resourceNamePart(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.':
+			return r
+		}
+		return '-'
+	}, value)
 }
 
+// headerParamPrefix marks header parameters among the query variables of a
+// resource template, e.g. users{?page,__header__Authorization}.
+const headerParamPrefix = "__header__"
+
 type OpenAPIParameterizedResource struct {
 	*OpenAPIResource
 	params       map[string]string
+	uriQuery     map[string][]string
 	headerParams map[string]string
 }
 
@@ -184,13 +240,82 @@ func NewOpenAPIParameterizedResource(
 	}
 }
 
+// WithURIQuery reads query and header parameter values from the query of the
+// resource URI, ahead of params. Values are kept as lists: a repeated name adds
+// elements and, for array parameters, a literal comma separates elements as
+// RFC 6570 writes them, while an encoded %2C stays inside its element. Names
+// with the __header__ prefix only fill header parameters, and the others only
+// query parameters.
+func (pr *OpenAPIParameterizedResource) WithURIQuery(uri string) *OpenAPIParameterizedResource {
+	idx := strings.Index(uri, "?")
+	if idx < 0 {
+		return pr
+	}
+	rawQuery := uri[idx+1:]
+	if hash := strings.Index(rawQuery, "#"); hash >= 0 {
+		rawQuery = rawQuery[:hash]
+	}
+	pr.uriQuery = make(map[string][]string)
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		rawName, rawValue, _ := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(rawName)
+		if err != nil {
+			continue
+		}
+		pr.uriQuery[name] = append(pr.uriQuery[name], rawValue)
+	}
+	return pr
+}
+
+// uriQueryValue looks param up in the URI query under prefix, trying the
+// sanitized form of its name too, and decodes the value for it.
+func (pr *OpenAPIParameterizedResource) uriQueryValue(param ir.ParameterInfo, prefix string) (interface{}, bool) {
+	raw, ok := pr.uriQuery[prefix+param.Name]
+	if !ok {
+		raw, ok = pr.uriQuery[prefix+strings.ReplaceAll(param.Name, "-", "_")]
+	}
+	if !ok || len(raw) == 0 {
+		return nil, false
+	}
+	if param.In == ir.ParameterInQuery && param.Schema != nil && schemaAllowsType(param.Schema, "array") {
+		items := make([]interface{}, 0, len(raw))
+		for _, value := range raw {
+			for _, part := range strings.Split(value, ",") {
+				items = append(items, queryUnescape(part))
+			}
+		}
+		return items, true
+	}
+	return queryUnescape(raw[0]), true
+}
+
+func queryUnescape(value string) string {
+	if decoded, err := url.QueryUnescape(value); err == nil {
+		return decoded
+	}
+	return value
+}
+
 func (pr *OpenAPIParameterizedResource) Read(ctx context.Context) (string, error) {
-	reqURL, err := pr.buildParameterizedURL()
+	contents, err := pr.ReadContents(ctx, pr.resource.URI)
 	if err != nil {
 		return "", err
 	}
+	return resourceContentsText(contents), nil
+}
 
-	return pr.readFromParameterizedURL(ctx, reqURL)
+// ReadContents reads the parameterized resource, returning binary payloads as blobs.
+func (pr *OpenAPIParameterizedResource) ReadContents(ctx context.Context, uri string) ([]mcp.ResourceContents, error) {
+	reqURL, err := pr.buildParameterizedURL()
+	if err != nil {
+		return nil, err
+	}
+
+	// reqURL 已经在 buildParameterizedURL() 中构建好了完整 URL，Header 参数随请求一并发送
+	return pr.readContents(ctx, reqURL, uri, pr.headerParams)
 }
 
 func (pr *OpenAPIParameterizedResource) buildParameterizedURL() (string, error) {
@@ -215,11 +340,21 @@ func (pr *OpenAPIParameterizedResource) buildParameterizedURL() (string, error)
 	}
 
 	// 处理查询参数和 Header 参数（保持参数顺序）
-	var queryParts []string
+	var queryParams []EncodedParameter
 	headerParams := make(map[string]string)
 
 	for _, param := range pr.route.Parameters {
 		if param.In == ir.ParameterInQuery {
+			if value, ok := pr.uriQueryValue(param, ""); ok {
+				if value != "" {
+					encoded, err := encodeParameterValues(param, value)
+					if err != nil {
+						return "", err
+					}
+					queryParams = append(queryParams, encoded...)
+				}
+				continue
+			}
 			// 尝试使用原始名称，如果不存在则尝试 sanitized 名称（下划线版本）
 			paramValue, exists := pr.params[param.Name]
 			if !exists {
@@ -228,10 +363,20 @@ func (pr *OpenAPIParameterizedResource) buildParameterizedURL() (string, error)
 				paramValue, exists = pr.params[sanitizedName]
 			}
 			if exists && paramValue != "" {
-				queryParts = append(queryParts, fmt.Sprintf("%s=%s", param.Name, paramValue))
+				encoded, err := encodeParameterValues(param, templateParameterValue(param, paramValue))
+				if err != nil {
+					return "", err
+				}
+				queryParams = append(queryParams, encoded...)
 			}
 			// 如果参数为空，不添加到查询字符串中（使用默认值）
 		} else if param.In == ir.ParameterInHeader {
+			if value, ok := pr.uriQueryValue(param, headerParamPrefix); ok {
+				if value != "" {
+					headerParams[param.Name] = value.(string)
+				}
+				continue
+			}
 			// 尝试使用原始名称，如果不存在则尝试 sanitized 名称（下划线版本）
 			paramValue, exists := pr.params[param.Name]
 			if !exists {
@@ -247,8 +392,8 @@ func (pr *OpenAPIParameterizedResource) buildParameterizedURL() (string, error)
 	}
 
 	// 添加查询参数到 URL
-	if len(queryParts) > 0 {
-		fullURL += "?" + strings.Join(queryParts, "&")
+	if len(queryParams) > 0 {
+		fullURL += "?" + buildQueryString(queryParams)
 	}
 
 	// 将 Header 参数存储到上下文中，供后续 HTTP 请求使用
@@ -261,50 +406,16 @@ func (pr *OpenAPIParameterizedResource) buildParameterizedURL() (string, error)
 	return fullURL, nil
 }
 
-func (pr *OpenAPIParameterizedResource) readFromParameterizedURL(ctx context.Context, reqURL string) (string, error) {
-	// reqURL 已经在 buildParameterizedURL() 中构建好了完整 URL，直接使用
-	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
-	if err != nil {
-		return "", err
-	}
-
-	// 添加 MCP Headers
-	if mcpHeaders := internal.GetMCPHeaders(ctx); mcpHeaders != nil {
-		for k, v := range mcpHeaders {
-			req.Header.Set(k, v)
-		}
-	}
-
-	// 添加 Header 参数
-	for headerName, headerValue := range pr.headerParams {
-		req.Header.Set(headerName, headerValue)
-	}
-
-	resp, err := pr.client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
+// templateParameterValue 将 params 中的字符串值按逗号还原为数组，便于按 style/explode 编码；
+// 来自资源 URI 的值走 WithURIQuery，不经过这里
+func templateParameterValue(param ir.ParameterInfo, value string) interface{} {
+	if param.Schema == nil || !schemaAllowsType(param.Schema, "array") {
+		return value
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	parts := strings.Split(value, ",")
+	items := make([]interface{}, len(parts))
+	for i, part := range parts {
+		items[i] = part
 	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	contentType := resp.Header.Get("Content-Type")
-	if strings.Contains(contentType, "json") {
-		var jsonResult interface{}
-		if json.Unmarshal(body, &jsonResult) == nil {
-			prettyJSON, err := json.MarshalIndent(jsonResult, "", "  ")
-			if err == nil {
-				return string(prettyJSON), nil
-			}
-		}
-	}
-
-	return string(body), nil
+	return items
 }
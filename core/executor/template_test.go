@@ -0,0 +1,97 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func TestParameterizedResourceEncodesQueryParameters(t *testing.T) {
+	explode := false
+	route := ir.HTTPRoute{
+		Path:   "/items/{id}",
+		Method: "GET",
+		Parameters: []ir.ParameterInfo{
+			{Name: "id", In: ir.ParameterInPath, Required: true, Schema: ir.Schema{"type": "string"}},
+			{Name: "q", In: ir.ParameterInQuery, Schema: ir.Schema{"type": "string"}},
+			{Name: "tags", In: ir.ParameterInQuery, Schema: ir.Schema{"type": "array", "items": map[string]interface{}{"type": "string"}}},
+			{Name: "ids", In: ir.ParameterInQuery, Explode: &explode, Schema: ir.Schema{"type": "array", "items": map[string]interface{}{"type": "integer"}}},
+		},
+	}
+
+	resource := NewOpenAPIParameterizedResource("items", "", route, nil, "https://api.example.com", map[string]string{
+		"id":   "42",
+		"q":    "a&b=c d",
+		"tags": "a,b",
+		"ids":  "1,2",
+	})
+
+	got, err := resource.buildParameterizedURL()
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	want := "https://api.example.com/items/42?q=a%26b%3Dc+d&tags=a&tags=b&ids=1%2C2"
+	if got != want {
+		t.Fatalf("unexpected URL:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestParameterizedResourceReadsURIQueryAsLists(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/items",
+		Method: "GET",
+		Parameters: []ir.ParameterInfo{
+			{Name: "q", In: ir.ParameterInQuery, Schema: ir.Schema{"type": "string"}},
+			{Name: "tags", In: ir.ParameterInQuery, Schema: ir.Schema{"type": "array", "items": map[string]interface{}{"type": "string"}}},
+			{Name: "q", In: ir.ParameterInHeader, Schema: ir.Schema{"type": "string"}},
+		},
+	}
+
+	resource := NewOpenAPIParameterizedResource("items", "", route, nil, "https://api.example.com", nil).
+		WithURIQuery("resource://items?q=a,b&tags=x%2Cy&tags=z,w&__header__q=h%26v")
+	got, err := resource.buildParameterizedURL()
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	want := "https://api.example.com/items?q=a%2Cb&tags=x%2Cy&tags=z&tags=w"
+	if got != want {
+		t.Fatalf("unexpected URL:\n got %s\nwant %s", got, want)
+	}
+	if resource.headerParams["q"] != "h&v" {
+		t.Fatalf("expected the header value from its own namespace, got %v", resource.headerParams)
+	}
+
+	resource = NewOpenAPIParameterizedResource("items", "", route, nil, "https://api.example.com", nil).
+		WithURIQuery("items?__header__q=h")
+	if got, _ := resource.buildParameterizedURL(); got != "https://api.example.com/items" {
+		t.Fatalf("expected a header value to stay out of the query, got %s", got)
+	}
+}
+
+func TestGenerateResourceSuffixIsStableAndDistinct(t *testing.T) {
+	params := map[string]string{"userId": "42", "format": "json"}
+	first := generateResourceSuffix(params)
+	for i := 0; i < 20; i++ {
+		if got := generateResourceSuffix(map[string]string{"format": "json", "userId": "42"}); got != first {
+			t.Fatalf("expected a stable suffix, got %q and %q", first, got)
+		}
+	}
+	if !strings.HasPrefix(first, "format-json_userId-42_") {
+		t.Fatalf("expected sorted readable pairs, got %q", first)
+	}
+
+	a := generateResourceSuffix(map[string]string{"a": "b_c"})
+	b := generateResourceSuffix(map[string]string{"a_b": "c"})
+	if a == b {
+		t.Fatalf("expected different parameter sets to get different suffixes, both got %q", a)
+	}
+
+	long := generateResourceSuffix(map[string]string{"query": strings.Repeat("x", 500)})
+	if len(long) > maxResourceSuffix+9 {
+		t.Fatalf("expected a bounded suffix, got %d characters", len(long))
+	}
+	if generateResourceSuffix(nil) != "" {
+		t.Fatalf("expected no suffix without parameters")
+	}
+}
@@ -3,17 +3,24 @@ package executor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/santhosh-tekuri/jsonschema/v5"
 	"github.com/specx2/openapi-mcp/core/internal"
 	"github.com/specx2/openapi-mcp/core/ir"
+	"github.com/specx2/openapi-mcp/core/parser"
 )
 
+// BaseURLResolver computes the upstream base URL for one call, e.g. from a
+// tenant header. It replaces the static base URL; path joining is unchanged.
+type BaseURLResolver func(ctx context.Context, route ir.HTTPRoute) (string, error)
+
 type OpenAPITool struct {
 	tool         mcp.Tool
 	route        ir.HTTPRoute
@@ -24,6 +31,37 @@ type OpenAPITool struct {
 	wrapResult   bool
 	validator    *jsonschema.Schema
 	tags         []string
+
+	serverVariables        map[string]string
+	serverVariableResolver ServerVariableResolver
+	ignoreOperationServers bool
+	pagination             *PaginationConfig
+	compressMinBytes       int
+	fileUploadRoot         string
+	timeoutFn              func(ir.HTTPRoute) time.Duration
+	headerPropagation      *HeaderPropagation
+	transformer            ResponseTransformer
+	lenientFormats         bool
+	additionalProperties   AdditionalPropertiesMode
+	logger                 Logger
+	maxRequestBytes        int64
+	maxResponseBytes       int64
+	contentType            string
+	baseURLResolver        BaseURLResolver
+	cache                  *ResponseCache
+	auditMetadata          bool
+	pathPrefix             string
+	defaultAccept          string
+	csvOptions             *CSVOptions
+	joinCookieArrays       bool
+	maxURLLength           int
+	normalizeDates         bool
+	tracer                 Tracer
+	unwrapSingleProperty   bool
+	outputStatus           string
+	streamingProgress      bool
+	queryArrayFormat       QueryArrayFormat
+	resultEnvelope         bool
 }
 
 func NewOpenAPITool(
@@ -56,7 +94,11 @@ func NewOpenAPITool(
 	if outputSchema != nil {
 		options = append(options, mcp.WithRawOutputSchema(outputSchemaJSON))
 	}
-	if derived := deriveToolAnnotations(route.Method, route.Summary, annotations); derived != nil {
+	title := route.Summary
+	if route.Deprecated && title != "" {
+		title += " (deprecated)"
+	}
+	if derived := deriveToolAnnotations(route.Method, title, annotations); derived != nil {
 		options = append(options, mcp.WithToolAnnotation(*derived))
 	}
 
@@ -78,7 +120,226 @@ func NewOpenAPITool(
 		wrapResult:   wrapResult,
 		validator:    validator,
 		tags:         uniqueStrings(tags),
+		logger:       nopLogger{},
+	}
+}
+
+// WithServerVariables sets values used when expanding operation server URL templates.
+func (t *OpenAPITool) WithServerVariables(vars map[string]string) *OpenAPITool {
+	t.serverVariables = vars
+	return t
+}
+
+// WithServerVariableResolver resolves server variables per call, e.g. from request headers.
+func (t *OpenAPITool) WithServerVariableResolver(fn ServerVariableResolver) *OpenAPITool {
+	t.serverVariableResolver = fn
+	return t
+}
+
+// WithOperationServers controls whether operation-level servers may override the base URL.
+func (t *OpenAPITool) WithOperationServers(enabled bool) *OpenAPITool {
+	t.ignoreOperationServers = !enabled
+	return t
+}
+
+// WithPagination enables following next-page cursors for GET operations.
+func (t *OpenAPITool) WithPagination(cfg *PaginationConfig) *OpenAPITool {
+	t.pagination = cfg
+	return t
+}
+
+// WithFileUploadRoot lets arguments upload files under root by path; see
+// RequestBuilder.WithFileUploadRoot.
+func (t *OpenAPITool) WithFileUploadRoot(root string) *OpenAPITool {
+	t.fileUploadRoot = root
+	return t
+}
+
+// WithRequestCompression gzips request bodies of at least minBytes.
+func (t *OpenAPITool) WithRequestCompression(minBytes int) *OpenAPITool {
+	t.compressMinBytes = minBytes
+	return t
+}
+
+// WithTimeoutFunc computes a per-call timeout; a non-positive result falls back to x-timeout-seconds.
+func (t *OpenAPITool) WithTimeoutFunc(fn func(ir.HTTPRoute) time.Duration) *OpenAPITool {
+	t.timeoutFn = fn
+	return t
+}
+
+// WithStrictFormats toggles enforcement of known JSON Schema formats (email, uuid,
+// date-time, ...) on arguments. Formats are enforced by default.
+func (t *OpenAPITool) WithStrictFormats(enabled bool) *OpenAPITool {
+	t.lenientFormats = !enabled
+	if validator := compileJSONSchemaWithFormats(t.tool.RawInputSchema, enabled); validator != nil {
+		t.validator = validator
+	}
+	return t
+}
+
+// WithAdditionalPropertiesMode sets how undeclared request body properties are
+// handled. Allow and Forbid rewrite the advertised input schema.
+func (t *OpenAPITool) WithAdditionalPropertiesMode(mode AdditionalPropertiesMode) *OpenAPITool {
+	t.additionalProperties = mode
+	rewritten := rewriteAdditionalProperties(t.tool.RawInputSchema, t.paramMap, mode)
+	if string(rewritten) == string(t.tool.RawInputSchema) {
+		return t
+	}
+	t.tool.RawInputSchema = rewritten
+	if validator := compileJSONSchemaWithFormats(rewritten, !t.lenientFormats); validator != nil {
+		t.validator = validator
+	}
+	return t
+}
+
+// WithNormalizeDates rewrites date and date-time arguments written in common
+// layouts (e.g. "Jan 2, 2006" or "2006-01-02 15:04") to 2006-01-02 or RFC 3339
+// before validation. It is off by default.
+func (t *OpenAPITool) WithNormalizeDates(enabled bool) *OpenAPITool {
+	t.normalizeDates = enabled
+	return t
+}
+
+// WithUnwrapSingleProperty sends the only property of an object body as the
+// bare body; see RequestBuilder.WithUnwrapSingleProperty.
+func (t *OpenAPITool) WithUnwrapSingleProperty(enabled bool) *OpenAPITool {
+	t.unwrapSingleProperty = enabled
+	return t
+}
+
+// WithOutputSchemaStatus records the response status the output schema
+// describes; responses with another status are not validated against it.
+func (t *OpenAPITool) WithOutputSchemaStatus(status string) *OpenAPITool {
+	t.outputStatus = status
+	return t
+}
+
+// WithStreamingProgress reads text/event-stream and NDJSON responses record by
+// record, reporting each one as an MCP progress notification when the call
+// carries a progress token, and returns all records as the result.
+func (t *OpenAPITool) WithStreamingProgress(enabled bool) *OpenAPITool {
+	t.streamingProgress = enabled
+	return t
+}
+
+// WithTracer wraps every upstream call in a span of tracer and propagates its
+// trace context in the request headers. A nil tracer disables tracing.
+func (t *OpenAPITool) WithTracer(tracer Tracer) *OpenAPITool {
+	t.tracer = tracer
+	return t
+}
+
+// WithHeaderPropagation restricts which inbound MCP headers reach the upstream.
+func (t *OpenAPITool) WithHeaderPropagation(policy *HeaderPropagation) *OpenAPITool {
+	t.headerPropagation = policy
+	return t
+}
+
+// WithResponseTransformer post-processes decoded responses before output validation.
+func (t *OpenAPITool) WithResponseTransformer(fn ResponseTransformer) *OpenAPITool {
+	t.transformer = fn
+	return t
+}
+
+// WithBodyLimits caps request bodies built in memory and the response bytes read
+// per call. Oversized requests fail before sending; oversized responses yield an
+// error result rather than truncated data. Zero disables a limit.
+func (t *OpenAPITool) WithBodyLimits(maxRequestBytes, maxResponseBytes int64) *OpenAPITool {
+	t.maxRequestBytes = maxRequestBytes
+	t.maxResponseBytes = maxResponseBytes
+	return t
+}
+
+// WithLogger routes call logging to logger. Arguments and results are only
+// logged at debug level since they may carry sensitive data.
+func (t *OpenAPITool) WithLogger(logger Logger) *OpenAPITool {
+	t.logger = loggerOrNop(logger)
+	return t
+}
+
+// WithContentType fixes the request body media type, for tools generated per
+// content type of an operation.
+func (t *OpenAPITool) WithContentType(contentType string) *OpenAPITool {
+	t.contentType = contentType
+	return t
+}
+
+// WithBaseURLResolver resolves the base URL per call; an empty result keeps the static one.
+func (t *OpenAPITool) WithBaseURLResolver(fn BaseURLResolver) *OpenAPITool {
+	t.baseURLResolver = fn
+	return t
+}
+
+// WithResponseCache serves repeated calls from cache when the tool is read-only
+// (ReadOnlyHint); other tools ignore it.
+func (t *OpenAPITool) WithResponseCache(cache *ResponseCache) *OpenAPITool {
+	t.cache = cache
+	return t
+}
+
+// WithAuditMetadata adds an AuditKey record to the structured content of every
+// result produced from an upstream response.
+func (t *OpenAPITool) WithAuditMetadata(enabled bool) *OpenAPITool {
+	t.auditMetadata = enabled
+	return t
+}
+
+// WithPathPrefix mounts the route path under prefix on the upstream.
+func (t *OpenAPITool) WithPathPrefix(prefix string) *OpenAPITool {
+	t.pathPrefix = prefix
+	return t
+}
+
+// WithDefaultAccept sets the Accept header sent when a call passes no `_accept`.
+func (t *OpenAPITool) WithDefaultAccept(contentType string) *OpenAPITool {
+	t.defaultAccept = contentType
+	return t
+}
+
+// WithJoinedCookieArrays sends array cookie parameters as one comma-separated cookie.
+func (t *OpenAPITool) WithJoinedCookieArrays(enabled bool) *OpenAPITool {
+	t.joinCookieArrays = enabled
+	return t
+}
+
+// WithDefaultQueryArrayFormat sets how array query parameters without a declared
+// style are sent; see RequestBuilder.WithDefaultQueryArrayFormat.
+func (t *OpenAPITool) WithDefaultQueryArrayFormat(format QueryArrayFormat) *OpenAPITool {
+	t.queryArrayFormat = format
+	return t
+}
+
+// WithMaxURLLength caps the request URL length; see RequestBuilder.WithMaxURLLength.
+func (t *OpenAPITool) WithMaxURLLength(limit int) *OpenAPITool {
+	t.maxURLLength = limit
+	return t
+}
+
+// WithCSVOptions sets how text/csv responses are split into rows.
+func (t *OpenAPITool) WithCSVOptions(delimiter rune, hasHeader bool) *OpenAPITool {
+	t.csvOptions = &CSVOptions{Delimiter: delimiter, HasHeader: hasHeader}
+	return t
+}
+
+// readOnlyCache returns the response cache if this tool may use it for
+// request. A call that asked for streaming progress bypasses it: storing
+// buffers the stream and a hit would report no progress at all.
+func (t *OpenAPITool) readOnlyCache(request mcp.CallToolRequest) *ResponseCache {
+	if t.cache == nil {
+		return nil
+	}
+	if hint := t.tool.Annotations.ReadOnlyHint; hint == nil || !*hint {
+		return nil
 	}
+	if t.streamingProgress && request.Params.Meta != nil && request.Params.Meta.ProgressToken != nil {
+		return nil
+	}
+	return t.cache
+}
+
+// ContentType returns the fixed request body media type, or "" when it is chosen per call.
+func (t *OpenAPITool) ContentType() string {
+	return t.contentType
 }
 
 func (t *OpenAPITool) Tool() mcp.Tool {
@@ -89,6 +350,10 @@ func (t *OpenAPITool) SetTool(tool mcp.Tool) {
 	t.tool = tool
 }
 
+func (t *OpenAPITool) GetRoute() ir.HTTPRoute {
+	return t.route
+}
+
 // ParameterMappings 返回公开的参数名称到 OpenAPI 参数的映射，主要用于测试和调试。
 func (t *OpenAPITool) ParameterMappings() map[string]ir.ParamMapping {
 	result := make(map[string]ir.ParamMapping, len(t.paramMap))
@@ -115,24 +380,63 @@ func (t *OpenAPITool) Run(ctx context.Context, request mcp.CallToolRequest) (*mc
 		return errorHandler.HandleParseError(err), nil
 	}
 
-	log.Printf("tool %s received arguments: %v", t.tool.Name, request.Params.Arguments)
+	t.logger.Debug("tool call received", "tool", t.tool.Name, "arguments", request.Params.Arguments)
 
 	t.normalizeArguments(args)
 
+	if err := t.applyDiscriminators(args); err != nil {
+		return errorHandler.HandleBuildError(err), nil
+	}
+
 	if err := t.validateArgs(args); err != nil {
+		var argErr *ArgumentValidationError
+		if errors.As(err, &argErr) {
+			return errorHandler.HandleArgumentError(argErr), nil
+		}
 		return errorHandler.HandleBuildError(err), nil
 	}
 
-	builder := NewRequestBuilder(t.route, t.paramMap, t.baseURL)
+	timeout := t.operationTimeout()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	baseURL := t.baseURL
+	if t.baseURLResolver != nil {
+		resolved, err := t.baseURLResolver(ctx, t.route)
+		if err != nil {
+			return errorHandler.HandleBuildError(fmt.Errorf("failed to resolve base URL: %w", err)), nil
+		}
+		if resolved != "" {
+			baseURL = resolved
+		}
+	}
+
+	builder := NewRequestBuilder(t.route, t.paramMap, baseURL).
+		WithContentType(t.contentType).
+		WithServerVariables(t.serverVariables).
+		WithServerVariableResolver(t.serverVariableResolver).
+		WithOperationServers(!t.ignoreOperationServers).
+		WithRequestCompression(t.compressMinBytes).
+		WithFileUploadRoot(t.fileUploadRoot).
+		WithMaxBodyBytes(t.maxRequestBytes).
+		WithPathPrefix(t.pathPrefix).
+		WithDefaultAccept(t.defaultAccept).
+		WithJoinedCookieArrays(t.joinCookieArrays).
+		WithDefaultQueryArrayFormat(t.queryArrayFormat).
+		WithMaxURLLength(t.maxURLLength).
+		WithUnwrapSingleProperty(t.unwrapSingleProperty)
 	httpReq, err := builder.Build(ctx, args)
 	if err != nil {
 		return errorHandler.HandleBuildError(err), nil
 	}
 
-	if mcpHeaders := internal.GetMCPHeaders(ctx); mcpHeaders != nil {
-		for k, v := range mcpHeaders {
-			httpReq.Header.Set(k, v)
-		}
+	applyMCPHeaders(ctx, httpReq.Header, t.headerPropagation)
+
+	if builder.DryRun() || internal.IsDryRun(ctx) {
+		return buildDryRunResult(httpReq)
 	}
 
 	// 检查是否有自定义 HTTP 客户端通过 context 传递
@@ -141,28 +445,112 @@ func (t *OpenAPITool) Run(ctx context.Context, request mcp.CallToolRequest) (*mc
 		client = customClient
 	}
 
-	resp, err := client.Do(httpReq)
+	var resp *http.Response
+	if t.tracer != nil {
+		var span Span
+		httpReq, span = t.startSpan(httpReq)
+		defer span.End()
+		defer func() {
+			if resp != nil {
+				span.SetAttribute("http.response.status_code", resp.StatusCode)
+			}
+			if err != nil {
+				span.RecordError(err)
+			}
+		}()
+	}
+
+	started := time.Now()
+	var truncated *paginationTruncation
+	var lookup *responseCacheLookup
+	if cache := t.readOnlyCache(request); cache != nil {
+		httpReq, lookup = withResponseCache(httpReq, cache, t.maxResponseBytes)
+		if _, ok := client.(*DefaultHTTPClient); !ok {
+			client = cachingClient{client}
+		}
+	}
+	if t.pagination != nil && strings.EqualFold(t.route.Method, http.MethodGet) {
+		pager := newPaginator(client, *t.pagination)
+		pager.maxBytes = t.maxResponseBytes
+		resp, err = pager.fetch(httpReq)
+		truncated = pager.truncated
+	} else {
+		resp, err = client.Do(httpReq)
+	}
 	if err != nil {
-		return errorHandler.HandleHTTPError(err), nil
+		var result *mcp.CallToolResult
+		if timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			result = errorHandler.HandleTimeout(timeout, err)
+		} else {
+			t.logger.Warn("upstream request failed", "tool", t.tool.Name, "method", httpReq.Method, "url", httpReq.URL.Redacted(), "error", err)
+			result = errorHandler.HandleHTTPError(err)
+		}
+		if t.auditMetadata {
+			attachAuditRecord(result, started, httpReq, nil)
+		}
+		return result, nil
+	}
+	if lookup != nil && lookup.hit {
+		t.logger.Debug("response served from cache", "tool", t.tool.Name, "url", httpReq.URL.Redacted())
 	}
 
-	processor := NewResponseProcessor(t.outputSchema, t.wrapResult, errorHandler)
+	processor := NewResponseProcessor(t.outputSchema, t.wrapResult, errorHandler).
+		WithTransformer(t.route, t.transformer).
+		WithMaxResponseBytes(t.maxResponseBytes).
+		WithSchemaStatus(t.outputStatus)
+	if t.auditMetadata {
+		processor.WithAudit(started)
+	}
+	if t.csvOptions != nil {
+		processor.WithCSVOptions(t.csvOptions.Delimiter, t.csvOptions.HasHeader)
+	}
+	if t.streamingProgress {
+		processor.WithStreamingProgress(progressReporter(ctx, request, t.logger))
+	}
+	if t.resultEnvelope {
+		processor.WithResultEnvelope(true)
+	}
+	if truncated != nil {
+		processor.WithMeta(truncated.meta())
+	}
 	callResult, err := processor.Process(resp)
 	if err != nil {
-		log.Printf("tool %s failed to process response: %v", t.tool.Name, err)
+		if timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return errorHandler.HandleTimeout(timeout, err), nil
+		}
+		t.logger.Error("failed to process response", "tool", t.tool.Name, "status", resp.StatusCode, "error", err)
 		return nil, err
 	}
 
-	log.Printf("tool %s returning result: isError=%v structured=%v content=%v meta=%v", t.tool.Name, callResult.IsError, callResult.StructuredContent, callResult.Content, callResult.Result.Meta)
+	t.logger.Info("tool call finished", "tool", t.tool.Name, "method", httpReq.Method, "status", resp.StatusCode, "isError", callResult.IsError)
+	t.logger.Debug("tool call result", "tool", t.tool.Name, "structured", callResult.StructuredContent, "content", callResult.Content, "meta", callResult.Result.Meta)
 	return callResult, nil
 }
 
+// requestContentType is the media type whose body schema the arguments follow.
+func (t *OpenAPITool) requestContentType() string {
+	if t.contentType != "" {
+		return t.contentType
+	}
+	return parser.GetContentType(t.route.RequestBody.ContentSchemas)
+}
+
+// operationTimeout prefers the programmatic timeout and falls back to the route's extension value.
+func (t *OpenAPITool) operationTimeout() time.Duration {
+	if t.timeoutFn != nil {
+		if timeout := t.timeoutFn(t.route); timeout > 0 {
+			return timeout
+		}
+	}
+	return t.route.Timeout
+}
+
 func (t *OpenAPITool) validateArgs(args map[string]interface{}) error {
 	if t.validator == nil {
 		return nil
 	}
 	if err := t.validator.Validate(args); err != nil {
-		return fmt.Errorf("argument validation failed: %w", err)
+		return &ArgumentValidationError{Violations: argumentViolations(err)}
 	}
 	return nil
 }
@@ -187,6 +575,16 @@ func (t *OpenAPITool) normalizeArguments(args map[string]interface{}) {
 			args[name] = coerced
 		}
 	}
+
+	t.coerceBodyArguments(args)
+
+	if t.normalizeDates {
+		t.normalizeDateArguments(args)
+	}
+
+	if t.additionalProperties == AdditionalPropertiesStrip {
+		t.stripAdditionalProperties(args)
+	}
 }
 
 func (t *OpenAPITool) findRouteParameter(mapping ir.ParamMapping) *ir.ParameterInfo {
@@ -232,6 +630,21 @@ func coerceValueForSchema(value interface{}, schema ir.Schema) (interface{}, boo
 				return parsed, true
 			}
 		}
+	case []interface{}:
+		// tuple elements are coerced with their positional schemas
+		if _, ok := schema["prefixItems"].([]interface{}); !ok {
+			return value, false
+		}
+		coerced := make([]interface{}, len(v))
+		changed := false
+		for i, item := range v {
+			var itemChanged bool
+			coerced[i], itemChanged = coerceValueForSchema(item, tupleItemSchema(schema, i))
+			changed = changed || itemChanged
+		}
+		if changed {
+			return coerced, true
+		}
 	}
 
 	return value, false
@@ -303,6 +716,9 @@ func buildToolMeta(route ir.HTTPRoute, tags []string) map[string]any {
 	if len(route.Tags) > 0 {
 		openapiMeta["tags"] = uniqueStrings(route.Tags)
 	}
+	if route.Deprecated {
+		openapiMeta["deprecated"] = true
+	}
 	if len(route.Extensions) > 0 {
 		openapiMeta["extensions"] = route.Extensions
 	}
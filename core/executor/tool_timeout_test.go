@@ -0,0 +1,40 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+type blockingClient struct{}
+
+func (blockingClient) Do(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestOpenAPIToolAppliesOperationTimeout(t *testing.T) {
+	route := ir.HTTPRoute{Path: "/reports", Method: "POST", Timeout: time.Hour}
+
+	tool := NewOpenAPITool("report", "", ir.Schema{"type": "object"}, nil, false, route, blockingClient{}, "https://api.example.com", nil, nil, nil).
+		WithTimeoutFunc(func(ir.HTTPRoute) time.Duration { return 20 * time.Millisecond })
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{}
+
+	result, err := tool.Run(context.Background(), request)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("expected timeout error result")
+	}
+	structured, ok := result.StructuredContent.(map[string]interface{})
+	if !ok || structured["timeout"] != true || structured["retryable"] != false {
+		t.Fatalf("expected non-retryable timeout result, got %#v", result.StructuredContent)
+	}
+}
@@ -1,8 +1,10 @@
 package executor
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/specx2/openapi-mcp/core/ir"
 )
 
@@ -51,3 +53,89 @@ func TestOpenAPIToolValidateArgs(t *testing.T) {
 		t.Fatalf("expected no tags, got %v", tags)
 	}
 }
+
+func TestOpenAPIToolStrictFormats(t *testing.T) {
+	inputSchema := ir.Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"id":    map[string]interface{}{"type": "string", "format": "uuid"},
+			"email": map[string]interface{}{"type": "string", "format": "email"},
+			"sku":   map[string]interface{}{"type": "string", "format": "x-internal-sku"},
+		},
+	}
+	route := ir.HTTPRoute{Path: "/check", Method: "POST"}
+	args := map[string]interface{}{"id": "not-a-uuid", "email": "a@example.com", "sku": "anything"}
+
+	lenient := NewOpenAPITool("check", "", inputSchema, nil, false, route, nil, "https://api.example.com", nil, nil, nil).
+		WithStrictFormats(false)
+	if err := lenient.validateArgs(args); err != nil {
+		t.Fatalf("expected formats to be ignored when disabled, got %v", err)
+	}
+
+	strict := NewOpenAPITool("check", "", inputSchema, nil, false, route, nil, "https://api.example.com", nil, nil, nil)
+	err := strict.validateArgs(args)
+	if err == nil || !strings.Contains(err.Error(), `argument "id"`) || !strings.Contains(err.Error(), "uuid") {
+		t.Fatalf("expected uuid format error naming id, got %v", err)
+	}
+
+	args["id"] = "9b2f3c3e-1d4a-4c43-9f1e-7a3b2c1d0e5f"
+	if err := strict.validateArgs(args); err != nil {
+		t.Fatalf("expected valid formats and unknown custom format to pass, got %v", err)
+	}
+}
+
+func TestOpenAPIToolReportsArgumentViolationsByField(t *testing.T) {
+	inputSchema := ir.Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string"},
+			"user": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"email": map[string]interface{}{"type": "string", "format": "email"},
+					"age":   map[string]interface{}{"type": "integer"},
+				},
+				"additionalProperties": false,
+			},
+		},
+		"required": []interface{}{"name"},
+	}
+	route := ir.HTTPRoute{Path: "/users", Method: "POST"}
+	tool := NewOpenAPITool("createUser", "", inputSchema, nil, false, route, nil, "https://api.example.com", nil, nil, nil)
+
+	err := tool.validateArgs(map[string]interface{}{
+		"user": map[string]interface{}{"email": "not-an-email", "age": "old", "nickname": "x"},
+	})
+	argErr, ok := err.(*ArgumentValidationError)
+	if !ok {
+		t.Fatalf("expected an ArgumentValidationError, got %T: %v", err, err)
+	}
+
+	messages := make(map[string]string)
+	for _, violation := range argErr.Violations {
+		messages[violation.Field] = violation.Message
+	}
+	expected := map[string]string{
+		"name":          "is required",
+		"user.email":    "must match format email",
+		"user.nickname": "is not allowed",
+	}
+	for field, message := range expected {
+		if messages[field] != message {
+			t.Fatalf("expected %q for %s, got %#v", message, field, argErr.Violations)
+		}
+	}
+	if !strings.Contains(messages["user.age"], "integer") {
+		t.Fatalf("expected a type error for user.age, got %#v", argErr.Violations)
+	}
+
+	result := NewErrorHandler("").HandleArgumentError(argErr)
+	text := result.Content[0].(mcp.TextContent).Text
+	if !result.IsError || !strings.Contains(text, "- field `user.email` must match format email") {
+		t.Fatalf("unexpected error text %q", text)
+	}
+	structured := result.StructuredContent.(map[string]interface{})
+	if violations, _ := structured["violations"].([]interface{}); len(violations) != len(argErr.Violations) {
+		t.Fatalf("expected the violations in structured content, got %#v", structured)
+	}
+}
@@ -0,0 +1,57 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Tracer wraps upstream calls in spans. It keeps the executor free of any
+// tracing dependency; an OpenTelemetry adapter starts spans with an
+// otel trace.Tracer and implements Inject with the global propagator:
+//
+//	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+type Tracer interface {
+	// Start begins a span named name as a child of any span in ctx.
+	Start(ctx context.Context, name string) (context.Context, Span)
+	// Inject writes the trace context of ctx into outgoing request headers,
+	// e.g. the W3C traceparent and tracestate headers.
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is a single traced upstream call.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// startSpan starts the span of one tool call and attaches it to req, whose trace
+// context is injected into its headers.
+func (t *OpenAPITool) startSpan(req *http.Request) (*http.Request, Span) {
+	name := t.route.OperationID
+	if name == "" {
+		name = t.tool.Name
+	}
+	ctx, span := t.tracer.Start(req.Context(), name)
+	span.SetAttribute("openapi.operation_id", t.route.OperationID)
+	span.SetAttribute("mcp.tool.name", t.tool.Name)
+	span.SetAttribute("http.request.method", req.Method)
+	span.SetAttribute("url.full", sanitizedURL(req.URL))
+
+	req = req.WithContext(ctx)
+	t.tracer.Inject(ctx, req.Header)
+	return req, span
+}
+
+// sanitizedURL drops credentials, the query string and the fragment, which may
+// carry API keys or personal data.
+func sanitizedURL(u *url.URL) string {
+	clean := *u
+	clean.User = nil
+	clean.RawQuery = ""
+	clean.ForceQuery = false
+	clean.Fragment = ""
+	clean.RawFragment = ""
+	return clean.String()
+}
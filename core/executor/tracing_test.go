@@ -0,0 +1,83 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+type recordingSpan struct {
+	name       string
+	attributes map[string]interface{}
+	errs       []error
+	ended      bool
+}
+
+func (s *recordingSpan) SetAttribute(key string, value interface{}) { s.attributes[key] = value }
+func (s *recordingSpan) RecordError(err error)                      { s.errs = append(s.errs, err) }
+func (s *recordingSpan) End()                                       { s.ended = true }
+
+type recordingTracer struct{ spans []*recordingSpan }
+
+func (r *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordingSpan{name: name, attributes: make(map[string]interface{})}
+	r.spans = append(r.spans, span)
+	return ctx, span
+}
+
+func (r *recordingTracer) Inject(ctx context.Context, header http.Header) {
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+}
+
+type refusedClient struct{}
+
+func (refusedClient) Do(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestOpenAPIToolTracesUpstreamCalls(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:        "/pets",
+		Method:      "GET",
+		OperationID: "listPets",
+		Parameters:  []ir.ParameterInfo{{Name: "api_key", In: ir.ParameterInQuery, Schema: ir.Schema{"type": "string"}}},
+	}
+	paramMap := map[string]ir.ParamMapping{"api_key": {OpenAPIName: "api_key", Location: ir.ParameterInQuery}}
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"api_key": "secret"}
+
+	tracer := &recordingTracer{}
+	resp := jsonResponse(`{"ok": true}`)
+	tool := NewOpenAPITool("list_pets", "", ir.Schema{"type": "object"}, nil, false, route, staticClient{resp: resp}, "https://api.example.com", paramMap, nil, nil).
+		WithTracer(tracer)
+	if _, err := tool.Run(context.Background(), request); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("expected one span, got %d", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.name != "listPets" || !span.ended || len(span.errs) != 0 {
+		t.Fatalf("unexpected span %+v", span)
+	}
+	if span.attributes["url.full"] != "https://api.example.com/pets" || span.attributes["http.request.method"] != "GET" || span.attributes["http.response.status_code"] != http.StatusOK {
+		t.Fatalf("unexpected span attributes %v", span.attributes)
+	}
+	if resp.Request.Header.Get("traceparent") == "" {
+		t.Fatalf("expected the trace context to be sent upstream")
+	}
+
+	tool = NewOpenAPITool("list_pets", "", ir.Schema{"type": "object"}, nil, false, route, refusedClient{}, "https://api.example.com", paramMap, nil, nil).
+		WithTracer(tracer)
+	if _, err := tool.Run(context.Background(), request); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if failed := tracer.spans[1]; len(failed.errs) != 1 || !failed.ended {
+		t.Fatalf("expected the upstream error on the span, got %+v", failed)
+	}
+}
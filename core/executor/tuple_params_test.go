@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+	"github.com/specx2/openapi-mcp/core/parser"
+)
+
+const tupleParamSpec = `{
+    "openapi": "3.1.0",
+    "info": {"title": "Tiles", "version": "1.0"},
+    "paths": {
+        "/tiles/{coord}": {
+            "get": {
+                "operationId": "getTile",
+                "parameters": [
+                    {
+                        "name": "coord", "in": "path", "required": true,
+                        "schema": {"type": "array", "prefixItems": [{"type": "string"}, {"type": "integer"}], "items": false, "minItems": 2}
+                    },
+                    {
+                        "name": "range", "in": "query", "style": "form", "explode": false,
+                        "schema": {"type": "array", "prefixItems": [{"type": "integer"}, {"type": "boolean"}]}
+                    }
+                ],
+                "responses": {"200": {"description": "ok"}}
+            }
+        }
+    }
+}`
+
+func TestOpenAPIToolSerializesTupleParameters(t *testing.T) {
+	routes, err := parser.NewOpenAPI31Parser().ParseSpec([]byte(tupleParamSpec))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	route := routes[0]
+	if _, ok := route.Parameters[0].Schema["prefixItems"].([]interface{}); !ok {
+		t.Fatalf("expected prefixItems to be preserved, got %#v", route.Parameters[0].Schema)
+	}
+
+	inputSchema := ir.Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"coord": map[string]interface{}(route.Parameters[0].Schema),
+			"range": map[string]interface{}(route.Parameters[1].Schema),
+		},
+		"required": []interface{}{"coord"},
+	}
+	paramMap := map[string]ir.ParamMapping{
+		"coord": {OpenAPIName: "coord", Location: ir.ParameterInPath},
+		"range": {OpenAPIName: "range", Location: ir.ParameterInQuery},
+	}
+	tool := NewOpenAPITool("getTile", "", inputSchema, nil, false, route, failingClient{t}, "https://api.example.com", paramMap, nil, nil)
+
+	run := func(args map[string]interface{}) *mcp.CallToolResult {
+		args["_dryRun"] = true
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := tool.Run(context.Background(), request)
+		if err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+		return result
+	}
+
+	result := run(map[string]interface{}{
+		"coord": []interface{}{"z7", "12"},
+		"range": []interface{}{float64(10), "true"},
+	})
+	if result.IsError {
+		t.Fatalf("expected tuple to validate, got %#v", result.Content)
+	}
+	preview := result.StructuredContent.(map[string]interface{})["request"].(map[string]interface{})
+	if preview["url"] != "https://api.example.com/tiles/z7,12?range=10%2Ctrue" {
+		t.Fatalf("unexpected url %v", preview["url"])
+	}
+
+	for _, coord := range []interface{}{
+		[]interface{}{"z7"},
+		[]interface{}{"z7", "twelve"},
+		[]interface{}{"z7", 12, "extra"},
+	} {
+		if result := run(map[string]interface{}{"coord": coord}); !result.IsError {
+			t.Fatalf("expected tuple shape violation for %v", coord)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// UpstreamError is an error body read through the error response schema the
+// operation declares for its status.
+type UpstreamError struct {
+	Code        string
+	Message     string
+	FieldErrors []UpstreamFieldError
+}
+
+// UpstreamFieldError is one entry of an error body's per-field error list.
+type UpstreamFieldError struct {
+	Field   string
+	Message string
+}
+
+// Property names recognised in declared error schemas, in order of preference.
+var (
+	errorCodeProperties    = []string{"code", "errorCode", "error_code", "type"}
+	errorMessageProperties = []string{"message", "detail", "error_description", "title", "error"}
+	fieldErrorsProperties  = []string{"fieldErrors", "field_errors", "errors", "violations", "invalidParams", "invalid_params", "details"}
+	fieldNameKeys          = []string{"field", "name", "path", "pointer", "param", "property", "loc"}
+	fieldMessageKeys       = []string{"message", "msg", "reason", "detail", "description"}
+)
+
+// upstreamError reads an error body with the schema declared for its status and
+// content type. It reports false when the operation declares no schema, the
+// body does not validate against it, or the schema has none of the code,
+// message or field error properties recognised here.
+func (rp *ResponseProcessor) upstreamError(resp *http.Response, body []byte) (*UpstreamError, bool) {
+	info := responseInfoForStatus(rp.route.Responses, resp.StatusCode)
+	if info == nil {
+		return nil, false
+	}
+	schema := errorContentSchema(info.ContentSchemas, resp.Header.Get("Content-Type"))
+	if schema == nil {
+		return nil, false
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, false
+	}
+	validationSchema := make(ir.Schema, len(schema)+1)
+	for key, value := range schema {
+		validationSchema[key] = value
+	}
+	if defs, ok := rp.route.SchemaDefs["$defs"]; ok {
+		if _, own := validationSchema["$defs"]; !own {
+			validationSchema["$defs"] = defs
+		}
+	}
+	validator := compileIRSchema(validationSchema)
+	if validator == nil || validator.Validate(data) != nil {
+		return nil, false
+	}
+
+	object, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	resolver := schemaResolver{defs: rp.route.SchemaDefs}
+	upstream := readUpstreamError(object, schema, resolver)
+	if upstream.Code == "" && upstream.Message == "" && len(upstream.FieldErrors) == 0 {
+		// {"error": {"code": ..., "message": ...}} wraps the details one level down.
+		properties, _ := variantShape(schema, resolver, 0)
+		nested, isObject := object["error"].(map[string]interface{})
+		if errorSchema, declared := properties["error"]; declared && isObject {
+			upstream = readUpstreamError(nested, errorSchema, resolver)
+		}
+	}
+	if upstream.Code == "" && upstream.Message == "" && len(upstream.FieldErrors) == 0 {
+		return nil, false
+	}
+	return &upstream, true
+}
+
+// errorContentSchema picks the declared schema for the response media type,
+// falling back to the first JSON one.
+func errorContentSchema(schemas map[string]ir.Schema, contentType string) ir.Schema {
+	base := baseMediaType(contentType)
+	if base != "" {
+		for mediaType, schema := range schemas {
+			if mediaRangeMatches(baseMediaType(mediaType), base) {
+				return schema
+			}
+		}
+	}
+	mediaTypes := make([]string, 0, len(schemas))
+	for mediaType := range schemas {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	for _, mediaType := range mediaTypes {
+		if strings.Contains(baseMediaType(mediaType), "json") {
+			return schemas[mediaType]
+		}
+	}
+	return nil
+}
+
+// readUpstreamError takes code, message and field errors from the properties
+// the schema declares.
+func readUpstreamError(object map[string]interface{}, schema ir.Schema, resolver schemaResolver) UpstreamError {
+	properties, _ := variantShape(schema, resolver, 0)
+	var upstream UpstreamError
+	for _, name := range errorCodeProperties {
+		if _, declared := properties[name]; declared {
+			if code := scalarText(object[name]); code != "" {
+				upstream.Code = code
+				break
+			}
+		}
+	}
+	for _, name := range errorMessageProperties {
+		if _, declared := properties[name]; declared {
+			if message := scalarText(object[name]); message != "" {
+				upstream.Message = message
+				break
+			}
+		}
+	}
+	for _, name := range fieldErrorsProperties {
+		if _, declared := properties[name]; !declared {
+			continue
+		}
+		if entries, ok := object[name].([]interface{}); ok && len(entries) > 0 {
+			upstream.FieldErrors = readFieldErrors(entries)
+			break
+		}
+	}
+	return upstream
+}
+
+func readFieldErrors(entries []interface{}) []UpstreamFieldError {
+	fieldErrors := make([]UpstreamFieldError, 0, len(entries))
+	for _, entry := range entries {
+		switch v := entry.(type) {
+		case string:
+			fieldErrors = append(fieldErrors, UpstreamFieldError{Message: v})
+		case map[string]interface{}:
+			var fieldError UpstreamFieldError
+			for _, key := range fieldNameKeys {
+				if field := fieldNameText(v[key]); field != "" {
+					fieldError.Field = field
+					break
+				}
+			}
+			for _, key := range fieldMessageKeys {
+				if message := scalarText(v[key]); message != "" {
+					fieldError.Message = message
+					break
+				}
+			}
+			if fieldError.Field != "" || fieldError.Message != "" {
+				fieldErrors = append(fieldErrors, fieldError)
+			}
+		}
+	}
+	return fieldErrors
+}
+
+// fieldNameText renders a field reference; a location list such as
+// ["body", "name"] is joined with dots.
+func fieldNameText(value interface{}) string {
+	if parts, ok := value.([]interface{}); ok {
+		names := make([]string, 0, len(parts))
+		for _, part := range parts {
+			if text := scalarText(part); text != "" {
+				names = append(names, text)
+			}
+		}
+		return strings.Join(names, ".")
+	}
+	return scalarText(value)
+}
+
+func scalarText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64, bool, json.Number:
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// HandleUpstreamError reports an upstream error response with its code,
+// message and field errors pulled out, keeping the raw body in structured
+// content next to them.
+func (eh *ErrorHandler) HandleUpstreamError(resp *http.Response, body []byte, upstream *UpstreamError) *mcp.CallToolResult {
+	result := eh.HandleHTTPResponse(resp, body)
+	structured, _ := result.StructuredContent.(map[string]interface{})
+
+	statusText := strings.TrimSpace(resp.Status)
+	if statusText == "" {
+		statusText = http.StatusText(resp.StatusCode)
+	}
+	summary := upstream.Message
+	if upstream.Code != "" && summary != "" {
+		summary = upstream.Code + ": " + summary
+	} else if upstream.Code != "" {
+		summary = upstream.Code
+	}
+	lines := []string{fmt.Sprintf("HTTP %d: %s", resp.StatusCode, statusText)}
+	if summary != "" {
+		lines[0] += " - " + summary
+	}
+
+	details := make(map[string]interface{})
+	if upstream.Code != "" {
+		details["code"] = upstream.Code
+	}
+	if upstream.Message != "" {
+		details["message"] = upstream.Message
+	}
+	if len(upstream.FieldErrors) > 0 {
+		fieldErrors := make([]interface{}, 0, len(upstream.FieldErrors))
+		for _, fieldError := range upstream.FieldErrors {
+			if fieldError.Field == "" {
+				lines = append(lines, "- "+fieldError.Message)
+			} else {
+				lines = append(lines, fmt.Sprintf("- field `%s` %s", fieldError.Field, fieldError.Message))
+			}
+			fieldErrors = append(fieldErrors, map[string]interface{}{
+				"field":   fieldError.Field,
+				"message": fieldError.Message,
+			})
+		}
+		details["fieldErrors"] = fieldErrors
+	}
+	structured["error"] = details
+
+	result.Content = []mcp.Content{mcp.NewTextContent(strings.Join(lines, "\n"))}
+	result.StructuredContent = structured
+	return result
+}
@@ -0,0 +1,69 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// queryArgument keeps a query parameter's argument next to its encoded pairs so
+// it can still be moved into the request body.
+type queryArgument struct {
+	name    string
+	value   interface{}
+	encoded []EncodedParameter
+}
+
+func (q queryArgument) length() int {
+	return len(buildQueryString(q.encoded))
+}
+
+func flattenQueryArguments(args []queryArgument) []EncodedParameter {
+	params := make([]EncodedParameter, 0, len(args))
+	for _, arg := range args {
+		params = append(params, arg.encoded...)
+	}
+	return params
+}
+
+// buildURLWithinLimit builds the request URL and enforces the maximum URL length.
+// When the URL is too long, query parameters that the request body also declares
+// as properties move into the body, longest first, until it fits. If it still
+// does not fit, the error names the longest remaining query parameter.
+func (rb *RequestBuilder) buildURLWithinLimit(ctx context.Context, pathParams map[string]string, queryArgs []queryArgument, bodyParams map[string]interface{}, rawBody interface{}) (string, error) {
+	reqURL, err := rb.buildURL(ctx, pathParams, flattenQueryArguments(queryArgs))
+	if err != nil || rb.maxURLLength <= 0 || len(reqURL) <= rb.maxURLLength {
+		return reqURL, err
+	}
+
+	remaining := append([]queryArgument(nil), queryArgs...)
+	sort.SliceStable(remaining, func(i, j int) bool {
+		return remaining[i].length() > remaining[j].length()
+	})
+
+	if rawBody == nil {
+		properties := rb.lookupBodySchema(rb.bodyContentType).Properties()
+		for i := 0; i < len(remaining); {
+			arg := remaining[i]
+			_, declared := properties[arg.name]
+			_, taken := bodyParams[arg.name]
+			if !declared || taken {
+				i++
+				continue
+			}
+			bodyParams[arg.name] = arg.value
+			remaining = append(remaining[:i], remaining[i+1:]...)
+
+			reqURL, err = rb.buildURL(ctx, pathParams, flattenQueryArguments(remaining))
+			if err != nil || len(reqURL) <= rb.maxURLLength {
+				return reqURL, err
+			}
+		}
+	}
+
+	if len(remaining) == 0 {
+		return "", fmt.Errorf("request URL is %d bytes, over the %d byte limit", len(reqURL), rb.maxURLLength)
+	}
+	return "", fmt.Errorf("request URL is %d bytes, over the %d byte limit; query parameter %q takes %d bytes, pass a smaller value or split the request",
+		len(reqURL), rb.maxURLLength, remaining[0].name, remaining[0].length())
+}
@@ -0,0 +1,400 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+const defaultXMLRootName = "root"
+
+func isXMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+// xmlMeta mirrors the OpenAPI `xml` object attached to a schema.
+type xmlMeta struct {
+	Name      string
+	Namespace string
+	Prefix    string
+	Attribute bool
+	Wrapped   bool
+}
+
+func schemaXMLMeta(schema ir.Schema) xmlMeta {
+	var meta xmlMeta
+	if schema == nil {
+		return meta
+	}
+	raw, ok := schema["xml"].(map[string]interface{})
+	if !ok {
+		return meta
+	}
+	meta.Name, _ = raw["name"].(string)
+	meta.Namespace, _ = raw["namespace"].(string)
+	meta.Prefix, _ = raw["prefix"].(string)
+	meta.Attribute, _ = raw["attribute"].(bool)
+	meta.Wrapped, _ = raw["wrapped"].(bool)
+	return meta
+}
+
+func (m xmlMeta) qualified(fallback string) xml.Name {
+	local := fallback
+	if m.Name != "" {
+		local = m.Name
+	}
+	if m.Prefix != "" {
+		local = m.Prefix + ":" + local
+	}
+	return xml.Name{Local: local}
+}
+
+// encodeXMLBody renders value as an XML document guided by the schema's xml
+// metadata (element/attribute names, prefixes, wrapped arrays).
+func encodeXMLBody(value interface{}, schema ir.Schema, defs ir.Schema) ([]byte, error) {
+	resolver := schemaResolver{defs: defs}
+	resolved, componentName := resolver.resolve(schema)
+	meta := schemaXMLMeta(resolved)
+
+	rootName := defaultXMLRootName
+	if componentName != "" {
+		rootName = componentName
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	if err := writeXMLElement(enc, resolver, meta.qualified(rootName), meta, value, resolved); err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeXMLElement(enc *xml.Encoder, resolver schemaResolver, name xml.Name, meta xmlMeta, value interface{}, schema ir.Schema) error {
+	start := xml.StartElement{Name: name}
+	if meta.Namespace != "" {
+		attr := "xmlns"
+		if meta.Prefix != "" {
+			attr = "xmlns:" + meta.Prefix
+		}
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: attr}, Value: meta.Namespace})
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties := schema.Properties()
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		type child struct {
+			key    string
+			schema ir.Schema
+			meta   xmlMeta
+		}
+		var children []child
+		for _, key := range keys {
+			propSchema, _ := resolver.resolve(properties[key])
+			propMeta := schemaXMLMeta(propSchema)
+			if propMeta.Attribute {
+				if v[key] == nil {
+					continue
+				}
+				start.Attr = append(start.Attr, xml.Attr{Name: propMeta.qualified(key), Value: formatScalar(v[key])})
+				continue
+			}
+			children = append(children, child{key: key, schema: propSchema, meta: propMeta})
+		}
+
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, c := range children {
+			if err := writeXMLProperty(enc, resolver, c.key, c.meta, v[c.key], c.schema); err != nil {
+				return err
+			}
+		}
+		return enc.EncodeToken(start.End())
+	case []interface{}:
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		itemSchema, _ := resolver.resolve(schemaItems(schema))
+		itemMeta := schemaXMLMeta(itemSchema)
+		for _, item := range v {
+			if err := writeXMLElement(enc, resolver, itemMeta.qualified("item"), itemMeta, item, itemSchema); err != nil {
+				return err
+			}
+		}
+		return enc.EncodeToken(start.End())
+	case nil:
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		return enc.EncodeToken(start.End())
+	default:
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		if err := enc.EncodeToken(xml.CharData(formatScalar(v))); err != nil {
+			return err
+		}
+		return enc.EncodeToken(start.End())
+	}
+}
+
+func writeXMLProperty(enc *xml.Encoder, resolver schemaResolver, key string, meta xmlMeta, value interface{}, schema ir.Schema) error {
+	items, isArray := value.([]interface{})
+	if !isArray {
+		return writeXMLElement(enc, resolver, meta.qualified(key), meta, value, schema)
+	}
+
+	itemSchema, _ := resolver.resolve(schemaItems(schema))
+	itemMeta := schemaXMLMeta(itemSchema)
+	if !meta.Wrapped {
+		// Unwrapped arrays repeat the item element; it defaults to the property name.
+		itemName := itemMeta.qualified(key)
+		if itemMeta.Name == "" {
+			itemName = meta.qualified(key)
+		}
+		for _, item := range items {
+			if err := writeXMLElement(enc, resolver, itemName, itemMeta, item, itemSchema); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	start := xml.StartElement{Name: meta.qualified(key)}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := writeXMLElement(enc, resolver, itemMeta.qualified(key), itemMeta, item, itemSchema); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+func schemaItems(schema ir.Schema) ir.Schema {
+	if schema == nil {
+		return nil
+	}
+	switch items := schema["items"].(type) {
+	case map[string]interface{}:
+		return items
+	case ir.Schema:
+		return items
+	}
+	return nil
+}
+
+// xmlNode is a generic element tree used when decoding XML responses.
+type xmlNode struct {
+	name     string
+	attrs    map[string]string
+	children []*xmlNode
+	text     strings.Builder
+}
+
+func parseXMLDocument(data []byte) (*xmlNode, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*xmlNode
+	var root *xmlNode
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name.Local, attrs: make(map[string]string)}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+					continue
+				}
+				node.attrs[attr.Name.Local] = attr.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else if root == nil {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("invalid XML: no root element")
+	}
+	return root, nil
+}
+
+// decodeXMLBody converts an XML document into JSON-compatible values, using the
+// schema (when available) to pick element names, unwrap arrays and coerce scalars.
+func decodeXMLBody(data []byte, schema ir.Schema, defs ir.Schema) (interface{}, error) {
+	root, err := parseXMLDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	resolver := schemaResolver{defs: defs}
+	resolved, _ := resolver.resolve(schema)
+	return xmlNodeValue(root, resolved, resolver), nil
+}
+
+func xmlNodeValue(node *xmlNode, schema ir.Schema, resolver schemaResolver) interface{} {
+	switch schema.Type() {
+	case "array":
+		itemSchema, _ := resolver.resolve(schemaItems(schema))
+		result := make([]interface{}, 0, len(node.children))
+		for _, child := range node.children {
+			result = append(result, xmlNodeValue(child, itemSchema, resolver))
+		}
+		return result
+	case "object":
+		return xmlObjectValue(node, schema, resolver)
+	case "":
+		if len(node.children) > 0 || len(node.attrs) > 0 {
+			return xmlObjectValue(node, schema, resolver)
+		}
+	}
+	return coerceXMLScalar(strings.TrimSpace(node.text.String()), schema)
+}
+
+func xmlObjectValue(node *xmlNode, schema ir.Schema, resolver schemaResolver) map[string]interface{} {
+	result := make(map[string]interface{})
+	properties := schema.Properties()
+	consumedAttrs := make(map[string]struct{})
+	consumedChildren := make(map[*xmlNode]struct{})
+
+	for key, raw := range properties {
+		propSchema, _ := resolver.resolve(raw)
+		meta := schemaXMLMeta(propSchema)
+		name := key
+		if meta.Name != "" {
+			name = meta.Name
+		}
+
+		if meta.Attribute {
+			if value, ok := node.attrs[name]; ok {
+				result[key] = coerceXMLScalar(value, propSchema)
+				consumedAttrs[name] = struct{}{}
+			}
+			continue
+		}
+
+		if propSchema.Type() == "array" {
+			itemSchema, _ := resolver.resolve(schemaItems(propSchema))
+			itemMeta := schemaXMLMeta(itemSchema)
+			var elements []*xmlNode
+			if meta.Wrapped {
+				for _, child := range node.children {
+					if child.name == name {
+						consumedChildren[child] = struct{}{}
+						elements = append(elements, child.children...)
+					}
+				}
+			} else {
+				itemName := name
+				if itemMeta.Name != "" {
+					itemName = itemMeta.Name
+				}
+				for _, child := range node.children {
+					if child.name == itemName {
+						consumedChildren[child] = struct{}{}
+						elements = append(elements, child)
+					}
+				}
+			}
+			if len(elements) == 0 {
+				continue
+			}
+			values := make([]interface{}, 0, len(elements))
+			for _, element := range elements {
+				values = append(values, xmlNodeValue(element, itemSchema, resolver))
+			}
+			result[key] = values
+			continue
+		}
+
+		for _, child := range node.children {
+			if child.name == name {
+				consumedChildren[child] = struct{}{}
+				result[key] = xmlNodeValue(child, propSchema, resolver)
+				break
+			}
+		}
+	}
+
+	// Elements and attributes the schema doesn't describe are kept generically;
+	// repeated element names collapse into arrays.
+	for name, value := range node.attrs {
+		if _, ok := consumedAttrs[name]; ok {
+			continue
+		}
+		if _, exists := result[name]; !exists {
+			result[name] = value
+		}
+	}
+	for _, child := range node.children {
+		if _, ok := consumedChildren[child]; ok {
+			continue
+		}
+		value := xmlNodeValue(child, nil, resolver)
+		switch existing := result[child.name].(type) {
+		case nil:
+			result[child.name] = value
+		case []interface{}:
+			result[child.name] = append(existing, value)
+		default:
+			result[child.name] = []interface{}{existing, value}
+		}
+	}
+	return result
+}
+
+func coerceXMLScalar(text string, schema ir.Schema) interface{} {
+	switch {
+	case schemaAllowsType(schema, "integer"):
+		if parsed, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return float64(parsed)
+		}
+	case schemaAllowsType(schema, "number"):
+		if parsed, err := strconv.ParseFloat(text, 64); err == nil {
+			return parsed
+		}
+	case schemaAllowsType(schema, "boolean"):
+		if parsed, err := strconv.ParseBool(text); err == nil {
+			return parsed
+		}
+	}
+	return text
+}
@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+var xmlPetSchema = ir.Schema{
+	"type": "object",
+	"xml":  map[string]interface{}{"name": "pet"},
+	"properties": map[string]interface{}{
+		"id": map[string]interface{}{
+			"type": "integer",
+			"xml":  map[string]interface{}{"attribute": true},
+		},
+		"name": map[string]interface{}{"type": "string", "xml": map[string]interface{}{"name": "petName"}},
+		"tags": map[string]interface{}{
+			"type": "array",
+			"xml":  map[string]interface{}{"wrapped": true},
+			"items": map[string]interface{}{
+				"type": "string",
+				"xml":  map[string]interface{}{"name": "tag"},
+			},
+		},
+		"photoUrls": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "string", "xml": map[string]interface{}{"name": "photoUrl"}},
+		},
+	},
+}
+
+func TestRequestBuilderEncodesXMLBodyWithSchemaMetadata(t *testing.T) {
+	route := ir.HTTPRoute{
+		Path:   "/pets",
+		Method: "POST",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{"application/xml": {"$ref": "#/$defs/Pet"}},
+		},
+		SchemaDefs: ir.Schema{"$defs": map[string]interface{}{"Pet": map[string]interface{}(xmlPetSchema)}},
+	}
+
+	req, err := NewRequestBuilder(route, nil, "https://api.example.com").Build(context.Background(), map[string]interface{}{
+		"id":        float64(7),
+		"name":      "Rex",
+		"tags":      []interface{}{"good", "dog"},
+		"photoUrls": []interface{}{"a.png", "b.png"},
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/xml" {
+		t.Fatalf("expected application/xml, got %q", ct)
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	expected := `<pet id="7"><petName>Rex</petName><photoUrl>a.png</photoUrl><photoUrl>b.png</photoUrl><tags><tag>good</tag><tag>dog</tag></tags></pet>`
+	if !strings.HasSuffix(string(body), expected) {
+		t.Fatalf("unexpected XML body:\n%s", body)
+	}
+}
+
+func TestResponseProcessorDecodesXMLUsingOutputSchema(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"application/xml; charset=utf-8"}},
+		Body: io.NopCloser(strings.NewReader(`<?xml version="1.0"?>
+<pet id="7"><petName>Rex</petName><tags><tag>good</tag></tags><photoUrl>a.png</photoUrl><photoUrl>b.png</photoUrl></pet>`)),
+	}
+
+	result, err := NewResponseProcessor(xmlPetSchema, false, nil).Process(resp)
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected output validation to pass, got %#v", result.Content)
+	}
+
+	expected := map[string]interface{}{
+		"id":        float64(7),
+		"name":      "Rex",
+		"tags":      []interface{}{"good"},
+		"photoUrls": []interface{}{"a.png", "b.png"},
+	}
+	if !reflect.DeepEqual(result.StructuredContent, expected) {
+		t.Fatalf("unexpected structured content: %#v", result.StructuredContent)
+	}
+}
@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// isYAMLContentType recognises application/yaml, text/yaml, their x- variants and +yaml suffixes.
+func isYAMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	switch mediaType {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+yaml")
+}
+
+// encodeYAML marshals a body via its JSON form so field names and numbers match the JSON encoding.
+func encodeYAML(body interface{}) ([]byte, error) {
+	data, err := yaml.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal YAML request body: %w", err)
+	}
+	return data, nil
+}
+
+// decodeYAMLBody converts a YAML document into the same generic values json.Unmarshal produces.
+func decodeYAMLBody(data []byte) (interface{}, error) {
+	converted, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	if err := json.Unmarshal(converted, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func yamlRoute() ir.HTTPRoute {
+	return ir.HTTPRoute{
+		Path:   "/configs",
+		Method: "PUT",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{"application/yaml": {
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name":     map[string]interface{}{"type": "string"},
+					"replicas": map[string]interface{}{"type": "integer"},
+				},
+			}},
+		},
+	}
+}
+
+func TestRequestBuilderEncodesYAMLBody(t *testing.T) {
+	req, err := NewRequestBuilder(yamlRoute(), nil, "https://api.example.com").Build(context.Background(), map[string]interface{}{
+		"name":     "web",
+		"replicas": float64(3),
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/yaml" {
+		t.Fatalf("expected application/yaml, got %q", ct)
+	}
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != "name: web\nreplicas: 3\n" {
+		t.Fatalf("unexpected YAML body:\n%s", body)
+	}
+}
+
+func TestRequestBuilderPassesRawYAMLThrough(t *testing.T) {
+	raw := "# hand written\nname: web\n"
+	req, err := NewRequestBuilder(yamlRoute(), nil, "https://api.example.com").Build(context.Background(), map[string]interface{}{
+		"_rawBody": raw,
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != raw {
+		t.Fatalf("expected raw YAML verbatim, got %q", body)
+	}
+}
+
+func TestResponseProcessorDecodesYAML(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"text/yaml; charset=utf-8"}},
+		Body:       io.NopCloser(strings.NewReader("name: web\nreplicas: 3\nports:\n  - 80\n  - 443\n")),
+	}
+
+	result, err := NewResponseProcessor(nil, false, nil).Process(resp)
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+	expected := map[string]interface{}{
+		"name":     "web",
+		"replicas": float64(3),
+		"ports":    []interface{}{float64(80), float64(443)},
+	}
+	if !reflect.DeepEqual(result.StructuredContent, expected) {
+		t.Fatalf("unexpected structured content: %#v", result.StructuredContent)
+	}
+}
@@ -0,0 +1,142 @@
+package factory
+
+import (
+	"strings"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// dropAccessProperties removes properties flagged with keyword ("readOnly" for
+// request schemas, "writeOnly" for response schemas) from schema and its nested
+// schemas, including allOf branches, and drops them from the matching required
+// lists. A property whose `$ref` points at a definition carrying the flag is
+// dropped as well.
+func dropAccessProperties(node interface{}, keyword string, defs map[string]interface{}) {
+	schema := schemaMapOf(node)
+	if schema == nil {
+		return
+	}
+
+	dropped := make(map[string]bool)
+	collectAccessProperties(schema, keyword, defs, dropped)
+	if len(dropped) > 0 {
+		removeAccessProperties(schema, dropped)
+	}
+
+	if props, ok := schema["properties"].(map[string]interface{}); ok {
+		for _, prop := range props {
+			dropAccessProperties(prop, keyword, defs)
+		}
+	}
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		dropAccessProperties(schema[key], keyword, defs)
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf", "prefixItems"} {
+		if branches, ok := schema[key].([]interface{}); ok {
+			for _, branch := range branches {
+				dropAccessProperties(branch, keyword, defs)
+			}
+		}
+	}
+}
+
+// dropAccessDefinitions filters every definition and returns those still
+// reachable from schema.
+func dropAccessDefinitions(schema ir.Schema, defs map[string]interface{}, keyword string) map[string]interface{} {
+	for _, def := range defs {
+		dropAccessProperties(def, keyword, defs)
+	}
+	return pruneSchemaDefinitions(schema, ir.Schema{"$defs": defs})
+}
+
+func collectAccessProperties(schema map[string]interface{}, keyword string, defs map[string]interface{}, dropped map[string]bool) {
+	if props, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, prop := range props {
+			if hasAccessFlag(schemaMapOf(prop), keyword, defs) {
+				dropped[name] = true
+			}
+		}
+	}
+	if branches, ok := schema["allOf"].([]interface{}); ok {
+		for _, branch := range branches {
+			if sub := schemaMapOf(branch); sub != nil {
+				collectAccessProperties(sub, keyword, defs, dropped)
+			}
+		}
+	}
+}
+
+func removeAccessProperties(schema map[string]interface{}, dropped map[string]bool) {
+	if props, ok := schema["properties"].(map[string]interface{}); ok {
+		for name := range dropped {
+			delete(props, name)
+		}
+	}
+	if required, ok := toStringSlice(schema["required"]); ok {
+		kept := make([]string, 0, len(required))
+		for _, name := range required {
+			if !dropped[name] {
+				kept = append(kept, name)
+			}
+		}
+		switch {
+		case len(kept) == 0:
+			delete(schema, "required")
+		case len(kept) == len(required):
+		case isStringSlice(schema["required"]):
+			schema["required"] = kept
+		default:
+			generic := make([]interface{}, len(kept))
+			for i, name := range kept {
+				generic[i] = name
+			}
+			schema["required"] = generic
+		}
+	}
+	if branches, ok := schema["allOf"].([]interface{}); ok {
+		for _, branch := range branches {
+			if sub := schemaMapOf(branch); sub != nil {
+				removeAccessProperties(sub, dropped)
+			}
+		}
+	}
+}
+
+func hasAccessFlag(schema map[string]interface{}, keyword string, defs map[string]interface{}) bool {
+	for depth := 0; schema != nil && depth < 16; depth++ {
+		if flagged, ok := schema[keyword].(bool); ok {
+			return flagged
+		}
+		ref, ok := schema["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/$defs/") {
+			return false
+		}
+		schema = schemaMapOf(defs[strings.TrimPrefix(ref, "#/$defs/")])
+	}
+	return false
+}
+
+func isStringSlice(value interface{}) bool {
+	_, ok := value.([]string)
+	return ok
+}
+
+// definitionsOf exposes a route's $defs in the generic form used by the filters.
+func definitionsOf(defs ir.Schema) map[string]interface{} {
+	definitions := defs.Definitions()
+	result := make(map[string]interface{}, len(definitions))
+	for name, def := range definitions {
+		result[name] = map[string]interface{}(def)
+	}
+	return result
+}
+
+func schemaMapOf(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case ir.Schema:
+		return v
+	case map[string]interface{}:
+		return v
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package factory
+
+import (
+	"mime"
+	"sort"
+	"strings"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// requestContentTypes lists the media types of a request body in declaration order.
+func requestContentTypes(body *ir.RequestBodyInfo) []string {
+	if body == nil || len(body.ContentSchemas) == 0 {
+		return nil
+	}
+
+	var contentTypes []string
+	seen := make(map[string]bool, len(body.ContentSchemas))
+	for _, contentType := range body.ContentOrder {
+		if _, ok := body.ContentSchemas[contentType]; ok && !seen[contentType] {
+			seen[contentType] = true
+			contentTypes = append(contentTypes, contentType)
+		}
+	}
+
+	var rest []string
+	for contentType := range body.ContentSchemas {
+		if !seen[contentType] {
+			rest = append(rest, contentType)
+		}
+	}
+	sort.Strings(rest)
+	return append(contentTypes, rest...)
+}
+
+// contentTypeSuffix turns a media type into a tool name suffix:
+// application/json -> json, multipart/form-data -> multipart,
+// application/x-www-form-urlencoded -> form, application/vnd.api+json -> json.
+func contentTypeSuffix(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+
+	major, subtype, _ := strings.Cut(mediaType, "/")
+	switch {
+	case major == "multipart":
+		return "multipart"
+	case subtype == "x-www-form-urlencoded":
+		return "form"
+	}
+	if idx := strings.LastIndex(subtype, "+"); idx >= 0 {
+		subtype = subtype[idx+1:]
+	}
+	if suffix := slugify(subtype); suffix != "" {
+		return suffix
+	}
+	if suffix := slugify(major); suffix != "" {
+		return suffix
+	}
+	return "body"
+}
+
+// routeForContentType narrows the request body of a route copy to contentType.
+func routeForContentType(route ir.HTTPRoute, contentType string) ir.HTTPRoute {
+	if route.RequestBody == nil {
+		return route
+	}
+	body := *route.RequestBody
+	body.ContentSchemas = map[string]ir.Schema{contentType: body.ContentSchemas[contentType]}
+	body.ContentOrder = []string{contentType}
+	route.RequestBody = &body
+	return route
+}
@@ -0,0 +1,100 @@
+package factory
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/specx2/openapi-mcp/core/executor"
+	"github.com/specx2/openapi-mcp/core/ir"
+	"github.com/specx2/openapi-mcp/core/mapper"
+)
+
+func TestCreateComponentsEmitsContentTypeVariants(t *testing.T) {
+	route := ir.HTTPRoute{
+		Method:      "POST",
+		Path:        "/pets",
+		OperationID: "createPet",
+		RequestBody: &ir.RequestBodyInfo{
+			Required:     true,
+			ContentOrder: []string{"application/json", "multipart/form-data"},
+			ContentSchemas: map[string]ir.Schema{
+				"application/json": {
+					"type":       "object",
+					"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}},
+					"required":   []interface{}{"name"},
+				},
+				"multipart/form-data": {
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name":  map[string]interface{}{"type": "string"},
+						"photo": map[string]interface{}{"type": "string", "format": "binary"},
+					},
+					"required": []interface{}{"photo"},
+				},
+			},
+		},
+	}
+	mapped := []mapper.MappedRoute{{Route: route, MCPType: mapper.MCPTypeTool}}
+
+	single, err := NewComponentFactory(nil, "https://api.example.com").CreateComponents(mapped)
+	if err != nil || len(single) != 1 {
+		t.Fatalf("expected one tool without variants, got %d (%v)", len(single), err)
+	}
+
+	components, err := NewComponentFactory(nil, "https://api.example.com").
+		WithContentTypeVariants(true).
+		CreateComponents(mapped)
+	if err != nil {
+		t.Fatalf("CreateComponents returned error: %v", err)
+	}
+	if len(components) != 2 {
+		t.Fatalf("expected two variant tools, got %d", len(components))
+	}
+
+	jsonTool := components[0].(*executor.OpenAPITool)
+	multipartTool := components[1].(*executor.OpenAPITool)
+	if jsonTool.Tool().Name != "createPet_json" || multipartTool.Tool().Name != "createPet_multipart" {
+		t.Fatalf("unexpected names %q, %q", jsonTool.Tool().Name, multipartTool.Tool().Name)
+	}
+
+	var multipartSchema map[string]interface{}
+	if err := json.Unmarshal(multipartTool.Tool().RawInputSchema, &multipartSchema); err != nil {
+		t.Fatalf("invalid input schema: %v", err)
+	}
+	if _, ok := multipartSchema["properties"].(map[string]interface{})["photo"]; !ok {
+		t.Fatalf("expected multipart schema to expose photo, got %v", multipartSchema)
+	}
+	if required, _ := multipartSchema["required"].([]interface{}); len(required) != 1 || required[0] != "photo" {
+		t.Fatalf("expected multipart required fields, got %v", multipartSchema["required"])
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"name": "Rex", "photo": "cGhvdG8=", "_dryRun": true}
+	result, err := multipartTool.Run(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("dry run failed: %v %#v", err, result)
+	}
+	preview := result.StructuredContent.(map[string]interface{})["request"].(map[string]interface{})
+	headers, _ := preview["headers"].(map[string]interface{})
+	if values, _ := headers["Content-Type"].([]interface{}); len(values) != 1 || !strings.HasPrefix(values[0].(string), "multipart/form-data") {
+		t.Fatalf("expected multipart request, got %#v", preview)
+	}
+}
+
+func TestContentTypeSuffix(t *testing.T) {
+	for contentType, expected := range map[string]string{
+		"application/json":                  "json",
+		"application/vnd.api+json":          "json",
+		"multipart/form-data":               "multipart",
+		"application/x-www-form-urlencoded": "form",
+		"text/plain; charset=utf-8":         "plain",
+		"application/octet-stream":          "octet_stream",
+	} {
+		if got := contentTypeSuffix(contentType); got != expected {
+			t.Fatalf("contentTypeSuffix(%q) = %q, want %q", contentType, got, expected)
+		}
+	}
+}
@@ -6,57 +6,180 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/specx2/openapi-mcp/core/ir"
 )
 
+// Trimming rounds of an over-budget description, in the order they apply.
+// Each round replaces its sections with their compact form, or drops them.
+const (
+	trimNever = iota
+	trimExtensions
+	trimCallbacks
+	trimTags
+	trimRequestBody
+	trimResponses
+	trimParameters
+	trimHTTPLine
+	trimDescription
+	trimRounds
+)
+
+// descriptionSection is one block of a generated description. compact stands
+// in for text once the trimming round named by trim is reached; an empty
+// compact drops the section.
+type descriptionSection struct {
+	text    string
+	compact string
+	trim    int
+}
+
 func (cf *ComponentFactory) formatDescription(route ir.HTTPRoute) string {
-	var parts []string
+	var sections []descriptionSection
+
+	if route.Deprecated {
+		sections = append(sections, descriptionSection{text: "**Deprecated:** this operation is being phased out; prefer an alternative if one exists."})
+	}
 
 	if route.Description != "" {
-		parts = append(parts, route.Description)
+		sections = append(sections, descriptionSection{text: route.Description, compact: briefDescription(route), trim: trimDescription})
 	} else if route.Summary != "" {
-		parts = append(parts, route.Summary)
+		sections = append(sections, descriptionSection{text: route.Summary})
 	} else {
-		parts = append(parts, fmt.Sprintf("%s %s", route.Method, route.Path))
+		sections = append(sections, descriptionSection{text: fmt.Sprintf("%s %s", route.Method, route.Path)})
+	}
+
+	// similarly described operations stay distinguishable by method and path
+	if !cf.omitHTTPLine && (route.Description != "" || route.Summary != "") {
+		sections = append(sections, descriptionSection{text: fmt.Sprintf("HTTP: %s %s", strings.ToUpper(route.Method), route.Path), trim: trimHTTPLine})
 	}
 
 	if paramSection := formatParameterSection(route.Parameters); paramSection != "" {
-		parts = append(parts, paramSection)
+		sections = append(sections, descriptionSection{text: paramSection, compact: formatRequiredParameters(route.Parameters), trim: trimParameters})
 	}
 
 	if route.RequestBody != nil && route.RequestBody.Description != "" {
-		parts = append(parts, fmt.Sprintf("**Request Body:** %s", route.RequestBody.Description))
+		sections = append(sections, descriptionSection{text: fmt.Sprintf("**Request Body:** %s", route.RequestBody.Description), trim: trimRequestBody})
 	}
 
 	if route.RequestBody != nil {
 		if summary := summarizeRequestBodyText(*route.RequestBody); summary != "" {
-			parts = append(parts, "**Request Body Schema:** "+summary)
+			sections = append(sections, descriptionSection{text: "**Request Body Schema:** " + summary, compact: formatRequiredBodyProperties(*route.RequestBody), trim: trimRequestBody})
 		}
 		if ext := summarizeExtensions(route.RequestBody.Extensions); ext != "" {
-			parts = append(parts, "**Request Body Extensions:** "+ext)
+			sections = append(sections, descriptionSection{text: "**Request Body Extensions:** " + ext, trim: trimExtensions})
 		}
 	}
 
 	if extSummary := summarizeExtensions(route.Extensions); extSummary != "" {
-		parts = append(parts, "**Extensions:** "+extSummary)
+		sections = append(sections, descriptionSection{text: "**Extensions:** " + extSummary, trim: trimExtensions})
 	}
 
 	if len(route.Responses) > 0 {
 		if responseSection := cf.formatResponseSection(route); responseSection != "" {
-			parts = append(parts, responseSection)
+			sections = append(sections, descriptionSection{text: responseSection, compact: formatPrimaryResponse(route), trim: trimResponses})
 		}
 	}
 
 	if len(route.Tags) > 0 {
-		parts = append(parts, fmt.Sprintf("**Tags:** %s", strings.Join(route.Tags, ", ")))
+		sections = append(sections, descriptionSection{text: fmt.Sprintf("**Tags:** %s", strings.Join(route.Tags, ", ")), trim: trimTags})
 	}
 
 	if callbackSection := formatCallbacks(route.Callbacks); callbackSection != "" {
-		parts = append(parts, callbackSection)
+		sections = append(sections, descriptionSection{text: callbackSection, trim: trimCallbacks})
+	}
+
+	return fitDescription(sections, cf.maxDescriptionLength)
+}
+
+// fitDescription joins sections, trimming them round by round until the
+// description is at most limit characters. Whole sections are replaced, so the
+// markdown stays intact; a description whose essentials alone are over the
+// limit is returned as short as it gets. A limit of zero or less keeps all.
+func fitDescription(sections []descriptionSection, limit int) string {
+	render := func() string {
+		parts := make([]string, 0, len(sections))
+		for _, section := range sections {
+			if section.text != "" {
+				parts = append(parts, section.text)
+			}
+		}
+		return strings.Join(parts, "\n\n")
+	}
+
+	description := render()
+	for round := trimNever + 1; limit > 0 && round < trimRounds && utf8.RuneCountInString(description) > limit; round++ {
+		for i := range sections {
+			if sections[i].trim == round {
+				sections[i].text = sections[i].compact
+			}
+		}
+		description = render()
+	}
+	return description
+}
+
+// briefDescription is the summary, or else the first paragraph of the description.
+func briefDescription(route ir.HTTPRoute) string {
+	if route.Summary != "" {
+		return route.Summary
 	}
+	first, _, _ := strings.Cut(strings.TrimSpace(route.Description), "\n\n")
+	return first
+}
+
+// formatRequiredParameters lists the required parameters by name.
+func formatRequiredParameters(params []ir.ParameterInfo) string {
+	var names []string
+	for _, param := range params {
+		if param.Required && param.Name != "" {
+			names = append(names, param.Name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return "**Required Parameters:** " + strings.Join(names, ", ")
+}
+
+// formatRequiredBodyProperties lists the required properties of a required body.
+func formatRequiredBodyProperties(body ir.RequestBodyInfo) string {
+	if !body.Required {
+		return ""
+	}
+	_, schema := selectPreferredMedia(body.ContentSchemas)
+	if required := schema.Required(); len(required) > 0 {
+		return "**Required Body Properties:** " + strings.Join(required, ", ")
+	}
+	return ""
+}
 
-	return strings.Join(parts, "\n\n")
+// formatPrimaryResponse describes the first success response on one line.
+func formatPrimaryResponse(route ir.HTTPRoute) string {
+	best := ""
+	for status := range route.Responses {
+		if best == "" || lessResponseStatus(status, best) {
+			best = status
+		}
+	}
+	response := route.Responses[best]
+	line := "**Returns:** " + best
+	if response.Description != "" {
+		line += ": " + response.Description
+	} else if summary := summarizeResponseContent(response); summary != "" {
+		line += ": " + summary
+	}
+	return line
+}
+
+func lessResponseStatus(a, b string) bool {
+	pa, oa := responsePriority(a)
+	pb, ob := responsePriority(b)
+	if pa != pb {
+		return pa < pb
+	}
+	return oa < ob
 }
 
 func formatParameterSection(params []ir.ParameterInfo) string {
@@ -122,14 +245,14 @@ func formatParameterLine(param ir.ParameterInfo) string {
 
 	if param.Schema != nil {
 		if def, ok := param.Schema["default"]; ok {
-			if formatted := formatExample(def); formatted != "" {
+			if formatted := FormatExample(def); formatted != "" {
 				details = append(details, "default: "+formatted)
 			}
 		}
 	}
 
 	if param.Example != nil {
-		if formatted := formatExample(param.Example); formatted != "" {
+		if formatted := FormatExample(param.Example); formatted != "" {
 			details = append(details, "example: "+formatted)
 		}
 	}
@@ -141,7 +264,7 @@ func formatParameterLine(param ir.ParameterInfo) string {
 		}
 		sort.Strings(keys)
 		if sample := param.Examples[keys[0]]; sample != nil {
-			if formatted := formatExample(sample); formatted != "" {
+			if formatted := FormatExample(sample); formatted != "" {
 				details = append(details, "example: "+formatted)
 			}
 		}
@@ -188,12 +311,7 @@ func (cf *ComponentFactory) formatResponseSection(route ir.HTTPRoute) string {
 		statuses = append(statuses, status)
 	}
 	sort.Slice(statuses, func(i, j int) bool {
-		pi, oi := responsePriority(statuses[i])
-		pj, oj := responsePriority(statuses[j])
-		if pi != pj {
-			return pi < pj
-		}
-		return oi < oj
+		return lessResponseStatus(statuses[i], statuses[j])
 	})
 
 	var responseParts []string
@@ -314,7 +432,7 @@ func pickExampleForMedia(mediaType string, examples map[string]interface{}, exam
 	}
 	if examples != nil {
 		if example, ok := examples[mediaType]; ok {
-			if formatted := formatExample(example); formatted != "" {
+			if formatted := FormatExample(example); formatted != "" {
 				return formatted
 			}
 		}
@@ -322,7 +440,7 @@ func pickExampleForMedia(mediaType string, examples map[string]interface{}, exam
 	if exampleSets != nil {
 		if set, ok := exampleSets[mediaType]; ok {
 			for _, example := range set {
-				if formatted := formatExample(example); formatted != "" {
+				if formatted := FormatExample(example); formatted != "" {
 					return formatted
 				}
 			}
@@ -497,7 +615,9 @@ func summarizeSchema(schema ir.Schema) (string, string) {
 	return summary, "object"
 }
 
-func formatExample(example interface{}) string {
+// FormatExample renders an example or default for a description as JSON,
+// shortened to 160 bytes. It returns "" for nil and unencodable values.
+func FormatExample(example interface{}) string {
 	if example == nil {
 		return ""
 	}
@@ -160,3 +160,77 @@ func TestFormatDescriptionIncludesVariantsAndExamples(t *testing.T) {
 		t.Fatalf("expected response extensions to be summarized, got %q", description)
 	}
 }
+
+func TestFormatDescriptionIncludesHTTPLine(t *testing.T) {
+	route := ir.HTTPRoute{Method: "get", Path: "/users/{id}", Summary: "Get a user"}
+
+	description := NewComponentFactory(nil, "").formatDescription(route)
+	if !strings.HasPrefix(description, "Get a user\n\nHTTP: GET /users/{id}") {
+		t.Fatalf("expected the HTTP line after the summary, got %q", description)
+	}
+
+	description = NewComponentFactory(nil, "").WithIncludeHTTPLine(false).formatDescription(route)
+	if strings.Contains(description, "HTTP:") {
+		t.Fatalf("expected no HTTP line when disabled, got %q", description)
+	}
+
+	route.Summary = ""
+	description = NewComponentFactory(nil, "").formatDescription(route)
+	if strings.Count(description, "/users/{id}") != 1 {
+		t.Fatalf("expected the method and path once when there is no summary, got %q", description)
+	}
+}
+
+func TestFormatDescriptionTrimsSectionsToMaxLength(t *testing.T) {
+	route := ir.HTTPRoute{
+		Method:      "post",
+		Path:        "/orders/{orderId}/items",
+		Summary:     "Add an order item",
+		Description: "Adds an item to an open order.\n\nThe item is priced at the current catalogue rate and reserved until the order is paid or cancelled.",
+		Tags:        []string{"orders", "items"},
+		Parameters: []ir.ParameterInfo{
+			{Name: "orderId", In: ir.ParameterInPath, Required: true, Description: "Order identifier", Schema: ir.Schema{"type": "string"}},
+			{Name: "dryRun", In: ir.ParameterInQuery, Description: "Validate without saving", Schema: ir.Schema{"type": "boolean"}},
+		},
+		RequestBody: &ir.RequestBodyInfo{
+			Required: true,
+			ContentSchemas: map[string]ir.Schema{
+				"application/json": {
+					"type":     "object",
+					"required": []interface{}{"sku"},
+					"properties": map[string]interface{}{
+						"sku":      map[string]interface{}{"type": "string"},
+						"quantity": map[string]interface{}{"type": "integer"},
+					},
+				},
+			},
+		},
+		Responses: map[string]ir.ResponseInfo{
+			"201": {Description: "Item added"},
+			"404": {Description: "Order not found"},
+		},
+		Callbacks: []ir.CallbackInfo{{Name: "itemReserved", Expression: "{$request.body#/callbackUrl}"}},
+		Extensions: map[string]interface{}{
+			"x-rate-limit": 10,
+		},
+	}
+
+	full := NewComponentFactory(nil, "").formatDescription(route)
+	if unlimited := NewComponentFactory(nil, "").WithMaxDescriptionLength(0).formatDescription(route); unlimited != full {
+		t.Fatalf("expected no limit to keep the full description, got %q", unlimited)
+	}
+
+	withoutExtras := NewComponentFactory(nil, "").WithMaxDescriptionLength(len(full) - 1).formatDescription(route)
+	if strings.Contains(withoutExtras, "**Extensions:**") {
+		t.Fatalf("expected extensions to be dropped first, got %q", withoutExtras)
+	}
+	if !strings.Contains(withoutExtras, "**Callbacks:**") || !strings.Contains(withoutExtras, "**Path Parameters:**") {
+		t.Fatalf("expected only the extensions to be dropped, got %q", withoutExtras)
+	}
+
+	essentials := NewComponentFactory(nil, "").WithMaxDescriptionLength(120).formatDescription(route)
+	want := "Add an order item\n\n**Required Parameters:** orderId\n\n**Required Body Properties:** sku\n\n**Returns:** 201: Item added"
+	if essentials != want {
+		t.Fatalf("unexpected trimmed description:\n got %q\nwant %q", essentials, want)
+	}
+}
@@ -1,6 +1,9 @@
 package factory
 
 import (
+	"strings"
+	"time"
+
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/specx2/openapi-mcp/core/executor"
 	"github.com/specx2/openapi-mcp/core/ir"
@@ -9,12 +12,57 @@ import (
 
 type ComponentFunc func(route ir.HTTPRoute, component interface{})
 
+// SchemaOverride patches a generated tool schema for one operation. Returning nil
+// keeps the schema passed in (which may also be modified in place).
+type SchemaOverride func(route ir.HTTPRoute, schema ir.Schema) ir.Schema
+
 type ComponentFactory struct {
 	client      executor.HTTPClient
 	baseURL     string
 	nameCounter map[string]map[string]int
 	customNames map[string]string
 	componentFn ComponentFunc
+
+	serverVariables        map[string]string
+	serverVariableResolver executor.ServerVariableResolver
+	ignoreOperationServers bool
+	pagination             *executor.PaginationConfig
+	compressMinBytes       int
+	fileUploadRoot         string
+	timeoutFn              func(ir.HTTPRoute) time.Duration
+	headerPropagation      *executor.HeaderPropagation
+	lenientFormats         bool
+	transformer            executor.ResponseTransformer
+	additionalProperties   executor.AdditionalPropertiesMode
+	defaultExamples        map[string]string
+	outputStatuses         map[string]string
+	parameterRenames       map[string]map[string]string
+	inputOverride          SchemaOverride
+	outputOverride         SchemaOverride
+	collisionStrategy      NameCollisionStrategy
+	specAlias              string
+	collisions             []NameCollision
+	logger                 executor.Logger
+	maxRequestBytes        int64
+	maxResponseBytes       int64
+	contentTypeVariants    bool
+	baseURLResolver        executor.BaseURLResolver
+	responseCache          *executor.ResponseCache
+	auditMetadata          bool
+	pathPrefix             string
+	defaultAccept          string
+	csvOptions             *executor.CSVOptions
+	omitHTTPLine           bool
+	joinCookieArrays       bool
+	maxURLLength           int
+	normalizeDates         bool
+	tracer                 executor.Tracer
+	unwrapSingleProperty   bool
+	streamingProgress      bool
+	maxDescriptionLength   int
+	queryArrayFormat       executor.QueryArrayFormat
+	resultEnvelope         bool
+	inputSchemaDialect     SchemaDialect
 }
 
 func NewComponentFactory(client executor.HTTPClient, baseURL string) *ComponentFactory {
@@ -36,12 +84,257 @@ func (cf *ComponentFactory) WithComponentFunc(fn ComponentFunc) *ComponentFactor
 	return cf
 }
 
+func (cf *ComponentFactory) WithServerVariables(vars map[string]string) *ComponentFactory {
+	cf.serverVariables = vars
+	return cf
+}
+
+func (cf *ComponentFactory) WithServerVariableResolver(fn executor.ServerVariableResolver) *ComponentFactory {
+	cf.serverVariableResolver = fn
+	return cf
+}
+
+func (cf *ComponentFactory) WithOperationServers(enabled bool) *ComponentFactory {
+	cf.ignoreOperationServers = !enabled
+	return cf
+}
+
+func (cf *ComponentFactory) WithPagination(cfg *executor.PaginationConfig) *ComponentFactory {
+	cf.pagination = cfg
+	return cf
+}
+
+// WithFileUploadRoot lets tool arguments upload files under root by path.
+func (cf *ComponentFactory) WithFileUploadRoot(root string) *ComponentFactory {
+	cf.fileUploadRoot = root
+	return cf
+}
+
+func (cf *ComponentFactory) WithRequestCompression(minBytes int) *ComponentFactory {
+	cf.compressMinBytes = minBytes
+	return cf
+}
+
+func (cf *ComponentFactory) WithOperationTimeout(fn func(ir.HTTPRoute) time.Duration) *ComponentFactory {
+	cf.timeoutFn = fn
+	return cf
+}
+
+func (cf *ComponentFactory) WithHeaderPropagation(policy *executor.HeaderPropagation) *ComponentFactory {
+	cf.headerPropagation = policy
+	return cf
+}
+
+func (cf *ComponentFactory) WithStrictFormats(enabled bool) *ComponentFactory {
+	cf.lenientFormats = !enabled
+	return cf
+}
+
+func (cf *ComponentFactory) WithResponseTransformer(fn executor.ResponseTransformer) *ComponentFactory {
+	cf.transformer = fn
+	return cf
+}
+
+func (cf *ComponentFactory) WithAdditionalPropertiesMode(mode executor.AdditionalPropertiesMode) *ComponentFactory {
+	cf.additionalProperties = mode
+	return cf
+}
+
+// WithDefaultExamples maps operationIds to the named request body example whose
+// values become argument defaults; an empty name selects the single media example.
+func (cf *ComponentFactory) WithDefaultExamples(examples map[string]string) *ComponentFactory {
+	cf.defaultExamples = examples
+	return cf
+}
+
+// WithOutputSchemaStatuses maps operationIds to the success status whose
+// response schema becomes the tool's output schema.
+func (cf *ComponentFactory) WithOutputSchemaStatuses(statuses map[string]string) *ComponentFactory {
+	cf.outputStatuses = statuses
+	return cf
+}
+
+// WithSchemaOverride patches each tool's input schema before its validator is compiled.
+func (cf *ComponentFactory) WithSchemaOverride(fn SchemaOverride) *ComponentFactory {
+	cf.inputOverride = fn
+	return cf
+}
+
+// WithOutputSchemaOverride patches each tool's output schema before it is attached.
+func (cf *ComponentFactory) WithOutputSchemaOverride(fn SchemaOverride) *ComponentFactory {
+	cf.outputOverride = fn
+	return cf
+}
+
+// WithNameCollisionStrategy selects how duplicate component names are resolved.
+func (cf *ComponentFactory) WithNameCollisionStrategy(strategy NameCollisionStrategy) *ComponentFactory {
+	cf.collisionStrategy = strategy
+	return cf
+}
+
+// SetSpecAlias sets the alias used to prefix colliding names for components created next.
+func (cf *ComponentFactory) SetSpecAlias(alias string) {
+	cf.specAlias = alias
+}
+
+// WithBodyLimits sets the request/response body caps of the tools created afterwards.
+func (cf *ComponentFactory) WithBodyLimits(maxRequestBytes, maxResponseBytes int64) *ComponentFactory {
+	cf.maxRequestBytes = maxRequestBytes
+	cf.maxResponseBytes = maxResponseBytes
+	return cf
+}
+
+// WithLogger passes logger to the tools created afterwards.
+func (cf *ComponentFactory) WithLogger(logger executor.Logger) *ComponentFactory {
+	cf.logger = logger
+	return cf
+}
+
+// WithContentTypeVariants emits one tool per request body media type for
+// operations accepting several, each with that media type's schema.
+func (cf *ComponentFactory) WithContentTypeVariants(enabled bool) *ComponentFactory {
+	cf.contentTypeVariants = enabled
+	return cf
+}
+
+// WithBaseURLResolver lets the tools created afterwards compute their base URL per call.
+func (cf *ComponentFactory) WithBaseURLResolver(fn executor.BaseURLResolver) *ComponentFactory {
+	cf.baseURLResolver = fn
+	return cf
+}
+
+// WithResponseCache shares cache between the read-only tools created afterwards.
+func (cf *ComponentFactory) WithResponseCache(cache *executor.ResponseCache) *ComponentFactory {
+	cf.responseCache = cache
+	return cf
+}
+
+// WithAuditMetadata makes the tools created afterwards report an `_audit` record
+// and declares it in their output schemas.
+func (cf *ComponentFactory) WithAuditMetadata(enabled bool) *ComponentFactory {
+	cf.auditMetadata = enabled
+	return cf
+}
+
+// WithPathPrefix mounts the operations created afterwards under prefix on the
+// upstream, e.g. a gateway prefix such as "/api/v2" that the spec's paths omit.
+func (cf *ComponentFactory) WithPathPrefix(prefix string) *ComponentFactory {
+	cf.pathPrefix = prefix
+	return cf
+}
+
+// WithDefaultAccept sets the Accept header of tools created afterwards for calls
+// without an `_accept` argument.
+func (cf *ComponentFactory) WithDefaultAccept(contentType string) *ComponentFactory {
+	cf.defaultAccept = contentType
+	return cf
+}
+
+// WithIncludeHTTPLine controls the "HTTP: GET /users/{id}" line that follows the
+// lead paragraph of every tool description. It is on by default.
+func (cf *ComponentFactory) WithIncludeHTTPLine(enabled bool) *ComponentFactory {
+	cf.omitHTTPLine = !enabled
+	return cf
+}
+
+// WithJoinedCookieArrays makes the tools created afterwards send array cookie
+// parameters as a single comma-separated cookie.
+func (cf *ComponentFactory) WithJoinedCookieArrays(enabled bool) *ComponentFactory {
+	cf.joinCookieArrays = enabled
+	return cf
+}
+
+// WithDefaultQueryArrayFormat sets how the tools created afterwards send array
+// query parameters that declare no style.
+func (cf *ComponentFactory) WithDefaultQueryArrayFormat(format executor.QueryArrayFormat) *ComponentFactory {
+	cf.queryArrayFormat = format
+	return cf
+}
+
+// WithMaxURLLength caps the request URL length of the tools created afterwards.
+func (cf *ComponentFactory) WithMaxURLLength(limit int) *ComponentFactory {
+	cf.maxURLLength = limit
+	return cf
+}
+
+// WithNormalizeDates makes the tools created afterwards rewrite date and
+// date-time arguments to their canonical form.
+func (cf *ComponentFactory) WithNormalizeDates(enabled bool) *ComponentFactory {
+	cf.normalizeDates = enabled
+	return cf
+}
+
+// WithTracer makes the tools created afterwards trace their upstream calls.
+func (cf *ComponentFactory) WithTracer(tracer executor.Tracer) *ComponentFactory {
+	cf.tracer = tracer
+	return cf
+}
+
+// WithUnwrapSingleProperty makes the tools created afterwards send the only
+// property of an object request body as the bare body.
+func (cf *ComponentFactory) WithUnwrapSingleProperty(enabled bool) *ComponentFactory {
+	cf.unwrapSingleProperty = enabled
+	return cf
+}
+
+// WithResultEnvelope makes the tools created afterwards return their results,
+// and describe them, as {data, status, headers}.
+func (cf *ComponentFactory) WithResultEnvelope(enabled bool) *ComponentFactory {
+	cf.resultEnvelope = enabled
+	return cf
+}
+
+// WithStreamingProgress makes the tools created afterwards report streamed
+// response records as MCP progress notifications.
+func (cf *ComponentFactory) WithStreamingProgress(enabled bool) *ComponentFactory {
+	cf.streamingProgress = enabled
+	return cf
+}
+
+// WithMaxDescriptionLength caps generated descriptions at limit characters by
+// dropping or condensing their sections; zero keeps them whole.
+func (cf *ComponentFactory) WithMaxDescriptionLength(limit int) *ComponentFactory {
+	cf.maxDescriptionLength = limit
+	return cf
+}
+
+// WithCSVOptions sets how the tools created afterwards parse text/csv responses.
+func (cf *ComponentFactory) WithCSVOptions(delimiter rune, hasHeader bool) *ComponentFactory {
+	cf.csvOptions = &executor.CSVOptions{Delimiter: delimiter, HasHeader: hasHeader}
+	return cf
+}
+
+// resourceBaseURL folds the path prefix into the base URL for resources, which
+// do not use operation servers.
+func (cf *ComponentFactory) resourceBaseURL() string {
+	prefix := strings.Trim(cf.pathPrefix, "/")
+	if prefix == "" {
+		return cf.baseURL
+	}
+	return strings.TrimSuffix(cf.baseURL, "/") + "/" + prefix
+}
+
+// NameCollisions lists every component renamed so far, in creation order.
+func (cf *ComponentFactory) NameCollisions() []NameCollision {
+	return append([]NameCollision(nil), cf.collisions...)
+}
+
 func (cf *ComponentFactory) CreateComponents(mappedRoutes []mapper.MappedRoute) ([]interface{}, error) {
 	var components []interface{}
 
 	for _, mapped := range mappedRoutes {
 		switch mapped.MCPType {
 		case mapper.MCPTypeTool:
+			if contentTypes := requestContentTypes(mapped.Route.RequestBody); cf.contentTypeVariants && len(contentTypes) > 1 {
+				for _, contentType := range contentTypes {
+					tool, err := cf.CreateToolForContentType(mapped.Route, mapped.Tags, mapped.Annotations, contentType)
+					if err != nil {
+						return nil, err
+					}
+					components = append(components, tool)
+				}
+				continue
+			}
 			tool, err := cf.CreateTool(mapped.Route, mapped.Tags, mapped.Annotations)
 			if err != nil {
 				return nil, err
@@ -68,16 +361,59 @@ func (cf *ComponentFactory) CreateComponents(mappedRoutes []mapper.MappedRoute)
 }
 
 func (cf *ComponentFactory) CreateTool(route ir.HTTPRoute, tags []string, annotations *mcp.ToolAnnotation) (*executor.OpenAPITool, error) {
-	inputSchema, paramMap, err := cf.combineSchemas(route)
+	return cf.createTool(route, tags, annotations, "")
+}
+
+// CreateToolForContentType creates a tool whose input schema and request body are
+// fixed to contentType. Its name carries a media type suffix, e.g. createPet_multipart.
+func (cf *ComponentFactory) CreateToolForContentType(route ir.HTTPRoute, tags []string, annotations *mcp.ToolAnnotation, contentType string) (*executor.OpenAPITool, error) {
+	return cf.createTool(route, tags, annotations, contentType)
+}
+
+func (cf *ComponentFactory) createTool(route ir.HTTPRoute, tags []string, annotations *mcp.ToolAnnotation, contentType string) (*executor.OpenAPITool, error) {
+	var (
+		inputSchema ir.Schema
+		paramMap    map[string]ir.ParamMapping
+		err         error
+	)
+	if contentType != "" {
+		inputSchema, paramMap, err = cf.combineSchemasFor(route, contentType)
+	} else {
+		inputSchema, paramMap, err = cf.combineSchemas(route)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	outputSchema, wrapResult := cf.extractOutputSchema(route)
+	if cf.inputOverride != nil {
+		if overridden := cf.inputOverride(route, inputSchema); overridden != nil {
+			inputSchema = overridden
+		}
+	}
 
-	name := cf.generateName(route, "tool")
+	if cf.inputSchemaDialect == Draft07 {
+		inputSchema = toDraft07(inputSchema)
+	}
 
-	description := cf.formatDescription(route)
+	outputSchema, wrapResult := cf.extractOutputSchema(route)
+	outputStatus := cf.outputStatus(route)
+	if cf.auditMetadata {
+		outputSchema = withAuditProperty(outputSchema)
+	}
+	if cf.outputOverride != nil && outputSchema != nil {
+		if overridden := cf.outputOverride(route, outputSchema); overridden != nil {
+			outputSchema = overridden
+		}
+	}
+
+	var name, description string
+	if contentType != "" {
+		name = cf.generateVariantName(route, "tool", contentTypeSuffix(contentType))
+		description = cf.formatDescription(routeForContentType(route, contentType))
+	} else {
+		name = cf.generateName(route, "tool")
+		description = cf.formatDescription(route)
+	}
 
 	// Persist parameter map on the route for downstream consumers (parity with fastmcp)
 	route.ParameterMap = paramMap
@@ -94,7 +430,54 @@ func (cf *ComponentFactory) CreateTool(route ir.HTTPRoute, tags []string, annota
 		paramMap,
 		tags,
 		annotations,
-	)
+	).WithServerVariables(cf.serverVariables).
+		WithServerVariableResolver(cf.serverVariableResolver).
+		WithOperationServers(!cf.ignoreOperationServers).
+		WithPagination(cf.pagination).
+		WithRequestCompression(cf.compressMinBytes).
+		WithFileUploadRoot(cf.fileUploadRoot).
+		WithTimeoutFunc(cf.timeoutFn).
+		WithHeaderPropagation(cf.headerPropagation).
+		WithResponseTransformer(cf.transformer).
+		WithLogger(cf.logger).
+		WithBodyLimits(cf.maxRequestBytes, cf.maxResponseBytes).
+		WithContentType(contentType).
+		WithBaseURLResolver(cf.baseURLResolver).
+		WithResponseCache(cf.responseCache).
+		WithAuditMetadata(cf.auditMetadata).
+		WithPathPrefix(cf.pathPrefix).
+		WithDefaultAccept(cf.defaultAccept).
+		WithJoinedCookieArrays(cf.joinCookieArrays).
+		WithDefaultQueryArrayFormat(cf.queryArrayFormat).
+		WithMaxURLLength(cf.maxURLLength)
+
+	if cf.lenientFormats {
+		tool = tool.WithStrictFormats(false)
+	}
+	if outputSchema != nil {
+		tool = tool.WithOutputSchemaStatus(outputStatus)
+	}
+	if cf.unwrapSingleProperty {
+		tool = tool.WithUnwrapSingleProperty(true)
+	}
+	if cf.tracer != nil {
+		tool = tool.WithTracer(cf.tracer)
+	}
+	if cf.normalizeDates {
+		tool = tool.WithNormalizeDates(true)
+	}
+	if cf.streamingProgress {
+		tool = tool.WithStreamingProgress(true)
+	}
+	if cf.resultEnvelope {
+		tool = tool.WithResultEnvelope(true)
+	}
+	if cf.additionalProperties != executor.AdditionalPropertiesStrict {
+		tool = tool.WithAdditionalPropertiesMode(cf.additionalProperties)
+	}
+	if cf.csvOptions != nil {
+		tool = tool.WithCSVOptions(cf.csvOptions.Delimiter, cf.csvOptions.HasHeader)
+	}
 
 	if cf.componentFn != nil {
 		cf.componentFn(route, tool)
@@ -112,8 +495,8 @@ func (cf *ComponentFactory) CreateResource(route ir.HTTPRoute, tags []string) (*
 		description,
 		route,
 		cf.client,
-		cf.baseURL,
-	)
+		cf.resourceBaseURL(),
+	).WithHeaderPropagation(cf.headerPropagation)
 
 	if cf.componentFn != nil {
 		cf.componentFn(route, resource)
@@ -131,8 +514,8 @@ func (cf *ComponentFactory) CreateResourceTemplate(route ir.HTTPRoute, tags []st
 		description,
 		route,
 		cf.client,
-		cf.baseURL,
-	)
+		cf.resourceBaseURL(),
+	).WithHeaderPropagation(cf.headerPropagation)
 
 	if cf.componentFn != nil {
 		cf.componentFn(route, template)
@@ -10,16 +10,48 @@ import (
 
 const maxComponentNameLength = 56
 
+// NameCollisionStrategy decides how a component name that was already emitted
+// (by this or an earlier spec) is made unique.
+type NameCollisionStrategy int
+
+const (
+	// NameCollisionSuffix appends a counter: listUsers, listUsers_2, ...
+	NameCollisionSuffix NameCollisionStrategy = iota
+	// NameCollisionPrefix prefixes the spec alias (billing_listUsers), falling
+	// back to a counter when the prefixed name is taken as well.
+	NameCollisionPrefix
+)
+
+// NameCollision records a component that was renamed to avoid a duplicate name.
+type NameCollision struct {
+	ComponentType string
+	OperationID   string
+	SpecAlias     string
+	Name          string
+	Renamed       string
+}
+
 func (cf *ComponentFactory) generateName(route ir.HTTPRoute, componentType string) string {
-	baseName := cf.resolveBaseName(route)
+	return cf.reserveName(route, componentType, cf.resolveBaseName(route))
+}
 
-	slug := slugify(baseName)
-	if slug == "" {
-		slug = slugify(fallbackNameFromRoute(route))
+// generateVariantName names one of several components created for a route,
+// appending suffix to the usual base name.
+func (cf *ComponentFactory) generateVariantName(route ir.HTTPRoute, componentType, suffix string) string {
+	baseName := slugify(cf.resolveBaseName(route))
+	if baseName == "" {
+		baseName = OperationSlug(route.Method, route.Path)
 	}
+	if limit := maxComponentNameLength - len(suffix) - 1; len(baseName) > limit && limit > 0 {
+		baseName = baseName[:limit]
+	}
+	return cf.reserveName(route, componentType, baseName+"_"+suffix)
+}
 
+func (cf *ComponentFactory) reserveName(route ir.HTTPRoute, componentType, baseName string) string {
+	slug := slugify(baseName)
 	if slug == "" {
-		slug = "operation"
+		slug = OperationSlug(route.Method, route.Path)
 	}
 
 	if len(slug) > maxComponentNameLength {
@@ -37,7 +69,39 @@ func (cf *ComponentFactory) generateName(route ir.HTTPRoute, componentType strin
 		return slug
 	}
 
-	return fmt.Sprintf("%s_%d", slug, count)
+	renamed := cf.resolveNameCollision(componentType, slug, count)
+	cf.collisions = append(cf.collisions, NameCollision{
+		ComponentType: componentType,
+		OperationID:   route.OperationID,
+		SpecAlias:     cf.specAlias,
+		Name:          slug,
+		Renamed:       renamed,
+	})
+	return renamed
+}
+
+// resolveNameCollision picks the first unused alternative for slug and reserves it.
+func (cf *ComponentFactory) resolveNameCollision(componentType, slug string, count int) string {
+	used := cf.nameCounter[componentType]
+
+	if cf.collisionStrategy == NameCollisionPrefix && cf.specAlias != "" {
+		prefixed := slugify(cf.specAlias + "_" + slug)
+		if len(prefixed) > maxComponentNameLength {
+			prefixed = prefixed[:maxComponentNameLength]
+		}
+		if used[prefixed] == 0 {
+			used[prefixed]++
+			return prefixed
+		}
+	}
+
+	for ; ; count++ {
+		candidate := fmt.Sprintf("%s_%d", slug, count)
+		if used[candidate] == 0 {
+			used[candidate]++
+			return candidate
+		}
+	}
 }
 
 func (cf *ComponentFactory) resolveBaseName(route ir.HTTPRoute) string {
@@ -61,7 +125,7 @@ func (cf *ComponentFactory) resolveBaseName(route ir.HTTPRoute) string {
 		return route.Description
 	}
 
-	return fallbackNameFromRoute(route)
+	return OperationSlug(route.Method, route.Path)
 }
 
 func (cf *ComponentFactory) lookupCustomName(route ir.HTTPRoute) (string, bool) {
@@ -114,25 +178,36 @@ func (cf *ComponentFactory) lookupCustomName(route ir.HTTPRoute) (string, bool)
 	return "", false
 }
 
-func fallbackNameFromRoute(route ir.HTTPRoute) string {
-	method := strings.ToLower(strings.TrimSpace(route.Method))
+// DeriveOperationID returns the route's operationId, or OperationSlug of its
+// method and path when the spec omits one.
+func DeriveOperationID(route ir.HTTPRoute) string {
+	if route.OperationID != "" {
+		return route.OperationID
+	}
+	return OperationSlug(route.Method, route.Path)
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// OperationSlug builds a stable identifier from an HTTP method and path, e.g.
+// GET /users/{id}/posts -> get_users_id_posts. Duplicates are left to the
+// name collision handling.
+func OperationSlug(method, path string) string {
+	method = strings.ToLower(strings.TrimSpace(method))
 	if method == "" {
 		method = "operation"
 	}
 
-	path := strings.TrimSpace(route.Path)
-	if path == "" {
-		path = "endpoint"
+	parts := []string{method}
+	for _, segment := range strings.Split(strings.TrimSpace(path), "/") {
+		if segment = strings.Trim(nonSlugChars.ReplaceAllString(segment, "_"), "_"); segment != "" {
+			parts = append(parts, segment)
+		}
 	}
-
-	path = strings.Trim(path, "/")
-	if path == "" {
-		path = "root"
+	if len(parts) == 1 {
+		parts = append(parts, "root")
 	}
-	path = strings.ReplaceAll(path, "{", "")
-	path = strings.ReplaceAll(path, "}", "")
-
-	return fmt.Sprintf("%s_%s", method, path)
+	return strings.Join(parts, "_")
 }
 
 func normalizeCustomNames(names map[string]string) map[string]string {
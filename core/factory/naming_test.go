@@ -87,6 +87,55 @@ func TestGenerateNameHandlesCollisions(t *testing.T) {
 	}
 }
 
+func TestGenerateNameDerivesOperationIDFromMethodAndPath(t *testing.T) {
+	cases := map[string]ir.HTTPRoute{
+		"get_users_id_posts":    {Method: "GET", Path: "/users/{id}/posts"},
+		"post_v1_user_groups":   {Method: "post", Path: "/v1/user-groups/"},
+		"delete_files_name_ext": {Method: "DELETE", Path: "/files/{name}.{ext}"},
+		"get_root":              {Method: "GET", Path: "/"},
+	}
+
+	for expected, route := range cases {
+		if got := NewComponentFactory(nil, "").generateName(route, "tool"); got != expected {
+			t.Fatalf("expected %q for %s %s, got %q", expected, route.Method, route.Path, got)
+		}
+		if got := DeriveOperationID(route); got != expected {
+			t.Fatalf("expected derived operationId %q, got %q", expected, got)
+		}
+	}
+
+	custom := NewComponentFactory(nil, "").
+		WithCustomNames(map[string]string{"GET /users/{id}/posts": "fetch_user_posts"})
+	if got := custom.generateName(ir.HTTPRoute{Method: "GET", Path: "/users/{id}/posts"}, "tool"); got != "fetch_user_posts" {
+		t.Fatalf("expected custom name to win, got %q", got)
+	}
+}
+
+func TestGenerateNamePrefixesSpecAliasOnCollision(t *testing.T) {
+	cf := NewComponentFactory(executor.NewDefaultHTTPClient(), "").
+		WithNameCollisionStrategy(NameCollisionPrefix)
+
+	route := ir.HTTPRoute{Method: "GET", Path: "/users", OperationID: "listUsers"}
+
+	cf.SetSpecAlias("accounts")
+	if got := cf.generateName(route, "tool"); got != "listUsers" {
+		t.Fatalf("expected first spec to keep listUsers, got %q", got)
+	}
+
+	cf.SetSpecAlias("billing")
+	if got := cf.generateName(route, "tool"); got != "billing_listUsers" {
+		t.Fatalf("expected billing_listUsers, got %q", got)
+	}
+	if got := cf.generateName(route, "tool"); got != "listUsers_3" {
+		t.Fatalf("expected numeric fallback listUsers_3, got %q", got)
+	}
+
+	collisions := cf.NameCollisions()
+	if len(collisions) != 2 || collisions[0].Renamed != "billing_listUsers" || collisions[0].SpecAlias != "billing" {
+		t.Fatalf("unexpected collision summary: %+v", collisions)
+	}
+}
+
 func TestGenerateNameFallsBackWhenEmpty(t *testing.T) {
 	cf := NewComponentFactory(executor.NewDefaultHTTPClient(), "")
 
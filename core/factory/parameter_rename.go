@@ -0,0 +1,78 @@
+package factory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// WithParameterRenames maps operationIds to argument renames, keyed by the
+// argument name the tool would otherwise expose (e.g. "id__path").
+func (cf *ComponentFactory) WithParameterRenames(renames map[string]map[string]string) *ComponentFactory {
+	cf.parameterRenames = renames
+	return cf
+}
+
+// applyParameterRenames moves arguments to their configured names. The schema
+// property, its required entry and the paramMap key move together, while the
+// mapping keeps the wire name the request builder sends. Renames of arguments
+// the schema does not have are ignored, since content type variants of one
+// operation may expose different body properties.
+func (cf *ComponentFactory) applyParameterRenames(route ir.HTTPRoute, schema ir.Schema, paramMap map[string]ir.ParamMapping) error {
+	renames := cf.parameterRenames[route.OperationID]
+	if len(renames) == 0 {
+		return nil
+	}
+
+	sources := make([]string, 0, len(renames))
+	for from, to := range renames {
+		if _, ok := paramMap[from]; ok && to != "" && to != from {
+			sources = append(sources, from)
+		}
+	}
+	sort.Strings(sources)
+
+	moving := make(map[string]bool, len(sources))
+	for _, from := range sources {
+		moving[from] = true
+	}
+	targets := make(map[string]string, len(sources))
+	for _, from := range sources {
+		to := renames[from]
+		if _, taken := paramMap[to]; taken && !moving[to] {
+			return fmt.Errorf("cannot rename argument %q of %s to %q: the name is already used", from, route.OperationID, to)
+		}
+		if other, dup := targets[to]; dup {
+			return fmt.Errorf("cannot rename arguments %q and %q of %s to the same name %q", other, from, route.OperationID, to)
+		}
+		targets[to] = from
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	movedProps := make(map[string]interface{}, len(sources))
+	movedMappings := make(map[string]ir.ParamMapping, len(sources))
+	for _, from := range sources {
+		if prop, ok := properties[from]; ok {
+			movedProps[renames[from]] = prop
+			delete(properties, from)
+		}
+		movedMappings[renames[from]] = paramMap[from]
+		delete(paramMap, from)
+	}
+	for to, prop := range movedProps {
+		properties[to] = prop
+	}
+	for to, mapping := range movedMappings {
+		paramMap[to] = mapping
+	}
+
+	if required, ok := schema["required"].([]string); ok {
+		for i, name := range required {
+			if moving[name] {
+				required[i] = renames[name]
+			}
+		}
+	}
+	return nil
+}
@@ -2,15 +2,27 @@ package factory
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"unicode"
 
+	"github.com/specx2/openapi-mcp/core/executor"
 	"github.com/specx2/openapi-mcp/core/ir"
 	"github.com/specx2/openapi-mcp/core/parser"
 )
 
 func (cf *ComponentFactory) combineSchemas(route ir.HTTPRoute) (ir.Schema, map[string]ir.ParamMapping, error) {
+	bodyContentType := ""
+	if route.RequestBody != nil {
+		bodyContentType = parser.GetContentType(route.RequestBody.ContentSchemas)
+	}
+	return cf.combineSchemasFor(route, bodyContentType)
+}
+
+// combineSchemasFor builds the input schema using the request body of bodyContentType.
+func (cf *ComponentFactory) combineSchemasFor(route ir.HTTPRoute, bodyContentType string) (ir.Schema, map[string]ir.ParamMapping, error) {
 	schema := ir.Schema{
 		"type":       "object",
 		"properties": make(map[string]interface{}),
@@ -18,13 +30,11 @@ func (cf *ComponentFactory) combineSchemas(route ir.HTTPRoute) (ir.Schema, map[s
 
 	var required []string
 	paramMap := make(map[string]ir.ParamMapping)
-	bodyProps := cf.collectBodyProperties(route)
+	bodyProps := cf.collectBodyProperties(route, bodyContentType)
 
-	bodyContentType := ""
 	var bodyExample interface{}
 	var bodyExampleSets map[string]interface{}
 	if route.RequestBody != nil {
-		bodyContentType = parser.GetContentType(route.RequestBody.ContentSchemas)
 		if route.RequestBody.MediaExamples != nil {
 			bodyExample = route.RequestBody.MediaExamples[bodyContentType]
 		}
@@ -33,13 +43,24 @@ func (cf *ComponentFactory) combineSchemas(route ir.HTTPRoute) (ir.Schema, map[s
 		}
 	}
 
+	defaultExample, hasDefaultExample := cf.selectDefaultExample(route, bodyExample, bodyExampleSets)
+
 	for _, param := range route.Parameters {
 		if param.Schema == nil {
 			continue
 		}
 
-		schemaCopy := normalizeSchema(param.Schema)
-		if !param.Required {
+		paramSchema := param.Schema
+		if param.Style == "deepObject" {
+			// deepObject 参数需要直接暴露嵌套字段名，避免被 $ref 隐藏
+			if resolved := resolveSchemaReference(paramSchema, route.SchemaDefs); resolved != nil {
+				paramSchema = resolved
+			}
+		}
+
+		paramRequired := isParameterRequired(param)
+		schemaCopy := normalizeSchemaWithDefs(paramSchema, route.SchemaDefs)
+		if !paramRequired {
 			schemaCopy = makeOptionalNullable(schemaCopy)
 		}
 
@@ -50,11 +71,15 @@ func (cf *ComponentFactory) combineSchemas(route ir.HTTPRoute) (ir.Schema, map[s
 			argName = fmt.Sprintf("%s__%s", param.Name, param.In)
 		}
 
-		schemaProps := schema["properties"].(map[string]interface{})
-		schemaProps[argName] = schemaCopy
+		// a const parameter has only one possible value; the request builder
+		// fills it in, so the caller is not asked for it
+		if _, fixed := executor.ConstValue(param.Schema, route.SchemaDefs); !fixed {
+			schemaProps := schema["properties"].(map[string]interface{})
+			schemaProps[argName] = schemaCopy
 
-		if param.Required {
-			required = append(required, argName)
+			if paramRequired {
+				required = append(required, argName)
+			}
 		}
 
 		paramMap[argName] = ir.ParamMapping{
@@ -69,7 +94,8 @@ func (cf *ComponentFactory) combineSchemas(route ir.HTTPRoute) (ir.Schema, map[s
 		if bodyContentType != "" {
 			bodySchema := route.RequestBody.ContentSchemas[bodyContentType]
 			if bodySchema != nil {
-				normalizedBody := normalizeSchema(bodySchema)
+				normalizedBody := normalizeSchemaWithDefs(bodySchema, route.SchemaDefs)
+				dropAccessProperties(normalizedBody, "readOnly", definitionsOf(route.SchemaDefs))
 				if route.RequestBody.Description != "" {
 					if _, ok := normalizedBody["description"].(string); !ok {
 						normalizedBody["description"] = route.RequestBody.Description
@@ -78,7 +104,11 @@ func (cf *ComponentFactory) combineSchemas(route ir.HTTPRoute) (ir.Schema, map[s
 				properties := normalizedBody.Properties()
 				if len(properties) == 0 {
 					propName := determineBodyPropertyName(normalizedBody)
+					surfaceDiscriminator(normalizedBody)
 					applyBodyExamplesToSchema(normalizedBody, "", bodyExample, bodyExampleSets)
+					if hasDefaultExample {
+						applyExampleDefault(normalizedBody, "", defaultExample)
+					}
 					schema["properties"].(map[string]interface{})[propName] = normalizedBody
 					paramMap[propName] = ir.ParamMapping{
 						OpenAPIName:  propName,
@@ -90,21 +120,32 @@ func (cf *ComponentFactory) combineSchemas(route ir.HTTPRoute) (ir.Schema, map[s
 						required = append(required, propName)
 					}
 				} else {
+					fixed := make(map[string]bool)
 					for propName, propSchema := range properties {
-						normalizedProp := normalizeSchema(propSchema)
-						applyBodyExamplesToSchema(normalizedProp, propName, bodyExample, bodyExampleSets)
-						schema["properties"].(map[string]interface{})[propName] = normalizedProp
+						normalizedProp := normalizeSchemaWithDefs(propSchema, route.SchemaDefs)
 						paramMap[propName] = ir.ParamMapping{
 							OpenAPIName:  propName,
 							Location:     "body",
 							IsSuffixed:   false,
 							OriginalName: propName,
 						}
+						if _, ok := executor.ConstValue(propSchema, route.SchemaDefs); ok {
+							fixed[propName] = true
+							continue
+						}
+						surfaceDiscriminator(normalizedProp)
+						applyBodyExamplesToSchema(normalizedProp, propName, bodyExample, bodyExampleSets)
+						if hasDefaultExample {
+							applyExampleDefault(normalizedProp, propName, defaultExample)
+						}
+						schema["properties"].(map[string]interface{})[propName] = normalizedProp
 					}
 
 					if route.RequestBody.Required {
 						for _, prop := range normalizedBody.Required() {
-							required = append(required, prop)
+							if !fixed[prop] {
+								required = append(required, prop)
+							}
 						}
 					}
 				}
@@ -117,22 +158,23 @@ func (cf *ComponentFactory) combineSchemas(route ir.HTTPRoute) (ir.Schema, map[s
 		schema["required"] = required
 	}
 
+	if err := cf.applyParameterRenames(route, schema, paramMap); err != nil {
+		return nil, nil, err
+	}
+
 	if defs := pruneSchemaDefinitions(schema, route.SchemaDefs); len(defs) > 0 {
-		schema["$defs"] = defs
+		if defs = dropAccessDefinitions(schema, defs, "readOnly"); len(defs) > 0 {
+			schema["$defs"] = defs
+		}
 	}
 
 	return schema, paramMap, nil
 }
 
-func (cf *ComponentFactory) collectBodyProperties(route ir.HTTPRoute) map[string]bool {
+func (cf *ComponentFactory) collectBodyProperties(route ir.HTTPRoute, contentType string) map[string]bool {
 	bodyProps := make(map[string]bool)
 
-	if route.RequestBody == nil {
-		return bodyProps
-	}
-
-	contentType := parser.GetContentType(route.RequestBody.ContentSchemas)
-	if contentType == "" {
+	if route.RequestBody == nil || contentType == "" {
 		return bodyProps
 	}
 
@@ -141,7 +183,9 @@ func (cf *ComponentFactory) collectBodyProperties(route ir.HTTPRoute) map[string
 		return bodyProps
 	}
 
-	properties := normalizeSchema(bodySchema).Properties()
+	normalizedBody := normalizeSchemaWithDefs(bodySchema, route.SchemaDefs)
+	dropAccessProperties(normalizedBody, "readOnly", definitionsOf(route.SchemaDefs))
+	properties := normalizedBody.Properties()
 	if len(properties) == 0 {
 		propName := determineBodyPropertyName(bodySchema)
 		bodyProps[propName] = true
@@ -177,6 +221,45 @@ func applyBodyExamplesToSchema(schema ir.Schema, propName string, rawExample int
 	}
 }
 
+// selectDefaultExample returns the body example configured via WithDefaultExamples
+// for the route: the named example when present, otherwise the single media
+// example, otherwise the only named example.
+func (cf *ComponentFactory) selectDefaultExample(route ir.HTTPRoute, rawExample interface{}, namedExamples map[string]interface{}) (interface{}, bool) {
+	exampleName, ok := cf.defaultExamples[route.OperationID]
+	if !ok || route.OperationID == "" {
+		return nil, false
+	}
+
+	if entry, ok := namedExamples[exampleName].(map[string]interface{}); ok && exampleName != "" {
+		if value, ok := entry["value"]; ok {
+			return value, true
+		}
+	}
+	if rawExample != nil {
+		return rawExample, true
+	}
+	if len(namedExamples) == 1 {
+		for _, entry := range namedExamples {
+			if entryMap, ok := entry.(map[string]interface{}); ok {
+				value, ok := entryMap["value"]
+				return value, ok
+			}
+		}
+	}
+	return nil, false
+}
+
+// applyExampleDefault pre-fills the argument with the example value as its default.
+func applyExampleDefault(schema ir.Schema, propName string, example interface{}) {
+	value, ok := exampleValueForProperty(example, propName)
+	if !ok || value == nil {
+		return
+	}
+	if _, exists := schema["default"]; !exists {
+		schema["default"] = cloneValue(value)
+	}
+}
+
 func projectNamedExamplesForProperty(named map[string]interface{}, propName string) map[string]interface{} {
 	projected := make(map[string]interface{}, len(named))
 	for name, entry := range named {
@@ -284,10 +367,22 @@ func annotateParameterSchema(schema ir.Schema, param ir.ParameterInfo) ir.Schema
 
 	if param.Deprecated {
 		schema["deprecated"] = true
+		if !strings.Contains(strings.ToLower(description), "(deprecated)") {
+			schema["description"] = description + " (deprecated)"
+		}
+	}
+
+	describeEnumValues(schema)
+	switch items := schema["items"].(type) {
+	case ir.Schema:
+		describeEnumValues(items)
+	case map[string]interface{}:
+		describeEnumValues(ir.Schema(items))
 	}
 
-	if param.AllowEmptyValue {
-		schema["x-allowEmptyValue"] = true
+	schema["x-parameter-required"] = isParameterRequired(param)
+	if param.In == ir.ParameterInQuery || param.AllowEmptyValue {
+		schema["x-allowEmptyValue"] = param.AllowEmptyValue
 	}
 
 	if param.Example != nil {
@@ -305,6 +400,64 @@ func annotateParameterSchema(schema ir.Schema, param ir.ParameterInfo) ir.Schema
 	return schema
 }
 
+// describeEnumValues turns x-enum-descriptions / x-enumNames into a oneOf of
+// const branches carrying a title and description per enum value. Both extensions
+// may be an array aligned with enum or an object keyed by the enum value.
+func describeEnumValues(schema ir.Schema) {
+	values, ok := schema["enum"].([]interface{})
+	if !ok || len(values) == 0 {
+		return
+	}
+	if _, exists := schema["oneOf"]; exists {
+		return
+	}
+	descriptions := schema["x-enum-descriptions"]
+	names := schema["x-enumNames"]
+	if descriptions == nil && names == nil {
+		return
+	}
+
+	branches := make([]interface{}, 0, len(values))
+	annotated := false
+	for i, value := range values {
+		branch := map[string]interface{}{"const": value}
+		if name := enumAnnotation(names, i, value); name != "" {
+			branch["title"] = name
+			annotated = true
+		}
+		if description := enumAnnotation(descriptions, i, value); description != "" {
+			branch["description"] = description
+			annotated = true
+		}
+		branches = append(branches, branch)
+	}
+	if annotated {
+		schema["oneOf"] = branches
+	}
+}
+
+func enumAnnotation(annotations interface{}, index int, value interface{}) string {
+	switch a := annotations.(type) {
+	case []interface{}:
+		if index < len(a) {
+			if text, ok := a[index].(string); ok {
+				return text
+			}
+		}
+	case map[string]interface{}:
+		if text, ok := a[fmt.Sprint(value)].(string); ok {
+			return text
+		}
+	}
+	return ""
+}
+
+// isParameterRequired treats path parameters as required even when the spec
+// omits `required: true`, since a path cannot be built without them.
+func isParameterRequired(param ir.ParameterInfo) bool {
+	return param.Required || param.In == ir.ParameterInPath
+}
+
 func titleCase(value string) string {
 	if value == "" {
 		return ""
@@ -317,31 +470,35 @@ func titleCase(value string) string {
 	return string(runes)
 }
 
+// maxSchemaDepth bounds recursion through nested schemas. Cloning and allOf
+// merging stop at this depth, or when a map turns out to contain itself, and
+// keep what was processed so far instead of overflowing the stack.
+const maxSchemaDepth = 64
+
 func cloneSchema(schema ir.Schema) ir.Schema {
 	if schema == nil {
 		return nil
 	}
-	cloned := make(ir.Schema, len(schema))
-	for key, value := range schema {
-		cloned[key] = cloneValue(value)
-	}
-	return cloned
+	return ir.Schema(cloneMap(schema, make(map[uintptr]bool), 0))
 }
 
 func cloneValue(value interface{}) interface{} {
+	return cloneGuarded(value, make(map[uintptr]bool), 0)
+}
+
+func cloneGuarded(value interface{}, ancestors map[uintptr]bool, depth int) interface{} {
 	switch v := value.(type) {
 	case ir.Schema:
-		return cloneSchema(v)
-	case map[string]interface{}:
-		cloned := make(map[string]interface{}, len(v))
-		for k, val := range v {
-			cloned[k] = cloneValue(val)
+		if v == nil {
+			return v
 		}
-		return cloned
+		return ir.Schema(cloneMap(v, ancestors, depth))
+	case map[string]interface{}:
+		return cloneMap(v, ancestors, depth)
 	case []interface{}:
 		cloned := make([]interface{}, len(v))
 		for i, val := range v {
-			cloned[i] = cloneValue(val)
+			cloned[i] = cloneGuarded(val, ancestors, depth)
 		}
 		return cloned
 	default:
@@ -349,6 +506,26 @@ func cloneValue(value interface{}) interface{} {
 	}
 }
 
+// cloneMap replaces a map that is one of its own ancestors, or lies deeper than
+// maxSchemaDepth, with an empty (unconstrained) schema.
+func cloneMap(m map[string]interface{}, ancestors map[uintptr]bool, depth int) map[string]interface{} {
+	cloned := make(map[string]interface{}, len(m))
+	if m == nil {
+		return cloned
+	}
+	id := reflect.ValueOf(m).Pointer()
+	if ancestors[id] || depth > maxSchemaDepth*2 {
+		return cloned
+	}
+	ancestors[id] = true
+	defer delete(ancestors, id)
+
+	for k, val := range m {
+		cloned[k] = cloneGuarded(val, ancestors, depth+1)
+	}
+	return cloned
+}
+
 func makeOptionalNullable(schema ir.Schema) ir.Schema {
 	if schema == nil {
 		return nil
@@ -384,6 +561,14 @@ func makeOptionalNullable(schema ir.Schema) ir.Schema {
 		}
 	}
 
+	// array constraints stay on the array branch and are repeated on the wrapper,
+	// where they are ignored for null but visible to code reading the top level
+	for _, field := range []string{"minItems", "maxItems", "uniqueItems"} {
+		if val, ok := original[field]; ok {
+			wrapper[field] = val
+		}
+	}
+
 	wrapper["anyOf"] = []interface{}{
 		original,
 		map[string]interface{}{"type": "null"},
@@ -458,6 +643,10 @@ func collectRefsInto(value interface{}, refs map[string]struct{}) {
 				}
 				continue
 			}
+			if key == "discriminator" {
+				collectDiscriminatorRefs(val, refs)
+				continue
+			}
 			collectRefsInto(val, refs)
 		}
 	case []interface{}:
@@ -467,27 +656,160 @@ func collectRefsInto(value interface{}, refs map[string]struct{}) {
 	}
 }
 
+// collectDiscriminatorRefs marks `#/$defs/` mapping targets as used so the
+// variants they name survive pruning even when oneOf lists them inline.
+func collectDiscriminatorRefs(value interface{}, refs map[string]struct{}) {
+	discriminator, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	mapping, ok := discriminator["mapping"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, target := range mapping {
+		if refStr, ok := target.(string); ok && strings.HasPrefix(refStr, "#/$defs/") {
+			refs[strings.TrimPrefix(refStr, "#/$defs/")] = struct{}{}
+		}
+	}
+}
+
+// surfaceDiscriminator exposes the discriminator of a oneOf/anyOf schema as a
+// required string property whose enum lists the accepted values. The composed
+// variants and the discriminator itself are kept so clients can still see them.
+func surfaceDiscriminator(schema ir.Schema) {
+	discriminator, ok := schema["discriminator"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	propertyName, _ := discriminator["propertyName"].(string)
+	if propertyName == "" {
+		return
+	}
+
+	var variants []interface{}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		variants = oneOf
+	} else if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		variants = anyOf
+	} else {
+		return
+	}
+
+	values := discriminatorValues(discriminator, variants)
+	if len(values) == 0 {
+		return
+	}
+
+	props, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		props = make(map[string]interface{})
+		schema["properties"] = props
+	}
+	enum := make([]interface{}, len(values))
+	for i, value := range values {
+		enum[i] = value
+	}
+	property := map[string]interface{}{
+		"type": "string",
+		"enum": enum,
+	}
+	if desc, ok := toSchema(props[propertyName])["description"].(string); ok && desc != "" {
+		property["description"] = desc
+	}
+	props[propertyName] = property
+
+	schema["required"] = deduplicate(append(schema.Required(), propertyName))
+	if _, ok := schema["type"]; !ok {
+		schema["type"] = "object"
+	}
+}
+
+// discriminatorValues lists the mapping keys, or the referenced component names
+// when the discriminator has no explicit mapping.
+func discriminatorValues(discriminator map[string]interface{}, variants []interface{}) []string {
+	var values []string
+	if mapping, ok := discriminator["mapping"].(map[string]interface{}); ok && len(mapping) > 0 {
+		for value := range mapping {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+		return values
+	}
+
+	for _, variant := range variants {
+		ref, _ := toSchema(variant)["$ref"].(string)
+		if strings.HasPrefix(ref, "#/$defs/") {
+			values = append(values, strings.TrimPrefix(ref, "#/$defs/"))
+		}
+	}
+	return deduplicate(values)
+}
+
 func normalizeSchema(schema ir.Schema) ir.Schema {
+	return normalizeSchemaAt(schema, nil, 0)
+}
+
+// normalizeSchemaWithDefs also inlines allOf entries that $ref a definition in defs.
+func normalizeSchemaWithDefs(schema ir.Schema, defs ir.Schema) ir.Schema {
+	if len(defs.Definitions()) == 0 {
+		return normalizeSchema(schema)
+	}
+	return normalizeSchemaAt(schema, &schemaRefs{defs: defs, expanding: make(map[string]bool)}, 0)
+}
+
+// schemaRefs resolves the #/$defs references inlined by mergeAllOf. expanding
+// holds the references being merged, so recursive definitions stop expanding.
+type schemaRefs struct {
+	defs      ir.Schema
+	expanding map[string]bool
+}
+
+func normalizeSchemaAt(schema ir.Schema, refs *schemaRefs, depth int) ir.Schema {
 	cloned := cloneSchema(schema)
-	cloned = mergeAllOf(cloned)
-	normalizeComposedSchemas(cloned, "oneOf")
-	normalizeComposedSchemas(cloned, "anyOf")
+	if depth > maxSchemaDepth {
+		return cloned
+	}
+	cloned = mergeAllOf(cloned, refs, depth)
+	normalizeComposedSchemas(cloned, "oneOf", refs, depth)
+	normalizeComposedSchemas(cloned, "anyOf", refs, depth)
 
 	if items, ok := cloned["items"].(map[string]interface{}); ok {
-		cloned["items"] = normalizeSchema(items)
+		cloned["items"] = normalizeSchemaAt(items, refs, depth+1)
 	} else if itemsSchema, ok := cloned["items"].(ir.Schema); ok {
-		cloned["items"] = normalizeSchema(itemsSchema)
+		cloned["items"] = normalizeSchemaAt(itemsSchema, refs, depth+1)
+	}
+	if prefixItems, ok := cloned["prefixItems"].([]interface{}); ok {
+		for i, item := range prefixItems {
+			if entry := toSchema(item); len(entry) > 0 {
+				prefixItems[i] = map[string]interface{}(normalizeSchemaAt(entry, refs, depth+1))
+			}
+		}
 	}
 
 	if props, ok := cloned["properties"].(map[string]interface{}); ok {
 		for key, value := range props {
-			props[key] = normalizeSchema(toSchema(value))
+			props[key] = normalizeSchemaAt(toSchema(value), refs, depth+1)
+		}
+	}
+	// map-like objects constrain their values through patternProperties and
+	// additionalProperties, and their keys through propertyNames
+	if patterns, ok := cloned["patternProperties"].(map[string]interface{}); ok {
+		for pattern, value := range patterns {
+			if entry := toSchema(value); len(entry) > 0 {
+				patterns[pattern] = normalizeSchemaAt(entry, refs, depth+1)
+			}
+		}
+	}
+	for _, key := range []string{"additionalProperties", "propertyNames"} {
+		if entry := toSchema(cloned[key]); len(entry) > 0 {
+			cloned[key] = normalizeSchemaAt(entry, refs, depth+1)
 		}
 	}
 	return cloned
 }
 
-func normalizeComposedSchemas(schema ir.Schema, key string) {
+func normalizeComposedSchemas(schema ir.Schema, key string, refs *schemaRefs, depth int) {
 	list, ok := schema[key].([]interface{})
 	if !ok {
 		return
@@ -500,24 +822,35 @@ func normalizeComposedSchemas(schema ir.Schema, key string) {
 			normalized = append(normalized, item)
 			continue
 		}
-		normalized = append(normalized, normalizeSchema(entry))
+		normalized = append(normalized, normalizeSchemaAt(entry, refs, depth+1))
 	}
 
 	schema[key] = normalized
 }
 
-func mergeAllOf(schema ir.Schema) ir.Schema {
+// mergeAllOf folds allOf branches into schema. With refs, branches that $ref a
+// definition are inlined first, so a referenced base that itself uses allOf
+// contributes all of its properties. Past maxSchemaDepth the remaining allOf is
+// left in place, so a recursive chain ends up partially merged.
+func mergeAllOf(schema ir.Schema, refs *schemaRefs, depth int) ir.Schema {
 	allOf, ok := schema["allOf"].([]interface{})
-	if !ok {
+	if !ok || depth > maxSchemaDepth {
 		return schema
 	}
 
 	combinedProps := make(map[string]interface{})
+	combinedPatterns := make(map[string]interface{})
 	var combinedRequired []string
 
 	for _, item := range allOf {
 		sub := toSchema(item)
-		sub = mergeAllOf(sub)
+		if ref, resolved := refs.resolve(sub); resolved != nil {
+			refs.expanding[ref] = true
+			sub = mergeAllOf(resolved, refs, depth+1)
+			delete(refs.expanding, ref)
+		} else {
+			sub = mergeAllOf(sub, refs, depth+1)
+		}
 
 		if props, ok := sub["properties"].(map[string]interface{}); ok {
 			for k, v := range props {
@@ -525,12 +858,18 @@ func mergeAllOf(schema ir.Schema) ir.Schema {
 			}
 		}
 
+		if patterns, ok := sub["patternProperties"].(map[string]interface{}); ok {
+			for k, v := range patterns {
+				combinedPatterns[k] = v
+			}
+		}
+
 		if req, ok := toStringSlice(sub["required"]); ok {
 			combinedRequired = append(combinedRequired, req...)
 		}
 
 		for key, val := range sub {
-			if key == "properties" || key == "required" || key == "allOf" {
+			if key == "properties" || key == "patternProperties" || key == "required" || key == "allOf" {
 				continue
 			}
 			if _, exists := schema[key]; !exists {
@@ -548,6 +887,15 @@ func mergeAllOf(schema ir.Schema) ir.Schema {
 		schema["properties"] = combinedProps
 	}
 
+	if len(combinedPatterns) > 0 {
+		if existing, ok := schema["patternProperties"].(map[string]interface{}); ok {
+			for k, v := range existing {
+				combinedPatterns[k] = v
+			}
+		}
+		schema["patternProperties"] = combinedPatterns
+	}
+
 	if len(combinedRequired) > 0 {
 		schema["required"] = deduplicate(combinedRequired)
 	}
@@ -556,6 +904,27 @@ func mergeAllOf(schema ir.Schema) ir.Schema {
 	return schema
 }
 
+// resolve returns the definition a bare $ref branch points to, or nil when refs
+// is nil, the reference is unknown or it is already being expanded.
+func (refs *schemaRefs) resolve(schema ir.Schema) (string, ir.Schema) {
+	if refs == nil {
+		return "", nil
+	}
+	ref, ok := schema["$ref"].(string)
+	if !ok || refs.expanding[ref] {
+		return "", nil
+	}
+	resolved := resolveSchemaReference(schema, refs.defs)
+	if resolved == nil {
+		return "", nil
+	}
+	if _, alias := resolved["$ref"]; alias {
+		// an alias of another definition; mergeAllOf follows it with the same guard
+		resolved = ir.Schema{"allOf": []interface{}{map[string]interface{}(resolved)}}
+	}
+	return ref, resolved
+}
+
 func toSchema(value interface{}) ir.Schema {
 	switch v := value.(type) {
 	case ir.Schema:
@@ -604,15 +973,28 @@ func determineBodyPropertyName(schema ir.Schema) string {
 	return "body"
 }
 
-func (cf *ComponentFactory) extractOutputSchema(route ir.HTTPRoute) (ir.Schema, bool) {
-	successStatuses := []string{"200", "201", "202", "204"}
+// outputStatus picks the success response that becomes the output schema: the
+// status pinned for the operation when the route declares it, otherwise the
+// first of 200, 201, 202 and 204.
+func (cf *ComponentFactory) outputStatus(route ir.HTTPRoute) string {
+	if status, ok := cf.outputStatuses[route.OperationID]; ok && route.OperationID != "" {
+		if _, declared := route.Responses[status]; declared {
+			return status
+		}
+	}
+	for _, status := range []string{"200", "201", "202", "204"} {
+		if _, ok := route.Responses[status]; ok {
+			return status
+		}
+	}
+	return ""
+}
 
+func (cf *ComponentFactory) extractOutputSchema(route ir.HTTPRoute) (ir.Schema, bool) {
 	var responseInfo *ir.ResponseInfo
-	for _, status := range successStatuses {
-		if resp, ok := route.Responses[status]; ok {
-			responseInfo = &resp
-			break
-		}
+	if status := cf.outputStatus(route); status != "" {
+		resp := route.Responses[status]
+		responseInfo = &resp
 	}
 
 	if responseInfo == nil || len(responseInfo.ContentSchemas) == 0 {
@@ -623,6 +1005,9 @@ func (cf *ComponentFactory) extractOutputSchema(route ir.HTTPRoute) (ir.Schema,
 	if contentType == "" {
 		return nil, false
 	}
+	if executor.IsBinaryContentType(contentType) {
+		return binaryOutputSchema(), false
+	}
 
 	schema := responseInfo.ContentSchemas[contentType]
 
@@ -649,10 +1034,16 @@ func (cf *ComponentFactory) extractOutputSchema(route ir.HTTPRoute) (ir.Schema,
 
 	optimizedSchema := parser.OptimizeSchema(wrappedSchema)
 
+	delete(optimizedSchema, "$defs")
+	dropAccessProperties(optimizedSchema, "writeOnly", definitionsOf(route.SchemaDefs))
 	if defs := pruneSchemaDefinitions(optimizedSchema, route.SchemaDefs); len(defs) > 0 {
-		optimizedSchema["$defs"] = defs
-	} else {
-		delete(optimizedSchema, "$defs")
+		if defs = dropAccessDefinitions(optimizedSchema, defs, "writeOnly"); len(defs) > 0 {
+			optimizedSchema["$defs"] = defs
+		}
+	}
+
+	if headers := responseHeadersSchema(responseInfo.Headers); headers != nil {
+		optimizedSchema = withResponseHeadersProperty(optimizedSchema, headers)
 	}
 
 	if wrapResult {
@@ -662,6 +1053,105 @@ func (cf *ComponentFactory) extractOutputSchema(route ir.HTTPRoute) (ir.Schema,
 	return optimizedSchema, wrapResult
 }
 
+// withResponseHeadersProperty declares `_headers` next to the body's
+// properties. A body given as a $ref is inlined from $defs so the property
+// sits beside the referenced ones; a ref that cannot be inlined is combined
+// with the headers through allOf.
+func withResponseHeadersProperty(schema ir.Schema, headers ir.Schema) ir.Schema {
+	if _, ok := schema["properties"].(map[string]interface{}); !ok {
+		ref, isRef := schema["$ref"].(string)
+		if !isRef {
+			return schema
+		}
+		resolved := resolveSchemaReference(schema, schema)
+		if _, ok := resolved["properties"].(map[string]interface{}); !ok {
+			combined := ir.Schema{
+				"allOf":      []interface{}{map[string]interface{}{"$ref": ref}},
+				"properties": map[string]interface{}{executor.ResponseHeadersKey: map[string]interface{}(headers)},
+			}
+			if defs, ok := schema["$defs"]; ok {
+				combined["$defs"] = defs
+			}
+			return combined
+		}
+		for key, value := range schema {
+			if _, exists := resolved[key]; !exists && key != "$ref" {
+				resolved[key] = value
+			}
+		}
+		schema = resolved
+	}
+
+	props := schema["properties"].(map[string]interface{})
+	extended := make(map[string]interface{}, len(props)+1)
+	for name, prop := range props {
+		extended[name] = prop
+	}
+	extended[executor.ResponseHeadersKey] = map[string]interface{}(headers)
+	schema["properties"] = extended
+	return schema
+}
+
+// responseHeadersSchema describes the declared response headers surfaced under
+// `_headers` in structured content.
+func responseHeadersSchema(headers map[string]ir.HeaderInfo) ir.Schema {
+	properties := make(map[string]interface{})
+	for name, header := range headers {
+		if strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		prop := ir.Schema{"type": "string"}
+		if header.Schema != nil {
+			prop = cloneSchema(header.Schema)
+		}
+		if header.Description != "" {
+			if _, exists := prop["description"]; !exists {
+				prop["description"] = header.Description
+			}
+		}
+		properties[name] = map[string]interface{}(prop)
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+	return ir.Schema{
+		"type":        "object",
+		"description": "Response headers declared by the API",
+		"properties":  properties,
+	}
+}
+
+// withAuditProperty declares the `_audit` record in an object output schema.
+// Tools without an output schema keep none.
+func withAuditProperty(schema ir.Schema) ir.Schema {
+	props, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		return schema
+	}
+	extended := make(map[string]interface{}, len(props)+1)
+	for name, prop := range props {
+		extended[name] = prop
+	}
+	extended[executor.AuditKey] = map[string]interface{}(executor.AuditSchema())
+	schema["properties"] = extended
+	return schema
+}
+
+// binaryOutputSchema describes the structured content produced for binary
+// responses: the base64 payload together with its media type.
+func binaryOutputSchema() ir.Schema {
+	return ir.Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"contentType": map[string]interface{}{"type": "string"},
+			"data":        map[string]interface{}{"type": "string", "contentEncoding": "base64"},
+			"size":        map[string]interface{}{"type": "integer"},
+			"filename":    map[string]interface{}{"type": "string"},
+		},
+		"required": []interface{}{"contentType", "data", "size"},
+	}
+}
+
 func resolveSchemaReference(schema ir.Schema, definitions ir.Schema) ir.Schema {
 	if schema == nil {
 		return nil
@@ -0,0 +1,197 @@
+package factory
+
+import (
+	"strings"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// SchemaDialect selects the JSON Schema draft tool input schemas are written in.
+type SchemaDialect int
+
+const (
+	// Draft2020 emits JSON Schema 2020-12 with $defs and prefixItems (default).
+	Draft2020 SchemaDialect = iota
+	// Draft07 emits draft-07 schemas with definitions and tuple items.
+	Draft07
+)
+
+const draft07SchemaURI = "http://json-schema.org/draft-07/schema#"
+
+// WithInputSchemaDialect sets the draft of the input schemas of the tools
+// created afterwards.
+func (cf *ComponentFactory) WithInputSchemaDialect(dialect SchemaDialect) *ComponentFactory {
+	cf.inputSchemaDialect = dialect
+	return cf
+}
+
+// annotationKeywords may sit next to a draft-07 $ref, which ignores them but
+// loses nothing by doing so.
+var annotationKeywords = map[string]bool{
+	"$ref": true, "title": true, "description": true, "default": true,
+	"example": true, "examples": true, "deprecated": true, "readOnly": true,
+	"writeOnly": true, "$comment": true,
+}
+
+// toDraft07 rewrites a 2020-12 input schema for draft-07 and declares the
+// draft in $schema, so the tool validator is compiled for the same draft.
+// $defs become definitions with their $ref targets, prefixItems become tuple
+// items (a following items schema becoming additionalItems), dependentRequired
+// and dependentSchemas merge into dependencies, and a $ref with validation
+// keywords beside it moves into allOf, since draft-07 ignores $ref siblings.
+func toDraft07(schema ir.Schema) ir.Schema {
+	if schema == nil {
+		return nil
+	}
+	converted := draft07Schema(schema, 0)
+	converted["$schema"] = draft07SchemaURI
+	return converted
+}
+
+func draft07Schema(schema map[string]interface{}, depth int) ir.Schema {
+	out := make(ir.Schema, len(schema))
+	if depth > 64 {
+		for key, value := range schema {
+			out[key] = value
+		}
+		return out
+	}
+
+	for key, value := range schema {
+		switch key {
+		case "$ref":
+			if ref, ok := value.(string); ok {
+				value = draft07Ref(ref)
+			}
+			out[key] = value
+		case "$defs", "definitions":
+			defs := draft07SchemaMap(value, depth)
+			if existing, ok := out["definitions"].(map[string]interface{}); ok {
+				for name, def := range existing {
+					defs[name] = def
+				}
+			}
+			out["definitions"] = defs
+		case "properties", "patternProperties":
+			out[key] = draft07SchemaMap(value, depth)
+		case "prefixItems":
+			out["items"] = draft07SchemaList(value, depth)
+			if items, ok := schema["items"]; ok {
+				out["additionalItems"] = draft07Subschema(items, depth)
+			}
+		case "items":
+			if _, tuple := schema["prefixItems"]; !tuple {
+				out[key] = draft07Subschema(value, depth)
+			}
+		case "additionalProperties", "additionalItems", "not", "contains", "propertyNames", "if", "then", "else":
+			out[key] = draft07Subschema(value, depth)
+		case "allOf", "anyOf", "oneOf":
+			out[key] = draft07SchemaList(value, depth)
+		case "dependentRequired", "dependentSchemas":
+			dependencies, _ := out["dependencies"].(map[string]interface{})
+			if dependencies == nil {
+				dependencies = make(map[string]interface{})
+			}
+			if entries, ok := value.(map[string]interface{}); ok {
+				for name, entry := range entries {
+					dependencies[name] = draft07Subschema(entry, depth)
+				}
+			}
+			out["dependencies"] = dependencies
+		case "discriminator":
+			out[key] = draft07Discriminator(value)
+		default:
+			out[key] = value
+		}
+	}
+
+	if _, ok := out["$ref"]; ok && hasValidationSiblings(out) {
+		ref := out["$ref"]
+		delete(out, "$ref")
+		allOf, _ := out["allOf"].([]interface{})
+		out["allOf"] = append([]interface{}{map[string]interface{}{"$ref": ref}}, allOf...)
+	}
+	return out
+}
+
+func draft07Subschema(value interface{}, depth int) interface{} {
+	switch v := value.(type) {
+	case ir.Schema:
+		return map[string]interface{}(draft07Schema(v, depth+1))
+	case map[string]interface{}:
+		return map[string]interface{}(draft07Schema(v, depth+1))
+	case []interface{}:
+		return draft07SchemaList(v, depth)
+	}
+	return value
+}
+
+func draft07SchemaMap(value interface{}, depth int) map[string]interface{} {
+	var entries map[string]interface{}
+	switch v := value.(type) {
+	case ir.Schema:
+		entries = v
+	case map[string]interface{}:
+		entries = v
+	case map[string]ir.Schema:
+		entries = make(map[string]interface{}, len(v))
+		for name, schema := range v {
+			entries[name] = schema
+		}
+	}
+	out := make(map[string]interface{}, len(entries))
+	for name, entry := range entries {
+		out[name] = draft07Subschema(entry, depth)
+	}
+	return out
+}
+
+func draft07SchemaList(value interface{}, depth int) interface{} {
+	items, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+	out := make([]interface{}, len(items))
+	for i, item := range items {
+		out[i] = draft07Subschema(item, depth)
+	}
+	return out
+}
+
+func draft07Discriminator(value interface{}) interface{} {
+	discriminator, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	out := make(map[string]interface{}, len(discriminator))
+	for key, entry := range discriminator {
+		out[key] = entry
+	}
+	if mapping, ok := discriminator["mapping"].(map[string]interface{}); ok {
+		rewritten := make(map[string]interface{}, len(mapping))
+		for name, target := range mapping {
+			if ref, ok := target.(string); ok {
+				target = draft07Ref(ref)
+			}
+			rewritten[name] = target
+		}
+		out["mapping"] = rewritten
+	}
+	return out
+}
+
+func draft07Ref(ref string) string {
+	if strings.HasPrefix(ref, "#/$defs/") {
+		return "#/definitions/" + strings.TrimPrefix(ref, "#/$defs/")
+	}
+	return ref
+}
+
+func hasValidationSiblings(schema map[string]interface{}) bool {
+	for key := range schema {
+		if !annotationKeywords[key] && !strings.HasPrefix(key, "x-") {
+			return true
+		}
+	}
+	return false
+}
@@ -1,9 +1,14 @@
 package factory
 
 import (
+	"context"
+	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 	"github.com/specx2/openapi-mcp/core/ir"
 )
 
@@ -52,6 +57,61 @@ func TestCombineSchemasOptionalParameterNullable(t *testing.T) {
 	}
 }
 
+func TestCombineSchemasOptionalArrayKeepsLengthConstraints(t *testing.T) {
+	cf := NewComponentFactory(nil, "")
+
+	route := ir.HTTPRoute{
+		Parameters: []ir.ParameterInfo{
+			{
+				Name: "ids",
+				In:   ir.ParameterInQuery,
+				Schema: ir.Schema{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "integer"},
+					"maxItems":    3,
+					"uniqueItems": true,
+				},
+			},
+		},
+	}
+
+	schema, _, err := cf.combineSchemas(route)
+	if err != nil {
+		t.Fatalf("combineSchemas returned error: %v", err)
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("failed to marshal schema: %v", err)
+	}
+	compiled, err := jsonschema.CompileString("input.json", string(data))
+	if err != nil {
+		t.Fatalf("failed to compile schema: %v", err)
+	}
+
+	for _, tc := range []struct {
+		args  string
+		valid bool
+	}{
+		{`{"ids": [1, 2, 3]}`, true},
+		{`{"ids": null}`, true},
+		{`{"ids": [1, 2, 3, 4]}`, false},
+		{`{"ids": [1, 1]}`, false},
+	} {
+		var args interface{}
+		if err := json.Unmarshal([]byte(tc.args), &args); err != nil {
+			t.Fatalf("bad fixture %s: %v", tc.args, err)
+		}
+		if err := compiled.Validate(args); (err == nil) != tc.valid {
+			t.Fatalf("validating %s: expected valid=%t, got %v", tc.args, tc.valid, err)
+		}
+	}
+
+	ids := extractSchemaMap(t, extractProperties(t, schema["properties"])["ids"])
+	if ids["maxItems"] != 3 || ids["uniqueItems"] != true {
+		t.Fatalf("expected the array constraints on the nullable wrapper, got %#v", ids)
+	}
+}
+
 func TestCombineSchemasAnnotatesParameterDescription(t *testing.T) {
 	cf := NewComponentFactory(nil, "")
 
@@ -198,6 +258,141 @@ func TestCombineSchemasMergesAllOf(t *testing.T) {
 	}
 }
 
+func TestNormalizeSchemaStopsOnRecursiveAllOf(t *testing.T) {
+	a := ir.Schema{}
+	b := ir.Schema{}
+	a["allOf"] = []interface{}{b, map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"a": map[string]interface{}{"type": "string"}},
+	}}
+	b["allOf"] = []interface{}{a, map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"b": map[string]interface{}{"type": "string"}},
+	}}
+
+	normalized := normalizeSchema(a)
+	props := extractProperties(t, normalized["properties"])
+	if _, ok := props["a"]; !ok {
+		t.Fatalf("expected property a, got %#v", normalized)
+	}
+	if _, ok := props["b"]; !ok {
+		t.Fatalf("expected property b, got %#v", normalized)
+	}
+
+	cf := NewComponentFactory(nil, "")
+	route := ir.HTTPRoute{
+		RequestBody: &ir.RequestBodyInfo{ContentSchemas: map[string]ir.Schema{"application/json": a}},
+	}
+	if _, _, err := cf.combineSchemas(route); err != nil {
+		t.Fatalf("combineSchemas returned error: %v", err)
+	}
+}
+
+func TestCombineSchemasResolvesAllOfReferences(t *testing.T) {
+	cf := NewComponentFactory(nil, "")
+
+	route := ir.HTTPRoute{
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{
+				"application/json": {
+					"allOf": []interface{}{
+						map[string]interface{}{"$ref": "#/$defs/Pet"},
+						map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"breed": map[string]interface{}{"type": "string"},
+							},
+						},
+					},
+				},
+			},
+			Required: true,
+		},
+		SchemaDefs: ir.Schema{"$defs": map[string]interface{}{
+			"Entity": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{"type": "string"},
+				},
+				"required": []interface{}{"id"},
+			},
+			"Animal": map[string]interface{}{"$ref": "#/$defs/Entity"},
+			"Pet": map[string]interface{}{
+				"allOf": []interface{}{
+					map[string]interface{}{"$ref": "#/$defs/Animal"},
+					map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"name":  map[string]interface{}{"type": "string"},
+							"owner": map[string]interface{}{"$ref": "#/$defs/Owner"},
+						},
+						"required": []interface{}{"name"},
+					},
+				},
+			},
+			"Owner": map[string]interface{}{"type": "object"},
+		}},
+	}
+
+	schema, _, err := cf.combineSchemas(route)
+	if err != nil {
+		t.Fatalf("combineSchemas returned error: %v", err)
+	}
+
+	props := extractProperties(t, schema["properties"])
+	for _, name := range []string{"id", "name", "owner", "breed"} {
+		if _, ok := props[name]; !ok {
+			t.Fatalf("expected flattened property %q, got %#v", name, props)
+		}
+	}
+	if _, ok := schema["$ref"]; ok {
+		t.Fatalf("did not expect a leftover $ref, got %#v", schema)
+	}
+
+	req := extractRequired(t, schema["required"])
+	if len(req) != 2 || req[0] != "id" || req[1] != "name" {
+		t.Fatalf("expected required id and name, got %v", req)
+	}
+
+	defs, _ := schema["$defs"].(map[string]interface{})
+	if len(defs) != 1 || defs["Owner"] == nil {
+		t.Fatalf("expected only the Owner definition to remain, got %#v", defs)
+	}
+}
+
+func TestNormalizeSchemaStopsOnRecursiveAllOfReference(t *testing.T) {
+	defs := ir.Schema{"$defs": map[string]interface{}{
+		"Node": map[string]interface{}{
+			"allOf": []interface{}{
+				map[string]interface{}{"$ref": "#/$defs/Node"},
+				map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"value": map[string]interface{}{"type": "string"}},
+				},
+			},
+		},
+	}}
+	schema := ir.Schema{"allOf": []interface{}{map[string]interface{}{"$ref": "#/$defs/Node"}}}
+
+	normalized := normalizeSchemaWithDefs(schema, defs)
+	props := extractProperties(t, normalized["properties"])
+	if _, ok := props["value"]; !ok {
+		t.Fatalf("expected property value, got %#v", normalized)
+	}
+}
+
+func TestMergeAllOfStopsAtMaxDepth(t *testing.T) {
+	schema := ir.Schema{"properties": map[string]interface{}{"leaf": map[string]interface{}{"type": "string"}}}
+	for i := 0; i < maxSchemaDepth*2; i++ {
+		schema = ir.Schema{"allOf": []interface{}{schema}}
+	}
+
+	merged := mergeAllOf(cloneSchema(schema), nil, 0)
+	if _, ok := merged["allOf"]; ok {
+		t.Fatalf("expected the outer allOf to be merged")
+	}
+}
+
 func TestCombineSchemasPropagatesParameterMetadata(t *testing.T) {
 	cf := NewComponentFactory(nil, "")
 
@@ -238,12 +433,70 @@ func TestCombineSchemasPropagatesParameterMetadata(t *testing.T) {
 	if deprecated, _ := paramSchema["deprecated"].(bool); !deprecated {
 		t.Fatalf("expected deprecated flag to be true")
 	}
+	if description, _ := paramSchema["description"].(string); !strings.HasSuffix(description, "(deprecated)") {
+		t.Fatalf("expected the description to note the deprecation, got %q", description)
+	}
 
 	if allowEmpty, _ := paramSchema["x-allowEmptyValue"].(bool); !allowEmpty {
 		t.Fatalf("expected x-allowEmptyValue to be true")
 	}
 }
 
+func TestCombineSchemasMarksParameterRequirement(t *testing.T) {
+	cf := NewComponentFactory(nil, "")
+
+	route := ir.HTTPRoute{
+		Parameters: []ir.ParameterInfo{
+			{Name: "id", In: ir.ParameterInPath, Schema: ir.Schema{"type": "string"}},
+			{Name: "q", In: ir.ParameterInQuery, Required: true, Schema: ir.Schema{"type": "string"}},
+			{Name: "filter", In: ir.ParameterInQuery, Style: "deepObject", Schema: ir.Schema{"$ref": "#/$defs/Filter"}},
+		},
+		SchemaDefs: ir.Schema{"$defs": map[string]interface{}{
+			"Filter": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"status": map[string]interface{}{"type": "string"},
+				},
+			},
+		}},
+	}
+
+	schema, _, err := cf.combineSchemas(route)
+	if err != nil {
+		t.Fatalf("combineSchemas returned error: %v", err)
+	}
+	props := extractProperties(t, schema["properties"])
+
+	for _, name := range []string{"id", "q"} {
+		param := extractSchemaMap(t, props[name])
+		if _, wrapped := param["anyOf"]; wrapped {
+			t.Fatalf("expected required parameter %s not to be nullable, got %#v", name, param)
+		}
+		if param["x-parameter-required"] != true {
+			t.Fatalf("expected x-parameter-required on %s, got %#v", name, param)
+		}
+	}
+	if extractSchemaMap(t, props["q"])["x-allowEmptyValue"] != false {
+		t.Fatalf("expected query parameter to declare x-allowEmptyValue=false")
+	}
+	if required := extractRequired(t, schema["required"]); len(required) != 2 {
+		t.Fatalf("expected id and q to be required, got %v", required)
+	}
+
+	filter := extractSchemaMap(t, props["filter"])
+	if filter["x-parameter-required"] != false {
+		t.Fatalf("expected optional filter, got %#v", filter)
+	}
+	variants, _ := filter["anyOf"].([]interface{})
+	if len(variants) != 2 {
+		t.Fatalf("expected nullable wrapper for optional filter, got %#v", filter)
+	}
+	inner := extractSchemaMap(t, variants[0])
+	if _, ok := extractProperties(t, inner["properties"])["status"]; !ok {
+		t.Fatalf("expected deepObject properties to be inlined, got %#v", inner)
+	}
+}
+
 func TestCombineSchemasPropagatesBodyExamplesToProperties(t *testing.T) {
 	cf := NewComponentFactory(nil, "")
 
@@ -489,6 +742,95 @@ func TestExtractOutputSchemaWrapsAllOfResponse(t *testing.T) {
 	}
 }
 
+func TestExtractOutputSchemaDescribesBinaryResponses(t *testing.T) {
+	cf := NewComponentFactory(nil, "")
+
+	route := ir.HTTPRoute{
+		Responses: map[string]ir.ResponseInfo{
+			"200": {ContentSchemas: map[string]ir.Schema{
+				"application/pdf": {"type": "string", "format": "binary"},
+			}},
+		},
+	}
+
+	schema, wrap := cf.extractOutputSchema(route)
+	if wrap {
+		t.Fatalf("did not expect binary output to be wrapped")
+	}
+	data := extractSchemaMap(t, extractProperties(t, schema["properties"])["data"])
+	if data["contentEncoding"] != "base64" {
+		t.Fatalf("expected base64 data property, got %#v", schema)
+	}
+}
+
+func TestExtractOutputSchemaDescribesResponseHeaders(t *testing.T) {
+	cf := NewComponentFactory(nil, "")
+
+	body := ir.Schema{"type": "object", "properties": map[string]interface{}{"id": map[string]interface{}{"type": "string"}}}
+	route := ir.HTTPRoute{
+		Responses: map[string]ir.ResponseInfo{
+			"201": {
+				ContentSchemas: map[string]ir.Schema{"application/json": body},
+				Headers: map[string]ir.HeaderInfo{
+					"Location":     {Name: "Location", Description: "URL of the new item", Schema: ir.Schema{"type": "string"}},
+					"X-Rate-Limit": {Name: "X-Rate-Limit", Schema: ir.Schema{"type": "integer"}},
+					"Content-Type": {Name: "Content-Type", Schema: ir.Schema{"type": "string"}},
+				},
+			},
+		},
+	}
+
+	schema, _ := cf.extractOutputSchema(route)
+	headers := extractSchemaMap(t, extractProperties(t, schema["properties"])["_headers"])
+	props := extractProperties(t, headers["properties"])
+	if len(props) != 2 {
+		t.Fatalf("expected Location and X-Rate-Limit only, got %#v", props)
+	}
+	if location := extractSchemaMap(t, props["Location"]); location["description"] != "URL of the new item" {
+		t.Fatalf("expected header description, got %#v", location)
+	}
+	if limit := extractSchemaMap(t, props["X-Rate-Limit"]); limit["type"] != "integer" {
+		t.Fatalf("expected typed header schema, got %#v", limit)
+	}
+	if _, leaked := body.Properties()["_headers"]; leaked {
+		t.Fatalf("expected response body schema to stay untouched")
+	}
+}
+
+func TestExtractOutputSchemaDescribesResponseHeadersOfReferencedBody(t *testing.T) {
+	cf := NewComponentFactory(nil, "")
+
+	item := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"id": map[string]interface{}{"type": "string"}},
+	}
+	route := ir.HTTPRoute{
+		Responses: map[string]ir.ResponseInfo{
+			"200": {
+				ContentSchemas: map[string]ir.Schema{"application/json": {"$ref": "#/$defs/Item"}},
+				Headers:        map[string]ir.HeaderInfo{"ETag": {Name: "ETag", Schema: ir.Schema{"type": "string"}}},
+			},
+		},
+		SchemaDefs: ir.Schema{"$defs": map[string]interface{}{"Item": item}},
+	}
+
+	schema, wrap := cf.extractOutputSchema(route)
+	if wrap {
+		t.Fatalf("did not expect a referenced object to be wrapped")
+	}
+	props := extractProperties(t, schema["properties"])
+	if _, ok := props["id"]; !ok {
+		t.Fatalf("expected the referenced body's properties, got %#v", schema)
+	}
+	headers := extractSchemaMap(t, props["_headers"])
+	if _, ok := extractProperties(t, headers["properties"])["ETag"]; !ok {
+		t.Fatalf("expected the ETag header, got %#v", headers)
+	}
+	if _, leaked := item["properties"].(map[string]interface{})["_headers"]; leaked {
+		t.Fatalf("expected the referenced definition to stay untouched")
+	}
+}
+
 func TestDetermineBodyPropertyNameFromTitle(t *testing.T) {
 	cf := NewComponentFactory(nil, "")
 
@@ -518,6 +860,71 @@ func TestDetermineBodyPropertyNameFromTitle(t *testing.T) {
 	}
 }
 
+func TestCombineSchemasSurfacesDiscriminator(t *testing.T) {
+	cf := NewComponentFactory(nil, "")
+
+	route := ir.HTTPRoute{
+		RequestBody: &ir.RequestBodyInfo{
+			Required: true,
+			ContentSchemas: map[string]ir.Schema{
+				"application/json": {
+					"oneOf": []interface{}{
+						map[string]interface{}{"$ref": "#/$defs/Dog"},
+						map[string]interface{}{"$ref": "#/$defs/Cat"},
+					},
+					"discriminator": map[string]interface{}{
+						"propertyName": "petType",
+						"mapping": map[string]interface{}{
+							"dog":    "#/$defs/Dog",
+							"cat":    "#/$defs/Cat",
+							"lizard": "#/$defs/Lizard",
+						},
+					},
+				},
+			},
+		},
+		SchemaDefs: ir.Schema{
+			"$defs": map[string]interface{}{
+				"Dog":    map[string]interface{}{"type": "object"},
+				"Cat":    map[string]interface{}{"type": "object"},
+				"Lizard": map[string]interface{}{"type": "object"},
+				"Unused": map[string]interface{}{"type": "object"},
+			},
+		},
+	}
+
+	schema, _, err := cf.combineSchemas(route)
+	if err != nil {
+		t.Fatalf("combineSchemas returned error: %v", err)
+	}
+
+	body := extractSchemaMap(t, extractProperties(t, schema["properties"])["body"])
+	if _, ok := body["discriminator"]; !ok {
+		t.Fatalf("expected discriminator to be retained: %#v", body)
+	}
+	if _, ok := body["oneOf"]; !ok {
+		t.Fatalf("expected oneOf to be retained: %#v", body)
+	}
+
+	petType := extractSchemaMap(t, extractProperties(t, body["properties"])["petType"])
+	if !reflect.DeepEqual(petType["enum"], []interface{}{"cat", "dog", "lizard"}) {
+		t.Fatalf("unexpected discriminator enum: %#v", petType["enum"])
+	}
+	if required := extractRequired(t, body["required"]); len(required) != 1 || required[0] != "petType" {
+		t.Fatalf("expected petType to be required, got %v", required)
+	}
+
+	defs, _ := schema["$defs"].(map[string]interface{})
+	for _, name := range []string{"Dog", "Cat", "Lizard"} {
+		if _, ok := defs[name]; !ok {
+			t.Fatalf("expected %s definition to survive pruning, got %v", name, defs)
+		}
+	}
+	if _, ok := defs["Unused"]; ok {
+		t.Fatalf("did not expect Unused definition to be present")
+	}
+}
+
 func extractProperties(t *testing.T, value interface{}) map[string]interface{} {
 	t.Helper()
 	switch v := value.(type) {
@@ -591,3 +998,481 @@ func extractExampleValueFromEntry(t *testing.T, entry interface{}) interface{} {
 	}
 	return value
 }
+
+func TestCombineSchemasDescribesEnumValues(t *testing.T) {
+	cf := NewComponentFactory(nil, "")
+
+	route := ir.HTTPRoute{
+		Parameters: []ir.ParameterInfo{
+			{Name: "sort", In: ir.ParameterInQuery, Required: true, Schema: ir.Schema{
+				"type":                "string",
+				"enum":                []interface{}{"asc", "desc"},
+				"x-enum-descriptions": []interface{}{"Oldest first", "Newest first"},
+			}},
+			{Name: "level", In: ir.ParameterInQuery, Required: true, Schema: ir.Schema{
+				"type":        "integer",
+				"enum":        []interface{}{float64(1), float64(2)},
+				"x-enumNames": map[string]interface{}{"1": "Low", "2": "High"},
+			}},
+			{Name: "plain", In: ir.ParameterInQuery, Required: true, Schema: ir.Schema{
+				"type": "string",
+				"enum": []interface{}{"a", "b"},
+			}},
+		},
+	}
+
+	schema, _, err := cf.combineSchemas(route)
+	if err != nil {
+		t.Fatalf("combineSchemas returned error: %v", err)
+	}
+	props := extractProperties(t, schema["properties"])
+
+	sortBranches, _ := extractSchemaMap(t, props["sort"])["oneOf"].([]interface{})
+	expectedSort := []interface{}{
+		map[string]interface{}{"const": "asc", "description": "Oldest first"},
+		map[string]interface{}{"const": "desc", "description": "Newest first"},
+	}
+	if !reflect.DeepEqual(sortBranches, expectedSort) {
+		t.Fatalf("unexpected sort branches: %#v", sortBranches)
+	}
+
+	levelBranches, _ := extractSchemaMap(t, props["level"])["oneOf"].([]interface{})
+	expectedLevel := []interface{}{
+		map[string]interface{}{"const": float64(1), "title": "Low"},
+		map[string]interface{}{"const": float64(2), "title": "High"},
+	}
+	if !reflect.DeepEqual(levelBranches, expectedLevel) {
+		t.Fatalf("unexpected level branches: %#v", levelBranches)
+	}
+
+	if _, ok := extractSchemaMap(t, props["plain"])["oneOf"]; ok {
+		t.Fatalf("expected plain enum to stay unchanged")
+	}
+}
+
+func TestSchemasRespectReadOnlyAndWriteOnly(t *testing.T) {
+	cf := NewComponentFactory(nil, "")
+
+	defs := ir.Schema{"$defs": map[string]interface{}{
+		"Audit": map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"createdAt": map[string]interface{}{"type": "string"}},
+		},
+		"Base": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id": map[string]interface{}{"type": "string", "readOnly": true},
+			},
+			"required": []interface{}{"id"},
+		},
+		"User": map[string]interface{}{
+			"allOf": []interface{}{
+				map[string]interface{}{"$ref": "#/$defs/Base"},
+				map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name":     map[string]interface{}{"type": "string"},
+						"password": map[string]interface{}{"type": "string", "writeOnly": true},
+						"audit":    map[string]interface{}{"$ref": "#/$defs/Audit", "readOnly": true},
+					},
+					"required": []interface{}{"name", "password"},
+				},
+			},
+		},
+	}}
+	route := ir.HTTPRoute{
+		Method: "POST",
+		Path:   "/users",
+		RequestBody: &ir.RequestBodyInfo{
+			Required: true,
+			ContentSchemas: map[string]ir.Schema{"application/json": {
+				"type": "object",
+				"properties": map[string]interface{}{
+					"user": map[string]interface{}{"$ref": "#/$defs/User"},
+				},
+			}},
+		},
+		Responses: map[string]ir.ResponseInfo{
+			"201": {ContentSchemas: map[string]ir.Schema{"application/json": {"$ref": "#/$defs/User"}}},
+		},
+		SchemaDefs: defs,
+	}
+
+	input, _, err := cf.combineSchemas(route)
+	if err != nil {
+		t.Fatalf("combineSchemas returned error: %v", err)
+	}
+	inputDefs, _ := input["$defs"].(map[string]interface{})
+	if _, ok := inputDefs["Audit"]; ok {
+		t.Fatalf("expected Audit to be pruned from input $defs, got %v", inputDefs)
+	}
+	base := extractSchemaMap(t, inputDefs["Base"])
+	if len(extractProperties(t, base["properties"])) != 0 || base["required"] != nil {
+		t.Fatalf("expected readOnly id to be removed from input, got %#v", base)
+	}
+	userBranch := extractSchemaMap(t, extractSchemaMap(t, inputDefs["User"])["allOf"].([]interface{})[1])
+	userProps := extractProperties(t, userBranch["properties"])
+	if _, ok := userProps["password"]; !ok {
+		t.Fatalf("expected writeOnly password in input, got %v", userProps)
+	}
+	if _, ok := userProps["audit"]; ok {
+		t.Fatalf("expected readOnly audit to be removed from input, got %v", userProps)
+	}
+
+	output, _ := cf.extractOutputSchema(route)
+	outputDefs, _ := output["$defs"].(map[string]interface{})
+	outputBranch := extractSchemaMap(t, extractSchemaMap(t, outputDefs["User"])["allOf"].([]interface{})[1])
+	outputProps := extractProperties(t, outputBranch["properties"])
+	if _, ok := outputProps["password"]; ok {
+		t.Fatalf("expected writeOnly password to be removed from output, got %v", outputProps)
+	}
+	if _, ok := outputProps["audit"]; !ok {
+		t.Fatalf("expected readOnly audit in output, got %v", outputProps)
+	}
+	if required := extractRequired(t, outputBranch["required"]); len(required) != 1 || required[0] != "name" {
+		t.Fatalf("expected password dropped from output required, got %v", required)
+	}
+	if _, ok := outputDefs["Audit"]; !ok {
+		t.Fatalf("expected Audit to remain in output $defs")
+	}
+}
+
+func TestCombineSchemasAppliesDefaultExample(t *testing.T) {
+	route := ir.HTTPRoute{
+		OperationID: "createOrder",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{"application/json": {
+				"type": "object",
+				"properties": map[string]interface{}{
+					"sku":      map[string]interface{}{"type": "string"},
+					"quantity": map[string]interface{}{"type": "integer", "default": float64(1)},
+				},
+			}},
+			MediaExampleSets: map[string]map[string]interface{}{"application/json": {
+				"small": map[string]interface{}{"value": map[string]interface{}{"sku": "A-1", "quantity": float64(2)}},
+				"bulk":  map[string]interface{}{"value": map[string]interface{}{"sku": "B-9", "quantity": float64(500)}},
+			}},
+		},
+	}
+
+	schema, _, err := NewComponentFactory(nil, "").
+		WithDefaultExamples(map[string]string{"createOrder": "bulk"}).
+		combineSchemas(route)
+	if err != nil {
+		t.Fatalf("combineSchemas returned error: %v", err)
+	}
+	props := extractProperties(t, schema["properties"])
+	if sku := extractSchemaMap(t, props["sku"]); sku["default"] != "B-9" {
+		t.Fatalf("expected bulk example sku as default, got %#v", sku)
+	}
+	if quantity := extractSchemaMap(t, props["quantity"]); quantity["default"] != float64(1) {
+		t.Fatalf("expected declared default to win, got %#v", quantity)
+	}
+
+	unconfigured, _, err := NewComponentFactory(nil, "").combineSchemas(route)
+	if err != nil {
+		t.Fatalf("combineSchemas returned error: %v", err)
+	}
+	if _, ok := extractSchemaMap(t, extractProperties(t, unconfigured["properties"])["sku"])["default"]; ok {
+		t.Fatalf("expected no default without WithDefaultExamples")
+	}
+}
+
+func TestCombineSchemasHidesConstValues(t *testing.T) {
+	cf := NewComponentFactory(nil, "")
+
+	route := ir.HTTPRoute{
+		Parameters: []ir.ParameterInfo{
+			{Name: "api-version", In: ir.ParameterInHeader, Required: true, Schema: ir.Schema{"type": "string", "const": "2024-01-01"}},
+			{Name: "q", In: ir.ParameterInQuery, Schema: ir.Schema{"type": "string"}},
+			{Name: "channel", In: ir.ParameterInQuery, Schema: ir.Schema{"$ref": "#/$defs/Channel"}},
+		},
+		RequestBody: &ir.RequestBodyInfo{
+			Required: true,
+			ContentSchemas: map[string]ir.Schema{
+				"application/json": {
+					"type":     "object",
+					"required": []interface{}{"kind", "name", "source"},
+					"properties": map[string]interface{}{
+						"kind":   map[string]interface{}{"type": "string", "const": "user"},
+						"name":   map[string]interface{}{"type": "string"},
+						"source": map[string]interface{}{"$ref": "#/$defs/Channel"},
+					},
+				},
+			},
+		},
+		SchemaDefs: ir.Schema{"$defs": map[string]interface{}{
+			"Channel": map[string]interface{}{"type": "string", "const": "api"},
+		}},
+	}
+
+	schema, paramMap, err := cf.combineSchemas(route)
+	if err != nil {
+		t.Fatalf("combineSchemas returned error: %v", err)
+	}
+
+	props := schema["properties"].(map[string]interface{})
+	if _, ok := props["api-version"]; ok {
+		t.Fatalf("expected the const header to be hidden, got %v", props)
+	}
+	if _, ok := props["kind"]; ok {
+		t.Fatalf("expected the const body property to be hidden, got %v", props)
+	}
+	if _, ok := props["channel"]; ok {
+		t.Fatalf("expected the referenced const parameter to be hidden, got %v", props)
+	}
+	if _, ok := props["source"]; ok {
+		t.Fatalf("expected the referenced const body property to be hidden, got %v", props)
+	}
+	if !reflect.DeepEqual(schema["required"], []string{"name"}) {
+		t.Fatalf("expected only name to be required, got %v", schema["required"])
+	}
+	if _, ok := paramMap["api-version"]; !ok {
+		t.Fatalf("expected the const header to stay mapped for the request builder")
+	}
+}
+
+func TestExtractOutputSchemaUsesPinnedStatus(t *testing.T) {
+	route := ir.HTTPRoute{
+		OperationID: "runReport",
+		Responses: map[string]ir.ResponseInfo{
+			"200": {ContentSchemas: map[string]ir.Schema{"application/json": {
+				"type":       "object",
+				"properties": map[string]interface{}{"rows": map[string]interface{}{"type": "array"}},
+			}}},
+			"202": {ContentSchemas: map[string]ir.Schema{"application/json": {
+				"type":       "object",
+				"properties": map[string]interface{}{"jobId": map[string]interface{}{"type": "string"}},
+			}}},
+		},
+	}
+
+	cf := NewComponentFactory(nil, "")
+	schema, _ := cf.extractOutputSchema(route)
+	if _, ok := extractProperties(t, schema["properties"])["rows"]; !ok || cf.outputStatus(route) != "200" {
+		t.Fatalf("expected the 200 schema by default, got %v", schema)
+	}
+
+	cf = NewComponentFactory(nil, "").WithOutputSchemaStatuses(map[string]string{"runReport": "202"})
+	schema, _ = cf.extractOutputSchema(route)
+	if _, ok := extractProperties(t, schema["properties"])["jobId"]; !ok || cf.outputStatus(route) != "202" {
+		t.Fatalf("expected the pinned 202 schema, got %v", schema)
+	}
+
+	cf = NewComponentFactory(nil, "").WithOutputSchemaStatuses(map[string]string{"runReport": "201"})
+	if status := cf.outputStatus(route); status != "200" {
+		t.Fatalf("expected an undeclared pin to fall back to 200, got %q", status)
+	}
+}
+
+func TestCombineSchemasAppliesParameterRenames(t *testing.T) {
+	route := ir.HTTPRoute{
+		OperationID: "updateUser",
+		Parameters: []ir.ParameterInfo{
+			{Name: "id", In: ir.ParameterInPath, Required: true, Schema: ir.Schema{"type": "string"}},
+		},
+		RequestBody: &ir.RequestBodyInfo{
+			Required: true,
+			ContentSchemas: map[string]ir.Schema{
+				"application/json": {
+					"type":     "object",
+					"required": []interface{}{"id"},
+					"properties": map[string]interface{}{
+						"id":   map[string]interface{}{"type": "string"},
+						"name": map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+
+	cf := NewComponentFactory(nil, "").WithParameterRenames(map[string]map[string]string{
+		"updateUser": {"id__path": "userId", "id": "recordId", "missing": "ignored"},
+	})
+	schema, paramMap, err := cf.combineSchemas(route)
+	if err != nil {
+		t.Fatalf("combineSchemas returned error: %v", err)
+	}
+
+	props := schema["properties"].(map[string]interface{})
+	for _, name := range []string{"id", "id__path", "ignored"} {
+		if _, ok := props[name]; ok {
+			t.Fatalf("expected %q to be renamed away, got %v", name, props)
+		}
+	}
+	if _, ok := props["userId"]; !ok {
+		t.Fatalf("expected userId property, got %v", props)
+	}
+	if mapping := paramMap["userId"]; mapping.OpenAPIName != "id" || mapping.Location != ir.ParameterInPath {
+		t.Fatalf("expected userId to map to the id path parameter, got %+v", mapping)
+	}
+	if mapping := paramMap["recordId"]; mapping.OpenAPIName != "id" || mapping.Location != "body" {
+		t.Fatalf("expected recordId to map to the id body property, got %+v", mapping)
+	}
+	if !reflect.DeepEqual(schema["required"], []string{"userId", "recordId"}) {
+		t.Fatalf("expected renamed required arguments, got %v", schema["required"])
+	}
+
+	cf = NewComponentFactory(nil, "").WithParameterRenames(map[string]map[string]string{
+		"updateUser": {"id__path": "name"},
+	})
+	if _, _, err := cf.combineSchemas(route); err == nil || !strings.Contains(err.Error(), "already used") {
+		t.Fatalf("expected a rename onto an existing argument to fail, got %v", err)
+	}
+}
+
+func TestCombineSchemasKeepsMapValueConstraints(t *testing.T) {
+	route := ir.HTTPRoute{
+		RequestBody: &ir.RequestBodyInfo{
+			Required: true,
+			ContentSchemas: map[string]ir.Schema{
+				"application/json": {
+					"type": "object",
+					"allOf": []interface{}{
+						map[string]interface{}{"patternProperties": map[string]interface{}{
+							"^x-": map[string]interface{}{"type": "string", "maxLength": 5},
+						}},
+						map[string]interface{}{"patternProperties": map[string]interface{}{
+							"^label-": map[string]interface{}{"$ref": "#/$defs/Label"},
+						}},
+					},
+					"propertyNames":        map[string]interface{}{"pattern": "^[a-z-]+$"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		SchemaDefs: ir.Schema{"$defs": map[string]interface{}{
+			"Label": map[string]interface{}{
+				"type":       "object",
+				"required":   []interface{}{"value"},
+				"properties": map[string]interface{}{"value": map[string]interface{}{"type": "string"}},
+			},
+		}},
+	}
+
+	schema, _, err := NewComponentFactory(nil, "").combineSchemas(route)
+	if err != nil {
+		t.Fatalf("combineSchemas returned error: %v", err)
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("failed to marshal schema: %v", err)
+	}
+	compiled, err := jsonschema.CompileString("input.json", string(data))
+	if err != nil {
+		t.Fatalf("failed to compile schema: %v", err)
+	}
+
+	for _, tc := range []struct {
+		args  string
+		valid bool
+	}{
+		{`{"body": {"x-team": "ops", "label-env": {"value": "prod"}}}`, true},
+		{`{"body": {"x-team": "operations"}}`, false},
+		{`{"body": {"label-env": {"name": "prod"}}}`, false},
+		{`{"body": {"other": "value"}}`, false},
+		{`{"body": {"x-Team": "ops"}}`, false},
+	} {
+		var args interface{}
+		if err := json.Unmarshal([]byte(tc.args), &args); err != nil {
+			t.Fatalf("bad fixture %s: %v", tc.args, err)
+		}
+		if err := compiled.Validate(args); (err == nil) != tc.valid {
+			t.Fatalf("validating %s: expected valid=%t, got %v", tc.args, tc.valid, err)
+		}
+	}
+}
+
+func TestCreateToolEmitsDraft07InputSchema(t *testing.T) {
+	route := ir.HTTPRoute{
+		Method:      "POST",
+		Path:        "/shapes",
+		OperationID: "createShape",
+		RequestBody: &ir.RequestBodyInfo{
+			ContentSchemas: map[string]ir.Schema{
+				"application/json": {
+					"type": "object",
+					"properties": map[string]interface{}{
+						"owner": map[string]interface{}{"$ref": "#/$defs/Owner"},
+						"point": map[string]interface{}{
+							"type":        "array",
+							"prefixItems": []interface{}{map[string]interface{}{"type": "number"}, map[string]interface{}{"type": "number"}},
+							"items":       false,
+						},
+					},
+				},
+			},
+			Required: true,
+		},
+		SchemaDefs: ir.Schema{"$defs": map[string]interface{}{
+			"Owner": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}},
+				"required":   []interface{}{"name"},
+			},
+		}},
+	}
+
+	tool, err := NewComponentFactory(nil, "https://api.example.com").
+		WithInputSchemaDialect(Draft07).
+		CreateTool(route, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateTool returned error: %v", err)
+	}
+	raw := tool.Tool().RawInputSchema
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("invalid input schema: %v", err)
+	}
+	if schema["$schema"] != "http://json-schema.org/draft-07/schema#" {
+		t.Fatalf("expected draft-07 $schema, got %v", schema["$schema"])
+	}
+	if _, ok := schema["$defs"]; ok || strings.Contains(string(raw), "#/$defs/") {
+		t.Fatalf("expected no $defs left, got %s", raw)
+	}
+	defs, _ := schema["definitions"].(map[string]interface{})
+	if defs["Owner"] == nil {
+		t.Fatalf("expected Owner under definitions, got %s", raw)
+	}
+	props := extractProperties(t, schema["properties"])
+	if ref := props["owner"].(map[string]interface{})["$ref"]; ref != "#/definitions/Owner" {
+		t.Fatalf("expected owner to reference definitions, got %v", ref)
+	}
+	point := props["point"].(map[string]interface{})
+	if items, ok := point["items"].([]interface{}); !ok || len(items) != 2 || point["additionalItems"] != false {
+		t.Fatalf("expected tuple items, got %#v", point)
+	}
+	if _, ok := point["prefixItems"]; ok {
+		t.Fatalf("expected prefixItems to be removed, got %#v", point)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft7
+	if err := compiler.AddResource("schema.json", strings.NewReader(string(raw))); err != nil {
+		t.Fatalf("add resource: %v", err)
+	}
+	validator, err := compiler.Compile("schema.json")
+	if err != nil {
+		t.Fatalf("draft-07 compile failed: %v", err)
+	}
+	valid := map[string]interface{}{"owner": map[string]interface{}{"name": "ada"}, "point": []interface{}{1.0, 2.0}}
+	if err := validator.Validate(valid); err != nil {
+		t.Fatalf("expected valid arguments, got %v", err)
+	}
+	for name, args := range map[string]map[string]interface{}{
+		"missing owner name": {"owner": map[string]interface{}{}},
+		"extra tuple item":   {"point": []interface{}{1.0, 2.0, 3.0}},
+	} {
+		if err := validator.Validate(args); err == nil {
+			t.Fatalf("%s: expected draft-07 validation error", name)
+		}
+		result, err := tool.Run(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}})
+		if err != nil || result == nil || !result.IsError {
+			t.Fatalf("%s: expected the tool to reject the arguments, got %+v (%v)", name, result, err)
+		}
+		if text, ok := result.Content[0].(mcp.TextContent); !ok || !strings.HasPrefix(text.Text, "Invalid arguments") {
+			t.Fatalf("%s: expected an argument validation error, got %+v", name, result.Content)
+		}
+	}
+}
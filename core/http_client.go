@@ -3,6 +3,8 @@ package openapimcp
 import (
 	"net/http"
 	"time"
+
+	"github.com/specx2/openapi-mcp/core/executor"
 )
 
 // HTTPClientConfig describes reusable HTTP client settings for OpenAPI MCP tooling.
@@ -11,3 +13,21 @@ type HTTPClientConfig struct {
 	Timeout time.Duration
 	Headers http.Header
 }
+
+// interceptingClient applies request interceptors in front of a caller-supplied client.
+type interceptingClient struct {
+	next         executor.HTTPClient
+	interceptors []executor.RequestInterceptor
+}
+
+func (c *interceptingClient) Do(req *http.Request) (*http.Response, error) {
+	for _, intercept := range c.interceptors {
+		if intercept == nil {
+			continue
+		}
+		if err := intercept(req); err != nil {
+			return nil, err
+		}
+	}
+	return c.next.Do(req)
+}
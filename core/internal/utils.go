@@ -10,7 +10,10 @@ import (
 
 type contextKey string
 
-const mcpHeadersKey contextKey = "mcp_headers"
+const (
+	mcpHeadersKey contextKey = "mcp_headers"
+	dryRunKey     contextKey = "dry_run"
+)
 
 func GetMCPHeaders(ctx context.Context) map[string]string {
 	if headers, ok := ctx.Value(mcpHeadersKey).(map[string]string); ok {
@@ -23,6 +26,15 @@ func SetMCPHeaders(ctx context.Context, headers map[string]string) context.Conte
 	return context.WithValue(ctx, mcpHeadersKey, headers)
 }
 
+func IsDryRun(ctx context.Context) bool {
+	enabled, _ := ctx.Value(dryRunKey).(bool)
+	return enabled
+}
+
+func SetDryRun(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, dryRunKey, enabled)
+}
+
 func ParseArguments(request mcp.CallToolRequest) (map[string]interface{}, error) {
 	args := request.GetArguments()
 	if args == nil {
@@ -5,6 +5,7 @@ type ParameterInfo struct {
 	In              string
 	Required        bool
 	Schema          Schema
+	Content         map[string]Schema
 	Description     string
 	Explode         *bool
 	Style           string
@@ -14,6 +15,17 @@ type ParameterInfo struct {
 	Example         interface{}
 	Examples        map[string]interface{}
 	Extensions      map[string]interface{}
+	Cookie          *CookieAttributes
+}
+
+// CookieAttributes are the attributes a cookie parameter declares through the
+// x-cookie extension, e.g. `x-cookie: {secure: true, sameSite: Strict}`.
+type CookieAttributes struct {
+	Secure   bool
+	HttpOnly bool
+	SameSite string
+	Path     string
+	Domain   string
 }
 
 const (
@@ -4,6 +4,7 @@ type RequestBodyInfo struct {
 	Required         bool
 	ContentSchemas   map[string]Schema
 	ContentOrder     []string
+	PropertyOrder    map[string][]string
 	Encodings        map[string]map[string]EncodingInfo
 	Description      string
 	MediaExamples    map[string]interface{}
@@ -3,6 +3,7 @@ package ir
 type ResponseInfo struct {
 	Description      string
 	ContentSchemas   map[string]Schema
+	Headers          map[string]HeaderInfo
 	MediaExamples    map[string]interface{}
 	MediaExampleSets map[string]map[string]interface{}
 	MediaExtensions  map[string]map[string]interface{}
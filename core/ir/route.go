@@ -1,11 +1,14 @@
 package ir
 
+import "time"
+
 type HTTPRoute struct {
 	Path           string
 	Method         string
 	OperationID    string
 	Summary        string
 	Description    string
+	Deprecated     bool
 	Tags           []string
 	Parameters     []ParameterInfo
 	RequestBody    *RequestBodyInfo
@@ -15,6 +18,8 @@ type HTTPRoute struct {
 	OpenAPIVersion string
 	ParameterMap   map[string]ParamMapping
 	Callbacks      []CallbackInfo
+	Servers        []ServerInfo
+	Timeout        time.Duration
 }
 
 type ParamMapping struct {
@@ -43,12 +43,21 @@ func (s Schema) Required() []string {
 
 func (s Schema) Definitions() map[string]Schema {
 	defs := make(map[string]Schema)
-	if d, ok := s["$defs"].(map[string]interface{}); ok {
+	switch d := s["$defs"].(type) {
+	case map[string]interface{}:
 		for k, v := range d {
 			if schema, ok := v.(map[string]interface{}); ok {
 				defs[k] = schema
+				continue
+			}
+			if nested, ok := v.(Schema); ok {
+				defs[k] = nested
 			}
 		}
+	case map[string]Schema:
+		for k, v := range d {
+			defs[k] = v
+		}
 	}
 	return defs
 }
@@ -0,0 +1,14 @@
+package ir
+
+// SecurityScheme is an entry of components.securitySchemes. For apiKey schemes
+// In is header, query or cookie and ParamName is the header, query parameter or
+// cookie name carrying the key; for http schemes Scheme is e.g. "bearer".
+type SecurityScheme struct {
+	Name         string
+	Type         string
+	In           string
+	ParamName    string
+	Scheme       string
+	BearerFormat string
+	Description  string
+}
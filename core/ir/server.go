@@ -0,0 +1,15 @@
+package ir
+
+// ServerInfo describes an OpenAPI server entry declared on an operation or path item.
+type ServerInfo struct {
+	URL         string
+	Description string
+	Variables   map[string]ServerVariable
+}
+
+// ServerVariable holds the substitution rules for a server URL template variable.
+type ServerVariable struct {
+	Default     string
+	Enum        []string
+	Description string
+}
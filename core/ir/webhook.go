@@ -0,0 +1,13 @@
+package ir
+
+// WebhookInfo describes an OpenAPI 3.1 webhook, a request the API sends to its
+// subscribers. It is kept as documentation of the event payloads; no tool is
+// generated for it.
+type WebhookInfo struct {
+	Name        string
+	Summary     string
+	Description string
+	Operations  []CallbackOperation
+	SchemaDefs  Schema
+	Extensions  map[string]interface{}
+}
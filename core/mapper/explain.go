@@ -0,0 +1,38 @@
+package mapper
+
+import "github.com/specx2/openapi-mcp/core/ir"
+
+// MappingExplanation records why a route became the MCP type it did.
+type MappingExplanation struct {
+	Method      string
+	Path        string
+	OperationID string
+	// ExcludedByFilter is set when an exclude filter dropped the route before
+	// any rule was consulted.
+	ExcludedByFilter bool
+	// RuleIndex is the position of the first matching RouteMap among the
+	// mapper's rules, the caller's rules first and then the defaults, or -1
+	// when no rule matched and the route fell back to a tool.
+	RuleIndex   int
+	DefaultRule bool
+	RuleMethods []string
+	RulePattern string
+	RuleTags    []string
+	// RuleType is the type chosen by the rules, before the map func ran.
+	RuleType MCPType
+	// Overridden is set when the map func returned its own decision.
+	Overridden bool
+	// MCPType is the final type; MCPTypeExclude means no component is created.
+	MCPType MCPType
+}
+
+// Explain maps routes like MapRoutes, excluded routes included, and reports for
+// each one the rule that matched and whether the map func overrode it.
+func (rm *RouteMapper) Explain(routes []ir.HTTPRoute) []MappingExplanation {
+	explanations := make([]MappingExplanation, 0, len(routes))
+	for _, route := range routes {
+		_, explanation := rm.decide(route)
+		explanations = append(explanations, explanation)
+	}
+	return explanations
+}
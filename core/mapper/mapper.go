@@ -9,10 +9,15 @@ import (
 
 type RouteMapFunc func(route ir.HTTPRoute, decision RouteDecision) *RouteDecision
 
+// RouteFilterFunc reports whether a route should be dropped before component creation.
+type RouteFilterFunc func(route ir.HTTPRoute) bool
+
 type RouteMapper struct {
-	routeMaps []RouteMap
-	mapFunc   RouteMapFunc
-	globTags  []string
+	routeMaps      []RouteMap
+	mapFunc        RouteMapFunc
+	globTags       []string
+	excludeFilters []RouteFilterFunc
+	customRules    int
 }
 
 func NewRouteMapper(routeMaps []RouteMap) *RouteMapper {
@@ -20,7 +25,8 @@ func NewRouteMapper(routeMaps []RouteMap) *RouteMapper {
 	copy(clone, routeMaps)
 	clone = append(clone, DefaultRouteMappings()...)
 	return &RouteMapper{
-		routeMaps: clone,
+		routeMaps:   clone,
+		customRules: len(routeMaps),
 	}
 }
 
@@ -34,6 +40,52 @@ func (rm *RouteMapper) WithGlobalTags(tags ...string) *RouteMapper {
 	return rm
 }
 
+// WithExcludeFilter drops every route for which filter returns true. Filters run
+// before route maps and the map func, so excluded routes never become components.
+func (rm *RouteMapper) WithExcludeFilter(filters ...RouteFilterFunc) *RouteMapper {
+	for _, filter := range filters {
+		if filter != nil {
+			rm.excludeFilters = append(rm.excludeFilters, filter)
+		}
+	}
+	return rm
+}
+
+func (rm *RouteMapper) excluded(route ir.HTTPRoute) bool {
+	for _, filter := range rm.excludeFilters {
+		if filter(route) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExcludeTags builds a filter matching routes that carry any of the given tags.
+func ExcludeTags(tags ...string) RouteFilterFunc {
+	return func(route ir.HTTPRoute) bool {
+		for _, routeTag := range route.Tags {
+			for _, tag := range tags {
+				if routeTag == tag {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// ExcludeMethods builds a filter matching routes using any of the given HTTP methods.
+func ExcludeMethods(methods ...string) RouteFilterFunc {
+	return func(route ir.HTTPRoute) bool {
+		for _, method := range methods {
+			if strings.EqualFold(route.Method, method) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 func (rm *RouteMapper) matches(route ir.HTTPRoute, mapping RouteMap) bool {
 	if !rm.matchesMethods(route.Method, mapping.Methods) {
 		return false
@@ -111,6 +163,24 @@ type RouteDecision struct {
 }
 
 func (rm *RouteMapper) MapRouteDecision(route ir.HTTPRoute) RouteDecision {
+	decision, _ := rm.decide(route)
+	return decision
+}
+
+// decide maps route and records which rule, if any, produced the decision.
+func (rm *RouteMapper) decide(route ir.HTTPRoute) (RouteDecision, MappingExplanation) {
+	explanation := MappingExplanation{
+		Method:      route.Method,
+		Path:        route.Path,
+		OperationID: route.OperationID,
+		RuleIndex:   -1,
+	}
+	if rm.excluded(route) {
+		explanation.ExcludedByFilter = true
+		explanation.MCPType = MCPTypeExclude
+		return RouteDecision{MCPType: MCPTypeExclude}, explanation
+	}
+
 	decision := RouteDecision{
 		MCPType: MCPTypeTool,
 		Tags:    rm.combineTags(route, nil),
@@ -128,18 +198,28 @@ func (rm *RouteMapper) MapRouteDecision(route ir.HTTPRoute) RouteDecision {
 			clone := *mapping.Annotations
 			decision.Annotations = &clone
 		}
+		explanation.RuleIndex = idx
+		explanation.DefaultRule = idx >= rm.customRules
+		explanation.RuleMethods = append([]string(nil), mapping.Methods...)
+		explanation.RuleTags = append([]string(nil), mapping.Tags...)
+		if mapping.PathPattern != nil {
+			explanation.RulePattern = mapping.PathPattern.String()
+		}
 		break
 	}
+	explanation.RuleType = decision.MCPType
 
 	if rm.mapFunc != nil {
 		if override := rm.mapFunc(route, decision); override != nil {
 			decision = *override
 			decision.Tags = uniqueStrings(decision.Tags)
+			explanation.Overridden = true
 		}
 	}
 
 	decision.Tags = uniqueStrings(decision.Tags)
-	return decision
+	explanation.MCPType = decision.MCPType
+	return decision, explanation
 }
 
 func (rm *RouteMapper) MapRoute(route ir.HTTPRoute) MCPType {
@@ -80,3 +80,59 @@ func boolPtr(v bool) *bool {
 	value := v
 	return &value
 }
+
+func TestRouteMapperExcludeFilters(t *testing.T) {
+	routes := []ir.HTTPRoute{
+		{Method: "GET", Path: "/items", Tags: []string{"public"}},
+		{Method: "GET", Path: "/admin/stats", Tags: []string{"internal"}},
+		{Method: "DELETE", Path: "/items/{id}"},
+	}
+
+	mapper := NewRouteMapper(nil).WithExcludeFilter(ExcludeTags("internal"), ExcludeMethods("delete"))
+	mapper = mapper.WithMapFunc(func(route ir.HTTPRoute, decision RouteDecision) *RouteDecision {
+		decision.MCPType = MCPTypeTool
+		return &decision
+	})
+
+	mapped := mapper.MapRoutes(routes)
+	if len(mapped) != 1 || mapped[0].Route.Path != "/items" {
+		t.Fatalf("expected only /items to survive filtering, got %+v", mapped)
+	}
+}
+
+func TestRouteMapperExplain(t *testing.T) {
+	routes := []ir.HTTPRoute{
+		{Method: "GET", Path: "/items", OperationID: "listItems"},
+		{Method: "GET", Path: "/items/{id}", OperationID: "getItem"},
+		{Method: "DELETE", Path: "/items/{id}", OperationID: "deleteItem"},
+	}
+
+	mapper := NewRouteMapper([]RouteMap{{
+		Methods:     []string{"GET"},
+		PathPattern: regexp.MustCompile(`^/items$`),
+		MCPType:     MCPTypeResource,
+	}}).WithMapFunc(func(route ir.HTTPRoute, decision RouteDecision) *RouteDecision {
+		if route.OperationID == "getItem" {
+			decision.MCPType = MCPTypeResourceTemplate
+			return &decision
+		}
+		return nil
+	}).WithExcludeFilter(ExcludeMethods("DELETE"))
+
+	explanations := mapper.Explain(routes)
+	if len(explanations) != 3 {
+		t.Fatalf("expected an explanation per route, got %d", len(explanations))
+	}
+
+	list := explanations[0]
+	if list.RuleIndex != 0 || list.DefaultRule || list.RulePattern != `^/items$` || list.MCPType != MCPTypeResource || list.Overridden {
+		t.Fatalf("unexpected explanation for listItems: %+v", list)
+	}
+	get := explanations[1]
+	if get.RuleIndex != 1 || !get.DefaultRule || get.RuleType != MCPTypeTool || !get.Overridden || get.MCPType != MCPTypeResourceTemplate {
+		t.Fatalf("unexpected explanation for getItem: %+v", get)
+	}
+	if del := explanations[2]; !del.ExcludedByFilter || del.MCPType != MCPTypeExclude || del.RuleIndex != -1 {
+		t.Fatalf("unexpected explanation for deleteItem: %+v", del)
+	}
+}
@@ -2,9 +2,11 @@ package openapimcp
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/specx2/openapi-mcp/core/executor"
 	"github.com/specx2/openapi-mcp/core/factory"
+	"github.com/specx2/openapi-mcp/core/ir"
 	"github.com/specx2/openapi-mcp/core/mapper"
 	"github.com/specx2/openapi-mcp/core/parser"
 )
@@ -22,8 +24,153 @@ type ServerOptions struct {
 	ServerName    string
 	ServerVersion string
 	SpecURL       string
+
+	ServerVariables         map[string]string
+	DisableOperationServers bool
+	DisableHTTPLine         bool
+	Pagination              *PaginationConfig
+	RequestCompressionMin   int
+	OperationTimeout        func(route ir.HTTPRoute) time.Duration
+	ExcludeFilters          []mapper.RouteFilterFunc
+	DryRun                  bool
+	HeaderPropagation       *executor.HeaderPropagation
+	DisableFormatAssertion  bool
+	ResponseTransformer     ResponseTransformer
+	OperationAllowlist      []string
+	OperationDenylist       []string
+	ExcludeDeprecated       bool
+	NameCollisionStrategy   NameCollisionStrategy
+	SpecAlias               string
+	AdditionalProperties    AdditionalPropertiesMode
+	RequestInterceptors     []RequestInterceptor
+	DefaultExamples         map[string]string
+	SchemaOverride          SchemaOverride
+	OutputSchemaOverride    SchemaOverride
+	Logger                  Logger
+	MaxRequestBytes         int64
+	MaxResponseBytes        int64
+	ContentTypeVariants     bool
+	Validation              bool
+	BaseURLResolver         BaseURLResolver
+	ResponseCacheTTL        time.Duration
+	ResponseCacheMaxEntries int
+	UserAgent               string
+	WebhookResources        bool
+	AuditMetadata           bool
+	PathPrefix              string
+	DefaultAccept           string
+	CSVOptions              *executor.CSVOptions
+	APIKeysFromEnv          []APIKeyFromEnv
+	SpecPatches             []SpecPatch
+	JoinCookieArrays        bool
+	MaxURLLength            int
+	NormalizeDates          bool
+	Tracer                  Tracer
+	UnwrapSingleProperty    bool
+	LenientSpecParsing      bool
+	OutputSchemaStatuses    map[string]string
+	RequestSigner           RequestSigner
+	ParameterRenames        map[string]map[string]string
+	StreamingProgress       bool
+	MaxDescriptionLength    int
+	QueryArrayFormat        QueryArrayFormat
+	ResultEnvelope          bool
+	ServerVariableResolver  ServerVariableResolver
+	InputSchemaDialect      SchemaDialect
+	MaxToolCount            int
+	ToolPriority            ToolPriority
+	CompressedResponses     bool
+	FileUploadRoot          string
+	MaxSpecBytes            int64
+	ExternalExamples        bool
+}
+
+// SpecPatch is a patch applied to the spec passed to NewServer before parsing.
+// JSONPatch selects RFC 6902 JSON Patch; otherwise it is an RFC 7386 merge patch.
+type SpecPatch struct {
+	Patch     []byte
+	JSONPatch bool
 }
 
+// PaginationConfig configures automatic next-page following for GET tools.
+type PaginationConfig = executor.PaginationConfig
+
+// NameCollisionStrategy decides how duplicate tool/resource names across specs are resolved.
+type NameCollisionStrategy = factory.NameCollisionStrategy
+
+// NameCollision describes a component that was renamed to keep names unique.
+type NameCollision = factory.NameCollision
+
+const (
+	NameCollisionSuffix = factory.NameCollisionSuffix
+	NameCollisionPrefix = factory.NameCollisionPrefix
+)
+
+// SchemaDialect selects the JSON Schema draft of tool input schemas.
+type SchemaDialect = factory.SchemaDialect
+
+const (
+	Draft2020 = factory.Draft2020
+	Draft07   = factory.Draft07
+)
+
+// AdditionalPropertiesMode controls how undeclared request body properties are validated.
+type AdditionalPropertiesMode = executor.AdditionalPropertiesMode
+
+const (
+	AdditionalPropertiesStrict = executor.AdditionalPropertiesStrict
+	AdditionalPropertiesAllow  = executor.AdditionalPropertiesAllow
+	AdditionalPropertiesStrip  = executor.AdditionalPropertiesStrip
+	AdditionalPropertiesForbid = executor.AdditionalPropertiesForbid
+)
+
+// RequestInterceptor runs on every outgoing upstream request just before it is sent.
+type RequestInterceptor = executor.RequestInterceptor
+
+// RequestSigner signs each final upstream request right before it is sent.
+type RequestSigner = executor.RequestSigner
+
+// AWSCredentials are the keys used by WithAWSSigV4.
+type AWSCredentials = executor.AWSCredentials
+
+// SchemaOverride patches a generated tool schema for one operation.
+type SchemaOverride = factory.SchemaOverride
+
+// Logger receives structured, levelled log records (key/value fields).
+type Logger = executor.Logger
+
+// Tracer starts spans around upstream calls; see executor.Tracer.
+type Tracer = executor.Tracer
+
+// BaseURLResolver computes a tool call's upstream base URL from its context.
+type BaseURLResolver = executor.BaseURLResolver
+
+// ServerVariableResolver supplies a server variable's value from a tool call's context.
+type ServerVariableResolver = executor.ServerVariableResolver
+
+// ResponseTransformer reshapes a decoded tool response before output validation.
+type ResponseTransformer = executor.ResponseTransformer
+
+// QueryArrayFormat is how array query parameters without a declared style are sent.
+type QueryArrayFormat = executor.QueryArrayFormat
+
+// Array query formats for WithDefaultQueryArrayFormat.
+const (
+	QueryArrayRepeat         = executor.QueryArrayRepeat
+	QueryArrayComma          = executor.QueryArrayComma
+	QueryArrayBrackets       = executor.QueryArrayBrackets
+	QueryArraySpaceDelimited = executor.QueryArraySpaceDelimited
+	QueryArrayPipeDelimited  = executor.QueryArrayPipeDelimited
+)
+
+// ContentEncoder writes request bodies of a custom media type; register one
+// with executor.RegisterContentEncoder.
+type ContentEncoder = executor.ContentEncoder
+
+// ContentDecoder reads responses of a custom media type; register one with
+// executor.RegisterContentDecoder.
+type ContentDecoder = executor.ContentDecoder
+
 func defaultServerOptions() *ServerOptions {
 	return &ServerOptions{
 		HTTPClient:    executor.NewDefaultHTTPClient(),
@@ -31,6 +178,7 @@ func defaultServerOptions() *ServerOptions {
 		RouteMaps:     mapper.DefaultRouteMappings(),
 		ServerName:    "openapi-mcp-server",
 		ServerVersion: "1.0.0",
+		Logger:        executor.NopLogger(),
 	}
 }
 
@@ -48,6 +196,9 @@ func WithHTTPClientConfig(cfg *HTTPClientConfig) ServerOption {
 	}
 }
 
+// WithBaseURL sets the upstream base URL. Operations that declare their own absolute
+// servers still target those hosts; use WithOperationServers(false) to force every
+// request through this base URL (e.g. when pointing at a mock or staging host).
 func WithBaseURL(url string) ServerOption {
 	return func(opts *ServerOptions) {
 		opts.BaseURL = url
@@ -102,3 +253,563 @@ func WithSpecURL(specURL string) ServerOption {
 		opts.SpecURL = specURL
 	}
 }
+
+// WithServerVariables overrides operation server variables such as {region}.
+// Values are validated against the variable's enum when one is declared.
+func WithServerVariables(vars map[string]string) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.ServerVariables = vars
+	}
+}
+
+// WithOperationServers controls whether operation/path-item servers take precedence over the base URL.
+func WithOperationServers(enabled bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.DisableOperationServers = !enabled
+	}
+}
+
+// WithPagination makes GET tools follow next-page cursors (or Link headers) and
+// return the concatenated items as a single result.
+func WithPagination(cfg PaginationConfig) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.Pagination = &cfg
+	}
+}
+
+// WithRequestCompression gzips JSON, XML and text request bodies of at least minBytes
+// and marks them with Content-Encoding: gzip. Multipart and form bodies are never compressed.
+func WithRequestCompression(minBytes int) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.RequestCompressionMin = minBytes
+	}
+}
+
+// WithOperationTimeout computes a per-operation timeout. Returning zero falls back
+// to the operation's x-timeout-seconds extension, then to the client timeout.
+func WithOperationTimeout(fn func(route ir.HTTPRoute) time.Duration) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.OperationTimeout = fn
+	}
+}
+
+// WithExcludeFilter drops matching operations entirely, e.g.
+// WithExcludeFilter(mapper.ExcludeTags("internal"), mapper.ExcludeMethods("DELETE")).
+func WithExcludeFilter(filters ...mapper.RouteFilterFunc) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.ExcludeFilters = append(opts.ExcludeFilters, filters...)
+	}
+}
+
+// WithDryRun makes every tool return the request it would send instead of executing it.
+// Individual calls can opt in with the `_dryRun: true` argument.
+func WithDryRun(enabled bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.DryRun = enabled
+	}
+}
+
+// WithHeaderPropagation limits which inbound MCP headers are forwarded upstream.
+// Names match case-insensitively and may end in `*` (e.g. "X-Trace-*"); deny wins.
+// With an empty allow list every header not denied is forwarded, except
+// Authorization, Proxy-Authorization and Cookie, which must be allowed by name.
+func WithHeaderPropagation(allow []string, deny []string) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.HeaderPropagation = &executor.HeaderPropagation{Allow: allow, Deny: deny}
+	}
+}
+
+// WithStrictFormats controls whether email, uuid, date, date-time and uri formats are
+// validated before calling the upstream (the default). Unknown formats are always ignored.
+func WithStrictFormats(enabled bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.DisableFormatAssertion = !enabled
+	}
+}
+
+// WithResponseTransformer reshapes successful tool responses (e.g. dropping noisy
+// fields or unwrapping envelopes) before output validation. Switch on
+// route.OperationID for per-operation behaviour; returning an error yields an error result.
+func WithResponseTransformer(fn ResponseTransformer) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.ResponseTransformer = fn
+	}
+}
+
+// WithOperationAllowlist exposes only the listed operationIds, regardless of tags.
+// Operations without an operationId are dropped; unknown ids are logged as warnings.
+func WithOperationAllowlist(operationIDs []string) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.OperationAllowlist = append(opts.OperationAllowlist, operationIDs...)
+	}
+}
+
+// WithOperationDenylist drops the listed operationIds. It is applied after the allowlist.
+func WithOperationDenylist(operationIDs []string) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.OperationDenylist = append(opts.OperationDenylist, operationIDs...)
+	}
+}
+
+// WithExcludeDeprecated skips operations marked `deprecated: true` instead of
+// exposing them as tools or resources.
+func WithExcludeDeprecated(exclude bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.ExcludeDeprecated = exclude
+	}
+}
+
+// WithNameCollisionStrategy controls how a name already used by an earlier operation
+// or spec is made unique: NameCollisionSuffix (default) appends _2, _3, ...;
+// NameCollisionPrefix prefixes the spec alias. Renames are reported by Server.NameCollisions.
+func WithNameCollisionStrategy(strategy NameCollisionStrategy) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.NameCollisionStrategy = strategy
+	}
+}
+
+// WithSpecAlias names the spec passed to NewServer for NameCollisionPrefix (default "spec1").
+func WithSpecAlias(alias string) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.SpecAlias = alias
+	}
+}
+
+// WithAdditionalPropertiesMode controls undeclared request body properties: Strict
+// (default) follows the spec, Allow accepts and forwards them, Strip silently drops
+// the ones the spec forbids, and Forbid rejects them on every declared object.
+func WithAdditionalPropertiesMode(mode AdditionalPropertiesMode) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.AdditionalProperties = mode
+	}
+}
+
+// WithRequestInterceptor adds interceptors that see each final upstream request
+// (URL, headers and body) just before it is sent; an error fails the tool call.
+func WithRequestInterceptor(interceptors ...RequestInterceptor) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.RequestInterceptors = append(opts.RequestInterceptors, interceptors...)
+	}
+}
+
+// WithDefaultExample pre-fills the body arguments of operationID with the values
+// of its named request example (as JSON Schema defaults the LLM may override).
+// When exampleName is empty or unknown the operation's single media example is used.
+func WithDefaultExample(operationID, exampleName string) ServerOption {
+	return func(opts *ServerOptions) {
+		if opts.DefaultExamples == nil {
+			opts.DefaultExamples = make(map[string]string)
+		}
+		opts.DefaultExamples[operationID] = exampleName
+	}
+}
+
+// WithSchemaOverride patches generated tool input schemas (add descriptions, tighten
+// enums, require fields) before validators are compiled. Return nil to keep the schema.
+func WithSchemaOverride(fn SchemaOverride) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.SchemaOverride = fn
+	}
+}
+
+// WithOutputSchemaOverride patches generated tool output schemas before they are
+// used for response validation. It is not called for tools without an output schema.
+func WithOutputSchemaOverride(fn SchemaOverride) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.OutputSchemaOverride = fn
+	}
+}
+
+// WithLogger sends server and tool call logs to logger. Nothing is logged by
+// default; tool arguments and results are only emitted at debug level.
+func WithLogger(logger Logger) ServerOption {
+	return func(opts *ServerOptions) {
+		if logger == nil {
+			logger = executor.NopLogger()
+		}
+		opts.Logger = logger
+	}
+}
+
+// WithMaxResponseBytes caps the response body read per tool call. A larger body
+// returns an error result saying it was truncated instead of partial data.
+func WithMaxResponseBytes(n int64) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.MaxResponseBytes = n
+	}
+}
+
+// WithMaxRequestBytes rejects tool calls whose in-memory request body exceeds n
+// bytes, bounding the copy kept for redirect replays. Streamed uploads are exempt.
+func WithMaxRequestBytes(n int64) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.MaxRequestBytes = n
+	}
+}
+
+// WithContentTypeVariants generates one tool per request body media type for
+// operations that accept several (e.g. createPet_json and createPet_multipart),
+// so callers no longer pick the encoding through `_contentType`.
+func WithContentTypeVariants(enabled bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.ContentTypeVariants = enabled
+	}
+}
+
+// WithValidation runs parser.ValidateSpec on every registered spec. Error-level
+// diagnostics (broken $refs, duplicate operationIds, mismatched path parameters)
+// fail registration with a *parser.ValidationError; warnings are logged.
+func WithValidation(enabled bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.Validation = enabled
+	}
+}
+
+// WithBaseURLResolver computes the upstream base URL per tool call, for example
+// from a tenant ID in the MCP request headers. A non-empty result overrides
+// WithBaseURL; an error fails the call before anything is sent.
+func WithBaseURLResolver(fn BaseURLResolver) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.BaseURLResolver = fn
+	}
+}
+
+// WithResponseCache caches successful GET/HEAD responses of read-only tools for
+// up to ttl, keyed by URL and the Accept/Authorization/Cookie headers. Responses
+// marked Cache-Control no-store or no-cache are skipped and max-age shortens the
+// TTL. At most maxEntries responses are kept; the least recently used goes first.
+func WithResponseCache(ttl time.Duration, maxEntries int) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.ResponseCacheTTL = ttl
+		opts.ResponseCacheMaxEntries = maxEntries
+	}
+}
+
+// WithUserAgent sets the product token the default HTTP client sends as
+// User-Agent (openapi-mcp/<version> otherwise). A User-Agent already set by the
+// spec's headers or an interceptor is kept and the token is appended to it.
+// Custom clients passed through WithHTTPClient are left unchanged.
+func WithUserAgent(userAgent string) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.UserAgent = userAgent
+	}
+}
+
+// WithWebhookResources registers a read-only webhook://<name> resource for each
+// OpenAPI 3.1 webhook, describing the payloads the API sends to subscribers.
+// Webhooks are listed by Server.Webhooks either way.
+func WithWebhookResources(enabled bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.WebhookResources = enabled
+	}
+}
+
+// WithAuditMetadata adds an `_audit` object to the structured content of every
+// tool result, success or error: the upstream url and method, the status code,
+// the call's start timestamp, durationMs and the X-Request-Id (or "" if none).
+func WithAuditMetadata(enabled bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.AuditMetadata = enabled
+	}
+}
+
+// WithPathPrefix mounts every operation under a fixed upstream prefix such as
+// "/api/v2" that the spec's paths leave out. It is joined after path parameters
+// are substituted and after the base URL or operation server is chosen.
+func WithPathPrefix(prefix string) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.PathPrefix = prefix
+	}
+}
+
+// WithDefaultAccept sets the Accept header sent by every tool call, e.g.
+// "application/json". Operations that do not declare the media type keep
+// their own preferred type, and a call's `_accept` argument overrides both.
+func WithDefaultAccept(contentType string) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.DefaultAccept = contentType
+	}
+}
+
+// WithCSVOptions sets how text/csv responses are parsed into rows. By default
+// fields are comma-separated and the first record names the columns; without a
+// header every row is returned as an array of its fields.
+func WithCSVOptions(delimiter rune, hasHeader bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.CSVOptions = &executor.CSVOptions{Delimiter: delimiter, HasHeader: hasHeader}
+	}
+}
+
+// WithAPIKeyFromEnv reads an API key from envVar and sends it on every upstream
+// request where the spec's apiKey security scheme schemeName declares it: as a
+// header, query parameter or cookie. NewServer fails if the scheme is missing,
+// is not an apiKey scheme, or the variable is unset.
+func WithAPIKeyFromEnv(schemeName, envVar string) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.APIKeysFromEnv = append(opts.APIKeysFromEnv, APIKeyFromEnv{Scheme: schemeName, EnvVar: envVar})
+	}
+}
+
+// WithSpecPatch applies an RFC 7386 JSON Merge Patch (JSON or YAML) to the spec
+// before it is parsed, e.g. to add a missing operationId or fix a wrong type
+// without forking the upstream document. Patches apply in the order given.
+func WithSpecPatch(patch []byte) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.SpecPatches = append(opts.SpecPatches, SpecPatch{Patch: patch})
+	}
+}
+
+// WithSpecJSONPatch applies an RFC 6902 JSON Patch to the spec before it is
+// parsed. Unlike a merge patch it can edit array elements such as a single
+// parameter, and its test operations guard against upstream changes.
+func WithSpecJSONPatch(patch []byte) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.SpecPatches = append(opts.SpecPatches, SpecPatch{Patch: patch, JSONPatch: true})
+	}
+}
+
+// WithIncludeHTTPLine adds an "HTTP: GET /users/{id}" line after the summary of
+// every tool description so similarly described operations can be told apart.
+// It is on by default.
+func WithIncludeHTTPLine(enabled bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.DisableHTTPLine = !enabled
+	}
+}
+
+// WithJoinedCookieArrays sends array cookie parameters as a single cookie with
+// comma-separated values, as `explode: false` does. By default the parameter's
+// explode setting is followed, and cookies default to `explode: true`: one
+// cookie per value, all with the same name, which many servers read as only the
+// first or last value.
+func WithJoinedCookieArrays(enabled bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.JoinCookieArrays = enabled
+	}
+}
+
+// WithMaxURLLength fails calls whose request URL would exceed limit bytes with an
+// error naming the longest query parameter, instead of a bare 414 from the
+// upstream server. Many servers stop at 8KB. Query parameters that the
+// operation's request body also declares are sent in the body instead when that
+// makes the URL fit. Zero, the default, disables the check.
+func WithMaxURLLength(limit int) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.MaxURLLength = limit
+	}
+}
+
+// WithNormalizeDates rewrites string arguments declared with `format: date` or
+// `date-time`, in parameters and anywhere in the request body, from common
+// layouts such as "March 5, 2024" or "2024-03-05 10:00" to 2006-01-02 or RFC 3339
+// form. Times without a zone are taken as UTC; values that match no layout are
+// left unchanged for validation to report. It is off by default.
+func WithNormalizeDates(enabled bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.NormalizeDates = enabled
+	}
+}
+
+// WithTracer wraps every upstream call in a span named after the operationId,
+// carrying the operation, method, URL without query string and response status,
+// and sends the trace context (e.g. W3C traceparent) with the request. Failed
+// calls record their error on the span. Tracer is an interface so that
+// OpenTelemetry stays an optional dependency; see executor.Tracer.
+func WithTracer(tracer Tracer) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.Tracer = tracer
+	}
+}
+
+// WithUnwrapSingleProperty sends request bodies whose schema is an object with a
+// single property as that property's bare value, for APIs that document
+// {"items": [...]} but expect [...]. The tool still takes the property as its
+// argument. By default bodies are sent as the declared object.
+func WithUnwrapSingleProperty(enabled bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.UnwrapSingleProperty = enabled
+	}
+}
+
+// WithLenientSpecParsing accepts JSON specs, and the JSON documents they
+// reference, written as JSONC: with // and /* */ comments and trailing commas.
+// YAML is unaffected. It is off by default so that malformed JSON is reported
+// rather than repaired.
+func WithLenientSpecParsing(enabled bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.LenientSpecParsing = enabled
+	}
+}
+
+// WithOutputSchemaStatus makes the response declared for status, e.g. "202",
+// the output schema of operationID instead of the first of 200, 201, 202 and
+// 204. Whichever status is chosen, only responses with that status are
+// validated against the output schema; others are returned unvalidated.
+func WithOutputSchemaStatus(operationID, status string) ServerOption {
+	return func(opts *ServerOptions) {
+		if opts.OutputSchemaStatuses == nil {
+			opts.OutputSchemaStatuses = make(map[string]string)
+		}
+		opts.OutputSchemaStatuses[operationID] = status
+	}
+}
+
+// WithRequestSigning signs every upstream request after the body is built and
+// all headers, interceptors included, are applied. Each attempt is signed
+// again, so retries carry a fresh timestamp. With a custom HTTPClient the
+// signer runs as the last interceptor.
+func WithRequestSigning(signer RequestSigner) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.RequestSigner = signer
+	}
+}
+
+// WithAWSSigV4 signs upstream requests with AWS Signature Version 4 for
+// service (e.g. "execute-api" for API Gateway with IAM authorization) in
+// region. See executor.AWSCredentialsFromEnv for reading the standard
+// environment variables.
+func WithAWSSigV4(region, service string, credentials AWSCredentials) ServerOption {
+	return WithRequestSigning(executor.NewSigV4Signer(region, service, credentials))
+}
+
+// WithParameterRename exposes arguments of operationID under friendlier names,
+// e.g. {"id__path": "userId"}. Keys are the names the tool would otherwise
+// use, including collision suffixes; the request still uses the names from the
+// spec. Renaming onto an existing argument fails NewServer.
+func WithParameterRename(operationID string, renames map[string]string) ServerOption {
+	return func(opts *ServerOptions) {
+		if opts.ParameterRenames == nil {
+			opts.ParameterRenames = make(map[string]map[string]string)
+		}
+		merged := opts.ParameterRenames[operationID]
+		if merged == nil {
+			merged = make(map[string]string, len(renames))
+			opts.ParameterRenames[operationID] = merged
+		}
+		for from, to := range renames {
+			merged[from] = to
+		}
+	}
+}
+
+// WithStreamingProgress reads text/event-stream and NDJSON responses as they
+// arrive and forwards each event or record to the client as an MCP progress
+// notification, for calls whose _meta carries a progressToken. The tool result
+// lists every record once the upstream closes the stream. Without it, event
+// streams are returned as raw text when complete.
+func WithStreamingProgress(enabled bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.StreamingProgress = enabled
+	}
+}
+
+// WithMaxDescriptionLength keeps generated tool and resource descriptions within
+// n characters, saving context tokens on large APIs. Over-long descriptions
+// lose whole sections in this order: extensions, callbacks, tags, the request
+// body (reduced to its required properties), the response list (reduced to the
+// primary response), the parameter list (reduced to the required names), the
+// HTTP line and finally the long description in favour of the summary. The
+// markdown is never cut mid-section, so the essentials may still exceed n.
+func WithMaxDescriptionLength(n int) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.MaxDescriptionLength = n
+	}
+}
+
+// WithDefaultQueryArrayFormat sets how array query parameters are sent when the
+// spec declares neither style nor explode for them, instead of the OpenAPI
+// default of one key per value: QueryArrayRepeat (ids=1&ids=2), QueryArrayComma
+// (ids=1,2), QueryArrayBrackets (ids[]=1&ids[]=2), QueryArraySpaceDelimited
+// (ids=1%202) or QueryArrayPipeDelimited (ids=1|2). Parameters with a declared
+// style keep it.
+func WithDefaultQueryArrayFormat(format QueryArrayFormat) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.QueryArrayFormat = format
+	}
+}
+
+// WithResultEnvelope returns every successful tool result in one shape,
+// {"data": ..., "status": 200, "headers": {...}}, whether the upstream answered
+// with an object, an array, text or nothing at all (data is then null). The
+// tools' output schemas describe the envelope, with the response body schema
+// under data. Error results are not wrapped.
+func WithResultEnvelope(enabled bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.ResultEnvelope = enabled
+	}
+}
+
+// WithServerVariableResolver fills operation server variables such as {tenant}
+// per tool call, for example from a request header carried in the context.
+// The resolver wins over WithServerVariables; when it reports no value the
+// static value or the declared default is used. Enums still apply.
+func WithServerVariableResolver(fn ServerVariableResolver) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.ServerVariableResolver = fn
+	}
+}
+
+// WithInputSchemaDialect writes tool input schemas for Draft07 clients instead
+// of the default Draft2020: $defs become definitions, prefixItems become tuple
+// items and $ref targets follow. The schemas declare the draft in $schema and
+// arguments are validated against that draft.
+func WithInputSchemaDialect(dialect SchemaDialect) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.InputSchemaDialect = dialect
+	}
+}
+
+// WithMaxToolCount caps the number of tools generated across all registered
+// specs. Operations beyond the cap are dropped by priority (see
+// WithToolPriority), each one logged at info level. Content type variants of
+// one operation count once. Zero or less means no cap.
+func WithMaxToolCount(n int) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.MaxToolCount = n
+	}
+}
+
+// WithToolPriority ranks operations for WithMaxToolCount; higher values are
+// kept first. The default is DefaultToolPriority.
+func WithToolPriority(fn ToolPriority) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.ToolPriority = fn
+	}
+}
+
+// WithCompressedResponses makes the default HTTP client ask upstreams for gzip
+// or deflate response bodies and decode them itself. It has no effect on a
+// custom client set with WithHTTPClient.
+func WithCompressedResponses() ServerOption {
+	return func(opts *ServerOptions) {
+		opts.CompressedResponses = true
+	}
+}
+
+// WithFileUploadRoot lets tool arguments upload files by path with the
+// {"_file": "reports/q3.pdf"} form, reading only files under root; relative
+// paths start at root and symbolic links are followed before the check.
+// Without it only FileUpload values passed by Go callers are uploaded.
+func WithFileUploadRoot(root string) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.FileUploadRoot = root
+	}
+}
+
+// WithMaxSpecBytes caps the spec downloaded by NewServerFromURL and every
+// document fetched for its remote $refs and external examples, measured after
+// decompression. The default is parser.DefaultMaxSpecBytes.
+func WithMaxSpecBytes(n int64) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.MaxSpecBytes = n
+	}
+}
+
+// WithExternalExamples fetches the payloads of spec examples given only by an
+// externalValue, resolved like remote $refs, so they appear in tool schemas.
+// Without it no example is fetched.
+func WithExternalExamples() ServerOption {
+	return func(opts *ServerOptions) {
+		opts.ExternalExamples = true
+	}
+}
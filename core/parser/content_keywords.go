@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// contentKeywords are schema keywords the libopenapi high-level model drops
+// when a schema is rendered.
+var contentKeywords = []string{"contentMediaType", "contentEncoding"}
+
+// restoreContentKeywords copies contentMediaType and contentEncoding from the
+// source document into converted, including inline property, item and
+// composition schemas. Schemas behind a $ref are converted from the raw
+// document and already keep them.
+func restoreContentKeywords(converted ir.Schema, schema interface{}) {
+	source, ok := schema.(*base.Schema)
+	if !ok || source == nil || converted == nil {
+		return
+	}
+	low := source.GoLow()
+	if low == nil || low.RootNode == nil {
+		return
+	}
+	var raw map[string]interface{}
+	if err := low.RootNode.Decode(&raw); err != nil {
+		return
+	}
+	copyContentKeywords(converted, raw, 0)
+}
+
+func copyContentKeywords(dst, src map[string]interface{}, depth int) {
+	if depth > 32 {
+		return
+	}
+	for _, keyword := range contentKeywords {
+		if value, ok := src[keyword].(string); ok {
+			if _, exists := dst[keyword]; !exists {
+				dst[keyword] = value
+			}
+		}
+	}
+	for _, key := range []string{"properties", "patternProperties"} {
+		srcProps, _ := src[key].(map[string]interface{})
+		dstProps, _ := dst[key].(map[string]interface{})
+		for name, prop := range srcProps {
+			copyNestedContentKeywords(dstProps[name], prop, depth)
+		}
+	}
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		copyNestedContentKeywords(dst[key], src[key], depth)
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf", "prefixItems"} {
+		srcItems, _ := src[key].([]interface{})
+		dstItems, _ := dst[key].([]interface{})
+		for i := range srcItems {
+			if i < len(dstItems) {
+				copyNestedContentKeywords(dstItems[i], srcItems[i], depth)
+			}
+		}
+	}
+}
+
+func copyNestedContentKeywords(dst, src interface{}, depth int) {
+	source, ok := src.(map[string]interface{})
+	if !ok {
+		return
+	}
+	switch target := dst.(type) {
+	case ir.Schema:
+		copyContentKeywords(target, source, depth+1)
+	case map[string]interface{}:
+		copyContentKeywords(target, source, depth+1)
+	}
+}
@@ -3,6 +3,7 @@ package parser
 import (
 	"encoding/json"
 
+	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/libopenapi/orderedmap"
 
@@ -98,3 +99,32 @@ func convertEncodingHeaders(headers *orderedmap.Map[string, *v3.Header], isOpenA
 
 	return result
 }
+
+// propertyOrder returns the declared order of a body schema's top-level
+// properties, following allOf branches in order. Converted schemas are plain
+// maps, so multipart encoders rely on this to emit parts deterministically.
+func propertyOrder(schema *base.Schema) []string {
+	var order []string
+	seen := make(map[string]bool)
+	var walk func(s *base.Schema, depth int)
+	walk = func(s *base.Schema, depth int) {
+		if s == nil || depth > 8 {
+			return
+		}
+		if s.Properties != nil {
+			for name := range s.Properties.KeysFromOldest() {
+				if !seen[name] {
+					seen[name] = true
+					order = append(order, name)
+				}
+			}
+		}
+		for _, branch := range s.AllOf {
+			if branch != nil {
+				walk(branch.Schema(), depth+1)
+			}
+		}
+	}
+	walk(schema, 0)
+	return order
+}
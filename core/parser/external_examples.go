@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+type externalExample struct {
+	value interface{}
+	err   error
+}
+
+// externalExample fetches the payload of an example's externalValue, resolved
+// against the spec URL like a remote $ref. Results, failures included, are
+// cached per URL so an example shared by many operations is fetched once.
+func (r *schemaResolver) externalExample(ref, mediaType string) (interface{}, error) {
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid externalValue %q: %w", ref, err)
+	}
+	absolute := parsed
+	if r.baseURL != nil && !parsed.IsAbs() {
+		absolute = r.baseURL.ResolveReference(parsed)
+	}
+	if !absolute.IsAbs() {
+		return nil, fmt.Errorf("unable to resolve relative externalValue %q: base URL unknown", ref)
+	}
+	key := absolute.String()
+
+	r.mu.Lock()
+	cached, ok := r.examples[key]
+	r.mu.Unlock()
+	if ok {
+		return cached.value, cached.err
+	}
+
+	data, err := r.fetchResource(absolute)
+	if err == nil {
+		cached.value, err = decodeExamplePayload(data, absolute.Path, mediaType)
+	}
+	cached.err = err
+
+	r.mu.Lock()
+	r.examples[key] = cached
+	r.mu.Unlock()
+	return cached.value, cached.err
+}
+
+// decodeExamplePayload decodes JSON, and YAML when the file or media type says
+// so; anything else is kept as text.
+func decodeExamplePayload(data []byte, location, mediaType string) (interface{}, error) {
+	var value interface{}
+	if json.Valid(data) {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+	ext := strings.ToLower(path.Ext(location))
+	if ext == ".yaml" || ext == ".yml" || isYAMLMediaType(mediaType) {
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("failed to parse example: %w", err)
+		}
+		return value, nil
+	}
+	return string(data), nil
+}
+
+func isYAMLMediaType(mediaType string) bool {
+	mediaType = strings.ToLower(mediaType)
+	return strings.Contains(mediaType, "yaml") || strings.Contains(mediaType, "yml")
+}
+
+// loadExternalExamples fills the value of named examples that only point to an
+// externalValue, so they flow into tool schemas like inline ones. It does
+// nothing unless WithExternalExamples is set. An example that cannot be
+// fetched keeps no value and is reported through the parser's warning
+// handler; the rest of the spec still parses.
+func loadExternalExamples(resolver *schemaResolver, examples map[string]interface{}, mediaType string, warn func(msg string, fields ...interface{})) {
+	if resolver == nil || !resolver.externalExamples {
+		return
+	}
+	for name, raw := range examples {
+		example, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ref, ok := example["externalValue"].(string)
+		if !ok || ref == "" {
+			continue
+		}
+		if _, inline := example["value"]; inline {
+			continue
+		}
+		value, err := resolver.externalExample(ref, mediaType)
+		if err != nil {
+			if warn != nil {
+				warn("skipping external example", "example", name, "externalValue", ref, "error", err.Error())
+			}
+			continue
+		}
+		example["value"] = value
+	}
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -10,6 +11,34 @@ import (
 
 type ParserConfig struct {
 	SpecURL string
+	// Warn receives problems that do not stop parsing, such as an external
+	// example that cannot be fetched. Fields are alternating key/value pairs.
+	Warn func(msg string, fields ...interface{})
+	// LenientSpecParsing accepts comments and trailing commas in JSON documents,
+	// the spec and the documents it references alike.
+	LenientSpecParsing bool
+	// MaxDocumentBytes caps each document fetched for a remote $ref or an
+	// external example. Zero means DefaultMaxSpecBytes.
+	MaxDocumentBytes int64
+	// ExternalExamples fetches the payloads of examples given only by
+	// externalValue while parsing.
+	ExternalExamples bool
+
+	// source and normalized keep the spec given to NewParser and its JSONC
+	// normalization, so ParseSpec does not normalize the same document again.
+	source, normalized []byte
+}
+
+// document returns spec as it is parsed: normalized from JSONC under lenient
+// parsing, reusing the normalization NewParser did for the same document.
+func (c ParserConfig) document(spec []byte) []byte {
+	if !c.LenientSpecParsing {
+		return spec
+	}
+	if c.normalized != nil && bytes.Equal(spec, c.source) {
+		return c.normalized
+	}
+	return NormalizeJSONC(spec)
 }
 
 type ParserOption func(*ParserConfig)
@@ -20,6 +49,39 @@ func WithSpecURL(url string) ParserOption {
 	}
 }
 
+// WithWarningHandler reports problems that do not stop parsing to warn.
+func WithWarningHandler(warn func(msg string, fields ...interface{})) ParserOption {
+	return func(cfg *ParserConfig) {
+		cfg.Warn = warn
+	}
+}
+
+// WithLenientSpecParsing accepts JSONC: JSON with comments and trailing commas.
+// It is off by default so that malformed specs are reported.
+func WithLenientSpecParsing(enabled bool) ParserOption {
+	return func(cfg *ParserConfig) {
+		cfg.LenientSpecParsing = enabled
+	}
+}
+
+// WithMaxDocumentBytes caps the size of each referenced document fetched while
+// parsing, after decompression.
+func WithMaxDocumentBytes(n int64) ParserOption {
+	return func(cfg *ParserConfig) {
+		cfg.MaxDocumentBytes = n
+	}
+}
+
+// WithExternalExamples fetches the payload of every example given only by an
+// externalValue while the spec is parsed, so it reaches tool schemas like an
+// inline value. It is off by default: parsing then makes no requests for
+// examples, and such examples keep only their externalValue.
+func WithExternalExamples(enabled bool) ParserOption {
+	return func(cfg *ParserConfig) {
+		cfg.ExternalExamples = enabled
+	}
+}
+
 type configurableParser interface {
 	setConfig(ParserConfig)
 }
@@ -29,6 +91,10 @@ func NewParser(spec []byte, opts ...ParserOption) (OpenAPIParser, error) {
 	for _, opt := range opts {
 		opt(&config)
 	}
+	if config.LenientSpecParsing {
+		config.source, config.normalized = spec, NormalizeJSONC(spec)
+		spec = config.normalized
+	}
 
 	_, version, err := detectVersionAndNormalize(spec)
 	if err != nil {
@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// DefaultMaxSpecBytes caps a fetched document when no other limit is set.
+const DefaultMaxSpecBytes int64 = 64 << 20
+
+// FetchSpec downloads the OpenAPI document at specURL (http, https, file or a
+// plain path) with the same logic used for remote $refs. Redirects are followed
+// and gzip-compressed documents are decompressed. A document larger than
+// maxBytes, before or after decompression, is an error; a maxBytes of zero or
+// less means DefaultMaxSpecBytes. The returned URL is where the document was
+// finally served from, so relative $refs resolve against it.
+func FetchSpec(specURL string, maxBytes int64) ([]byte, string, error) {
+	u, err := url.Parse(specURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid spec url %q: %w", specURL, err)
+	}
+	return fetchDocument(newFetchClient(), u, maxBytes)
+}
+
+func newFetchClient() *http.Client {
+	return &http.Client{Timeout: 15 * time.Second}
+}
+
+// fetchDocument reads u and returns its content together with the final URL after redirects.
+func fetchDocument(client *http.Client, u *url.URL, maxBytes int64) ([]byte, string, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxSpecBytes
+	}
+	var data []byte
+	switch u.Scheme {
+	case "http", "https":
+		resp, err := client.Get(u.String())
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to fetch %q: %w", u.String(), err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			return nil, "", fmt.Errorf("failed to fetch %q: status %s", u.String(), resp.Status)
+		}
+		data, err = readLimited(resp.Body, maxBytes)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read %q: %w", u.String(), err)
+		}
+		if resp.Request != nil && resp.Request.URL != nil {
+			u = resp.Request.URL
+		}
+	case "file", "":
+		// 无 scheme 时按本地文件路径处理
+		var err error
+		data, err = os.ReadFile(u.Path)
+		if err != nil {
+			return nil, "", err
+		}
+	default:
+		return nil, "", fmt.Errorf("unsupported URI scheme %q in reference %q", u.Scheme, u.String())
+	}
+
+	data, err := gunzipDocument(data, maxBytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decompress %q: %w", u.String(), err)
+	}
+	return data, u.String(), nil
+}
+
+// gunzipDocument decompresses gzip content, recognised by its magic bytes. Go's
+// transport already decodes Content-Encoding: gzip; this covers .gz files and
+// servers that send compressed bodies without that header.
+func gunzipDocument(data []byte, maxBytes int64) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		return data, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return readLimited(reader, maxBytes)
+}
+
+// readLimited reads r to the end, failing once more than maxBytes arrive.
+func readLimited(r io.Reader, maxBytes int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("document exceeds %d bytes", maxBytes)
+	}
+	return data, nil
+}
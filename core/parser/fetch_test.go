@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetchSpecFollowsRedirectsAndDecompresses(t *testing.T) {
+	const spec = `{"openapi": "3.0.3", "info": {"title": "Test", "version": "1.0.0"}, "paths": {}}`
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, _ = gz.Write([]byte(spec))
+	_ = gz.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/openapi", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/v1/openapi.json.gz", http.StatusFound)
+	})
+	mux.HandleFunc("/v1/openapi.json.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/gzip")
+		_, _ = w.Write(compressed.Bytes())
+	})
+	upstream := httptest.NewServer(mux)
+	defer upstream.Close()
+
+	data, finalURL, err := FetchSpec(upstream.URL+"/openapi", 0)
+	if err != nil {
+		t.Fatalf("FetchSpec returned error: %v", err)
+	}
+	if string(data) != spec {
+		t.Fatalf("expected decompressed spec, got %q", data)
+	}
+	if finalURL != upstream.URL+"/v1/openapi.json.gz" {
+		t.Fatalf("expected the redirect target as final URL, got %q", finalURL)
+	}
+}
+
+func TestFetchSpecReportsHTTPErrors(t *testing.T) {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	defer upstream.Close()
+
+	_, _, err := FetchSpec(upstream.URL+"/missing.yaml", 0)
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "/missing.yaml") {
+		t.Fatalf("expected a 404 error naming the URL, got %v", err)
+	}
+}
+
+func TestFetchSpecRejectsOversizedDocuments(t *testing.T) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, _ = gz.Write(bytes.Repeat([]byte(" "), 4096))
+	_ = gz.Close()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/openapi.json.gz" {
+			_, _ = w.Write(compressed.Bytes())
+			return
+		}
+		_, _ = w.Write(bytes.Repeat([]byte(" "), 4096))
+	}))
+	defer upstream.Close()
+
+	for _, path := range []string{"/openapi.json", "/openapi.json.gz"} {
+		if _, _, err := FetchSpec(upstream.URL+path, 1024); err == nil || !strings.Contains(err.Error(), "exceeds 1024 bytes") {
+			t.Fatalf("expected %s to exceed the limit, got %v", path, err)
+		}
+	}
+	if _, _, err := FetchSpec(upstream.URL+"/openapi.json", 4096); err != nil {
+		t.Fatalf("expected a document at the limit to be read, got %v", err)
+	}
+}
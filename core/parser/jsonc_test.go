@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNormalizeJSONC(t *testing.T) {
+	input := `{
+  // the API title
+  "info": {"title": "Pets // not a comment", "version": "1.0",},
+  /* servers
+     block */
+  "tags": ["a", "b",],
+  "escaped": "quote \" , ]",
+}`
+	normalized := NormalizeJSONC([]byte(input))
+	var doc map[string]interface{}
+	if err := json.Unmarshal(normalized, &doc); err != nil {
+		t.Fatalf("expected valid JSON, got %v:\n%s", err, normalized)
+	}
+	if doc["info"].(map[string]interface{})["title"] != "Pets // not a comment" || doc["escaped"] != `quote " , ]` {
+		t.Fatalf("expected strings to be kept, got %v", doc)
+	}
+	if len(doc["tags"].([]interface{})) != 2 {
+		t.Fatalf("expected the trailing comma to be dropped, got %v", doc["tags"])
+	}
+
+	yamlSpec := "openapi: 3.0.0\nservers:\n  - url: https://api.example.com\n"
+	if got := string(NormalizeJSONC([]byte(yamlSpec))); got != yamlSpec {
+		t.Fatalf("expected YAML to be left alone, got %q", got)
+	}
+}
+
+func TestNewParserLenientSpecParsing(t *testing.T) {
+	spec := []byte(`{
+  "openapi": "3.0.3", // version
+  "info": {"title": "Test", "version": "1.0.0"},
+  "paths": {
+    "/pets": {"get": {"operationId": "listPets", "responses": {"200": {"description": "ok"}}}},
+  },
+}`)
+	if _, err := NewParser(spec); err == nil {
+		t.Fatalf("expected JSONC to be rejected without lenient parsing")
+	}
+
+	p, err := NewParser(spec, WithLenientSpecParsing(true))
+	if err != nil {
+		t.Fatalf("NewParser failed: %v", err)
+	}
+	routes, err := p.ParseSpec(spec)
+	if err != nil {
+		t.Fatalf("ParseSpec failed: %v", err)
+	}
+	if len(routes) != 1 || routes[0].OperationID != "listPets" {
+		t.Fatalf("unexpected routes %+v", routes)
+	}
+}
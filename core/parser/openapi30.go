@@ -35,6 +35,7 @@ func (p *OpenAPI30Parser) setConfig(cfg ParserConfig) {
 }
 
 func (p *OpenAPI30Parser) ParseSpec(spec []byte) ([]ir.HTTPRoute, error) {
+	spec = p.config.document(spec)
 	var err error
 	for k := range p.components {
 		delete(p.components, k)
@@ -51,6 +52,9 @@ func (p *OpenAPI30Parser) ParseSpec(spec []byte) ([]ir.HTTPRoute, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialise schema resolver: %w", err)
 	}
+	p.resolver.lenient = p.config.LenientSpecParsing
+	p.resolver.maxBytes = p.config.MaxDocumentBytes
+	p.resolver.externalExamples = p.config.ExternalExamples
 
 	converter := newSchemaConverter(p.resolver, true)
 	p.converter = converter
@@ -71,7 +75,7 @@ func (p *OpenAPI30Parser) ParseSpec(spec []byte) ([]ir.HTTPRoute, error) {
 		if doc.Components.Schemas != nil {
 			for name, schema := range doc.Components.Schemas.FromOldest() {
 				converted := converter.convert(schema.Schema())
-				ref := fmt.Sprintf("#/components/schemas/%s", name)
+				ref := "#/components/schemas/" + escapeJSONPointerToken(name)
 				converter.registerComponent(ref, converted)
 				p.components[name] = converted
 			}
@@ -125,8 +129,9 @@ func (p *OpenAPI30Parser) ParseSpec(spec []byte) ([]ir.HTTPRoute, error) {
 				OperationID:    operation.OperationId,
 				Summary:        operation.Summary,
 				Description:    operation.Description,
+				Deprecated:     operation.Deprecated != nil && *operation.Deprecated,
 				Tags:           operation.Tags,
-				Parameters:     append(commonParams, p.convertParameters(operation.Parameters)...),
+				Parameters:     mergeParameters(commonParams, p.convertParameters(operation.Parameters)),
 				Responses:      p.convertResponses(operation.Responses),
 				Extensions:     convertExtensionsMap(operation.Extensions),
 				OpenAPIVersion: "3.0",
@@ -140,6 +145,13 @@ func (p *OpenAPI30Parser) ParseSpec(spec []byte) ([]ir.HTTPRoute, error) {
 				route.Callbacks = callbacks
 			}
 
+			if servers := operationServers(pathItem, operation); len(servers) > 0 {
+				route.Servers = servers
+			}
+
+			route.Timeout = extensionTimeout(route.Extensions)
+			warnUndeclaredPathPlaceholders(route, p.config.Warn)
+
 			routes = append(routes, route)
 		}
 	}
@@ -181,6 +193,23 @@ func (p *OpenAPI30Parser) convertParameters(params []*v3.Parameter) []ir.Paramet
 			paramInfo.Schema = p.convertSchema(param.Schema.Schema())
 		}
 
+		if param.Content != nil {
+			content := make(map[string]ir.Schema)
+			for mediaType, mediaTypeObj := range param.Content.FromOldest() {
+				var converted ir.Schema
+				if mediaTypeObj != nil && mediaTypeObj.Schema != nil {
+					converted = p.convertSchema(mediaTypeObj.Schema.Schema())
+				}
+				content[mediaType] = converted
+				if paramInfo.Schema == nil && converted != nil {
+					paramInfo.Schema = converted
+				}
+			}
+			if len(content) > 0 {
+				paramInfo.Content = content
+			}
+		}
+
 		if param.Example != nil {
 			paramInfo.Example = extractExampleValue(param.Example)
 		}
@@ -191,6 +220,7 @@ func (p *OpenAPI30Parser) convertParameters(params []*v3.Parameter) []ir.Paramet
 
 		if extensions := convertExtensionsMap(param.Extensions); len(extensions) > 0 {
 			paramInfo.Extensions = extensions
+			paramInfo.Cookie = extensionCookieAttributes(param.In, extensions)
 		}
 
 		result = append(result, paramInfo)
@@ -222,8 +252,15 @@ func (p *OpenAPI30Parser) convertRequestBody(requestBody *v3.RequestBody) *ir.Re
 			}
 			var converted ir.Schema
 			if mediaTypeObj.Schema != nil {
-				converted = p.convertSchema(mediaTypeObj.Schema.Schema())
+				resolved := mediaTypeObj.Schema.Schema()
+				converted = p.convertSchema(resolved)
 				info.ContentSchemas[mediaType] = converted
+				if order := propertyOrder(resolved); len(order) > 0 {
+					if info.PropertyOrder == nil {
+						info.PropertyOrder = make(map[string][]string)
+					}
+					info.PropertyOrder[mediaType] = order
+				}
 			}
 			if encodings := convertEncodings(mediaTypeObj.Encoding, true); len(encodings) > 0 {
 				info.Encodings[mediaType] = encodings
@@ -245,6 +282,7 @@ func (p *OpenAPI30Parser) convertRequestBody(requestBody *v3.RequestBody) *ir.Re
 				}
 			}
 			if examples := convertExamplesMap(mediaTypeObj.Examples); len(examples) > 0 {
+				loadExternalExamples(p.resolver, examples, mediaType, p.config.Warn)
 				if info.MediaExampleSets == nil {
 					info.MediaExampleSets = make(map[string]map[string]interface{})
 				}
@@ -284,6 +322,7 @@ func (p *OpenAPI30Parser) convertResponses(responses *v3.Responses) map[string]i
 		if extensions := convertExtensionsMap(response.Extensions); len(extensions) > 0 {
 			respInfo.Extensions = extensions
 		}
+		respInfo.Headers = convertEncodingHeaders(response.Headers, true)
 
 		if response.Content != nil {
 			for mediaType, mediaTypeObj := range response.Content.FromOldest() {
@@ -299,6 +338,7 @@ func (p *OpenAPI30Parser) convertResponses(responses *v3.Responses) map[string]i
 					}
 				}
 				if examples := convertExamplesMap(mediaTypeObj.Examples); len(examples) > 0 {
+					loadExternalExamples(p.resolver, examples, mediaType, p.config.Warn)
 					respInfo.MediaExampleSets[mediaType] = examples
 				}
 				if extensions := convertExtensionsMap(mediaTypeObj.Extensions); len(extensions) > 0 {
@@ -331,6 +371,7 @@ func (p *OpenAPI30Parser) convertResponses(responses *v3.Responses) map[string]i
 		if extensions := convertExtensionsMap(responses.Default.Extensions); len(extensions) > 0 {
 			respInfo.Extensions = extensions
 		}
+		respInfo.Headers = convertEncodingHeaders(responses.Default.Headers, true)
 
 		if responses.Default.Content != nil {
 			for mediaType, mediaTypeObj := range responses.Default.Content.FromOldest() {
@@ -346,6 +387,7 @@ func (p *OpenAPI30Parser) convertResponses(responses *v3.Responses) map[string]i
 					}
 				}
 				if examples := convertExamplesMap(mediaTypeObj.Examples); len(examples) > 0 {
+					loadExternalExamples(p.resolver, examples, mediaType, p.config.Warn)
 					respInfo.MediaExampleSets[mediaType] = examples
 				}
 				if extensions := convertExtensionsMap(mediaTypeObj.Extensions); len(extensions) > 0 {
@@ -546,13 +588,17 @@ func (p *OpenAPI30Parser) convertSchema(schema interface{}) ir.Schema {
 		return nil
 	}
 	if p.converter != nil {
-		return p.converter.convert(schema)
+		converted := p.converter.convert(schema)
+		restoreContentKeywords(converted, schema)
+		return converted
 	}
 	converted, err := convertToGenericMap(schema)
 	if err != nil {
 		return nil
 	}
-	return ConvertToJSONSchema(converted, true)
+	result := ConvertToJSONSchema(converted, true)
+	restoreContentKeywords(result, schema)
+	return result
 }
 
 func (p *OpenAPI30Parser) ResolveReference(ref string) (ir.Schema, error) {
@@ -576,13 +622,3 @@ func (p *OpenAPI30Parser) GetVersion() string {
 	// libopenapi doesn't expose OpenAPI version directly, return default
 	return "3.0"
 }
-
-func (p *OpenAPI30Parser) Validate() error {
-	if p.document == nil {
-		return fmt.Errorf("no document loaded")
-	}
-
-	// libopenapi validation is done during parsing
-	// Return nil as validation errors would have been caught earlier
-	return nil
-}
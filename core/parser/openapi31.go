@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pb33f/libopenapi"
@@ -20,6 +21,7 @@ type OpenAPI31Parser struct {
 	resolver           *schemaResolver
 	schemaDefs         map[string]ir.Schema
 	converter          *schemaConverter
+	webhooks           []ir.WebhookInfo
 }
 
 func NewOpenAPI31Parser() *OpenAPI31Parser {
@@ -35,6 +37,7 @@ func (p *OpenAPI31Parser) setConfig(cfg ParserConfig) {
 }
 
 func (p *OpenAPI31Parser) ParseSpec(spec []byte) ([]ir.HTTPRoute, error) {
+	spec = p.config.document(spec)
 	var err error
 	for k := range p.components {
 		delete(p.components, k)
@@ -51,6 +54,9 @@ func (p *OpenAPI31Parser) ParseSpec(spec []byte) ([]ir.HTTPRoute, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialise schema resolver: %w", err)
 	}
+	p.resolver.lenient = p.config.LenientSpecParsing
+	p.resolver.maxBytes = p.config.MaxDocumentBytes
+	p.resolver.externalExamples = p.config.ExternalExamples
 
 	converter := newSchemaConverter(p.resolver, false)
 	p.converter = converter
@@ -71,7 +77,7 @@ func (p *OpenAPI31Parser) ParseSpec(spec []byte) ([]ir.HTTPRoute, error) {
 		if doc.Components.Schemas != nil {
 			for name, schema := range doc.Components.Schemas.FromOldest() {
 				converted := converter.convert(schema.Schema())
-				ref := fmt.Sprintf("#/components/schemas/%s", name)
+				ref := "#/components/schemas/" + escapeJSONPointerToken(name)
 				converter.registerComponent(ref, converted)
 				p.components[name] = converted
 			}
@@ -92,7 +98,10 @@ func (p *OpenAPI31Parser) ParseSpec(spec []byte) ([]ir.HTTPRoute, error) {
 		}
 	}
 
+	p.webhooks = p.convertWebhooks(doc.Webhooks)
+
 	if doc.Paths == nil {
+		p.attachWebhookDefs(converter.definitions())
 		return routes, nil
 	}
 
@@ -125,8 +134,9 @@ func (p *OpenAPI31Parser) ParseSpec(spec []byte) ([]ir.HTTPRoute, error) {
 				OperationID:    operation.OperationId,
 				Summary:        operation.Summary,
 				Description:    operation.Description,
+				Deprecated:     operation.Deprecated != nil && *operation.Deprecated,
 				Tags:           operation.Tags,
-				Parameters:     append(commonParams, p.convertParameters(operation.Parameters)...),
+				Parameters:     mergeParameters(commonParams, p.convertParameters(operation.Parameters)),
 				Responses:      p.convertResponses(operation.Responses),
 				Extensions:     convertExtensionsMap(operation.Extensions),
 				OpenAPIVersion: "3.1",
@@ -140,11 +150,19 @@ func (p *OpenAPI31Parser) ParseSpec(spec []byte) ([]ir.HTTPRoute, error) {
 				route.Callbacks = callbacks
 			}
 
+			if servers := operationServers(pathItem, operation); len(servers) > 0 {
+				route.Servers = servers
+			}
+
+			route.Timeout = extensionTimeout(route.Extensions)
+			warnUndeclaredPathPlaceholders(route, p.config.Warn)
+
 			routes = append(routes, route)
 		}
 	}
 
 	p.schemaDefs = converter.definitions()
+	p.attachWebhookDefs(p.schemaDefs)
 	if len(p.schemaDefs) > 0 {
 		defs := ir.Schema{"$defs": p.schemaDefs}
 		for i := range routes {
@@ -181,6 +199,23 @@ func (p *OpenAPI31Parser) convertParameters(params []*v3.Parameter) []ir.Paramet
 			paramInfo.Schema = p.convertSchema(param.Schema.Schema())
 		}
 
+		if param.Content != nil {
+			content := make(map[string]ir.Schema)
+			for mediaType, mediaTypeObj := range param.Content.FromOldest() {
+				var converted ir.Schema
+				if mediaTypeObj != nil && mediaTypeObj.Schema != nil {
+					converted = p.convertSchema(mediaTypeObj.Schema.Schema())
+				}
+				content[mediaType] = converted
+				if paramInfo.Schema == nil && converted != nil {
+					paramInfo.Schema = converted
+				}
+			}
+			if len(content) > 0 {
+				paramInfo.Content = content
+			}
+		}
+
 		if param.Example != nil {
 			paramInfo.Example = extractExampleValue(param.Example)
 		}
@@ -191,6 +226,7 @@ func (p *OpenAPI31Parser) convertParameters(params []*v3.Parameter) []ir.Paramet
 
 		if extensions := convertExtensionsMap(param.Extensions); len(extensions) > 0 {
 			paramInfo.Extensions = extensions
+			paramInfo.Cookie = extensionCookieAttributes(param.In, extensions)
 		}
 
 		result = append(result, paramInfo)
@@ -222,8 +258,15 @@ func (p *OpenAPI31Parser) convertRequestBody(requestBody *v3.RequestBody) *ir.Re
 			}
 			var converted ir.Schema
 			if mediaTypeObj.Schema != nil {
-				converted = p.convertSchema(mediaTypeObj.Schema.Schema())
+				resolved := mediaTypeObj.Schema.Schema()
+				converted = p.convertSchema(resolved)
 				info.ContentSchemas[mediaType] = converted
+				if order := propertyOrder(resolved); len(order) > 0 {
+					if info.PropertyOrder == nil {
+						info.PropertyOrder = make(map[string][]string)
+					}
+					info.PropertyOrder[mediaType] = order
+				}
 			}
 			if encodings := convertEncodings(mediaTypeObj.Encoding, false); len(encodings) > 0 {
 				info.Encodings[mediaType] = encodings
@@ -245,6 +288,7 @@ func (p *OpenAPI31Parser) convertRequestBody(requestBody *v3.RequestBody) *ir.Re
 				}
 			}
 			if examples := convertExamplesMap(mediaTypeObj.Examples); len(examples) > 0 {
+				loadExternalExamples(p.resolver, examples, mediaType, p.config.Warn)
 				if info.MediaExampleSets == nil {
 					info.MediaExampleSets = make(map[string]map[string]interface{})
 				}
@@ -284,6 +328,7 @@ func (p *OpenAPI31Parser) convertResponses(responses *v3.Responses) map[string]i
 		if extensions := convertExtensionsMap(response.Extensions); len(extensions) > 0 {
 			respInfo.Extensions = extensions
 		}
+		respInfo.Headers = convertEncodingHeaders(response.Headers, false)
 
 		if response.Content != nil {
 			for mediaType, mediaTypeObj := range response.Content.FromOldest() {
@@ -299,6 +344,7 @@ func (p *OpenAPI31Parser) convertResponses(responses *v3.Responses) map[string]i
 					}
 				}
 				if examples := convertExamplesMap(mediaTypeObj.Examples); len(examples) > 0 {
+					loadExternalExamples(p.resolver, examples, mediaType, p.config.Warn)
 					respInfo.MediaExampleSets[mediaType] = examples
 				}
 				if extensions := convertExtensionsMap(mediaTypeObj.Extensions); len(extensions) > 0 {
@@ -331,6 +377,7 @@ func (p *OpenAPI31Parser) convertResponses(responses *v3.Responses) map[string]i
 		if extensions := convertExtensionsMap(responses.Default.Extensions); len(extensions) > 0 {
 			respInfo.Extensions = extensions
 		}
+		respInfo.Headers = convertEncodingHeaders(responses.Default.Headers, false)
 
 		if responses.Default.Content != nil {
 			for mediaType, mediaTypeObj := range responses.Default.Content.FromOldest() {
@@ -346,6 +393,7 @@ func (p *OpenAPI31Parser) convertResponses(responses *v3.Responses) map[string]i
 					}
 				}
 				if examples := convertExamplesMap(mediaTypeObj.Examples); len(examples) > 0 {
+					loadExternalExamples(p.resolver, examples, mediaType, p.config.Warn)
 					respInfo.MediaExampleSets[mediaType] = examples
 				}
 				if extensions := convertExtensionsMap(mediaTypeObj.Extensions); len(extensions) > 0 {
@@ -499,6 +547,44 @@ func (p *OpenAPI31Parser) convertCallbackOperations(pathItem *v3.PathItem) []ir.
 	return ops
 }
 
+// Webhooks returns the top-level webhooks of the last parsed document.
+func (p *OpenAPI31Parser) Webhooks() []ir.WebhookInfo {
+	return p.webhooks
+}
+
+func (p *OpenAPI31Parser) convertWebhooks(webhooks *orderedmap.Map[string, *v3.PathItem]) []ir.WebhookInfo {
+	if webhooks == nil || webhooks.Len() == 0 {
+		return nil
+	}
+
+	var infos []ir.WebhookInfo
+	for name, pathItem := range webhooks.FromOldest() {
+		resolved := p.resolveCallbackPathItem(pathItem)
+		if resolved == nil {
+			continue
+		}
+		operations := p.convertCallbackOperations(resolved)
+		sort.Slice(operations, func(i, j int) bool { return operations[i].Method < operations[j].Method })
+		infos = append(infos, ir.WebhookInfo{
+			Name:        name,
+			Summary:     resolved.Summary,
+			Description: resolved.Description,
+			Operations:  operations,
+			Extensions:  convertExtensionsMap(resolved.Extensions),
+		})
+	}
+	return infos
+}
+
+func (p *OpenAPI31Parser) attachWebhookDefs(defs map[string]ir.Schema) {
+	if len(defs) == 0 {
+		return
+	}
+	for i := range p.webhooks {
+		p.webhooks[i].SchemaDefs = ir.Schema{"$defs": defs}
+	}
+}
+
 func (p *OpenAPI31Parser) resolveCallback(callback *v3.Callback) *v3.Callback {
 	if callback == nil {
 		return nil
@@ -546,13 +632,17 @@ func (p *OpenAPI31Parser) convertSchema(schema interface{}) ir.Schema {
 		return nil
 	}
 	if p.converter != nil {
-		return p.converter.convert(schema)
+		converted := p.converter.convert(schema)
+		restoreContentKeywords(converted, schema)
+		return converted
 	}
 	converted, err := convertToGenericMap(schema)
 	if err != nil {
 		return nil
 	}
-	return ConvertToJSONSchema(converted, false)
+	result := ConvertToJSONSchema(converted, false)
+	restoreContentKeywords(result, schema)
+	return result
 }
 
 func (p *OpenAPI31Parser) ResolveReference(ref string) (ir.Schema, error) {
@@ -576,13 +666,3 @@ func (p *OpenAPI31Parser) GetVersion() string {
 	// libopenapi doesn't expose OpenAPI version directly, return default
 	return "3.1"
 }
-
-func (p *OpenAPI31Parser) Validate() error {
-	if p.document == nil {
-		return fmt.Errorf("no document loaded")
-	}
-
-	// libopenapi validation is done during parsing
-	// Return nil as validation errors would have been caught earlier
-	return nil
-}
@@ -137,3 +137,59 @@ func TestOpenAPI31ParserResolvesCallbackReferences(t *testing.T) {
 		t.Fatalf("expected POST operation from referenced path item, got %#v", cb.Operations)
 	}
 }
+
+func TestOpenAPI31ParserExtractsWebhooks(t *testing.T) {
+	spec := `{
+        "openapi": "3.1.0",
+        "info": {"title": "Webhooks", "version": "1.0"},
+        "webhooks": {
+            "orderCreated": {
+                "summary": "A new order was placed",
+                "post": {
+                    "requestBody": {
+                        "content": {
+                            "application/json": {"schema": {"$ref": "#/components/schemas/Order"}}
+                        }
+                    },
+                    "responses": {"200": {"description": "received"}}
+                }
+            }
+        },
+        "components": {
+            "schemas": {
+                "Order": {
+                    "type": "object",
+                    "properties": {"id": {"type": "string"}}
+                }
+            }
+        }
+    }`
+
+	parser := NewOpenAPI31Parser()
+	routes, err := parser.ParseSpec([]byte(spec))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(routes) != 0 {
+		t.Fatalf("expected no routes for a webhooks-only spec, got %d", len(routes))
+	}
+
+	webhooks := parser.Webhooks()
+	if len(webhooks) != 1 {
+		t.Fatalf("expected 1 webhook, got %d", len(webhooks))
+	}
+	webhook := webhooks[0]
+	if webhook.Name != "orderCreated" || webhook.Summary != "A new order was placed" {
+		t.Fatalf("unexpected webhook metadata: %#v", webhook)
+	}
+	if len(webhook.Operations) != 1 || webhook.Operations[0].Method != "POST" {
+		t.Fatalf("expected a POST operation, got %#v", webhook.Operations)
+	}
+	body := webhook.Operations[0].RequestBody
+	if body == nil || body.ContentSchemas["application/json"] == nil {
+		t.Fatalf("expected a JSON payload schema, got %#v", body)
+	}
+	if _, ok := body.ContentSchemas["application/json"].Properties()["id"]; !ok {
+		t.Fatalf("expected the Order payload properties, got %#v", body.ContentSchemas["application/json"])
+	}
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+const timeoutExtension = "x-timeout-seconds"
+
+// extensionTimeout reads the x-timeout-seconds extension, accepting numbers or numeric strings.
+func extensionTimeout(extensions map[string]interface{}) time.Duration {
+	raw, ok := extensions[timeoutExtension]
+	if !ok {
+		return 0
+	}
+
+	var seconds float64
+	switch v := raw.(type) {
+	case int:
+		seconds = float64(v)
+	case int64:
+		seconds = float64(v)
+	case float64:
+		seconds = v
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0
+		}
+		seconds = parsed
+	default:
+		return 0
+	}
+
+	if seconds <= 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+const cookieExtension = "x-cookie"
+
+// extensionCookieAttributes reads the x-cookie extension of a cookie parameter.
+func extensionCookieAttributes(in string, extensions map[string]interface{}) *ir.CookieAttributes {
+	if in != ir.ParameterInCookie {
+		return nil
+	}
+	raw, ok := extensions[cookieExtension].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	attrs := &ir.CookieAttributes{}
+	attrs.Secure, _ = raw["secure"].(bool)
+	attrs.HttpOnly, _ = raw["httpOnly"].(bool)
+	attrs.SameSite, _ = raw["sameSite"].(string)
+	attrs.Path, _ = raw["path"].(string)
+	attrs.Domain, _ = raw["domain"].(string)
+	return attrs
+}
@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParserReadsTimeoutExtension(t *testing.T) {
+	spec := `{
+        "openapi": "3.0.3",
+        "info": {"title": "Reports", "version": "1.0"},
+        "paths": {
+            "/reports": {
+                "post": {
+                    "operationId": "generateReport",
+                    "x-timeout-seconds": 90,
+                    "responses": {"200": {"description": "ok"}}
+                },
+                "get": {
+                    "operationId": "listReports",
+                    "x-timeout-seconds": "1.5",
+                    "responses": {"200": {"description": "ok"}}
+                }
+            }
+        }
+    }`
+
+	routes, err := NewOpenAPI30Parser().ParseSpec([]byte(spec))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	timeouts := make(map[string]time.Duration)
+	for _, route := range routes {
+		timeouts[route.OperationID] = route.Timeout
+	}
+	if timeouts["generateReport"] != 90*time.Second {
+		t.Fatalf("expected 90s timeout, got %v", timeouts["generateReport"])
+	}
+	if timeouts["listReports"] != 1500*time.Millisecond {
+		t.Fatalf("expected 1.5s timeout, got %v", timeouts["listReports"])
+	}
+}
@@ -7,6 +7,8 @@ import (
 	low "github.com/pb33f/libopenapi/datamodel/low"
 	"github.com/pb33f/libopenapi/orderedmap"
 	yaml "go.yaml.in/yaml/v4"
+
+	"github.com/specx2/openapi-mcp/core/ir"
 )
 
 func extractExampleValue(node *yaml.Node) interface{} {
@@ -131,3 +133,23 @@ func convertLowExtensionsMap(exts *orderedmap.Map[low.KeyReference[string], low.
 	}
 	return result
 }
+
+// mergeParameters combines path-item and operation parameters. An operation
+// parameter with the same name and location replaces the path-item one, as the
+// spec requires, keeping its position; the rest are appended in order.
+func mergeParameters(common, operation []ir.ParameterInfo) []ir.ParameterInfo {
+	merged := make([]ir.ParameterInfo, 0, len(common)+len(operation))
+	index := make(map[string]int, len(common))
+	for _, param := range common {
+		index[param.In+"\x00"+param.Name] = len(merged)
+		merged = append(merged, param)
+	}
+	for _, param := range operation {
+		if i, ok := index[param.In+"\x00"+param.Name]; ok {
+			merged[i] = param
+			continue
+		}
+		merged = append(merged, param)
+	}
+	return merged
+}
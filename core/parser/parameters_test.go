@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+)
+
+const contentParameterSpecTemplate = `{
+    "openapi": "%s",
+    "info": {"title": "Content params", "version": "1.0"},
+    "paths": {
+        "/items": {
+            "get": {
+                "operationId": "listItems",
+                "parameters": [{
+                    "name": "filter",
+                    "in": "query",
+                    "content": {
+                        "application/json": {
+                            "schema": {"type": "object", "properties": {"status": {"type": "string"}}}
+                        }
+                    }
+                }],
+                "responses": {"200": {"description": "ok"}}
+            }
+        }
+    }
+}`
+
+func TestParsersCaptureParameterContent(t *testing.T) {
+	cases := map[string]struct {
+		version string
+		parser  OpenAPIParser
+	}{
+		"3.0": {version: "3.0.3", parser: NewOpenAPI30Parser()},
+		"3.1": {version: "3.1.0", parser: NewOpenAPI31Parser()},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			routes, err := tc.parser.ParseSpec([]byte(fmt.Sprintf(contentParameterSpecTemplate, tc.version)))
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+			if len(routes) != 1 || len(routes[0].Parameters) != 1 {
+				t.Fatalf("expected one route with one parameter, got %#v", routes)
+			}
+
+			param := routes[0].Parameters[0]
+			schema, ok := param.Content["application/json"]
+			if !ok || schema.Type() != "object" {
+				t.Fatalf("expected JSON content schema, got %#v", param.Content)
+			}
+			if _, ok := param.Schema.Properties()["status"]; !ok {
+				t.Fatalf("expected content schema to stand in for the parameter schema, got %#v", param.Schema)
+			}
+		})
+	}
+}
+
+func TestParsersCaptureCookieAttributes(t *testing.T) {
+	spec := `openapi: %s
+info:
+  title: Test
+  version: 1.0.0
+paths:
+  /session:
+    get:
+      parameters:
+        - name: sid
+          in: cookie
+          schema:
+            type: string
+          x-cookie:
+            secure: true
+            sameSite: Lax
+            path: /
+      responses:
+        '200':
+          description: ok
+`
+	for _, tc := range []struct {
+		version string
+		parser  OpenAPIParser
+	}{
+		{"3.0.3", NewOpenAPI30Parser()},
+		{"3.1.0", NewOpenAPI31Parser()},
+	} {
+		routes, err := tc.parser.ParseSpec([]byte(fmt.Sprintf(spec, tc.version)))
+		if err != nil {
+			t.Fatalf("parse failed: %v", err)
+		}
+		cookie := routes[0].Parameters[0].Cookie
+		if cookie == nil || !cookie.Secure || cookie.SameSite != "Lax" || cookie.Path != "/" {
+			t.Fatalf("%s: unexpected cookie attributes %#v", tc.version, cookie)
+		}
+	}
+}
+
+const overriddenParameterSpecTemplate = `{
+    "openapi": "%s",
+    "info": {"title": "Overrides", "version": "1.0"},
+    "paths": {
+        "/items": {
+            "parameters": [
+                {"name": "limit", "in": "query", "schema": {"type": "integer", "maximum": 100}},
+                {"name": "X-Tenant", "in": "header", "schema": {"type": "string"}}
+            ],
+            "get": {
+                "operationId": "listItems",
+                "parameters": [
+                    {"name": "limit", "in": "query", "required": true, "schema": {"type": "integer", "maximum": 10}},
+                    {"name": "limit", "in": "header", "schema": {"type": "string"}}
+                ],
+                "responses": {"200": {"description": "ok"}}
+            },
+            "delete": {
+                "operationId": "clearItems",
+                "responses": {"204": {"description": "gone"}}
+            }
+        }
+    }
+}`
+
+func TestParsersLetOperationParametersOverridePathItemParameters(t *testing.T) {
+	cases := map[string]struct {
+		version string
+		parser  OpenAPIParser
+	}{
+		"3.0": {version: "3.0.3", parser: NewOpenAPI30Parser()},
+		"3.1": {version: "3.1.0", parser: NewOpenAPI31Parser()},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			routes, err := tc.parser.ParseSpec([]byte(fmt.Sprintf(overriddenParameterSpecTemplate, tc.version)))
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+			for _, route := range routes {
+				switch route.OperationID {
+				case "listItems":
+					if len(route.Parameters) != 3 {
+						t.Fatalf("expected limit, X-Tenant and the limit header, got %#v", route.Parameters)
+					}
+					limit := route.Parameters[0]
+					if limit.Name != "limit" || limit.In != "query" || !limit.Required || limit.Schema["maximum"] != float64(10) {
+						t.Fatalf("expected the operation's limit to win, got %#v", limit)
+					}
+					if route.Parameters[2].In != "header" || route.Parameters[2].Name != "limit" {
+						t.Fatalf("expected the limit header to be kept apart, got %#v", route.Parameters[2])
+					}
+				case "clearItems":
+					if len(route.Parameters) != 2 || route.Parameters[0].Schema["maximum"] != float64(100) {
+						t.Fatalf("expected the path-item parameters unchanged, got %#v", route.Parameters)
+					}
+				}
+			}
+		})
+	}
+}
@@ -11,6 +11,16 @@ type OpenAPIParser interface {
 	Validate() error
 }
 
+// WebhookParser is implemented by parsers that extract OpenAPI 3.1 webhooks.
+type WebhookParser interface {
+	Webhooks() []ir.WebhookInfo
+}
+
+// SecuritySchemeParser is implemented by parsers that expose components.securitySchemes.
+type SecuritySchemeParser interface {
+	SecuritySchemes() map[string]ir.SecurityScheme
+}
+
 type ParseError struct {
 	Message string
 	Path    string
@@ -0,0 +1,295 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ApplyMergePatch applies an RFC 7386 JSON Merge Patch to a JSON or YAML spec and
+// returns the patched document as JSON. The patch may be JSON or YAML as well;
+// a null value removes the key. Object key order is not preserved.
+func ApplyMergePatch(spec, patch []byte) ([]byte, error) {
+	doc, err := decodePatchDocument(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode spec: %w", err)
+	}
+	merge, err := decodePatchDocument(patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode merge patch: %w", err)
+	}
+	return json.Marshal(mergePatch(doc, merge))
+}
+
+// ApplyJSONPatch applies an RFC 6902 JSON Patch (add, remove, replace, move, copy
+// and test operations) to a JSON or YAML spec and returns the patched document
+// as JSON. Operations apply in order and the first failing one aborts the patch.
+func ApplyJSONPatch(spec, patch []byte) ([]byte, error) {
+	doc, err := decodePatchDocument(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode spec: %w", err)
+	}
+	decoded, err := decodePatchDocument(patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JSON patch: %w", err)
+	}
+	operations, ok := decoded.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("JSON patch must be an array of operations")
+	}
+
+	for i, raw := range operations {
+		operation, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("JSON patch operation %d is not an object", i)
+		}
+		doc, err = applyPatchOperation(doc, operation)
+		if err != nil {
+			op, _ := operation["op"].(string)
+			return nil, fmt.Errorf("JSON patch operation %d (%s): %w", i, op, err)
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// decodePatchDocument decodes JSON or YAML, keeping numbers as json.Number so
+// large integers survive the round trip.
+func decodePatchDocument(data []byte) (interface{}, error) {
+	if !json.Valid(data) {
+		converted, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		data = converted
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	patchMap, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetMap, ok := target.(map[string]interface{})
+	if !ok {
+		targetMap = make(map[string]interface{})
+	}
+	for key, value := range patchMap {
+		if value == nil {
+			delete(targetMap, key)
+			continue
+		}
+		targetMap[key] = mergePatch(targetMap[key], value)
+	}
+	return targetMap
+}
+
+func applyPatchOperation(doc interface{}, operation map[string]interface{}) (interface{}, error) {
+	op, _ := operation["op"].(string)
+	path, err := patchPointer(operation, "path")
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "add", "replace", "test":
+		value, ok := operation["value"]
+		if !ok {
+			return nil, fmt.Errorf("missing value")
+		}
+		switch op {
+		case "add":
+			return patchAdd(doc, path, value)
+		case "replace":
+			if _, err := patchGet(doc, path); err != nil {
+				return nil, err
+			}
+			if len(path) == 0 {
+				return value, nil
+			}
+			return patchRemoveThenAdd(doc, path, value)
+		default:
+			current, err := patchGet(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, fmt.Errorf("value at %q does not match", operation["path"])
+			}
+			return doc, nil
+		}
+	case "remove":
+		return patchRemove(doc, path)
+	case "move", "copy":
+		from, err := patchPointer(operation, "from")
+		if err != nil {
+			return nil, err
+		}
+		value, err := patchGet(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op == "copy" {
+			return patchAdd(doc, path, cloneGenericValue(value))
+		}
+		if len(path) > len(from) && reflect.DeepEqual(path[:len(from)], from) {
+			return nil, fmt.Errorf("cannot move a value into one of its children")
+		}
+		doc, err = patchRemove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, path, value)
+	default:
+		return nil, fmt.Errorf("unsupported operation %q", op)
+	}
+}
+
+// patchPointer reads a JSON Patch pointer. Unlike a $ref fragment it is not
+// percent-encoded, so only ~1 and ~0 are unescaped.
+func patchPointer(operation map[string]interface{}, field string) ([]string, error) {
+	pointer, ok := operation[field].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing %s", field)
+	}
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%s %q must start with /", field, pointer)
+	}
+	tokens := strings.Split(pointer, "/")[1:]
+	for i, token := range tokens {
+		tokens[i] = unescapeJSONPointerToken(token)
+	}
+	return tokens, nil
+}
+
+func patchGet(doc interface{}, path []string) (interface{}, error) {
+	current := doc
+	for _, token := range path {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("path segment %q not found", token)
+			}
+			current = value
+		case []interface{}:
+			idx, err := patchIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			current = node[idx]
+		default:
+			return nil, fmt.Errorf("path segment %q does not address a container", token)
+		}
+	}
+	return current, nil
+}
+
+// patchAt runs fn on the container holding the last path segment and stores the
+// (possibly reallocated) container back into its parent.
+func patchAt(node interface{}, path []string, fn func(container interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(node, path[0])
+	}
+	child, err := patchGet(node, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	updated, err := patchAt(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	switch container := node.(type) {
+	case map[string]interface{}:
+		container[path[0]] = updated
+	case []interface{}:
+		idx, _ := patchIndex(path[0], len(container)-1)
+		container[idx] = updated
+	}
+	return node, nil
+}
+
+func patchAdd(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return patchAt(doc, path, func(container interface{}, key string) (interface{}, error) {
+		switch node := container.(type) {
+		case map[string]interface{}:
+			node[key] = value
+			return node, nil
+		case []interface{}:
+			idx := len(node)
+			if key != "-" {
+				var err error
+				if idx, err = patchIndex(key, len(node)); err != nil {
+					return nil, err
+				}
+			}
+			result := make([]interface{}, 0, len(node)+1)
+			result = append(result, node[:idx]...)
+			result = append(result, value)
+			return append(result, node[idx:]...), nil
+		default:
+			return nil, fmt.Errorf("path segment %q does not address a container", key)
+		}
+	})
+}
+
+func patchRemove(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("cannot remove the whole document")
+	}
+	return patchAt(doc, path, func(container interface{}, key string) (interface{}, error) {
+		switch node := container.(type) {
+		case map[string]interface{}:
+			if _, ok := node[key]; !ok {
+				return nil, fmt.Errorf("path segment %q not found", key)
+			}
+			delete(node, key)
+			return node, nil
+		case []interface{}:
+			idx, err := patchIndex(key, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			return append(append(make([]interface{}, 0, len(node)-1), node[:idx]...), node[idx+1:]...), nil
+		default:
+			return nil, fmt.Errorf("path segment %q does not address a container", key)
+		}
+	})
+}
+
+func patchRemoveThenAdd(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	doc, err := patchRemove(doc, path)
+	if err != nil {
+		return nil, err
+	}
+	return patchAdd(doc, path, value)
+}
+
+// patchIndex parses an array index no greater than max.
+func patchIndex(token string, max int) (int, error) {
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if idx > max {
+		return 0, fmt.Errorf("array index %d out of range", idx)
+	}
+	return idx, nil
+}
@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const patchFixture = `openapi: 3.0.3
+info:
+  title: Test
+  version: 1.0.0
+paths:
+  /items:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: string
+        - name: cursor
+          in: query
+          schema:
+            type: string
+      responses:
+        '200':
+          description: ok
+`
+
+func decodePatched(t *testing.T, data []byte) map[string]interface{} {
+	t.Helper()
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("patched spec is not JSON: %v", err)
+	}
+	return doc
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	patched, err := ApplyMergePatch([]byte(patchFixture), []byte(`{
+  "info": {"version": null, "x-patched": true},
+  "paths": {"/items": {"get": {"operationId": "listItems"}}}
+}`))
+	if err != nil {
+		t.Fatalf("ApplyMergePatch failed: %v", err)
+	}
+	doc := decodePatched(t, patched)
+
+	info := doc["info"].(map[string]interface{})
+	if _, ok := info["version"]; ok || info["title"] != "Test" || info["x-patched"] != true {
+		t.Fatalf("unexpected info after merge: %#v", info)
+	}
+	get := doc["paths"].(map[string]interface{})["/items"].(map[string]interface{})["get"].(map[string]interface{})
+	if get["operationId"] != "listItems" || len(get["parameters"].([]interface{})) != 2 {
+		t.Fatalf("expected operationId merged next to the existing fields, got %#v", get)
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	patched, err := ApplyJSONPatch([]byte(patchFixture), []byte(`[
+  {"op": "test", "path": "/paths/~1items/get/parameters/0/name", "value": "limit"},
+  {"op": "replace", "path": "/paths/~1items/get/parameters/0/schema/type", "value": "integer"},
+  {"op": "add", "path": "/paths/~1items/get/operationId", "value": "listItems"},
+  {"op": "move", "from": "/paths/~1items/get/parameters/1", "path": "/paths/~1items/get/parameters/0"},
+  {"op": "copy", "from": "/info/title", "path": "/info/x-title"},
+  {"op": "remove", "path": "/info/version"}
+]`))
+	if err != nil {
+		t.Fatalf("ApplyJSONPatch failed: %v", err)
+	}
+	doc := decodePatched(t, patched)
+
+	get := doc["paths"].(map[string]interface{})["/items"].(map[string]interface{})["get"].(map[string]interface{})
+	params := get["parameters"].([]interface{})
+	names := []interface{}{params[0].(map[string]interface{})["name"], params[1].(map[string]interface{})["name"]}
+	if !reflect.DeepEqual(names, []interface{}{"cursor", "limit"}) || get["operationId"] != "listItems" {
+		t.Fatalf("unexpected operation after patch: %#v", get)
+	}
+	if typ := params[1].(map[string]interface{})["schema"].(map[string]interface{})["type"]; typ != "integer" {
+		t.Fatalf("expected limit to become an integer, got %v", typ)
+	}
+	info := doc["info"].(map[string]interface{})
+	if _, ok := info["version"]; ok || info["x-title"] != "Test" {
+		t.Fatalf("unexpected info after patch: %#v", info)
+	}
+
+	_, err = ApplyJSONPatch([]byte(patchFixture), []byte(`[{"op": "test", "path": "/info/title", "value": "Other"}]`))
+	if err == nil || !strings.Contains(err.Error(), "operation 0 (test)") {
+		t.Fatalf("expected a failing test operation, got %v", err)
+	}
+	_, err = ApplyJSONPatch([]byte(patchFixture), []byte(`[{"op": "remove", "path": "/paths/~1items/get/parameters/5"}]`))
+	if err == nil {
+		t.Fatalf("expected an out of range index to fail")
+	}
+}
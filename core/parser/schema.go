@@ -19,15 +19,6 @@ func ConvertToJSONSchema(openAPISchema map[string]interface{}, isOpenAPI30 bool)
 				continue
 			}
 			result[key] = value
-		case "type":
-			if isOpenAPI30 && openAPISchema["nullable"] == true {
-				result["anyOf"] = []interface{}{
-					map[string]interface{}{"type": value},
-					map[string]interface{}{"type": "null"},
-				}
-				continue
-			}
-			result[key] = value
 		case "properties":
 			if props, ok := value.(map[string]interface{}); ok {
 				convertedProps := make(map[string]interface{})
@@ -54,7 +45,7 @@ func ConvertToJSONSchema(openAPISchema map[string]interface{}, isOpenAPI30 bool)
 			} else {
 				result[key] = value
 			}
-		case "allOf", "anyOf", "oneOf":
+		case "allOf", "anyOf", "oneOf", "prefixItems":
 			if schemas, ok := value.([]interface{}); ok {
 				convertedSchemas := make([]interface{}, len(schemas))
 				for i, schema := range schemas {
@@ -73,6 +64,10 @@ func ConvertToJSONSchema(openAPISchema map[string]interface{}, isOpenAPI30 bool)
 		}
 	}
 
+	if isOpenAPI30 && openAPISchema["nullable"] == true {
+		applyNullable(result)
+	}
+
 	return result
 }
 
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/specx2/openapi-mcp/core/ir"
 )
@@ -92,6 +93,8 @@ func (c *schemaConverter) convertMap(input map[string]interface{}) ir.Schema {
 			}
 		case "not", "if", "then", "else":
 			result[key] = c.convertValue(raw)
+		case "discriminator":
+			result[key] = c.convertDiscriminator(raw)
 		default:
 			result[key] = cloneGenericValue(raw)
 		}
@@ -143,7 +146,49 @@ func (c *schemaConverter) convertReference(ref string) (string, error) {
 	return name, nil
 }
 
+// convertDiscriminator rewrites mapping targets that are component references to
+// local `#/$defs/` references; bare schema names are kept as-is.
+func (c *schemaConverter) convertDiscriminator(raw interface{}) interface{} {
+	discriminator, ok := raw.(map[string]interface{})
+	if !ok {
+		return cloneGenericValue(raw)
+	}
+
+	result := make(map[string]interface{}, len(discriminator))
+	for key, value := range discriminator {
+		result[key] = cloneGenericValue(value)
+	}
+
+	mapping, ok := discriminator["mapping"].(map[string]interface{})
+	if !ok {
+		return result
+	}
+	converted := make(map[string]interface{}, len(mapping))
+	for value, target := range mapping {
+		ref, ok := target.(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			converted[value] = cloneGenericValue(target)
+			continue
+		}
+		name, err := c.convertReference(ref)
+		if err != nil {
+			converted[value] = ref
+			continue
+		}
+		converted[value] = "#/$defs/" + name
+	}
+	result["mapping"] = converted
+	return result
+}
+
 func (c *schemaConverter) applyNullable(schema ir.Schema) {
+	applyNullable(schema)
+}
+
+// applyNullable rewrites an OpenAPI 3.0 `nullable: true` schema so JSON Schema
+// accepts null as well. A $ref or allOf cannot carry a null type next to it, so
+// it moves into an anyOf branch together with any sibling type.
+func applyNullable(schema ir.Schema) {
 	if schema == nil {
 		return
 	}
@@ -156,15 +201,27 @@ func (c *schemaConverter) applyNullable(schema ir.Schema) {
 		schema["oneOf"] = append(oneOf, map[string]interface{}{"type": "null"})
 		return
 	}
+	_, hasRef := schema["$ref"]
+	_, hasAllOf := schema["allOf"]
+	if hasRef || hasAllOf {
+		branch := make(map[string]interface{})
+		for _, key := range []string{"$ref", "allOf", "type"} {
+			if value, ok := schema[key]; ok {
+				branch[key] = value
+				delete(schema, key)
+			}
+		}
+		schema["anyOf"] = []interface{}{branch, map[string]interface{}{"type": "null"}}
+		return
+	}
 	if t, ok := schema["type"]; ok {
 		schema["anyOf"] = []interface{}{
 			map[string]interface{}{"type": t},
 			map[string]interface{}{"type": "null"},
 		}
 		delete(schema, "type")
-		return
 	}
-	schema["type"] = []interface{}{"null"}
+	// an untyped schema already accepts null
 }
 
 func (c *schemaConverter) registerComponent(ref string, schema ir.Schema) {
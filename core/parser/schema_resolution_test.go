@@ -3,6 +3,7 @@ package parser
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/specx2/openapi-mcp/core/ir"
@@ -198,3 +199,127 @@ components:
 		t.Fatalf("expected resolved schema, got nil")
 	}
 }
+
+func TestSchemaResolverDecodesEscapedPointerSegments(t *testing.T) {
+	tempDir := t.TempDir()
+	external := `{
+  "schemas": {
+    "foo/bar": {"type": "object", "properties": {"remote": {"type": "string"}}},
+    "odd~name": {"type": "integer"}
+  }
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "defs.json"), []byte(external), 0o600); err != nil {
+		t.Fatalf("failed to write defs.json: %v", err)
+	}
+
+	spec := []byte(`{
+  "openapi": "3.0.3",
+  "info": {"title": "Test", "version": "1.0.0"},
+  "paths": {},
+  "components": {
+    "schemas": {
+      "foo/bar": {"type": "string"},
+      "with space": {"type": "boolean"}
+    }
+  }
+}`)
+	resolver, err := newSchemaResolver(spec, "file://"+filepath.Join(tempDir, "spec.json"))
+	if err != nil {
+		t.Fatalf("newSchemaResolver failed: %v", err)
+	}
+
+	for ref, wantType := range map[string]string{
+		"#/components/schemas/foo~1bar":     "string",
+		"#/components/schemas/with%20space": "boolean",
+		"./defs.json#/schemas/foo~1bar":     "object",
+		"./defs.json#/schemas/odd~0name":    "integer",
+		"./defs.json#/schemas/foo%7E1bar":   "object",
+	} {
+		name, schema, err := resolver.resolveRef(ref)
+		if err != nil {
+			t.Fatalf("resolveRef(%q) failed: %v", ref, err)
+		}
+		if schema["type"] != wantType {
+			t.Fatalf("resolveRef(%q) resolved %#v, expected type %s", ref, schema, wantType)
+		}
+		if strings.Contains(name, "~") || strings.Contains(name, "%") {
+			t.Fatalf("resolveRef(%q) derived the unescaped name %q", ref, name)
+		}
+	}
+
+	if _, _, err := resolver.resolveRef("#/components/schemas/foo/bar"); err == nil {
+		t.Fatalf("expected an unescaped slash to be a path separator")
+	}
+}
+
+func TestParserLoadsExternalExampleValues(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "pet.json"), []byte(`{"name": "Rex", "age": 3}`), 0o600); err != nil {
+		t.Fatalf("failed to write pet.json: %v", err)
+	}
+
+	spec := `openapi: 3.0.3
+info:
+  title: Test
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      operationId: createPet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                age:
+                  type: integer
+            examples:
+              rex:
+                externalValue: ./pet.json
+              ghost:
+                externalValue: ./missing.json
+      responses:
+        '204':
+          description: created
+`
+	specPath := filepath.Join(tempDir, "spec.yaml")
+	var warnings []string
+	parser, err := NewParser([]byte(spec), WithSpecURL("file://"+specPath), WithExternalExamples(true), WithWarningHandler(func(msg string, fields ...interface{}) {
+		warnings = append(warnings, msg)
+	}))
+	if err != nil {
+		t.Fatalf("NewParser failed: %v", err)
+	}
+	routes, err := parser.ParseSpec([]byte(spec))
+	if err != nil {
+		t.Fatalf("ParseSpec failed: %v", err)
+	}
+
+	examples := routes[0].RequestBody.MediaExampleSets["application/json"]
+	rex, _ := examples["rex"].(map[string]interface{})
+	if value, _ := rex["value"].(map[string]interface{}); value["name"] != "Rex" {
+		t.Fatalf("expected the external example to be loaded, got %#v", rex)
+	}
+	ghost, _ := examples["ghost"].(map[string]interface{})
+	if _, ok := ghost["value"]; ok {
+		t.Fatalf("expected the unreachable example to stay without a value, got %#v", ghost)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "external example") {
+		t.Fatalf("expected one warning for the missing example, got %v", warnings)
+	}
+
+	parser, err = NewParser([]byte(spec), WithSpecURL("file://"+specPath))
+	if err != nil {
+		t.Fatalf("NewParser failed: %v", err)
+	}
+	if routes, err = parser.ParseSpec([]byte(spec)); err != nil {
+		t.Fatalf("ParseSpec failed: %v", err)
+	}
+	rex, _ = routes[0].RequestBody.MediaExampleSets["application/json"]["rex"].(map[string]interface{})
+	if _, ok := rex["value"]; ok {
+		t.Fatalf("expected external examples to be left unfetched by default, got %#v", rex)
+	}
+}
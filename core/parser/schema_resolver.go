@@ -4,13 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 	"sync"
-	"time"
 
 	"sigs.k8s.io/yaml"
 )
@@ -23,7 +20,12 @@ type schemaResolver struct {
 	cache       map[string]map[string]interface{}
 	nameCache   map[string]string
 	nameCounter map[string]int
-	mu          sync.Mutex
+	examples    map[string]externalExample
+	lenient     bool
+	maxBytes    int64
+	// externalExamples enables fetching example externalValue payloads.
+	externalExamples bool
+	mu               sync.Mutex
 }
 
 func newSchemaResolver(spec []byte, specURL string) (*schemaResolver, error) {
@@ -40,7 +42,7 @@ func newSchemaResolver(spec []byte, specURL string) (*schemaResolver, error) {
 		}
 	}
 
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := newFetchClient()
 
 	return &schemaResolver{
 		root:        root,
@@ -49,6 +51,7 @@ func newSchemaResolver(spec []byte, specURL string) (*schemaResolver, error) {
 		cache:       make(map[string]map[string]interface{}),
 		nameCache:   make(map[string]string),
 		nameCounter: make(map[string]int),
+		examples:    make(map[string]externalExample),
 	}, nil
 }
 
@@ -125,10 +128,14 @@ func (r *schemaResolver) resolveRemote(ref string) (map[string]interface{}, erro
 		return nil, fmt.Errorf("invalid reference %q: %w", ref, err)
 	}
 
+	// keep the fragment percent-encoded so navigateJSONPointer decodes it exactly
+	// once, before splitting (RFC 6901 section 6); an encoded "/" therefore still
+	// separates tokens, and a "/" inside a name has to be written as ~1
 	var fragment string
 	if parsed.Fragment != "" {
-		fragment = parsed.Fragment
+		fragment = parsed.EscapedFragment()
 		parsed.Fragment = ""
+		parsed.RawFragment = ""
 	}
 
 	absolute := parsed
@@ -149,6 +156,9 @@ func (r *schemaResolver) resolveRemote(ref string) (map[string]interface{}, erro
 		if err != nil {
 			return nil, err
 		}
+		if r.lenient {
+			data = NormalizeJSONC(data)
+		}
 		doc, err = decodeDocumentToMap(data)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse referenced document %q: %w", absolute.String(), err)
@@ -174,25 +184,8 @@ func (r *schemaResolver) resolveRemote(ref string) (map[string]interface{}, erro
 }
 
 func (r *schemaResolver) fetchResource(u *url.URL) ([]byte, error) {
-	switch u.Scheme {
-	case "http", "https":
-		resp, err := r.client.Get(u.String())
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch %q: %w", u.String(), err)
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode >= 400 {
-			return nil, fmt.Errorf("failed to fetch %q: status %s", u.String(), resp.Status)
-		}
-		return io.ReadAll(resp.Body)
-	case "file":
-		return os.ReadFile(u.Path)
-	case "":
-		// treat as file path
-		return os.ReadFile(u.Path)
-	default:
-		return nil, fmt.Errorf("unsupported URI scheme %q in reference %q", u.Scheme, u.String())
-	}
+	data, _, err := fetchDocument(r.client, u, r.maxBytes)
+	return data, err
 }
 
 func (r *schemaResolver) generateNameForRef(ref string) string {
@@ -232,17 +225,11 @@ func decodeDocumentToMap(data []byte) (map[string]interface{}, error) {
 }
 
 // navigateJSONPointer resolves a JSON pointer (without leading '#') within the provided document.
+// The pointer is in URI fragment form: percent-encoding is decoded first, then
+// every segment is unescaped (~1 is "/", ~0 is "~").
 func navigateJSONPointer(doc interface{}, pointer string) (interface{}, error) {
-	if pointer == "" {
-		return doc, nil
-	}
-	if !strings.HasPrefix(pointer, "/") {
-		pointer = "/" + pointer
-	}
-	tokens := strings.Split(pointer, "/")[1:]
 	current := doc
-	for _, rawToken := range tokens {
-		token := strings.ReplaceAll(strings.ReplaceAll(rawToken, "~1", "/"), "~0", "~")
+	for _, token := range splitJSONPointer(pointer) {
 		switch node := current.(type) {
 		case map[string]interface{}:
 			var ok bool
@@ -262,3 +249,32 @@ func navigateJSONPointer(doc interface{}, pointer string) (interface{}, error) {
 	}
 	return current, nil
 }
+
+// splitJSONPointer decodes a URI fragment JSON pointer into its reference tokens.
+func splitJSONPointer(pointer string) []string {
+	if decoded, err := url.PathUnescape(pointer); err == nil {
+		pointer = decoded
+	}
+	if pointer == "" {
+		return nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		pointer = "/" + pointer
+	}
+	tokens := strings.Split(pointer, "/")[1:]
+	for i, token := range tokens {
+		tokens[i] = unescapeJSONPointerToken(token)
+	}
+	return tokens
+}
+
+// unescapeJSONPointerToken reverses escapeJSONPointerToken; ~1 is replaced first
+// so "~01" becomes "~1" rather than "/".
+func unescapeJSONPointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// escapeJSONPointerToken encodes a key such as a component name for use in a pointer.
+func escapeJSONPointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func TestConvertToJSONSchemaNullableReferences(t *testing.T) {
+	schema := ConvertToJSONSchema(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"owner": map[string]interface{}{"$ref": "#/components/schemas/User", "nullable": true},
+			"tags": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string", "nullable": true},
+			},
+		},
+	}, true)
+
+	props := schema.Properties()
+	wantOwner := []interface{}{
+		map[string]interface{}{"$ref": "#/$defs/User"},
+		map[string]interface{}{"type": "null"},
+	}
+	if got := props["owner"]["anyOf"]; !reflect.DeepEqual(got, wantOwner) {
+		t.Fatalf("expected nullable $ref to become anyOf, got %#v", props["owner"])
+	}
+	if _, ok := props["owner"]["$ref"]; ok {
+		t.Fatalf("did not expect $ref next to anyOf, got %#v", props["owner"])
+	}
+
+	items, _ := props["tags"]["items"].(ir.Schema)
+	wantItems := []interface{}{
+		map[string]interface{}{"type": "string"},
+		map[string]interface{}{"type": "null"},
+	}
+	if !reflect.DeepEqual(items["anyOf"], wantItems) {
+		t.Fatalf("expected nullable array items, got %#v", items)
+	}
+}
+
+func TestOpenAPI30ParserNullableAllOfReference(t *testing.T) {
+	spec := `openapi: 3.0.3
+info:
+  title: Test
+  version: 1.0.0
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  customer:
+                    nullable: true
+                    allOf:
+                      - $ref: '#/components/schemas/Customer'
+components:
+  schemas:
+    Customer:
+      type: object
+      properties:
+        name:
+          type: string
+`
+	routes, err := NewOpenAPI30Parser().ParseSpec([]byte(spec))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	body := routes[0].Responses["200"].ContentSchemas["application/json"]
+	customer := body.Properties()["customer"]
+	anyOf, ok := customer["anyOf"].([]interface{})
+	if !ok || len(anyOf) != 2 {
+		t.Fatalf("expected customer to be anyOf [allOf, null], got %#v", customer)
+	}
+	if _, ok := anyOf[0].(map[string]interface{})["allOf"]; !ok {
+		t.Fatalf("expected the allOf to move into the first branch, got %#v", anyOf[0])
+	}
+	if _, ok := customer["allOf"]; ok {
+		t.Fatalf("did not expect allOf next to anyOf, got %#v", customer)
+	}
+}
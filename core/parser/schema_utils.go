@@ -102,9 +102,9 @@ func deriveRefBase(ref string) string {
 		return "schema"
 	}
 	if strings.HasPrefix(ref, "#/") {
-		parts := strings.Split(ref, "/")
-		if len(parts) > 0 {
-			return sanitizeDefinitionName(parts[len(parts)-1])
+		tokens := splitJSONPointer(strings.TrimPrefix(ref, "#"))
+		if len(tokens) > 0 {
+			return sanitizeDefinitionName(tokens[len(tokens)-1])
 		}
 		return "schema"
 	}
@@ -112,17 +112,15 @@ func deriveRefBase(ref string) string {
 	if err != nil {
 		return "schema"
 	}
-	fragment := parsed.Fragment
+	fragment := parsed.EscapedFragment()
 	parsed.Fragment = ""
 	base := path.Base(parsed.Path)
 	if idx := strings.Index(base, "."); idx >= 0 {
 		base = base[:idx]
 	}
 	if fragment != "" {
-		segments := strings.Split(fragment, "/")
-		fragBase := segments[len(segments)-1]
-		if fragBase != "" {
-			base = fragBase
+		if tokens := splitJSONPointer(fragment); len(tokens) > 0 && tokens[len(tokens)-1] != "" {
+			base = tokens[len(tokens)-1]
 		}
 	}
 	if base == "" {
@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// SecuritySchemes returns components.securitySchemes of the last parsed document.
+func (p *OpenAPI30Parser) SecuritySchemes() map[string]ir.SecurityScheme {
+	return convertSecuritySchemes(p.model)
+}
+
+// SecuritySchemes returns components.securitySchemes of the last parsed document.
+func (p *OpenAPI31Parser) SecuritySchemes() map[string]ir.SecurityScheme {
+	return convertSecuritySchemes(p.model)
+}
+
+func convertSecuritySchemes(model *libopenapi.DocumentModel[v3.Document]) map[string]ir.SecurityScheme {
+	if model == nil || model.Model.Components == nil || model.Model.Components.SecuritySchemes == nil {
+		return nil
+	}
+
+	schemes := make(map[string]ir.SecurityScheme)
+	for name, scheme := range model.Model.Components.SecuritySchemes.FromOldest() {
+		if scheme == nil {
+			continue
+		}
+		schemes[name] = ir.SecurityScheme{
+			Name:         name,
+			Type:         scheme.Type,
+			In:           scheme.In,
+			ParamName:    scheme.Name,
+			Scheme:       scheme.Scheme,
+			BearerFormat: scheme.BearerFormat,
+			Description:  scheme.Description,
+		}
+	}
+	return schemes
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"strings"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func convertServers(servers []*v3.Server) []ir.ServerInfo {
+	if len(servers) == 0 {
+		return nil
+	}
+
+	result := make([]ir.ServerInfo, 0, len(servers))
+	for _, server := range servers {
+		if server == nil || strings.TrimSpace(server.URL) == "" {
+			continue
+		}
+
+		info := ir.ServerInfo{
+			URL:         strings.TrimSpace(server.URL),
+			Description: server.Description,
+		}
+
+		if server.Variables != nil && server.Variables.Len() > 0 {
+			info.Variables = make(map[string]ir.ServerVariable, server.Variables.Len())
+			for name, variable := range server.Variables.FromOldest() {
+				if variable == nil {
+					continue
+				}
+				info.Variables[name] = ir.ServerVariable{
+					Default:     variable.Default,
+					Enum:        append([]string(nil), variable.Enum...),
+					Description: variable.Description,
+				}
+			}
+		}
+
+		result = append(result, info)
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// operationServers returns the servers declared on the operation, falling back to the path item.
+func operationServers(pathItem *v3.PathItem, operation *v3.Operation) []ir.ServerInfo {
+	if operation != nil {
+		if servers := convertServers(operation.Servers); len(servers) > 0 {
+			return servers
+		}
+	}
+	if pathItem != nil {
+		return convertServers(pathItem.Servers)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+const serversSpecTemplate = `{
+    "openapi": "%s",
+    "info": {"title": "Servers", "version": "1.0"},
+    "servers": [{"url": "https://api.example.com"}],
+    "paths": {
+        "/uploads": {
+            "servers": [{"url": "https://files.example.com"}],
+            "post": {
+                "operationId": "upload",
+                "servers": [{
+                    "url": "https://{region}.cdn.example.com",
+                    "description": "CDN origin",
+                    "variables": {
+                        "region": {"default": "eu", "enum": ["eu", "us"], "description": "Region"}
+                    }
+                }],
+                "responses": {"200": {"description": "ok"}}
+            },
+            "get": {
+                "operationId": "listUploads",
+                "responses": {"200": {"description": "ok"}}
+            }
+        },
+        "/items": {
+            "get": {
+                "operationId": "listItems",
+                "responses": {"200": {"description": "ok"}}
+            }
+        }
+    }
+}`
+
+func TestParsersCollectOperationServers(t *testing.T) {
+	cases := map[string]struct {
+		version string
+		parser  OpenAPIParser
+	}{
+		"3.0": {version: "3.0.3", parser: NewOpenAPI30Parser()},
+		"3.1": {version: "3.1.0", parser: NewOpenAPI31Parser()},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			spec := fmt.Sprintf(serversSpecTemplate, tc.version)
+			routes, err := tc.parser.ParseSpec([]byte(spec))
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+
+			byID := make(map[string]ir.HTTPRoute, len(routes))
+			for _, route := range routes {
+				byID[route.OperationID] = route
+			}
+
+			upload := byID["upload"]
+			if len(upload.Servers) != 1 {
+				t.Fatalf("expected operation server, got %+v", upload.Servers)
+			}
+			server := upload.Servers[0]
+			if server.URL != "https://{region}.cdn.example.com" || server.Description != "CDN origin" {
+				t.Fatalf("unexpected operation server: %+v", server)
+			}
+			region, ok := server.Variables["region"]
+			if !ok {
+				t.Fatalf("expected region variable, got %+v", server.Variables)
+			}
+			if region.Default != "eu" || len(region.Enum) != 2 || region.Enum[1] != "us" || region.Description != "Region" {
+				t.Fatalf("unexpected region variable: %+v", region)
+			}
+
+			list := byID["listUploads"]
+			if len(list.Servers) != 1 || list.Servers[0].URL != "https://files.example.com" {
+				t.Fatalf("expected path item server fallback, got %+v", list.Servers)
+			}
+
+			if servers := byID["listItems"].Servers; len(servers) != 0 {
+				t.Fatalf("expected document servers to be left to the base URL, got %+v", servers)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/index"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+// Severity grades a Diagnostic. Only errors make a spec unusable.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is one problem found in a spec. Path is a JSON path such as
+// $.paths['/users/{id}'].get; Line is 1-based and zero when unknown.
+type Diagnostic struct {
+	Path     string
+	Message  string
+	Severity Severity
+	Line     int
+}
+
+func (d Diagnostic) String() string {
+	location := d.Path
+	if d.Line > 0 {
+		location = fmt.Sprintf("%s (line %d)", location, d.Line)
+	}
+	if location == "" {
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", d.Severity, location, d.Message)
+}
+
+// ValidationError carries the error-level diagnostics of a rejected spec.
+type ValidationError struct {
+	Diagnostics []Diagnostic
+}
+
+func (e *ValidationError) Error() string {
+	const shown = 3
+	messages := make([]string, 0, shown)
+	for i, diag := range e.Diagnostics {
+		if i == shown {
+			messages = append(messages, fmt.Sprintf("and %d more", len(e.Diagnostics)-shown))
+			break
+		}
+		messages = append(messages, diag.String())
+	}
+	return fmt.Sprintf("spec validation failed: %s", strings.Join(messages, "; "))
+}
+
+// CheckDiagnostics returns a *ValidationError listing the error-level
+// diagnostics, or nil when there are only warnings.
+func CheckDiagnostics(diagnostics []Diagnostic) error {
+	var failures []Diagnostic
+	for _, diag := range diagnostics {
+		if diag.Severity == SeverityError {
+			failures = append(failures, diag)
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return &ValidationError{Diagnostics: failures}
+}
+
+// ValidateSpec checks an OpenAPI 3.0/3.1 document and returns every problem found:
+// unresolvable $refs, missing or duplicate operationIds and path parameters that
+// do not match the path template. The error is only set when the document cannot
+// be read at all.
+func ValidateSpec(spec []byte) ([]Diagnostic, error) {
+	normalized, version, err := detectVersionAndNormalize(spec)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(version, "3.0") && !strings.HasPrefix(version, "3.1") {
+		return nil, fmt.Errorf("unsupported OpenAPI version: %s", version)
+	}
+
+	cfg := datamodel.NewDocumentConfiguration()
+	// libopenapi logs every broken reference; they are reported as diagnostics instead.
+	cfg.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	document, err := libopenapi.NewDocumentWithConfiguration(normalized, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create document: %w", err)
+	}
+
+	model, buildErr := document.BuildV3Model()
+	var diagnostics []Diagnostic
+	seen := make(map[string]bool)
+	add := func(diag Diagnostic) {
+		key := diag.Path + "\x00" + diag.Message
+		if !seen[key] {
+			seen[key] = true
+			diagnostics = append(diagnostics, diag)
+		}
+	}
+
+	if model != nil && model.Index != nil {
+		for _, err := range model.Index.GetReferenceIndexErrors() {
+			add(diagnosticFromError(err))
+		}
+	}
+	for _, err := range unwrapJoined(buildErr) {
+		add(diagnosticFromError(err))
+	}
+	if model == nil {
+		return diagnostics, nil
+	}
+
+	for _, diag := range validateOperations(&model.Model) {
+		add(diag)
+	}
+	return diagnostics, nil
+}
+
+// Validate checks the loaded document with ValidateSpec and returns a
+// *ValidationError when it has error-level diagnostics.
+func (p *OpenAPI30Parser) Validate() error {
+	return validateLoadedDocument(p.document)
+}
+
+// Validate checks the loaded document with ValidateSpec and returns a
+// *ValidationError when it has error-level diagnostics.
+func (p *OpenAPI31Parser) Validate() error {
+	return validateLoadedDocument(p.document)
+}
+
+func validateLoadedDocument(document libopenapi.Document) error {
+	if document == nil {
+		return fmt.Errorf("no document loaded")
+	}
+	info := document.GetSpecInfo()
+	if info == nil || info.SpecBytes == nil {
+		return fmt.Errorf("no document loaded")
+	}
+	diagnostics, err := ValidateSpec(*info.SpecBytes)
+	if err != nil {
+		return err
+	}
+	return CheckDiagnostics(diagnostics)
+}
+
+func unwrapJoined(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, inner := range joined.Unwrap() {
+			errs = append(errs, unwrapJoined(inner)...)
+		}
+		return errs
+	}
+	return []error{err}
+}
+
+func diagnosticFromError(err error) Diagnostic {
+	diag := Diagnostic{Message: err.Error(), Severity: SeverityError}
+
+	var indexErr *index.IndexingError
+	var resolveErr *index.ResolvingError
+	switch {
+	case errors.As(err, &indexErr):
+		diag.Path = indexErr.Path
+		if indexErr.Node != nil {
+			diag.Line = indexErr.Node.Line
+		}
+	case errors.As(err, &resolveErr):
+		diag.Path = resolveErr.Path
+		if resolveErr.Node != nil {
+			diag.Line = resolveErr.Node.Line
+		}
+		// recursive schemas are supported; only report them
+		if resolveErr.CircularReference != nil {
+			diag.Severity = SeverityWarning
+		}
+	}
+	return diag
+}
+
+var pathTemplateParam = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// undeclaredPathPlaceholders returns the {placeholders} of path that no path
+// parameter fills, in order of appearance.
+func undeclaredPathPlaceholders(path string, params []ir.ParameterInfo) []string {
+	declared := make(map[string]bool)
+	for _, param := range params {
+		if param.In == ir.ParameterInPath {
+			declared[param.Name] = true
+		}
+	}
+	var missing []string
+	for _, match := range pathTemplateParam.FindAllStringSubmatch(path, -1) {
+		if !declared[match[1]] {
+			declared[match[1]] = true
+			missing = append(missing, match[1])
+		}
+	}
+	return missing
+}
+
+// warnUndeclaredPathPlaceholders reports a route whose path has placeholders
+// without a path parameter; calls to it fail until the spec is fixed.
+// WithValidation turns the same problem into an error.
+func warnUndeclaredPathPlaceholders(route ir.HTTPRoute, warn func(msg string, fields ...interface{})) {
+	if warn == nil {
+		return
+	}
+	if missing := undeclaredPathPlaceholders(route.Path, route.Parameters); len(missing) > 0 {
+		warn("path placeholders have no matching path parameter", "method", route.Method, "path", route.Path, "placeholders", strings.Join(missing, ", "))
+	}
+}
+
+func validateOperations(doc *v3.Document) []Diagnostic {
+	if doc.Paths == nil || doc.Paths.PathItems == nil {
+		return nil
+	}
+
+	var diagnostics []Diagnostic
+	operationIDs := make(map[string]string)
+
+	for path, pathItem := range doc.Paths.PathItems.FromOldest() {
+		if pathItem == nil {
+			continue
+		}
+
+		templateParams := make(map[string]bool)
+		for _, match := range pathTemplateParam.FindAllStringSubmatch(path, -1) {
+			templateParams[match[1]] = true
+		}
+
+		for method, operation := range pathItem.GetOperations().FromOldest() {
+			if operation == nil {
+				continue
+			}
+			opPath := fmt.Sprintf("$.paths['%s'].%s", path, method)
+			line := 0
+			if low := operation.GoLow(); low != nil && low.KeyNode != nil {
+				line = low.KeyNode.Line
+			} else if low != nil && low.RootNode != nil {
+				line = low.RootNode.Line
+			}
+
+			switch {
+			case operation.OperationId == "":
+				diagnostics = append(diagnostics, Diagnostic{
+					Path:     opPath,
+					Message:  fmt.Sprintf("operation %s %s has no operationId; its tool name is derived from the method and path", strings.ToUpper(method), path),
+					Severity: SeverityWarning,
+					Line:     line,
+				})
+			case operationIDs[operation.OperationId] != "":
+				diagnostics = append(diagnostics, Diagnostic{
+					Path:     opPath,
+					Message:  fmt.Sprintf("operationId %q is already used by %s", operation.OperationId, operationIDs[operation.OperationId]),
+					Severity: SeverityError,
+					Line:     line,
+				})
+			default:
+				operationIDs[operation.OperationId] = opPath
+			}
+
+			declared := make(map[string]bool)
+			for _, param := range append(append([]*v3.Parameter(nil), pathItem.Parameters...), operation.Parameters...) {
+				if param == nil || param.In != "path" {
+					continue
+				}
+				declared[param.Name] = true
+				if !templateParams[param.Name] {
+					diagnostics = append(diagnostics, Diagnostic{
+						Path:     opPath,
+						Message:  fmt.Sprintf("path parameter %q does not appear in path %s", param.Name, path),
+						Severity: SeverityError,
+						Line:     line,
+					})
+				}
+			}
+
+			var missing []string
+			for name := range templateParams {
+				if !declared[name] {
+					missing = append(missing, name)
+				}
+			}
+			sort.Strings(missing)
+			for _, name := range missing {
+				diagnostics = append(diagnostics, Diagnostic{
+					Path:     opPath,
+					Message:  fmt.Sprintf("path template parameter %q is not declared as a path parameter", name),
+					Severity: SeverityError,
+					Line:     line,
+				})
+			}
+		}
+	}
+
+	return diagnostics
+}
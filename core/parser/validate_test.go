@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const invalidSpecTemplate = `{
+    "openapi": "%s",
+    "info": {"title": "Broken", "version": "1.0"},
+    "paths": {
+        "/users/{id}": {
+            "get": {
+                "operationId": "getUser",
+                "parameters": [{"name": "userId", "in": "path", "required": true, "schema": {"type": "string"}}],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Missing"}}}
+                    }
+                }
+            },
+            "delete": {
+                "operationId": "getUser",
+                "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+                "responses": {"204": {"description": "gone"}}
+            }
+        },
+        "/health": {
+            "get": {"responses": {"200": {"description": "ok"}}}
+        }
+    }
+}`
+
+func TestValidateSpecReportsDiagnostics(t *testing.T) {
+	for _, version := range []string{"3.0.3", "3.1.0"} {
+		t.Run(version, func(t *testing.T) {
+			diagnostics, err := ValidateSpec([]byte(fmt.Sprintf(invalidSpecTemplate, version)))
+			if err != nil {
+				t.Fatalf("ValidateSpec returned error: %v", err)
+			}
+
+			expected := map[string]Severity{
+				"#/components/schemas/Missing":                 SeverityError,
+				`operationId "getUser" is already used`:        SeverityError,
+				`path parameter "userId" does not appear`:      SeverityError,
+				`path template parameter "id" is not declared`: SeverityError,
+				"GET /health has no operationId":               SeverityWarning,
+			}
+			for fragment, severity := range expected {
+				found := false
+				for _, diag := range diagnostics {
+					if strings.Contains(diag.Message, fragment) {
+						found = true
+						if diag.Severity != severity {
+							t.Fatalf("expected %s severity for %q, got %s", severity, fragment, diag.Severity)
+						}
+						if diag.Path == "" {
+							t.Fatalf("expected a path for %q", fragment)
+						}
+					}
+				}
+				if !found {
+					t.Fatalf("missing diagnostic %q in %v", fragment, diagnostics)
+				}
+			}
+
+			var validationErr *ValidationError
+			if err := CheckDiagnostics(diagnostics); !errors.As(err, &validationErr) || len(validationErr.Diagnostics) != 4 {
+				t.Fatalf("expected four error diagnostics, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateSpecAcceptsValidSpec(t *testing.T) {
+	diagnostics, err := ValidateSpec([]byte(fmt.Sprintf(serversSpecTemplate, "3.0.3")))
+	if err != nil {
+		t.Fatalf("ValidateSpec returned error: %v", err)
+	}
+	if len(diagnostics) != 0 {
+		t.Fatalf("expected no diagnostics, got %v", diagnostics)
+	}
+
+	parser := NewOpenAPI30Parser()
+	if _, err := parser.ParseSpec([]byte(fmt.Sprintf(serversSpecTemplate, "3.0.3"))); err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if err := parser.Validate(); err != nil {
+		t.Fatalf("expected loaded spec to validate, got %v", err)
+	}
+}
+
+func TestValidateSpecRejectsUnreadableSpec(t *testing.T) {
+	if _, err := ValidateSpec([]byte(`{"info": {}}`)); err == nil {
+		t.Fatalf("expected error for a document without an openapi field")
+	}
+}
+
+func TestParseSpecWarnsAboutUndeclaredPathPlaceholders(t *testing.T) {
+	spec := []byte(`{
+    "openapi": "3.0.3",
+    "info": {"title": "Misnamed", "version": "1.0"},
+    "paths": {
+        "/users/{userId}": {
+            "get": {
+                "operationId": "getUser",
+                "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+                "responses": {"200": {"description": "ok"}}
+            },
+            "delete": {
+                "operationId": "deleteUser",
+                "parameters": [{"name": "userId", "in": "path", "required": true, "schema": {"type": "string"}}],
+                "responses": {"204": {"description": "gone"}}
+            }
+        }
+    }
+}`)
+	var warnings []string
+	parser, err := NewParser(spec, WithWarningHandler(func(msg string, fields ...interface{}) {
+		warnings = append(warnings, fmt.Sprintln(append([]interface{}{msg}, fields...)...))
+	}))
+	if err != nil {
+		t.Fatalf("NewParser failed: %v", err)
+	}
+	if _, err := parser.ParseSpec(spec); err != nil {
+		t.Fatalf("ParseSpec failed: %v", err)
+	}
+
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "GET") || !strings.Contains(warnings[0], "placeholders userId") {
+		t.Fatalf("expected one warning for GET /users/{userId}, got %v", warnings)
+	}
+}
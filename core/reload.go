@@ -0,0 +1,168 @@
+package openapimcp
+
+import (
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ReloadSpec replaces the spec the server was created with by spec and
+// generates every component again, using the server's options as NewServer
+// would; specs added with RegisterSpec are registered again under their
+// aliases. The MCP server, and every server given to RegisterInto, switches
+// over in one step: new tools and resources replace their old versions before
+// the ones that are gone are removed, and clients are notified that the lists
+// changed. On error the registered components are left as they were. API keys
+// resolved at construction are kept.
+func (s *Server) ReloadSpec(spec []byte) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	spec, parserOpts, err := prepareSpec(spec, s.options)
+	if err != nil {
+		return err
+	}
+	staged := &Server{
+		mcpServer: server.NewMCPServer(s.options.ServerName, s.options.ServerVersion),
+		mapper:    s.mapper,
+		factory:   newComponentFactory(s.options),
+		options:   s.options,
+	}
+	if err := staged.registerSpec(s.options.SpecAlias, spec, parserOpts...); err != nil {
+		return fmt.Errorf("failed to reload spec: %w", err)
+	}
+	for _, registered := range s.specs[1:] {
+		if err := staged.registerSpec(registered.alias, registered.spec, registered.parserOpts...); err != nil {
+			return fmt.Errorf("failed to reload spec %s: %w", registered.alias, err)
+		}
+	}
+
+	replaceComponents(s.mcpServer, s.registered, staged.registered)
+	for _, target := range s.targets {
+		replaceComponents(target, s.registered, staged.registered)
+	}
+	s.parser = staged.parser
+	s.factory = staged.factory
+	s.specCount = staged.specCount
+	s.toolCount = staged.toolCount
+	s.components = staged.components
+	s.registered = staged.registered
+	s.webhooks = staged.webhooks
+	s.routes = staged.routes
+	s.specs = staged.specs
+	s.options.Logger.Info("spec reloaded", "components", len(s.registered))
+	return nil
+}
+
+// replaceComponents swaps previous for next on mcpServer. Components of next
+// are added over their old versions first, so a call never finds its tool
+// missing, then the stale ones are deleted. mcp-go cannot delete a single
+// resource template, so a stale template resets all templates to next's.
+func replaceComponents(mcpServer *server.MCPServer, previous, next []Component) {
+	var (
+		tools     []server.ServerTool
+		resources []server.ServerResource
+		templates []server.ServerResourceTemplate
+	)
+	nextKeys := make([]string, 0, len(next))
+	for _, component := range next {
+		switch {
+		case component.Tool != nil:
+			tools = append(tools, *component.Tool)
+		case component.Resource != nil:
+			resources = append(resources, *component.Resource)
+		case component.ResourceTemplate != nil:
+			templates = append(templates, *component.ResourceTemplate)
+		}
+		nextKeys = append(nextKeys, componentKey(component))
+	}
+	previousKeys := make([]string, len(previous))
+	for i, component := range previous {
+		previousKeys[i] = componentKey(component)
+	}
+	stale := StaleComponentKeys(previousKeys, nextKeys)
+
+	var staleTools, staleResources []string
+	staleTemplates, hadResources := false, false
+	for i, component := range previous {
+		hadResources = hadResources || component.Resource != nil || component.ResourceTemplate != nil
+		if !stale[previousKeys[i]] {
+			continue
+		}
+		switch {
+		case component.Tool != nil:
+			staleTools = append(staleTools, component.Tool.Tool.Name)
+		case component.Resource != nil:
+			staleResources = append(staleResources, component.Resource.Resource.URI)
+		case component.ResourceTemplate != nil:
+			staleTemplates = true
+		}
+	}
+
+	if len(tools) > 0 {
+		mcpServer.AddTools(tools...)
+	}
+	if len(staleTools) > 0 {
+		mcpServer.DeleteTools(staleTools...)
+	}
+	if len(resources) > 0 {
+		mcpServer.AddResources(resources...)
+	}
+	if len(staleResources) > 0 {
+		mcpServer.DeleteResources(staleResources...)
+	}
+	if staleTemplates {
+		mcpServer.SetResourceTemplates(templates...)
+	} else if len(templates) > 0 {
+		mcpServer.AddResourceTemplates(templates...)
+	}
+	// Resource list changes are only announced by mcp-go when the server
+	// declared the capability up front, which NewServer does not.
+	if hadResources || len(resources) > 0 || len(templates) > 0 {
+		mcpServer.SendNotificationToAllClients(mcp.MethodNotificationResourcesListChanged, nil)
+	}
+}
+
+func componentKey(component Component) string {
+	switch {
+	case component.Tool != nil:
+		return ComponentKey(&component.Tool.Tool, nil, nil)
+	case component.Resource != nil:
+		return ComponentKey(nil, &component.Resource.Resource, nil)
+	case component.ResourceTemplate != nil:
+		return ComponentKey(nil, nil, &component.ResourceTemplate.Template)
+	}
+	return ""
+}
+
+// ComponentKey identifies a tool, resource or resource template the way the
+// MCP server indexes it: tools by name, resources by URI and templates by URI
+// template. Pass the one the component is; it returns "" when all are nil.
+func ComponentKey(tool *mcp.Tool, resource *mcp.Resource, template *mcp.ResourceTemplate) string {
+	switch {
+	case tool != nil:
+		return "tool:" + tool.Name
+	case resource != nil:
+		return "resource:" + resource.URI
+	case template != nil && template.URITemplate != nil:
+		return "template:" + template.URITemplate.Template.Raw()
+	}
+	return ""
+}
+
+// StaleComponentKeys returns the keys of previous that next no longer has, for
+// removing what a reload dropped from an MCP server.
+func StaleComponentKeys(previous, next []string) map[string]bool {
+	current := make(map[string]bool, len(next))
+	for _, key := range next {
+		current[key] = true
+	}
+	stale := make(map[string]bool)
+	for _, key := range previous {
+		if !current[key] {
+			stale[key] = true
+		}
+	}
+	return stale
+}
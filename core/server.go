@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -22,19 +23,45 @@ type Server struct {
 	mapper    *mapper.RouteMapper
 	factory   *factory.ComponentFactory
 	options   *ServerOptions
+	specCount int
+	toolCount int
+	reloadMu  sync.RWMutex
+
+	components []ComponentInfo
+	registered []Component
+	webhooks   []ir.WebhookInfo
+	routes     []ir.HTTPRoute
+	specs      []registeredSpec
+	targets    []*server.MCPServer
+}
+
+// registeredSpec is a spec as it was registered, kept so ReloadSpec can
+// register it again.
+type registeredSpec struct {
+	alias      string
+	spec       []byte
+	parserOpts []parser.ParserOption
 }
 
 func prepareHTTPClient(opts *ServerOptions) (executor.HTTPClient, *HTTPClientConfig) {
 	client, ok := opts.HTTPClient.(*executor.DefaultHTTPClient)
 	config := opts.HTTPConfig
 	if !ok {
+		custom := opts.HTTPClient
+		interceptors := opts.RequestInterceptors
+		if opts.RequestSigner != nil {
+			interceptors = append(interceptors[:len(interceptors):len(interceptors)], opts.RequestSigner.Sign)
+		}
+		if len(interceptors) > 0 {
+			custom = &interceptingClient{next: custom, interceptors: interceptors}
+		}
 		if config == nil {
-			return opts.HTTPClient, &HTTPClientConfig{Headers: make(http.Header)}
+			return custom, &HTTPClientConfig{Headers: make(http.Header)}
 		}
 		if config.Headers == nil {
 			config.Headers = make(http.Header)
 		}
-		return opts.HTTPClient, config
+		return custom, config
 	}
 
 	if config == nil {
@@ -51,16 +78,48 @@ func prepareHTTPClient(opts *ServerOptions) (executor.HTTPClient, *HTTPClientCon
 	if len(config.Headers) > 0 {
 		client.WithHeaders(config.Headers)
 	}
+	client.WithInterceptor(opts.RequestInterceptors...)
+	if opts.UserAgent != "" {
+		client.WithUserAgent(opts.UserAgent)
+	}
+	if opts.RequestSigner != nil {
+		client.WithRequestSigner(opts.RequestSigner)
+	}
+	if opts.CompressedResponses {
+		client.WithCompressedResponses()
+	}
 
 	return client, config
 }
 
+// NewServerFromURL downloads the spec at specURL (see parser.FetchSpec) and
+// builds a server from it. SpecURL is set to the URL the document was served
+// from, so relative $refs resolve against it; an explicit WithSpecURL wins.
+// WithMaxSpecBytes caps the download.
+func NewServerFromURL(specURL string, opts ...ServerOption) (*Server, error) {
+	options := defaultServerOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	spec, resolvedURL, err := parser.FetchSpec(specURL, options.MaxSpecBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI spec: %w", err)
+	}
+	return NewServer(spec, append([]ServerOption{WithSpecURL(resolvedURL)}, opts...)...)
+}
+
 func NewServer(spec []byte, opts ...ServerOption) (*Server, error) {
 	options := defaultServerOptions()
 	for _, opt := range opts {
 		opt(options)
 	}
 
+	var auth *apiKeyAuth
+	if len(options.APIKeysFromEnv) > 0 {
+		auth = &apiKeyAuth{}
+		options.RequestInterceptors = append(options.RequestInterceptors, auth.intercept)
+	}
+
 	client, clientConfig := prepareHTTPClient(options)
 	options.HTTPClient = client
 	if clientConfig != nil && options.BaseURL == "" && clientConfig.BaseURL != "" {
@@ -74,15 +133,12 @@ func NewServer(spec []byte, opts ...ServerOption) (*Server, error) {
 	if len(options.GlobalTags) > 0 {
 		m = m.WithGlobalTags(options.GlobalTags...)
 	}
-
-	f := factory.NewComponentFactory(options.HTTPClient, options.BaseURL)
-	if options.CustomNames != nil {
-		f = f.WithCustomNames(options.CustomNames)
-	}
-	if options.ComponentFunc != nil {
-		f = f.WithComponentFunc(options.ComponentFunc)
+	if len(options.ExcludeFilters) > 0 {
+		m = m.WithExcludeFilter(options.ExcludeFilters...)
 	}
 
+	f := newComponentFactory(options)
+
 	mcpServer := server.NewMCPServer(
 		options.ServerName,
 		options.ServerVersion,
@@ -96,22 +152,232 @@ func NewServer(spec []byte, opts ...ServerOption) (*Server, error) {
 		options:   options,
 	}
 
-	if err := s.RegisterSpecWithURL(spec, options.SpecURL); err != nil {
+	spec, parserOpts, err := prepareSpec(spec, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.registerSpec(options.SpecAlias, spec, parserOpts...); err != nil {
 		return nil, fmt.Errorf("failed to register components: %w", err)
 	}
+	if auth != nil {
+		if err := auth.resolve(s.parser, options.APIKeysFromEnv); err != nil {
+			return nil, err
+		}
+	}
 
 	return s, nil
 }
 
+// newComponentFactory configures a component factory from the server options.
+func newComponentFactory(options *ServerOptions) *factory.ComponentFactory {
+	f := factory.NewComponentFactory(options.HTTPClient, options.BaseURL)
+	if options.CustomNames != nil {
+		f = f.WithCustomNames(options.CustomNames)
+	}
+	if options.ComponentFunc != nil {
+		f = f.WithComponentFunc(options.ComponentFunc)
+	}
+	if len(options.ServerVariables) > 0 {
+		f = f.WithServerVariables(options.ServerVariables)
+	}
+	if options.ServerVariableResolver != nil {
+		f = f.WithServerVariableResolver(options.ServerVariableResolver)
+	}
+	if options.DisableOperationServers {
+		f = f.WithOperationServers(false)
+	}
+	if options.DisableHTTPLine {
+		f = f.WithIncludeHTTPLine(false)
+	}
+	if options.Pagination != nil {
+		f = f.WithPagination(options.Pagination)
+	}
+	if options.RequestCompressionMin > 0 {
+		f = f.WithRequestCompression(options.RequestCompressionMin)
+	}
+	if options.FileUploadRoot != "" {
+		f = f.WithFileUploadRoot(options.FileUploadRoot)
+	}
+	if options.OperationTimeout != nil {
+		f = f.WithOperationTimeout(options.OperationTimeout)
+	}
+	if options.HeaderPropagation != nil {
+		f = f.WithHeaderPropagation(options.HeaderPropagation)
+	}
+	if options.DisableFormatAssertion {
+		f = f.WithStrictFormats(false)
+	}
+	if options.ResponseTransformer != nil {
+		f = f.WithResponseTransformer(options.ResponseTransformer)
+	}
+	if options.SchemaOverride != nil {
+		f = f.WithSchemaOverride(options.SchemaOverride)
+	}
+	if options.OutputSchemaOverride != nil {
+		f = f.WithOutputSchemaOverride(options.OutputSchemaOverride)
+	}
+	if len(options.DefaultExamples) > 0 {
+		f = f.WithDefaultExamples(options.DefaultExamples)
+	}
+	if len(options.OutputSchemaStatuses) > 0 {
+		f = f.WithOutputSchemaStatuses(options.OutputSchemaStatuses)
+	}
+	if len(options.ParameterRenames) > 0 {
+		f = f.WithParameterRenames(options.ParameterRenames)
+	}
+	if options.AdditionalProperties != AdditionalPropertiesStrict {
+		f = f.WithAdditionalPropertiesMode(options.AdditionalProperties)
+	}
+	if options.NameCollisionStrategy != NameCollisionSuffix {
+		f = f.WithNameCollisionStrategy(options.NameCollisionStrategy)
+	}
+	f = f.WithLogger(options.Logger)
+	if options.MaxRequestBytes > 0 || options.MaxResponseBytes > 0 {
+		f = f.WithBodyLimits(options.MaxRequestBytes, options.MaxResponseBytes)
+	}
+	if options.ContentTypeVariants {
+		f = f.WithContentTypeVariants(true)
+	}
+	if options.BaseURLResolver != nil {
+		f = f.WithBaseURLResolver(options.BaseURLResolver)
+	}
+	if options.ResponseCacheTTL > 0 {
+		f = f.WithResponseCache(executor.NewResponseCache(options.ResponseCacheTTL, options.ResponseCacheMaxEntries))
+	}
+	if options.AuditMetadata {
+		f = f.WithAuditMetadata(true)
+	}
+	if options.PathPrefix != "" {
+		f = f.WithPathPrefix(options.PathPrefix)
+	}
+	if options.DefaultAccept != "" {
+		f = f.WithDefaultAccept(options.DefaultAccept)
+	}
+	if options.JoinCookieArrays {
+		f = f.WithJoinedCookieArrays(true)
+	}
+	if options.QueryArrayFormat != "" {
+		f = f.WithDefaultQueryArrayFormat(options.QueryArrayFormat)
+	}
+	if options.UnwrapSingleProperty {
+		f = f.WithUnwrapSingleProperty(true)
+	}
+	if options.Tracer != nil {
+		f = f.WithTracer(options.Tracer)
+	}
+	if options.NormalizeDates {
+		f = f.WithNormalizeDates(true)
+	}
+	if options.StreamingProgress {
+		f = f.WithStreamingProgress(true)
+	}
+	if options.ResultEnvelope {
+		f = f.WithResultEnvelope(true)
+	}
+	if options.InputSchemaDialect != Draft2020 {
+		f = f.WithInputSchemaDialect(options.InputSchemaDialect)
+	}
+	if options.MaxDescriptionLength > 0 {
+		f = f.WithMaxDescriptionLength(options.MaxDescriptionLength)
+	}
+	if options.MaxURLLength > 0 {
+		f = f.WithMaxURLLength(options.MaxURLLength)
+	}
+	if options.CSVOptions != nil {
+		f = f.WithCSVOptions(options.CSVOptions.Delimiter, options.CSVOptions.HasHeader)
+	}
+	return f
+}
+
+// prepareSpec applies the spec patch options to spec and returns the parser
+// options the server parses it with. Patches and validation need plain JSON,
+// so a JSONC spec is normalized here for them; the parser then receives valid
+// JSON, which it leaves as is.
+func prepareSpec(spec []byte, options *ServerOptions) ([]byte, []parser.ParserOption, error) {
+	if options.LenientSpecParsing && (len(options.SpecPatches) > 0 || options.Validation) {
+		spec = parser.NormalizeJSONC(spec)
+	}
+	for i, patch := range options.SpecPatches {
+		var err error
+		if patch.JSONPatch {
+			spec, err = parser.ApplyJSONPatch(spec, patch.Patch)
+		} else {
+			spec, err = parser.ApplyMergePatch(spec, patch.Patch)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to apply spec patch %d: %w", i+1, err)
+		}
+	}
+
+	var parserOpts []parser.ParserOption
+	if options.SpecURL != "" {
+		parserOpts = append(parserOpts, parser.WithSpecURL(options.SpecURL))
+	}
+	if options.LenientSpecParsing {
+		parserOpts = append(parserOpts, parser.WithLenientSpecParsing(true))
+	}
+	if options.ExternalExamples {
+		parserOpts = append(parserOpts, parser.WithExternalExamples(true))
+	}
+	if options.MaxSpecBytes > 0 {
+		parserOpts = append(parserOpts, parser.WithMaxDocumentBytes(options.MaxSpecBytes))
+	}
+	return spec, parserOpts, nil
+}
+
 // RegisterSpec parses an OpenAPI document and registers all derived MCP components with the server.
 // Additional specs can be registered after the server has been constructed.
 func (s *Server) RegisterSpec(spec []byte, parserOpts ...parser.ParserOption) error {
-	p, err := parser.NewParser(spec, parserOpts...)
+	return s.RegisterSpecWithAlias("", spec, parserOpts...)
+}
+
+// RegisterSpecWithAlias registers a spec under an alias used by NameCollisionPrefix
+// to rename components that clash with earlier specs. An empty alias becomes "specN".
+func (s *Server) RegisterSpecWithAlias(alias string, spec []byte, parserOpts ...parser.ParserOption) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	return s.registerSpec(alias, spec, parserOpts...)
+}
+
+func (s *Server) registerSpec(alias string, spec []byte, parserOpts ...parser.ParserOption) error {
+	s.specCount++
+	if alias == "" {
+		alias = fmt.Sprintf("spec%d", s.specCount)
+	}
+	s.factory.SetSpecAlias(alias)
+
+	if s.options.Validation {
+		if err := s.validateSpec(alias, spec); err != nil {
+			return err
+		}
+	}
+
+	warn := func(msg string, fields ...interface{}) {
+		s.options.Logger.Warn(msg, append([]interface{}{"spec", alias}, fields...)...)
+	}
+	p, err := parser.NewParser(spec, append([]parser.ParserOption{parser.WithWarningHandler(warn)}, parserOpts...)...)
 	if err != nil {
 		return fmt.Errorf("failed to create parser: %w", err)
 	}
 
-	return s.registerParsedSpec(p, spec)
+	if err := s.registerParsedSpec(p, spec); err != nil {
+		return err
+	}
+	s.specs = append(s.specs, registeredSpec{alias: alias, spec: spec, parserOpts: parserOpts})
+	return nil
+}
+
+func (s *Server) validateSpec(alias string, spec []byte) error {
+	diagnostics, err := parser.ValidateSpec(spec)
+	if err != nil {
+		return fmt.Errorf("failed to validate spec: %w", err)
+	}
+	for _, diag := range diagnostics {
+		if diag.Severity == parser.SeverityWarning {
+			s.options.Logger.Warn("spec validation warning", "spec", alias, "path", diag.Path, "line", diag.Line, "message", diag.Message)
+		}
+	}
+	return parser.CheckDiagnostics(diagnostics)
 }
 
 // RegisterSpecWithURL registers a spec with an optional base URL used for resolving references.
@@ -130,10 +396,16 @@ func (s *Server) registerParsedSpec(p parser.OpenAPIParser, spec []byte) error {
 	}
 
 	s.parser = p
+	s.registerWebhooks(p)
 	return s.registerComponents(routes)
 }
 
 func (s *Server) registerComponents(routes []ir.HTTPRoute) error {
+	routes = filterOperations(routes, s.options.OperationAllowlist, s.options.OperationDenylist, s.options.Logger)
+	if s.options.ExcludeDeprecated {
+		routes = excludeDeprecated(routes)
+	}
+	s.routes = append(s.routes, routes...)
 	mappedRoutes := s.mapper.MapRoutes(routes)
 	for idx := range mappedRoutes {
 		merged := mergeTags(mappedRoutes[idx].Route.Tags, mappedRoutes[idx].Tags)
@@ -141,47 +413,114 @@ func (s *Server) registerComponents(routes []ir.HTTPRoute) error {
 		mappedRoutes[idx].Tags = merged
 	}
 
+	mappedRoutes = s.limitTools(mappedRoutes)
+
+	seen := len(s.factory.NameCollisions())
 	components, err := s.factory.CreateComponents(mappedRoutes)
 	if err != nil {
 		return err
 	}
+	for _, collision := range s.factory.NameCollisions()[seen:] {
+		s.options.Logger.Warn("component name already in use", "type", collision.ComponentType, "name", collision.Name, "operationId", collision.OperationID, "renamed", collision.Renamed)
+	}
 
 	for _, component := range components {
+		info, ok := describeComponent(component)
+		if !ok {
+			continue
+		}
+		s.components = append(s.components, info)
+
+		registered := Component{ComponentInfo: info}
 		switch c := component.(type) {
 		case *executor.OpenAPITool:
-			s.mcpServer.AddTool(c.Tool(), s.createToolHandler(c))
+			registered.Tool = &server.ServerTool{Tool: c.Tool(), Handler: s.createToolHandler(c)}
 
 		case *executor.OpenAPIResource:
-			s.mcpServer.AddResource(c.Resource(), s.createResourceHandler(c))
+			registered.Resource = &server.ServerResource{Resource: c.Resource(), Handler: s.createResourceHandler(c)}
 
 		case *executor.OpenAPIResourceTemplate:
-			s.mcpServer.AddResourceTemplate(c.Template(), s.createResourceTemplateHandler(c))
+			registered.ResourceTemplate = &server.ServerResourceTemplate{Template: c.Template(), Handler: s.createResourceTemplateHandler(c)}
 		}
+		s.addComponent(registered)
 	}
 
 	return nil
 }
 
+// filterOperations keeps routes whose operationId is allowed and not denied. An
+// empty allowlist allows everything; allowlisted ids missing from the spec are logged.
+func filterOperations(routes []ir.HTTPRoute, allow, deny []string, logger Logger) []ir.HTTPRoute {
+	if len(allow) == 0 && len(deny) == 0 {
+		return routes
+	}
+
+	allowed := make(map[string]bool, len(allow))
+	for _, id := range allow {
+		allowed[id] = false
+	}
+	denied := make(map[string]bool, len(deny))
+	for _, id := range deny {
+		denied[id] = true
+	}
+
+	filtered := make([]ir.HTTPRoute, 0, len(routes))
+	for _, route := range routes {
+		if len(allow) > 0 {
+			if _, ok := allowed[route.OperationID]; !ok {
+				continue
+			}
+			allowed[route.OperationID] = true
+		}
+		if denied[route.OperationID] {
+			continue
+		}
+		filtered = append(filtered, route)
+	}
+
+	for _, id := range allow {
+		if !allowed[id] {
+			logger.Warn("allowlisted operation not found in spec", "operationId", id)
+			allowed[id] = true
+		}
+	}
+
+	return filtered
+}
+
+// excludeDeprecated drops the operations marked `deprecated: true`.
+func excludeDeprecated(routes []ir.HTTPRoute) []ir.HTTPRoute {
+	kept := make([]ir.HTTPRoute, 0, len(routes))
+	for _, route := range routes {
+		if !route.Deprecated {
+			kept = append(kept, route)
+		}
+	}
+	return kept
+}
+
+// NameCollisions reports the components renamed because their name was already taken.
+func (s *Server) NameCollisions() []NameCollision {
+	s.reloadMu.RLock()
+	defer s.reloadMu.RUnlock()
+	return s.factory.NameCollisions()
+}
+
+// createToolHandler settles everything the handler needs up front, so a call
+// reads nothing ReloadSpec replaces.
 func (s *Server) createToolHandler(tool *executor.OpenAPITool) server.ToolHandlerFunc {
+	dryRun := s.options.DryRun
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if dryRun {
+			ctx = executor.WithDryRun(ctx, true)
+		}
 		return tool.Run(ctx, request)
 	}
 }
 
 func (s *Server) createResourceHandler(resource *executor.OpenAPIResource) server.ResourceHandlerFunc {
 	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-		content, err := resource.Read(ctx)
-		if err != nil {
-			return nil, err
-		}
-
-		return []mcp.ResourceContents{
-			mcp.TextResourceContents{
-				URI:      resource.Resource().URI,
-				MIMEType: "application/json",
-				Text:     content,
-			},
-		}, nil
+		return resource.ReadContents(ctx, resource.Resource().URI)
 	}
 }
 
@@ -198,19 +537,10 @@ func (s *Server) createResourceTemplateHandler(template *executor.OpenAPIResourc
 			template.GetBaseURL(),
 			params,
 		)
+		paramResource.WithHeaderPropagation(template.GetHeaderPropagation())
+		paramResource.WithURIQuery(request.Params.URI)
 
-		content, err := paramResource.Read(ctx)
-		if err != nil {
-			return nil, err
-		}
-
-		return []mcp.ResourceContents{
-			mcp.TextResourceContents{
-				URI:      request.Params.URI,
-				MIMEType: "application/json",
-				Text:     content,
-			},
-		}, nil
+		return paramResource.ReadContents(ctx, request.Params.URI)
 	}
 }
 
@@ -232,6 +562,9 @@ func extractParametersFromURI(uri, template string) map[string]string {
 			return nil
 		}
 
+		if idx := strings.Index(value, "{?"); idx >= 0 {
+			value = value[:idx]
+		}
 		if idx := strings.IndexAny(value, "?#"); idx >= 0 {
 			value = value[:idx]
 		}
@@ -1,11 +1,23 @@
 package openapimcp
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/specx2/openapi-mcp/core/executor"
+	"github.com/specx2/openapi-mcp/core/ir"
+	"github.com/specx2/openapi-mcp/core/mapper"
+	"github.com/specx2/openapi-mcp/core/parser"
 )
 
 func TestPrepareHTTPClientDefaultConfig(t *testing.T) {
@@ -53,6 +65,35 @@ func TestPrepareHTTPClientAppliesConfig(t *testing.T) {
 	}
 }
 
+type recordingClient struct {
+	requests []*http.Request
+}
+
+func (c *recordingClient) Do(req *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req)
+	return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Header: make(http.Header)}, nil
+}
+
+func TestPrepareHTTPClientWrapsCustomClientWithInterceptors(t *testing.T) {
+	custom := &recordingClient{}
+	opts := defaultServerOptions()
+	WithHTTPClient(custom)(opts)
+	WithRequestInterceptor(func(req *http.Request) error {
+		req.Header.Set("X-Correlation-ID", "abc")
+		return nil
+	})(opts)
+
+	client, _ := prepareHTTPClient(opts)
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/items", nil)
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+
+	if len(custom.requests) != 1 || custom.requests[0].Header.Get("X-Correlation-ID") != "abc" {
+		t.Fatalf("expected interceptor to run before custom client")
+	}
+}
+
 func TestNewServerUsesClientConfigBaseURL(t *testing.T) {
 	spec := []byte(`{
         "openapi": "3.1.0",
@@ -125,3 +166,815 @@ func TestExtractParametersFromURIMismatchSegments(t *testing.T) {
 		t.Fatalf("expected no parameters for mismatched segments, got %#v", params)
 	}
 }
+
+func TestExtractParametersFromURIIgnoresQuery(t *testing.T) {
+	params := extractParametersFromURI(
+		"items/42?tags=a&tags=b&__header__X-Tenant=acme",
+		"items/{id}{?tags,__header__X-Tenant}",
+	)
+
+	if len(params) != 1 || params["id"] != "42" {
+		t.Fatalf("expected only the path parameter, got %#v", params)
+	}
+}
+
+func TestNewServerAppliesOperationAllowlist(t *testing.T) {
+	spec := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Test", "version": "1.0.0"},
+        "paths": {
+            "/items": {
+                "get": {"operationId": "listItems", "tags": ["public"], "responses": {"200": {"description": "ok"}}},
+                "post": {"operationId": "createItem", "tags": ["public"], "responses": {"201": {"description": "ok"}}}
+            },
+            "/items/{id}": {
+                "delete": {
+                    "operationId": "deleteItem",
+                    "tags": ["public"],
+                    "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+                    "responses": {"204": {"description": "ok"}}
+                }
+            }
+        }
+    }`)
+
+	toolMaps := WithRouteMaps([]mapper.RouteMap{{
+		Methods:     []string{"*"},
+		PathPattern: regexp.MustCompile(".*"),
+		MCPType:     mapper.MCPTypeTool,
+	}})
+	srv, err := NewServer(spec,
+		toolMaps,
+		WithOperationAllowlist([]string{"listItems", "deleteItem", "lsitItems"}),
+		WithOperationDenylist([]string{"deleteItem"}),
+	)
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+
+	tools := srv.MCPServer().ListTools()
+	if len(tools) != 1 || tools["listItems"] == nil {
+		names := make([]string, 0, len(tools))
+		for name := range tools {
+			names = append(names, name)
+		}
+		t.Fatalf("expected only listItems to be registered, got %v", names)
+	}
+}
+
+func TestNewServerMarksAndExcludesDeprecatedOperations(t *testing.T) {
+	spec := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Test", "version": "1.0.0"},
+        "paths": {
+            "/items": {
+                "get": {"operationId": "listItems", "summary": "List items", "deprecated": true, "responses": {"200": {"description": "ok"}}},
+                "post": {"operationId": "createItem", "responses": {"201": {"description": "ok"}}}
+            }
+        }
+    }`)
+	toolMaps := WithRouteMaps([]mapper.RouteMap{{
+		Methods:     []string{"*"},
+		PathPattern: regexp.MustCompile(".*"),
+		MCPType:     mapper.MCPTypeTool,
+	}})
+
+	srv, err := NewServer(spec, toolMaps)
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+	listItems := srv.MCPServer().ListTools()["listItems"]
+	if listItems == nil {
+		t.Fatalf("expected the deprecated operation to be registered by default")
+	}
+	tool := listItems.Tool
+	if !strings.HasPrefix(tool.Description, "**Deprecated:**") || tool.Annotations.Title != "List items (deprecated)" {
+		t.Fatalf("expected deprecation markers, got description %q and title %q", tool.Description, tool.Annotations.Title)
+	}
+	if openapiMeta := tool.Meta.AdditionalFields["openapi"].(map[string]any); openapiMeta["deprecated"] != true {
+		t.Fatalf("expected deprecated in the tool meta, got %#v", openapiMeta)
+	}
+
+	srv, err = NewServer(spec, toolMaps, WithExcludeDeprecated(true))
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+	if tools := srv.MCPServer().ListTools(); len(tools) != 1 || tools["createItem"] == nil {
+		t.Fatalf("expected only createItem to be registered, got %d tools", len(tools))
+	}
+}
+
+func TestRegisterSpecWithAliasResolvesNameCollisions(t *testing.T) {
+	spec := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Test", "version": "1.0.0"},
+        "paths": {
+            "/users": {"post": {"operationId": "listUsers", "responses": {"200": {"description": "ok"}}}}
+        }
+    }`)
+
+	srv, err := NewServer(spec, WithSpecAlias("accounts"), WithNameCollisionStrategy(NameCollisionPrefix))
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+	if err := srv.RegisterSpecWithAlias("billing", spec); err != nil {
+		t.Fatalf("RegisterSpecWithAlias returned error: %v", err)
+	}
+
+	tools := srv.MCPServer().ListTools()
+	if len(tools) != 2 || tools["listUsers"] == nil || tools["billing_listUsers"] == nil {
+		t.Fatalf("expected listUsers and billing_listUsers, got %d tools", len(tools))
+	}
+
+	collisions := srv.NameCollisions()
+	if len(collisions) != 1 || collisions[0].Name != "listUsers" || collisions[0].Renamed != "billing_listUsers" {
+		t.Fatalf("unexpected collisions: %+v", collisions)
+	}
+}
+
+func TestServerComponentsDescribesRegisteredComponents(t *testing.T) {
+	spec := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Test", "version": "1.0.0"},
+        "paths": {
+            "/items": {
+                "get": {"operationId": "listItems", "tags": ["items"], "responses": {"200": {"description": "ok"}}},
+                "post": {"operationId": "createItem", "tags": ["items"], "responses": {"201": {"description": "ok"}}}
+            }
+        }
+    }`)
+
+	srv, err := NewServer(spec,
+		WithCustomNames(map[string]string{"createItem": "add_item"}),
+		WithRouteMaps([]mapper.RouteMap{{
+			Methods:     []string{"GET"},
+			PathPattern: regexp.MustCompile(".*"),
+			MCPType:     mapper.MCPTypeResource,
+		}}),
+	)
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+
+	components := srv.Components()
+	if len(components) != 2 {
+		t.Fatalf("expected two components, got %+v", components)
+	}
+
+	byOperation := make(map[string]ComponentInfo)
+	for _, component := range components {
+		byOperation[component.OperationID] = component
+	}
+
+	list := byOperation["listItems"]
+	if list.Type != mapper.MCPTypeResource || list.Method != "GET" || list.Path != "/items" || list.Name != "listItems" {
+		t.Fatalf("unexpected listItems info: %+v", list)
+	}
+
+	create := byOperation["createItem"]
+	if create.Type != mapper.MCPTypeTool || create.Name != "add_item" || len(create.Tags) != 1 || create.Tags[0] != "items" {
+		t.Fatalf("unexpected createItem info: %+v", create)
+	}
+
+	components[0].Tags = append(components[0].Tags, "mutated")
+	if len(srv.Components()[0].Tags) == len(components[0].Tags) {
+		t.Fatalf("expected Components to return copies")
+	}
+
+	explanations := srv.ExplainRouteMappings()
+	if len(explanations) != 2 {
+		t.Fatalf("expected an explanation per operation, got %+v", explanations)
+	}
+	for _, explanation := range explanations {
+		if explanation.OperationID == "listItems" && (explanation.RuleIndex != 0 || explanation.DefaultRule || explanation.MCPType != mapper.MCPTypeResource) {
+			t.Fatalf("unexpected listItems explanation: %+v", explanation)
+		}
+		if explanation.OperationID == "createItem" && (!explanation.DefaultRule || explanation.MCPType != mapper.MCPTypeTool) {
+			t.Fatalf("unexpected createItem explanation: %+v", explanation)
+		}
+	}
+}
+
+func TestBuildComponentsRegistersIntoExternalServer(t *testing.T) {
+	spec := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Test", "version": "1.0.0"},
+        "paths": {
+            "/items": {
+                "get": {"operationId": "listItems", "responses": {"200": {"description": "ok"}}},
+                "post": {"operationId": "createItem", "responses": {"201": {"description": "ok"}}}
+            },
+            "/items/{id}": {
+                "get": {
+                    "operationId": "getItem",
+                    "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+                    "responses": {"200": {"description": "ok"}}
+                }
+            }
+        }
+    }`)
+
+	components, err := BuildComponents(spec, WithRouteMaps([]mapper.RouteMap{
+		{Methods: []string{"GET"}, PathPattern: regexp.MustCompile(`\{`), MCPType: mapper.MCPTypeResourceTemplate},
+		{Methods: []string{"GET"}, PathPattern: regexp.MustCompile(".*"), MCPType: mapper.MCPTypeResource},
+	}))
+	if err != nil {
+		t.Fatalf("BuildComponents returned error: %v", err)
+	}
+	if len(components) != 3 {
+		t.Fatalf("expected three components, got %+v", components)
+	}
+	for _, component := range components {
+		set := 0
+		for _, present := range []bool{component.Tool != nil, component.Resource != nil, component.ResourceTemplate != nil} {
+			if present {
+				set++
+			}
+		}
+		if set != 1 {
+			t.Fatalf("expected exactly one kind per component, got %+v", component)
+		}
+	}
+
+	external := server.NewMCPServer("host", "1.0.0")
+	external.AddTool(mcp.NewTool("own_tool"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	for _, component := range components {
+		component.AddTo(external)
+	}
+
+	tools := external.ListTools()
+	if len(tools) != 2 || tools["own_tool"] == nil || tools["createItem"] == nil {
+		t.Fatalf("expected the host tool next to createItem, got %v", tools)
+	}
+
+	srv, err := NewServer(spec)
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+	other := server.NewMCPServer("other", "1.0.0")
+	srv.RegisterInto(other)
+	if got := len(other.ListTools()); got != 3 {
+		t.Fatalf("expected RegisterInto to add all three tools, got %d", got)
+	}
+}
+
+type notificationSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *notificationSession) Initialize()       {}
+func (s *notificationSession) Initialized() bool { return true }
+func (s *notificationSession) SessionID() string { return "test-session" }
+func (s *notificationSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func TestNewServerStreamsProgressNotifications(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 1; i <= 3; i++ {
+			fmt.Fprintf(w, "data: {\"step\":%d}\n\n", i)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer upstream.Close()
+
+	spec := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Test", "version": "1.0.0"},
+        "paths": {
+            "/jobs": {"post": {"operationId": "runJob", "responses": {"200": {"description": "ok", "content": {"text/event-stream": {}}}}}}
+        }
+    }`)
+	srv, err := NewServer(spec, WithBaseURL(upstream.URL), WithStreamingProgress(true))
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+
+	session := &notificationSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	ctx := srv.MCPServer().WithContext(context.Background(), session)
+	response := srv.MCPServer().HandleMessage(ctx, []byte(`{
+        "jsonrpc": "2.0",
+        "id": 1,
+        "method": "tools/call",
+        "params": {"name": "runJob", "arguments": {}, "_meta": {"progressToken": "job-1"}}
+    }`))
+	result, ok := response.(mcp.JSONRPCResponse).Result.(mcp.CallToolResult)
+	if !ok || result.IsError {
+		t.Fatalf("expected a successful tool result, got %#v", response)
+	}
+	records, _ := result.StructuredContent.(map[string]interface{})["result"].([]interface{})
+	if len(records) != 3 {
+		t.Fatalf("expected the aggregated events, got %#v", result.StructuredContent)
+	}
+
+	close(session.notifications)
+	var progress []interface{}
+	for notification := range session.notifications {
+		if notification.Method != "notifications/progress" {
+			continue
+		}
+		fields := notification.Params.AdditionalFields
+		if fields["progressToken"] != "job-1" {
+			t.Fatalf("unexpected progress token in %v", fields)
+		}
+		progress = append(progress, fields["progress"])
+	}
+	if !reflect.DeepEqual(progress, []interface{}{1, 2, 3}) {
+		t.Fatalf("expected a progress notification per event, got %v", progress)
+	}
+}
+
+func TestNewServerAppliesSchemaOverrides(t *testing.T) {
+	spec := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Test", "version": "1.0.0"},
+        "paths": {
+            "/notes": {
+                "post": {
+                    "operationId": "createNote",
+                    "requestBody": {"content": {"application/json": {"schema": {"type": "object", "properties": {"text": {"type": "string"}}}}}},
+                    "responses": {"200": {"description": "ok", "content": {"application/json": {"schema": {"type": "object"}}}}}
+                }
+            }
+        }
+    }`)
+
+	client := &recordingClient{}
+	srv, err := NewServer(spec,
+		WithHTTPClient(client),
+		WithSchemaOverride(func(route ir.HTTPRoute, schema ir.Schema) ir.Schema {
+			if route.OperationID == "createNote" {
+				schema["required"] = []string{"text"}
+			}
+			return nil
+		}),
+		WithOutputSchemaOverride(func(route ir.HTTPRoute, schema ir.Schema) ir.Schema {
+			return ir.Schema{"type": "object", "description": "patched"}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+
+	tool := srv.MCPServer().GetTool("createNote")
+	if tool == nil {
+		t.Fatalf("expected createNote tool")
+	}
+	if !strings.Contains(string(tool.Tool.RawInputSchema), `"required":["text"]`) {
+		t.Fatalf("expected overridden input schema, got %s", tool.Tool.RawInputSchema)
+	}
+	if !strings.Contains(string(tool.Tool.RawOutputSchema), "patched") {
+		t.Fatalf("expected overridden output schema, got %s", tool.Tool.RawOutputSchema)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{}
+	result, err := tool.Handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if !result.IsError || len(client.requests) != 0 {
+		t.Fatalf("expected validator compiled from overridden schema to reject the call")
+	}
+}
+
+type recordingLogger struct {
+	records []string
+}
+
+func (l *recordingLogger) record(level, msg string, fields []interface{}) {
+	parts := []string{level, msg}
+	for _, field := range fields {
+		parts = append(parts, fmt.Sprint(field))
+	}
+	l.records = append(l.records, strings.Join(parts, " "))
+}
+
+func (l *recordingLogger) Debug(msg string, fields ...interface{}) { l.record("debug", msg, fields) }
+func (l *recordingLogger) Info(msg string, fields ...interface{})  { l.record("info", msg, fields) }
+func (l *recordingLogger) Warn(msg string, fields ...interface{})  { l.record("warn", msg, fields) }
+func (l *recordingLogger) Error(msg string, fields ...interface{}) { l.record("error", msg, fields) }
+
+func TestNewServerRoutesLogsThroughLogger(t *testing.T) {
+	spec := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Test", "version": "1.0.0"},
+        "paths": {
+            "/notes": {
+                "post": {
+                    "operationId": "createNote",
+                    "requestBody": {"content": {"application/json": {"schema": {"type": "object", "properties": {"text": {"type": "string"}}}}}},
+                    "responses": {"204": {"description": "ok"}}
+                }
+            }
+        }
+    }`)
+
+	logger := &recordingLogger{}
+	srv, err := NewServer(spec,
+		WithHTTPClient(&recordingClient{}),
+		WithOperationAllowlist([]string{"createNote", "createNotes"}),
+		WithLogger(logger),
+	)
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"text": "secret"}
+	if _, err := srv.MCPServer().GetTool("createNote").Handler(context.Background(), request); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+
+	joined := strings.Join(logger.records, "\n")
+	for _, want := range []string{
+		"warn allowlisted operation not found in spec operationId createNotes",
+		"debug tool call received tool createNote arguments map[text:secret]",
+		"info tool call finished tool createNote method POST status 204 isError false",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("expected log record %q, got:\n%s", want, joined)
+		}
+	}
+	for _, record := range logger.records {
+		if !strings.HasPrefix(record, "debug") && strings.Contains(record, "secret") {
+			t.Fatalf("expected arguments only at debug level, got %q", record)
+		}
+	}
+}
+
+func TestNewServerWithValidationRejectsBrokenSpec(t *testing.T) {
+	spec := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Test", "version": "1.0.0"},
+        "paths": {
+            "/items": {
+                "get": {"operationId": "listItems", "responses": {"200": {"description": "ok"}}},
+                "post": {"operationId": "listItems", "responses": {"201": {"description": "ok"}}}
+            }
+        }
+    }`)
+
+	if _, err := NewServer(spec); err != nil {
+		t.Fatalf("expected spec to register without validation, got %v", err)
+	}
+
+	_, err := NewServer(spec, WithValidation(true))
+	var validationErr *parser.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if len(validationErr.Diagnostics) != 1 || !strings.Contains(validationErr.Diagnostics[0].Message, `operationId "listItems" is already used`) {
+		t.Fatalf("expected the duplicate operationId to be reported, got %v", validationErr.Diagnostics)
+	}
+}
+
+func TestNewServerFromURLFetchesSpec(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/specs/api.yaml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`openapi: 3.0.3
+info:
+  title: Test
+  version: 1.0.0
+paths:
+  /items:
+    get:
+      operationId: listItems
+      responses:
+        '200':
+          description: ok
+`))
+	})
+	upstream := httptest.NewServer(mux)
+	defer upstream.Close()
+
+	srv, err := NewServerFromURL(upstream.URL+"/specs/api.yaml", WithRouteMaps([]mapper.RouteMap{{
+		Methods:     []string{"*"},
+		PathPattern: regexp.MustCompile(".*"),
+		MCPType:     mapper.MCPTypeTool,
+	}}))
+	if err != nil {
+		t.Fatalf("NewServerFromURL returned error: %v", err)
+	}
+	if srv.MCPServer().ListTools()["listItems"] == nil {
+		t.Fatalf("expected listItems to be registered")
+	}
+
+	if _, err := NewServerFromURL(upstream.URL + "/specs/missing.yaml"); err == nil || !strings.Contains(err.Error(), "failed to load OpenAPI spec") {
+		t.Fatalf("expected a fetch error, got %v", err)
+	}
+}
+
+func TestNewServerExposesWebhooks(t *testing.T) {
+	spec := []byte(`{
+        "openapi": "3.1.0",
+        "info": {"title": "Test", "version": "1.0.0"},
+        "paths": {
+            "/orders": {"get": {"operationId": "listOrders", "responses": {"200": {"description": "ok"}}}}
+        },
+        "webhooks": {
+            "orderCreated": {
+                "post": {
+                    "summary": "Order created",
+                    "requestBody": {
+                        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Order"}}}
+                    },
+                    "responses": {"200": {"description": "received"}}
+                }
+            }
+        },
+        "components": {
+            "schemas": {
+                "Order": {"type": "object", "properties": {"id": {"type": "string"}}},
+                "Unused": {"type": "string"}
+            }
+        }
+    }`)
+
+	srv, err := NewServer(spec, WithWebhookResources(true))
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+	webhooks := srv.Webhooks()
+	if len(webhooks) != 1 || webhooks[0].Name != "orderCreated" {
+		t.Fatalf("expected the orderCreated webhook, got %#v", webhooks)
+	}
+
+	response := srv.MCPServer().HandleMessage(context.Background(), []byte(`{
+        "jsonrpc": "2.0",
+        "id": 1,
+        "method": "resources/read",
+        "params": {"uri": "webhook://orderCreated"}
+    }`))
+	result, ok := response.(mcp.JSONRPCResponse).Result.(mcp.ReadResourceResult)
+	if !ok || len(result.Contents) != 1 {
+		t.Fatalf("expected the webhook resource to be readable, got %#v", response)
+	}
+	text := result.Contents[0].(mcp.TextResourceContents).Text
+	if !strings.Contains(text, `"id"`) || !strings.Contains(text, `"application/json"`) || strings.Contains(text, "Unused") {
+		t.Fatalf("expected only the payload schema, got %s", text)
+	}
+}
+
+func TestNewServerInjectsAPIKeyFromEnv(t *testing.T) {
+	spec := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Test", "version": "1.0.0"},
+        "servers": [{"url": "https://api.example.com"}],
+        "components": {
+            "securitySchemes": {
+                "headerKey": {"type": "apiKey", "in": "header", "name": "X-API-Key"},
+                "queryKey": {"type": "apiKey", "in": "query", "name": "api_key"},
+                "bearer": {"type": "http", "scheme": "bearer"}
+            }
+        },
+        "paths": {
+            "/items": {"post": {"operationId": "createItem", "responses": {"204": {"description": "ok"}}}}
+        }
+    }`)
+	t.Setenv("TEST_API_KEY", "secret")
+
+	client := &recordingClient{}
+	srv, err := NewServer(spec,
+		WithHTTPClient(client),
+		WithAPIKeyFromEnv("headerKey", "TEST_API_KEY"),
+		WithAPIKeyFromEnv("queryKey", "TEST_API_KEY"),
+	)
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{}
+	if _, err := srv.MCPServer().GetTool("createItem").Handler(context.Background(), request); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if len(client.requests) != 1 {
+		t.Fatalf("expected one upstream request, got %d", len(client.requests))
+	}
+	req := client.requests[0]
+	if req.Header.Get("X-API-Key") != "secret" || req.URL.Query().Get("api_key") != "secret" {
+		t.Fatalf("expected the key in the header and query, got %v and %s", req.Header, req.URL)
+	}
+
+	for _, tc := range []struct {
+		option ServerOption
+		want   string
+	}{
+		{WithAPIKeyFromEnv("missing", "TEST_API_KEY"), `security scheme "missing" is not defined in the spec (available: bearer, headerKey, queryKey)`},
+		{WithAPIKeyFromEnv("bearer", "TEST_API_KEY"), "expected apiKey"},
+		{WithAPIKeyFromEnv("headerKey", "TEST_UNSET_API_KEY"), "TEST_UNSET_API_KEY"},
+	} {
+		if _, err := NewServer(spec, WithHTTPClient(&recordingClient{}), tc.option); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("expected an error containing %q, got %v", tc.want, err)
+		}
+	}
+}
+
+func TestNewServerAppliesSpecPatches(t *testing.T) {
+	spec := []byte(`openapi: 3.0.3
+info:
+  title: Test
+  version: 1.0.0
+paths:
+  /items:
+    get:
+      responses:
+        '200':
+          description: ok
+`)
+	toolMaps := WithRouteMaps([]mapper.RouteMap{{
+		Methods:     []string{"*"},
+		PathPattern: regexp.MustCompile(".*"),
+		MCPType:     mapper.MCPTypeTool,
+	}})
+
+	srv, err := NewServer(spec, toolMaps,
+		WithSpecPatch([]byte(`{"paths": {"/items": {"get": {"operationId": "listItems"}}}}`)),
+		WithSpecJSONPatch([]byte(`[{"op": "add", "path": "/paths/~1items/get/summary", "value": "List items"}]`)),
+	)
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+	tool := srv.MCPServer().GetTool("listItems")
+	if tool == nil || tool.Tool.Annotations.Title != "List items" {
+		t.Fatalf("expected the patched operation, got %v", srv.MCPServer().ListTools())
+	}
+
+	if _, err := NewServer(spec, WithSpecJSONPatch([]byte(`[{"op": "remove", "path": "/missing"}]`))); err == nil || !strings.Contains(err.Error(), "failed to apply spec patch 1") {
+		t.Fatalf("expected a patch error, got %v", err)
+	}
+}
+
+func TestNewServerCapsToolCountByPriority(t *testing.T) {
+	spec := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Test", "version": "1.0.0"},
+        "paths": {
+            "/legacy": {"post": {"operationId": "legacyImport", "deprecated": true, "responses": {"200": {"description": "ok"}}}},
+            "/anonymous": {"post": {"responses": {"200": {"description": "ok"}}}},
+            "/items": {
+                "post": {"operationId": "createItem", "responses": {"201": {"description": "ok"}}},
+                "delete": {"operationId": "deleteItems", "responses": {"204": {"description": "ok"}}}
+            }
+        }
+    }`)
+	toolMaps := WithRouteMaps([]mapper.RouteMap{{
+		Methods:     []string{"*"},
+		PathPattern: regexp.MustCompile(".*"),
+		MCPType:     mapper.MCPTypeTool,
+	}})
+
+	logger := &recordingLogger{}
+	srv, err := NewServer(spec, toolMaps, WithMaxToolCount(3), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+	tools := srv.MCPServer().ListTools()
+	if len(tools) != 3 || tools["createItem"] == nil || tools["deleteItems"] == nil || tools["legacyImport"] == nil {
+		t.Fatalf("expected the operations with operationIds to be kept, got %d tools", len(tools))
+	}
+	joined := strings.Join(logger.records, "\n")
+	for _, want := range []string{
+		"info tool dropped by max tool count operationId  method POST path /anonymous priority 1",
+		"warn max tool count reached limit 3 dropped 1",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("expected log record %q, got:\n%s", want, joined)
+		}
+	}
+
+	srv, err = NewServer(spec, toolMaps, WithMaxToolCount(2), WithToolPriority(func(route ir.HTTPRoute) int {
+		switch {
+		case route.Deprecated:
+			return 2
+		case route.OperationID == "createItem":
+			return 1
+		}
+		return 0
+	}))
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+	tools = srv.MCPServer().ListTools()
+	if len(tools) != 2 || tools["legacyImport"] == nil || tools["createItem"] == nil {
+		t.Fatalf("expected the custom priority to pick the kept tools, got %d tools", len(tools))
+	}
+
+	if err := srv.RegisterSpecWithAlias("mirror", spec); err != nil {
+		t.Fatalf("RegisterSpec returned error: %v", err)
+	}
+	if tools := srv.MCPServer().ListTools(); len(tools) != 2 {
+		t.Fatalf("expected the cap to span registered specs, got %d tools", len(tools))
+	}
+}
+
+func TestServerReloadSpecSwapsComponents(t *testing.T) {
+	spec := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Test", "version": "1.0.0"},
+        "paths": {
+            "/items": {
+                "get": {"operationId": "listItems", "responses": {"200": {"description": "ok"}}},
+                "post": {"operationId": "createItem", "responses": {"201": {"description": "ok"}}}
+            }
+        }
+    }`)
+	srv, err := NewServer(spec)
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+
+	reloaded := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Test", "version": "1.1.0"},
+        "paths": {
+            "/items": {
+                "post": {"operationId": "createItem", "responses": {"201": {"description": "ok"}}},
+                "delete": {"operationId": "deleteItems", "responses": {"204": {"description": "ok"}}}
+            }
+        }
+    }`)
+	if err := srv.ReloadSpec(reloaded); err != nil {
+		t.Fatalf("ReloadSpec returned error: %v", err)
+	}
+	tools := srv.MCPServer().ListTools()
+	if len(tools) != 2 || tools["createItem"] == nil || tools["deleteItems"] == nil {
+		t.Fatalf("expected the reloaded tools, got %v", tools)
+	}
+	components := srv.Components()
+	if len(components) != 2 {
+		t.Fatalf("expected the reloaded components, got %+v", components)
+	}
+	for _, component := range components {
+		if component.OperationID == "listItems" {
+			t.Fatalf("expected listItems to be gone, got %+v", components)
+		}
+	}
+
+	if err := srv.ReloadSpec([]byte(`{"openapi": "3.0.3", "paths": `)); err == nil {
+		t.Fatal("expected a broken spec to be rejected")
+	}
+	if tools := srv.MCPServer().ListTools(); len(tools) != 2 || tools["deleteItems"] == nil {
+		t.Fatalf("expected a failed reload to keep the tools, got %v", tools)
+	}
+}
+
+func TestServerReloadSpecKeepsRegisteredSpecs(t *testing.T) {
+	spec := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Test", "version": "1.0.0"},
+        "paths": {
+            "/items": {"get": {"operationId": "listItems", "responses": {"200": {"description": "ok"}}}}
+        }
+    }`)
+	orders := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Orders", "version": "1.0.0"},
+        "paths": {
+            "/orders": {"get": {"operationId": "listOrders", "responses": {"200": {"description": "ok"}}}}
+        }
+    }`)
+	srv, err := NewServer(spec, WithMaxToolCount(2))
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+	if err := srv.RegisterSpecWithAlias("orders", orders); err != nil {
+		t.Fatalf("RegisterSpec returned error: %v", err)
+	}
+	external := server.NewMCPServer("external", "1.0.0")
+	srv.RegisterInto(external)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			srv.Components()
+			srv.RegisteredComponents()
+			srv.ExplainRouteMappings()
+		}
+	}()
+	reloaded := []byte(`{
+        "openapi": "3.0.3",
+        "info": {"title": "Test", "version": "1.1.0"},
+        "paths": {
+            "/items": {"post": {"operationId": "createItem", "responses": {"201": {"description": "ok"}}}}
+        }
+    }`)
+	if err := srv.ReloadSpec(reloaded); err != nil {
+		t.Fatalf("ReloadSpec returned error: %v", err)
+	}
+	<-done
+
+	for name, mcpServer := range map[string]*server.MCPServer{"server": srv.MCPServer(), "external": external} {
+		tools := mcpServer.ListTools()
+		if len(tools) != 2 || tools["createItem"] == nil || tools["listOrders"] == nil {
+			t.Fatalf("%s: expected the reloaded tools and the registered spec's, got %v", name, tools)
+		}
+	}
+	if srv.toolCount != 2 {
+		t.Fatalf("expected the tool count to cover both specs, got %d", srv.toolCount)
+	}
+}
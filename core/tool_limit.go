@@ -0,0 +1,80 @@
+package openapimcp
+
+import (
+	"sort"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+	"github.com/specx2/openapi-mcp/core/mapper"
+)
+
+// ToolPriority ranks operations when WithMaxToolCount has to drop some; higher
+// values are kept first.
+type ToolPriority func(route ir.HTTPRoute) int
+
+// DefaultToolPriority prefers operations that have an operationId, then those
+// that are not deprecated.
+func DefaultToolPriority(route ir.HTTPRoute) int {
+	priority := 0
+	if route.OperationID != "" {
+		priority += 2
+	}
+	if !route.Deprecated {
+		priority++
+	}
+	return priority
+}
+
+// limitTools keeps at most MaxToolCount tools across every registered spec.
+// When a spec brings more tool routes than are left, the highest-priority ones
+// stay, ties going to the earlier route, and the rest are logged and dropped.
+// Resources, resource templates and the order of kept routes are unchanged.
+func (s *Server) limitTools(mappedRoutes []mapper.MappedRoute) []mapper.MappedRoute {
+	limit := s.options.MaxToolCount
+	if limit <= 0 {
+		return mappedRoutes
+	}
+
+	var tools []int
+	for idx, mapped := range mappedRoutes {
+		if mapped.MCPType == mapper.MCPTypeTool {
+			tools = append(tools, idx)
+		}
+	}
+	remaining := limit - s.toolCount
+	if remaining < 0 {
+		remaining = 0
+	}
+	if len(tools) <= remaining {
+		s.toolCount += len(tools)
+		return mappedRoutes
+	}
+	s.toolCount += remaining
+
+	priority := s.options.ToolPriority
+	if priority == nil {
+		priority = DefaultToolPriority
+	}
+	ranks := make(map[int]int, len(tools))
+	for _, idx := range tools {
+		ranks[idx] = priority(mappedRoutes[idx].Route)
+	}
+	sort.SliceStable(tools, func(i, j int) bool {
+		return ranks[tools[i]] > ranks[tools[j]]
+	})
+
+	dropped := make(map[int]bool, len(tools)-remaining)
+	for _, idx := range tools[remaining:] {
+		dropped[idx] = true
+		route := mappedRoutes[idx].Route
+		s.options.Logger.Info("tool dropped by max tool count", "operationId", route.OperationID, "method", route.Method, "path", route.Path, "priority", ranks[idx])
+	}
+	s.options.Logger.Warn("max tool count reached", "limit", limit, "dropped", len(dropped))
+
+	kept := make([]mapper.MappedRoute, 0, len(mappedRoutes)-len(dropped))
+	for idx, mapped := range mappedRoutes {
+		if !dropped[idx] {
+			kept = append(kept, mapped)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,120 @@
+package openapimcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/specx2/openapi-mcp/core/ir"
+	"github.com/specx2/openapi-mcp/core/mapper"
+	"github.com/specx2/openapi-mcp/core/parser"
+)
+
+// Webhooks lists the OpenAPI 3.1 webhooks of every registered spec, in
+// registration order. Specs parsed by a parser without webhook support add none.
+func (s *Server) Webhooks() []ir.WebhookInfo {
+	s.reloadMu.RLock()
+	defer s.reloadMu.RUnlock()
+	return append([]ir.WebhookInfo(nil), s.webhooks...)
+}
+
+func (s *Server) registerWebhooks(p parser.OpenAPIParser) {
+	wp, ok := p.(parser.WebhookParser)
+	if !ok {
+		return
+	}
+	webhooks := wp.Webhooks()
+	s.webhooks = append(s.webhooks, webhooks...)
+	if !s.options.WebhookResources {
+		return
+	}
+
+	for _, webhook := range webhooks {
+		payload, err := json.MarshalIndent(describeWebhook(webhook), "", "  ")
+		if err != nil {
+			s.options.Logger.Warn("failed to describe webhook", "webhook", webhook.Name, "error", err)
+			continue
+		}
+		uri := "webhook://" + webhook.Name
+		description := webhook.Summary
+		if description == "" {
+			description = fmt.Sprintf("Payload sent by the %s webhook", webhook.Name)
+		}
+		resource := mcp.NewResource(uri, "webhook_"+webhook.Name,
+			mcp.WithResourceDescription(description),
+			mcp.WithMIMEType("application/json"),
+		)
+		handler := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(payload),
+			}}, nil
+		}
+		s.addComponent(Component{
+			ComponentInfo: ComponentInfo{Name: resource.Name, Type: mapper.MCPTypeResource},
+			Resource:      &server.ServerResource{Resource: resource, Handler: handler},
+		})
+	}
+}
+
+// describeWebhook renders the request bodies of a webhook, with the $defs they
+// reference, as the JSON document served by its resource.
+func describeWebhook(webhook ir.WebhookInfo) map[string]interface{} {
+	doc := map[string]interface{}{"name": webhook.Name}
+	if webhook.Summary != "" {
+		doc["summary"] = webhook.Summary
+	}
+	if webhook.Description != "" {
+		doc["description"] = webhook.Description
+	}
+
+	defs := webhook.SchemaDefs.Definitions()
+	used := make(map[string]interface{})
+	var operations []interface{}
+	for _, op := range webhook.Operations {
+		entry := map[string]interface{}{"method": op.Method}
+		if op.Summary != "" {
+			entry["summary"] = op.Summary
+		}
+		if op.RequestBody != nil && len(op.RequestBody.ContentSchemas) > 0 {
+			payloads := make(map[string]interface{}, len(op.RequestBody.ContentSchemas))
+			for contentType, schema := range op.RequestBody.ContentSchemas {
+				payloads[contentType] = map[string]interface{}(schema)
+				collectWebhookDefs(map[string]interface{}(schema), defs, used)
+			}
+			entry["payload"] = payloads
+		}
+		operations = append(operations, entry)
+	}
+	doc["operations"] = operations
+	if len(used) > 0 {
+		doc["$defs"] = used
+	}
+	return doc
+}
+
+func collectWebhookDefs(value interface{}, defs map[string]ir.Schema, used map[string]interface{}) {
+	switch v := value.(type) {
+	case ir.Schema:
+		collectWebhookDefs(map[string]interface{}(v), defs, used)
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, "#/$defs/") {
+			name := strings.TrimPrefix(ref, "#/$defs/")
+			if def, ok := defs[name]; ok && used[name] == nil {
+				used[name] = map[string]interface{}(def)
+				collectWebhookDefs(def, defs, used)
+			}
+		}
+		for _, child := range v {
+			collectWebhookDefs(child, defs, used)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectWebhookDefs(item, defs, used)
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/url"
 	"regexp"
 	"strings"
@@ -94,7 +93,9 @@ func WithHTTPClient(c *executorpkg.DefaultHTTPClient) HandlerOption { return htt
 type baseURLOpt struct{ u string }
 
 func (o baseURLOpt) applyHandler(cfg *handlerConfig) { cfg.BaseURL = o.u }
-func WithBaseURL(u string) HandlerOption             { return baseURLOpt{u: u} }
+
+// WithBaseURL 设置上游基础地址；声明了绝对 operation servers 的操作仍会请求其自身的主机。
+func WithBaseURL(u string) HandlerOption { return baseURLOpt{u: u} }
 
 // WithValue: 将任意键值注入 handlerConfig.Extra，便于外部无侵入扩展
 type valueOpt struct {
@@ -115,6 +116,7 @@ type registryConfig struct {
 	Tool     ToolHandler
 	Resource ResourceHandler
 	Template TemplateHandler
+	Logger   executorpkg.Logger
 }
 
 // RegistryOption：外部可替换默认处理器
@@ -135,6 +137,17 @@ type templateHandlerOpt struct{ h TemplateHandler }
 func (o templateHandlerOpt) applyRegistry(cfg *registryConfig) { cfg.Template = o.h }
 func WithTemplateHandler(h TemplateHandler) RegistryOption     { return templateHandlerOpt{h: h} }
 
+type loggerOpt struct{ l executorpkg.Logger }
+
+func (o loggerOpt) applyRegistry(cfg *registryConfig) {
+	if o.l != nil {
+		cfg.Logger = o.l
+	}
+}
+
+// WithLogger 设置注册阶段的结构化日志；默认不输出任何日志，注册摘要仅在 Debug 级别输出。
+func WithLogger(l executorpkg.Logger) RegistryOption { return loggerOpt{l: l} }
+
 // 统一的 Handler 签名（ctx, req, component, opts...）
 type ToolHandler func(ctx context.Context, req mcp.CallToolRequest, component interfaces.MCPComponent, opts ...HandlerOption) (*mcp.CallToolResult, error)
 type ResourceHandler func(ctx context.Context, req mcp.ReadResourceRequest, component interfaces.MCPComponent, opts ...HandlerOption) ([]mcp.ResourceContents, error)
@@ -221,6 +234,7 @@ func DefaultTemplateHandler(ctx context.Context, req mcp.ReadResourceRequest, co
 	}
 	params := ExtractParametersFromURI(req.Params.URI, tpl.URITemplate.Template.Raw())
 	reader := executorpkg.NewOpenAPIParameterizedResource(tpl.Name, tpl.Description, oa.Route(), cfg.HTTPClient, cfg.BaseURL, params)
+	reader.WithURIQuery(req.Params.URI)
 	text, err := reader.Read(ctx)
 	if err != nil {
 		return nil, err
@@ -230,7 +244,7 @@ func DefaultTemplateHandler(ctx context.Context, req mcp.ReadResourceRequest, co
 
 // RegisterComponents 将组件注册到 mcp-go；opts 同时支持 RegistryOption 与 HandlerOption
 func RegisterComponents(server *srv.MCPServer, components []interfaces.MCPComponent, opts ...interface{}) error {
-	rc := &registryConfig{}
+	rc := &registryConfig{Logger: executorpkg.NopLogger()}
 	// 收集 HandlerOptions（供默认 handler 使用）
 	hOpts := make([]HandlerOption, 0, len(opts))
 	for _, opt := range opts {
@@ -288,7 +302,7 @@ func RegisterComponents(server *srv.MCPServer, components []interfaces.MCPCompon
 			server.AddResourceTemplate(*tpl, h)
 
 			// 同时将 ResourceTemplate 注册为 Resource，以便在 resources/list 中显示
-			resourceURI := buildResourceURIFromTemplate(tpl)
+			resourceURI := buildResourceURIFromTemplate(tpl, rc.Logger)
 			resource := mcp.Resource{
 				URI:         resourceURI,
 				Name:        tpl.Name,
@@ -311,46 +325,64 @@ func RegisterComponents(server *srv.MCPServer, components []interfaces.MCPCompon
 	}
 
 	// 打印注册统计信息
-	printRegistrationSummary(server)
+	printRegistrationSummary(server, rc.Logger)
 
 	return nil
 }
 
-// printRegistrationSummary 打印注册到 server 的所有组件
-func printRegistrationSummary(server *srv.MCPServer) {
-	ctx := context.Background()
+// UnregisterComponents 从 mcp-go 中移除 RegisterComponents 注册的 tool 与 resource（含由 ResourceTemplate 派生的 resource）。
+// mcp-go 无法单独删除 ResourceTemplate，调用方需用 server.SetResourceTemplates 重置模板列表。
+func UnregisterComponents(server *srv.MCPServer, components []interfaces.MCPComponent) {
+	var toolNames, resourceURIs []string
+	for _, component := range components {
+		switch component.GetType() {
+		case interfaces.MCPTypeTool:
+			if tool := component.GetMCPTool(); tool != nil {
+				toolNames = append(toolNames, tool.Name)
+			}
+		case interfaces.MCPTypeResource:
+			if res := component.GetMCPResource(); res != nil {
+				resourceURIs = append(resourceURIs, res.URI)
+			}
+		case interfaces.MCPTypeResourceTemplate:
+			if tpl := component.GetMCPResourceTemplate(); tpl != nil {
+				resourceURIs = append(resourceURIs, buildResourceURIFromTemplate(tpl, executorpkg.NopLogger()))
+			}
+		}
+	}
+	if len(toolNames) > 0 {
+		server.DeleteTools(toolNames...)
+	}
+	if len(resourceURIs) > 0 {
+		server.DeleteResources(resourceURIs...)
+	}
+}
 
-	log.Println("========================================")
-	log.Println("=== MCP Server Registration Summary ===")
-	log.Println("========================================")
+// printRegistrationSummary 在 Debug 级别输出注册到 server 的所有组件
+func printRegistrationSummary(server *srv.MCPServer, logger executorpkg.Logger) {
+	ctx := context.Background()
 
-	// 1. 打印 tools/list - 完整的 tool 对象
+	// 1. tools/list - 完整的 tool 对象
 	registeredTools := server.ListTools()
-	log.Printf("[Tools/List] Total: %d", len(registeredTools))
+	logger.Info("mcp components registered", "tools", len(registeredTools))
 	for name, serverTool := range registeredTools {
-		toolJSON, _ := json.MarshalIndent(serverTool.Tool, "", "  ")
-		log.Printf("Tool [%s]:\n%s", name, string(toolJSON))
+		toolJSON, _ := json.Marshal(serverTool.Tool)
+		logger.Debug("registered tool", "name", name, "tool", string(toolJSON))
 	}
 
-	// 2. 打印 resources/list - 完整的响应 JSON
+	// 2. resources/list - 完整的响应 JSON
 	listResourcesMessage := `{"jsonrpc": "2.0", "id": 1, "method": "resources/list"}`
-	resourceResponse := server.HandleMessage(ctx, []byte(listResourcesMessage))
-	if resourceResponse != nil {
-		resourceJSON, _ := json.MarshalIndent(resourceResponse, "", "  ")
-		log.Printf("\n[Resources/List] Response:\n%s", string(resourceJSON))
+	if resourceResponse := server.HandleMessage(ctx, []byte(listResourcesMessage)); resourceResponse != nil {
+		resourceJSON, _ := json.Marshal(resourceResponse)
+		logger.Debug("registered resources", "response", string(resourceJSON))
 	}
 
-	// 3. 打印 resources/templates/list - 完整的响应 JSON
+	// 3. resources/templates/list - 完整的响应 JSON
 	listTemplatesMessage := `{"jsonrpc": "2.0", "id": 2, "method": "resources/templates/list"}`
-	templateResponse := server.HandleMessage(ctx, []byte(listTemplatesMessage))
-	if templateResponse != nil {
-		templateJSON, _ := json.MarshalIndent(templateResponse, "", "  ")
-		log.Printf("\n[Resources/Templates/List] Response:\n%s", string(templateJSON))
+	if templateResponse := server.HandleMessage(ctx, []byte(listTemplatesMessage)); templateResponse != nil {
+		templateJSON, _ := json.Marshal(templateResponse)
+		logger.Debug("registered resource templates", "response", string(templateJSON))
 	}
-
-	log.Println("========================================")
-	log.Println("=== Registration Complete ===")
-	log.Println("========================================")
 }
 
 func executionResultToCallToolResult(result *interfaces.ExecutionResult) *mcp.CallToolResult {
@@ -520,9 +552,9 @@ func valueOrDefault(v, fallback string) string {
 }
 
 // buildResourceURIFromTemplate 从 ResourceTemplate 构建固定的 Resource URI
-func buildResourceURIFromTemplate(tpl *mcp.ResourceTemplate) string {
+func buildResourceURIFromTemplate(tpl *mcp.ResourceTemplate, logger executorpkg.Logger) string {
 	if tpl == nil || tpl.URITemplate == nil {
-		log.Printf("[buildResourceURIFromTemplate] tpl or URITemplate is nil")
+		logger.Warn("resource template has no URI template")
 		return "resource://unknown"
 	}
 
@@ -530,29 +562,17 @@ func buildResourceURIFromTemplate(tpl *mcp.ResourceTemplate) string {
 	// 例如: "users{?page,limit}" -> "resource://users{?page,limit}"
 	//      "users/{id}" -> "resource://users/{id}"
 	templateStr := tpl.URITemplate.Template.Raw()
-	log.Printf("[buildResourceURIFromTemplate] Template.Raw() returned: %s", templateStr)
-
 	if templateStr == "" {
-		log.Printf("[buildResourceURIFromTemplate] Template is empty, using name: %s", tpl.Name)
 		templateStr = tpl.Name
 	}
 
 	// 如果已经有 resource:// 前缀，直接使用
 	if strings.HasPrefix(templateStr, "resource://") {
-		log.Printf("[buildResourceURIFromTemplate] Already has resource:// prefix, returning: %s", templateStr)
 		return templateStr
 	}
 
 	// 否则添加 resource:// 前缀，保留完整的模板字符串
 	result := fmt.Sprintf("resource://%s", strings.TrimPrefix(templateStr, "/"))
-	log.Printf("[buildResourceURIFromTemplate] Final URI: %s", result)
-
-	// 检查是否保留了查询参数
-	if strings.Contains(templateStr, "{?") {
-		log.Printf("[buildResourceURIFromTemplate] ✓ Query parameters preserved in URI")
-	} else {
-		log.Printf("[buildResourceURIFromTemplate] ⚠️ No query parameters in template (may be expected)")
-	}
-
+	logger.Debug("resource URI built from template", "template", tpl.Name, "uri", result, "queryParameters", strings.Contains(templateStr, "{?"))
 	return result
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/specx2/mcp-forgebird/core/interfaces"
 	"github.com/specx2/openapi-mcp/core/executor"
+	"github.com/specx2/openapi-mcp/core/factory"
 	"github.com/specx2/openapi-mcp/core/ir"
 )
 
@@ -188,20 +189,14 @@ func (o *openapiOperation) GetParameterMappings() map[string]interfaces.ParamMap
 }
 
 func deriveOperationID(route ir.HTTPRoute) string {
-	if route.OperationID != "" {
-		return route.OperationID
-	}
-	return strings.ToLower(route.Method) + " " + route.Path
+	return factory.DeriveOperationID(route)
 }
 
 func deriveOperationName(route ir.HTTPRoute) string {
 	if route.Summary != "" {
 		return route.Summary
 	}
-	if route.OperationID != "" {
-		return route.OperationID
-	}
-	return strings.ToLower(route.Method) + " " + route.Path
+	return factory.DeriveOperationID(route)
 }
 
 func buildOperationMetadata(route ir.HTTPRoute) *interfaces.OperationMetadata {
@@ -307,14 +302,14 @@ func formatParameterLine(param ir.ParameterInfo) string {
 
 	if param.Schema != nil {
 		if def, ok := param.Schema["default"]; ok {
-			if formatted := formatExample(def); formatted != "" {
+			if formatted := factory.FormatExample(def); formatted != "" {
 				details = append(details, "default: "+formatted)
 			}
 		}
 	}
 
 	if param.Example != nil {
-		if formatted := formatExample(param.Example); formatted != "" {
+		if formatted := factory.FormatExample(param.Example); formatted != "" {
 			details = append(details, "example: "+formatted)
 		}
 	}
@@ -395,17 +390,6 @@ func summarizeSchema(schema ir.Schema) (summary string, schemaType string) {
 	return summary, schemaType
 }
 
-func formatExample(value interface{}) string {
-	switch v := value.(type) {
-	case string:
-		return v
-	case fmt.Stringer:
-		return v.String()
-	default:
-		return fmt.Sprintf("%v", value)
-	}
-}
-
 func cloneSchema(schema interfaces.Schema) interfaces.Schema {
 	if schema == nil {
 		return nil
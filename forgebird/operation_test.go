@@ -0,0 +1,21 @@
+package forgebird
+
+import (
+	"testing"
+
+	"github.com/specx2/openapi-mcp/core/ir"
+)
+
+func TestFormatParameterLineWritesStructuredExamplesAsJSON(t *testing.T) {
+	line := formatParameterLine(ir.ParameterInfo{
+		Name:    "filter",
+		In:      ir.ParameterInQuery,
+		Schema:  ir.Schema{"type": "object", "default": map[string]interface{}{"status": "open"}},
+		Example: map[string]interface{}{"tags": []interface{}{"a", "b"}},
+	})
+
+	want := `- filter: type: object; default: {"status":"open"}; example: {"tags":["a","b"]}`
+	if line != want {
+		t.Fatalf("unexpected parameter line:\n got %s\nwant %s", line, want)
+	}
+}